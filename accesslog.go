@@ -0,0 +1,132 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/internal"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// AccessLogOption configures the AccessLog middleware.
+type AccessLogOption func(config *accessLogConfig)
+
+type accessLogConfig struct {
+	logger  *slog.Logger
+	level   slog.Level
+	message string
+	attrs   []func(r *http.Request) slog.Attr
+}
+
+// AccessLog returns a Middleware that writes one structured log record per request.
+// The record contains the requests method and path, the status code and number of bytes
+// of the response, as well as the time it took to handle the request.
+//
+// Additional attributes can be added using AccessLogAttr.
+// They are computed after the request was handled, so they can also
+// inspect values set during handling, like the requests pattern.
+func AccessLog(options ...AccessLogOption) Middleware {
+	config := accessLogConfig{
+		level:   slog.LevelInfo,
+		message: "Request handled",
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			startTime := time.Now()
+
+			// collect the pattern of the route for AccessLogPattern
+			r, _ = internal.WithRoute(r)
+
+			rw := internal.NewRecordingWriter(w)
+			delegate.ServeHTTP(rw, r)
+
+			duration := time.Since(startTime)
+
+			ctx := r.Context()
+
+			logger := config.logger
+			if logger == nil {
+				logger = internal.LoggerOf(ctx)
+			}
+
+			if !logger.Enabled(ctx, config.level) {
+				return
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rw.StatusCode()),
+				slog.Int64("bytes", rw.BytesWritten()),
+				slog.Duration("duration", duration),
+			}
+
+			for _, attrOf := range config.attrs {
+				if attr := attrOf(r); !attr.Equal(slog.Attr{}) {
+					attrs = append(attrs, attr)
+				}
+			}
+
+			logger.LogAttrs(ctx, config.level, config.message, attrs...)
+		})
+	}
+}
+
+// AccessLogLogger sets the logger to write access log records to.
+// Defaults to the logger provided using ProvideLogger, or slog.Default.
+func AccessLogLogger(logger *slog.Logger) AccessLogOption {
+	return func(config *accessLogConfig) {
+		config.logger = logger
+	}
+}
+
+// AccessLogLevel sets the level of access log records. Defaults to slog.LevelInfo.
+func AccessLogLevel(level slog.Level) AccessLogOption {
+	return func(config *accessLogConfig) {
+		config.level = level
+	}
+}
+
+// AccessLogAttr adds an attribute computed from the request to each access log record.
+// An empty slog.Attr returned by attrOf is ignored.
+func AccessLogAttr(attrOf func(r *http.Request) slog.Attr) AccessLogOption {
+	return func(config *accessLogConfig) {
+		config.attrs = append(config.attrs, attrOf)
+	}
+}
+
+// AccessLogPattern adds the pattern of the matched route as attribute "route".
+// The pattern is only available, if the request was routed by a http.ServeMux. It is
+// recorded by the Handler serving the request, so it is also available if a middleware
+// between AccessLog and the http.ServeMux replaces the request.
+func AccessLogPattern() AccessLogOption {
+	return AccessLogAttr(func(r *http.Request) slog.Attr {
+		_, route := internal.WithRoute(r)
+
+		pattern := route.Pattern(r)
+		if pattern == "" {
+			return slog.Attr{}
+		}
+
+		return slog.String("route", pattern)
+	})
+}
+
+// AccessLogHeader adds the value of the given request header as an attribute,
+// e.g. to include a request id provided by a load balancer. The value of a header
+// listed in the requests Redaction is replaced with RedactedValue.
+func AccessLogHeader(key string, header string) AccessLogOption {
+	return AccessLogAttr(func(r *http.Request) slog.Attr {
+		value := r.Header.Get(header)
+		if value == "" {
+			return slog.Attr{}
+		}
+
+		value = redactionOf(r).RedactHeaderValue(header, value)
+		return slog.String(key, value)
+	})
+}
@@ -0,0 +1,63 @@
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /items/{id}", Handler(func() http.Handler {
+		return response.Text("hello").WithStatusCode(http.StatusAccepted)
+	}))
+
+	accessLog := AccessLog(
+		AccessLogLogger(logger),
+		AccessLogPattern(),
+		AccessLogHeader("request_id", "X-Request-Id"),
+	)
+
+	req := httptest.NewRequest("GET", "/items/12", nil)
+	req.Header.Set("X-Request-Id", "abc")
+
+	accessLog(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	AssertEqual(t, json.Unmarshal(buf.Bytes(), &record), nil)
+
+	AssertEqual(t, record["method"], any("GET"))
+	AssertEqual(t, record["path"], any("/items/12"))
+	AssertEqual(t, record["route"], any("GET /items/{id}"))
+	AssertEqual(t, record["request_id"], any("abc"))
+	AssertEqual(t, record["status"], any(float64(http.StatusAccepted)))
+	AssertEqual(t, record["bytes"], any(float64(5)))
+}
+
+func TestAccessLogPatternBehindMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /items/{id}", Handler(func() http.Handler {
+		return response.Text("hello")
+	}))
+
+	// the middleware passes a copy of the request to the mux
+	accessLog := AccessLog(AccessLogLogger(logger), AccessLogPattern())
+	handler := accessLog(ProvideLogger(logger)(mux))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items/12", nil))
+
+	var record map[string]any
+	AssertEqual(t, json.Unmarshal(buf.Bytes(), &record), nil)
+	AssertEqual(t, record["route"], any("GET /items/{id}"))
+}
@@ -0,0 +1,87 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-gum/gum/internal"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// AfterResponse registers functions that run after the response was written,
+// e.g. to populate a cache or dispatch notifications without delaying the response:
+//
+//	func(path gum.PathValues[ItemPath], after gum.AfterResponse) (Item, error) {
+//	  item, err := loadItem(path.Value.ID)
+//	  after.Do(func(ctx context.Context) { cache.Put(ctx, path.Value.ID, item) })
+//	  return item, err
+//	}
+//
+// The functions run one after another in the order they were registered, on a new
+// goroutine that is started once the response was written. The response is completed
+// when the Handler returns, it does not wait for the functions. A panic in one function
+// is logged and does not affect the other functions. AfterResponse must be a parameter
+// of the handler function, as the functions are started when the Handler closes its
+// parameters. Functions still running when the process exits are not completed,
+// use an Enqueuer for work that must not get lost.
+type AfterResponse struct {
+	state *afterResponse
+}
+
+type afterResponse struct {
+	mu  sync.Mutex
+	fns []func(ctx context.Context)
+	ctx context.Context
+}
+
+var _ = AssertFromRequest[AfterResponse]()
+
+func (AfterResponse) FromRequest(r *http.Request) (AfterResponse, error) {
+	state := &afterResponse{
+		ctx: context.WithoutCancel(r.Context()),
+	}
+
+	return AfterResponse{state: state}, nil
+}
+
+// Do registers fn to run after the response was written. The context carries the values
+// of the requests context, but is not canceled when the client disconnects.
+func (a AfterResponse) Do(fn func(ctx context.Context)) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+
+	a.state.fns = append(a.state.fns, fn)
+}
+
+// Close starts a goroutine running the registered functions.
+func (a AfterResponse) Close() error {
+	a.state.mu.Lock()
+	fns := a.state.fns
+	a.state.fns = nil
+	a.state.mu.Unlock()
+
+	if len(fns) == 0 {
+		return nil
+	}
+
+	go func() {
+		for _, fn := range fns {
+			a.state.run(fn)
+		}
+	}()
+
+	return nil
+}
+
+func (a *afterResponse) run(fn func(ctx context.Context)) {
+	defer func() {
+		if p := recover(); p != nil {
+			internal.LoggerOf(a.ctx).ErrorContext(a.ctx, "After response function panicked",
+				slog.String("panic", fmt.Sprint(p)),
+			)
+		}
+	}()
+
+	fn(a.ctx)
+}
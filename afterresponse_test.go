@@ -0,0 +1,91 @@
+package gum
+
+import (
+	"context"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAfterResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	var calls []string
+	done := make(chan struct{})
+
+	handler := Handler(func(after AfterResponse) http.Handler {
+		after.Do(func(ctx context.Context) {
+			calls = append(calls, "first")
+		})
+
+		after.Do(func(ctx context.Context) {
+			panic("boom")
+		})
+
+		after.Do(func(ctx context.Context) {
+			defer close(done)
+
+			calls = append(calls, "third")
+			AssertEqual(t, ctx.Err(), nil)
+		})
+
+		return response.Text("ok")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	AssertEqual(t, rec.Body.String(), "ok")
+
+	AssertTrue(t, !rec.Flushed)
+
+	<-done
+	AssertEqual(t, calls, []string{"first", "third"})
+}
+
+func TestAfterResponseDoesNotDelay(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(Handler(func(after AfterResponse) http.Handler {
+		after.Do(func(ctx context.Context) { <-release })
+		return response.Text("ok")
+	}))
+
+	defer server.Close()
+	defer close(release)
+
+	client := server.Client()
+	client.Timeout = time.Second
+
+	resp, err := client.Get(server.URL)
+	AssertEqual(t, err, nil)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// the response is complete and not chunked while the function is still running
+	body, err := io.ReadAll(resp.Body)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, string(body), "ok")
+	AssertEqual(t, resp.ContentLength, int64(2))
+}
+
+func TestAfterResponseOnError(t *testing.T) {
+	called := make(chan struct{})
+
+	handler := Handler(func(after AfterResponse) error {
+		after.Do(func(ctx context.Context) { close(called) })
+		return NewHTTPError(http.StatusConflict, nil)
+	})
+
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusConflict)
+
+	<-called
+}
@@ -0,0 +1,53 @@
+package gum
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// BearerToken is the token of a bearer Authorization header as defined in RFC 6750.
+// Extraction fails with 401 Unauthorized if the header is missing or malformed.
+type BearerToken string
+
+// BasicAuth holds the credentials of a basic Authorization header as defined in RFC 7617.
+// Extraction fails with 401 Unauthorized if the header is missing or malformed.
+type BasicAuth struct {
+	User     string
+	Password string
+}
+
+func init() {
+	Register(func(r *http.Request) (BearerToken, error) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			err := errors.New("no Authorization header in request")
+			return "", UnauthorizedError("Bearer", err)
+		}
+
+		scheme, token, _ := strings.Cut(authorization, " ")
+		token = strings.TrimSpace(token)
+
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			err := errors.New("malformed bearer token in Authorization header")
+			return "", UnauthorizedError(`Bearer error="invalid_request"`, err)
+		}
+
+		return BearerToken(token), nil
+	})
+
+	Register(func(r *http.Request) (BasicAuth, error) {
+		if r.Header.Get("Authorization") == "" {
+			err := errors.New("no Authorization header in request")
+			return BasicAuth{}, UnauthorizedError(`Basic realm="restricted", charset="UTF-8"`, err)
+		}
+
+		user, password, ok := r.BasicAuth()
+		if !ok {
+			err := errors.New("malformed basic credentials in Authorization header")
+			return BasicAuth{}, UnauthorizedError(`Basic realm="restricted", charset="UTF-8"`, err)
+		}
+
+		return BasicAuth{User: user, Password: password}, nil
+	})
+}
@@ -0,0 +1,58 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBearerToken(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "bearer abc.def")
+
+		var extractedValue BearerToken
+		Handler(func(v BearerToken) { extractedValue = v }).ServeHTTP(nil, req)
+		AssertEqual(t, extractedValue, "abc.def")
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+
+		rec := httptest.NewRecorder()
+		Handler(func(v BearerToken) { t.FailNow() }).ServeHTTP(rec, req)
+		AssertEqual(t, rec.Code, http.StatusUnauthorized)
+		AssertEqual(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Basic Zm9vOmJhcg==")
+
+		rec := httptest.NewRecorder()
+		Handler(func(v BearerToken) { t.FailNow() }).ServeHTTP(rec, req)
+		AssertEqual(t, rec.Code, http.StatusUnauthorized)
+		AssertEqual(t, rec.Header().Get("WWW-Authenticate"), `Bearer error="invalid_request"`)
+	})
+}
+
+func TestBasicAuth(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.SetBasicAuth("albert", "secret")
+
+		var extractedValue BasicAuth
+		Handler(func(v BasicAuth) { extractedValue = v }).ServeHTTP(nil, req)
+		AssertEqual(t, extractedValue, BasicAuth{User: "albert", Password: "secret"})
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+
+		rec := httptest.NewRecorder()
+		Handler(func(v BasicAuth) { t.FailNow() }).ServeHTTP(rec, req)
+		AssertEqual(t, rec.Code, http.StatusUnauthorized)
+		AssertTrue(t, rec.Header().Get("WWW-Authenticate") != "")
+	})
+}
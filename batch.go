@@ -0,0 +1,238 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/response"
+	"io"
+	"iter"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+)
+
+// Batch extracts a batch of operations of type T from the request body. The body is
+// either a json array of operations, or a multipart/mixed body with one json document per
+// part. Operations are decoded lazily while iterating over Batch.Items, so large batches
+// are never held in memory:
+//
+//	func handleBatch(batch gum.Batch[CreateUser]) response.Response {
+//		var results []gum.BatchResult
+//
+//		for item := range batch.Items() {
+//			if item.Err != nil {
+//				results = append(results, item.Fail(http.StatusBadRequest, item.Err))
+//				continue
+//			}
+//
+//			user := createUser(item.Value)
+//			results = append(results, item.Result(http.StatusCreated, user))
+//		}
+//
+//		return batch.Respond(results)
+//	}
+//
+// Extraction fails with 415 Unsupported Media Type for other content types. The size of
+// the body is limited by JSONOptions.MaxBytes, like the JSON extractor does. An operation
+// exceeding the limit has an HTTPError with status 413 Request Entity Too Large as Err.
+type Batch[T any] struct {
+	multipart bool
+	items     iter.Seq[BatchItem[T]]
+}
+
+var _ = AssertFromRequest[Batch[any]]()
+
+// BatchItem is a single operation of a Batch.
+type BatchItem[T any] struct {
+	// Index is the position of the operation within the batch
+	Index int
+
+	// ID is the Content-ID of the part of a multipart/mixed batch. Empty for json arrays.
+	ID string
+
+	// Value holds the decoded operation
+	Value T
+
+	// Err is set if the operation could not be decoded
+	Err error
+}
+
+// Result creates the BatchResult of this operation.
+func (b BatchItem[T]) Result(statusCode int, body any) BatchResult {
+	return BatchResult{ID: b.ID, StatusCode: statusCode, Body: body}
+}
+
+// Fail creates a BatchResult reporting err for this operation.
+func (b BatchItem[T]) Fail(statusCode int, err error) BatchResult {
+	return b.Result(statusCode, batchError{Error: err.Error()})
+}
+
+type batchError struct {
+	Error string `json:"error"`
+}
+
+// BatchResult is the result of a single operation of a batch.
+type BatchResult struct {
+	ID         string `json:"id,omitempty"`
+	StatusCode int    `json:"status"`
+	Body       any    `json:"body,omitempty"`
+}
+
+// ErrUnsupportedBatch is returned if the request body is neither a json array nor multipart/mixed.
+var ErrUnsupportedBatch = errors.New("batch must be a json array or multipart/mixed")
+
+func (Batch[T]) consumesBody() bool { return true }
+
+func (Batch[T]) FromRequest(r *http.Request) (Batch[T], error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return Batch[T]{}, NewHTTPError(http.StatusUnsupportedMediaType, ErrUnsupportedBatch)
+	}
+
+	body := jsonOptionsOf(r).limitBody(r)
+
+	switch mediaType {
+	case "application/json":
+		return Batch[T]{items: jsonBatchItems[T](body)}, nil
+
+	case "multipart/mixed":
+		boundary := params["boundary"]
+		if boundary == "" {
+			return Batch[T]{}, errors.New("multipart/mixed without boundary")
+		}
+
+		reader := multipart.NewReader(body, boundary)
+		return Batch[T]{multipart: true, items: multipartBatchItems[T](reader)}, nil
+
+	default:
+		return Batch[T]{}, NewHTTPError(http.StatusUnsupportedMediaType, ErrUnsupportedBatch)
+	}
+}
+
+// Items iterates over the operations of the batch. The body is consumed while iterating,
+// so Items can only be iterated once. A malformed body stops the iteration after
+// yielding an item with Err set.
+func (b Batch[T]) Items() iter.Seq[BatchItem[T]] {
+	if b.items == nil {
+		return func(yield func(BatchItem[T]) bool) {}
+	}
+
+	return b.items
+}
+
+// Respond builds a response with the results of the operations, using the format of
+// the request: a json array of BatchResult values, or a multipart/mixed body with one
+// json part per result. The parts carry the Content-ID of their operation and a Status
+// header with the status code. The response itself has status 200 OK.
+func (b Batch[T]) Respond(results []BatchResult) response.Response {
+	if !b.multipart {
+		return response.New(func(w io.Writer) error {
+			return json.NewEncoder(w).Encode(results)
+		}).SetHeader("Content-Type", "application/json; charset=utf8")
+	}
+
+	writer := multipart.NewWriter(io.Discard)
+	boundary := writer.Boundary()
+
+	body := func(w io.Writer) error {
+		writer := multipart.NewWriter(w)
+		if err := writer.SetBoundary(boundary); err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Type", "application/json")
+			header.Set("Status", strconv.Itoa(result.StatusCode))
+
+			if result.ID != "" {
+				header.Set("Content-ID", result.ID)
+			}
+
+			part, err := writer.CreatePart(header)
+			if err != nil {
+				return err
+			}
+
+			if result.Body != nil {
+				if err := json.NewEncoder(part).Encode(result.Body); err != nil {
+					return fmt.Errorf("encode result %q: %w", result.ID, err)
+				}
+			}
+		}
+
+		return writer.Close()
+	}
+
+	return response.New(body).
+		SetHeader("Content-Type", "multipart/mixed; boundary="+boundary)
+}
+
+func jsonBatchItems[T any](body io.Reader) iter.Seq[BatchItem[T]] {
+	return func(yield func(BatchItem[T]) bool) {
+		decoder := json.NewDecoder(body)
+
+		token, err := decoder.Token()
+		if err != nil {
+			yield(BatchItem[T]{Err: bodyTooLarge(fmt.Errorf("batch must be a json array: %w", err))})
+			return
+		}
+
+		if token != json.Delim('[') {
+			yield(BatchItem[T]{Err: errors.New("batch must be a json array")})
+			return
+		}
+
+		for idx := 0; decoder.More(); idx++ {
+			item := BatchItem[T]{Index: idx}
+
+			if err := decoder.Decode(&item.Value); err != nil {
+				// the decoder can not recover from syntax errors
+				item.Err = bodyTooLarge(fmt.Errorf("decode operation %d: %w", idx, err))
+				yield(item)
+				return
+			}
+
+			if err := validateValue(item.Value); err != nil {
+				item.Err = err
+			}
+
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+func multipartBatchItems[T any](reader *multipart.Reader) iter.Seq[BatchItem[T]] {
+	return func(yield func(BatchItem[T]) bool) {
+		for idx := 0; ; idx++ {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				yield(BatchItem[T]{Index: idx, Err: bodyTooLarge(fmt.Errorf("read part %d: %w", idx, err))})
+				return
+			}
+
+			item := BatchItem[T]{Index: idx, ID: part.Header.Get("Content-ID")}
+
+			if err := json.NewDecoder(part).Decode(&item.Value); err != nil {
+				item.Err = bodyTooLarge(fmt.Errorf("decode operation %d: %w", idx, err))
+			} else if err := validateValue(item.Value); err != nil {
+				item.Err = err
+			}
+
+			_ = part.Close()
+
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	type Operation struct {
+		Name string `json:"name"`
+	}
+
+	fn := func(batch Batch[Operation]) response.Response {
+		var results []BatchResult
+
+		for item := range batch.Items() {
+			if item.Err != nil {
+				results = append(results, item.Fail(http.StatusBadRequest, item.Err))
+				continue
+			}
+
+			results = append(results, item.Result(http.StatusOK, "hello "+item.Value.Name))
+		}
+
+		return batch.Respond(results)
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"name": "Albert"}, {"name": "Bob"}]`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+
+		var results []struct {
+			StatusCode int    `json:"status"`
+			Body       string `json:"body"`
+		}
+
+		AssertEqual(t, json.Unmarshal(resp.Body, &results), nil)
+		AssertEqual(t, len(results), 2)
+		AssertEqual(t, results[0].StatusCode, http.StatusOK)
+		AssertEqual(t, results[0].Body, "hello Albert")
+		AssertEqual(t, results[1].Body, "hello Bob")
+	})
+
+	t.Run("JSONInvalidOperation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"name": "Albert"}, {"name": 1}]`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+
+		var results []BatchResult
+		AssertEqual(t, json.Unmarshal(resp.Body, &results), nil)
+		AssertEqual(t, len(results), 2)
+		AssertEqual(t, results[0].StatusCode, http.StatusOK)
+		AssertEqual(t, results[1].StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("Multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+
+		for _, name := range []string{"Albert", "Bob"} {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Type", "application/json")
+			header.Set("Content-ID", "<"+name+">")
+
+			part, _ := writer.CreatePart(header)
+			_, _ = io.WriteString(part, `{"name": "`+name+`"}`)
+		}
+
+		_ = writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/", &body)
+		req.Header.Set("Content-Type", "multipart/mixed; boundary="+writer.Boundary())
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+
+		mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		AssertEqual(t, err, nil)
+		AssertEqual(t, mediaType, "multipart/mixed")
+
+		reader := multipart.NewReader(bytes.NewReader(resp.Body), params["boundary"])
+
+		for _, name := range []string{"Albert", "Bob"} {
+			part, err := reader.NextPart()
+			AssertEqual(t, err, nil)
+			AssertEqual(t, part.Header.Get("Content-ID"), "<"+name+">")
+			AssertEqual(t, part.Header.Get("Status"), "200")
+
+			var value string
+			AssertEqual(t, json.NewDecoder(part).Decode(&value), nil)
+			AssertEqual(t, value, "hello "+name)
+		}
+
+		_, err = reader.NextPart()
+		AssertEqual(t, err, io.EOF)
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		handler := ProvideContextValue(JSONOptions{MaxBytes: 32})(Handler(func(batch Batch[Operation]) error {
+			for item := range batch.Items() {
+				if item.Err != nil {
+					return item.Err
+				}
+			}
+
+			return nil
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"name": "Albert"}, {"name": "Bob"}]`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := gumtest.Serve(handler, req)
+		AssertEqual(t, resp.StatusCode, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("UnsupportedMediaType", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`name=Albert`))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusUnsupportedMediaType)
+	})
+}
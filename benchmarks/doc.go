@@ -0,0 +1,11 @@
+// Package benchmarks contains benchmarks of the reflection heavy core of gum: dispatching
+// requests in Handler, decoding QueryValues, PathValues and JSON bodies, and encoding
+// responses. All benchmarks report allocations.
+//
+// Run them multiple times and compare the results of two revisions using benchstat:
+//
+//	go test -run - -bench . -count 10 ./benchmarks > old.txt
+//	git checkout my-branch
+//	go test -run - -bench . -count 10 ./benchmarks > new.txt
+//	benchstat old.txt new.txt
+package benchmarks
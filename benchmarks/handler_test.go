@@ -0,0 +1,146 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// discardWriter is a http.ResponseWriter that does not allocate while writing.
+type discardWriter struct {
+	header http.Header
+}
+
+func newDiscardWriter() *discardWriter {
+	return &discardWriter{header: http.Header{}}
+}
+
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardWriter) Write(buf []byte) (int, error) {
+	return len(buf), nil
+}
+
+func (w *discardWriter) WriteHeader(int) {}
+
+func serve(b *testing.B, handler http.Handler, req *http.Request) {
+	w := newDiscardWriter()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		handler.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkHandler(b *testing.B) {
+	req := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+
+	b.Run("NoParameters", func(b *testing.B) {
+		serve(b, gum.Handler(func() {}), req)
+	})
+
+	b.Run("Request", func(b *testing.B) {
+		serve(b, gum.Handler(func(*http.Request) {}), req)
+	})
+
+	b.Run("ContextMethodHost", func(b *testing.B) {
+		serve(b, gum.Handler(func(context.Context, gum.Method, gum.Host) {}), req)
+	})
+
+	b.Run("Option", func(b *testing.B) {
+		serve(b, gum.Handler(func(gum.Option[gum.ContentType]) {}), req)
+	})
+
+	b.Run("Error", func(b *testing.B) {
+		serve(b, gum.Handler(func() error { return io.EOF }), req)
+	})
+}
+
+type Page struct {
+	Page    int      `json:"page"`
+	PerPage int      `json:"per_page"`
+	Sort    string   `json:"sort"`
+	Tags    []string `json:"tags"`
+}
+
+func BenchmarkQueryValues(b *testing.B) {
+	req := httptest.NewRequest(http.MethodGet, "/users?page=2&per_page=50&sort=name&tags=a&tags=b", nil)
+	serve(b, gum.Handler(func(gum.QueryValues[Page]) {}), req)
+}
+
+type UserPath struct {
+	OrgID  string `json:"org"`
+	UserID int64  `json:"id"`
+}
+
+func BenchmarkPathValues(b *testing.B) {
+	req := httptest.NewRequest(http.MethodGet, "/orgs/gum/users/12", nil)
+	req.SetPathValue("org", "gum")
+	req.SetPathValue("id", "12")
+
+	serve(b, gum.Handler(func(gum.PathValues[UserPath]) {}), req)
+}
+
+type User struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Email   string            `json:"email"`
+	Roles   []string          `json:"roles"`
+	Profile map[string]string `json:"profile"`
+}
+
+var userJSON = []byte(`{"id": 12, "name": "Albert", "email": "albert@example.com", "roles": ["admin", "dev"], "profile": {"city": "Ulm"}}`)
+
+func BenchmarkJSON(b *testing.B) {
+	handler := gum.Handler(func(gum.JSON[User]) {})
+
+	body := bytes.NewReader(userJSON)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := newDiscardWriter()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		body.Reset(userJSON)
+		req.Body = io.NopCloser(body)
+
+		handler.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkResponse(b *testing.B) {
+	req := httptest.NewRequest(http.MethodGet, "/users/12", nil)
+
+	user := User{
+		ID:      12,
+		Name:    "Albert",
+		Email:   "albert@example.com",
+		Roles:   []string{"admin", "dev"},
+		Profile: map[string]string{"city": "Ulm"},
+	}
+
+	b.Run("Text", func(b *testing.B) {
+		serve(b, gum.Handler(func() response.Response { return response.Text("hello") }), req)
+	})
+
+	b.Run("JSON", func(b *testing.B) {
+		serve(b, gum.Handler(func() response.Lazy { return response.JSON(user) }), req)
+	})
+
+	b.Run("Status", func(b *testing.B) {
+		serve(b, gum.Handler(func() response.Response { return response.NoContent() }), req)
+	})
+}
@@ -0,0 +1,82 @@
+package benchmarks
+
+import (
+	"github.com/go-gum/gum/serde"
+	"testing"
+)
+
+// valuesSource is a serde.SourceValue for nested maps of strings, similar to url.Values.
+type valuesSource struct {
+	value    string
+	children map[string]*valuesSource
+}
+
+func (v *valuesSource) Bool() (bool, error) {
+	return serde.StringValue(v.value).Bool()
+}
+
+func (v *valuesSource) Int() (int64, error) {
+	return serde.StringValue(v.value).Int()
+}
+
+func (v *valuesSource) Float() (float64, error) {
+	return serde.StringValue(v.value).Float()
+}
+
+func (v *valuesSource) String() (string, error) {
+	return v.value, nil
+}
+
+func (v *valuesSource) Get(key string) (serde.SourceValue, error) {
+	child, ok := v.children[key]
+	if !ok {
+		return nil, serde.ErrNoValue
+	}
+
+	return child, nil
+}
+
+func BenchmarkSerde(b *testing.B) {
+	source := &valuesSource{children: map[string]*valuesSource{
+		"page":     {value: "2"},
+		"per_page": {value: "50"},
+		"sort":     {value: "name"},
+	}}
+
+	b.Run("UnmarshalNew", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			if _, err := serde.UnmarshalNew[Page](source); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Decoder", func(b *testing.B) {
+		decoder, err := serde.For[Page]()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+
+		for range b.N {
+			if _, err := decoder.Decode(source); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("MarshalValues", func(b *testing.B) {
+		page := Page{Page: 2, PerPage: 50, Sort: "name", Tags: []string{"a", "b"}}
+
+		b.ReportAllocs()
+
+		for range b.N {
+			if _, err := serde.MarshalValues(page); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ErrBodyReadTimeout is wrapped by the error returned when reading the request body
+// takes longer than allowed by BodyReadTimeout.
+var ErrBodyReadTimeout = errors.New("request body read timeout")
+
+// BodyReadTimeout returns a Middleware that limits the time to read the request body,
+// protecting against clients sending their body very slowly. The timeout starts when
+// the middleware is called. Apply it to the whole server and wrap single routes, e.g.
+// upload endpoints, in another BodyReadTimeout to change the timeout for them. The inner
+// BodyReadTimeout replaces the deadline of the outer one, so it can extend the timeout.
+//
+// If the timeout is exceeded, reading the body fails with an error wrapping an HTTPError
+// with status 408 Request Timeout, so an extractor reading the body responds with 408.
+// The deadline is enforced on the connection using http.ResponseController. If the
+// ResponseWriter does not support read deadlines, the timeout is only checked between
+// reads of the body.
+func BodyReadTimeout(timeout time.Duration) Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deadline := time.Now().Add(timeout)
+
+			// errors are ignored, we check the deadline on each read anyway
+			_ = http.NewResponseController(w).SetReadDeadline(deadline)
+
+			switch existing, ok := r.Context().Value(timeoutBodyKey{}).(*timeoutBody); {
+			case ok:
+				// the body is already wrapped by an outer BodyReadTimeout
+				existing.deadline = deadline
+
+			case r.Body != nil && r.Body != http.NoBody:
+				body := &timeoutBody{ReadCloser: r.Body, deadline: deadline}
+
+				r = r.WithContext(context.WithValue(r.Context(), timeoutBodyKey{}, body))
+				r.Body = body
+			}
+
+			delegate.ServeHTTP(w, r)
+		})
+	}
+}
+
+// timeoutBodyKey is the context key of the timeoutBody installed by BodyReadTimeout.
+type timeoutBodyKey struct{}
+
+type timeoutBody struct {
+	io.ReadCloser
+	deadline time.Time
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	if time.Now().After(b.deadline) {
+		return 0, bodyTimeoutError()
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+		return n, bodyTimeoutError()
+	}
+
+	return n, err
+}
+
+func bodyTimeoutError() error {
+	return NewHTTPError(http.StatusRequestTimeout, ErrBodyReadTimeout).
+		WithHeader("Connection", "close")
+}
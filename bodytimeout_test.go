@@ -0,0 +1,75 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBodyReadTimeout(t *testing.T) {
+	handler := BodyReadTimeout(50 * time.Millisecond)(Handler(func(body RawBody) error {
+		return nil
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// a client sending its body too slowly
+	bodyReader, bodyWriter := io.Pipe()
+
+	go func() {
+		_, _ = bodyWriter.Write([]byte("slow"))
+		time.Sleep(200 * time.Millisecond)
+		_ = bodyWriter.Close()
+	}()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, bodyReader)
+	req.ContentLength = 100
+
+	resp, err := http.DefaultClient.Do(req)
+	AssertEqual(t, err, nil)
+	_ = resp.Body.Close()
+	AssertEqual(t, resp.StatusCode, http.StatusRequestTimeout)
+
+	// a fast client is not affected
+	resp, err = http.Post(server.URL, "text/plain", strings.NewReader("fast"))
+	AssertEqual(t, err, nil)
+	_ = resp.Body.Close()
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+}
+
+// slowReader waits before returning its content.
+type slowReader struct {
+	delay   time.Duration
+	content io.Reader
+}
+
+func (s slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.content.Read(p)
+}
+
+func TestBodyReadTimeoutNested(t *testing.T) {
+	handler := Handler(func(body RawBody) http.Handler {
+		return response.Text(string(body))
+	})
+
+	serve := func(handler http.Handler) int {
+		body := slowReader{delay: 50 * time.Millisecond, content: strings.NewReader("upload")}
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		return gumtest.Serve(handler, req).StatusCode
+	}
+
+	outer := BodyReadTimeout(10 * time.Millisecond)
+	AssertEqual(t, serve(outer(handler)), http.StatusRequestTimeout)
+
+	// the inner timeout extends the timeout of the outer one
+	inner := BodyReadTimeout(time.Second)
+	AssertEqual(t, serve(outer(inner(handler))), http.StatusOK)
+}
@@ -0,0 +1,531 @@
+// Package cache caches responses of GET requests on the server side.
+//
+// Responses are stored in a Store, keyed by the request url and the values of the
+// request headers listed in the Vary header of the response, plus the headers
+// configured using KeyHeaders. Concurrent requests for the same resource are
+// coalesced, so only one of them reaches the handler while the others wait for
+// its response:
+//
+//	responses := cache.New(cache.NewMemoryStore(), cache.TTL(30*time.Second))
+//	mux.Handle("GET /products", responses.Middleware()(productsHandler))
+//
+//	// after a product was changed
+//	_ = responses.Invalidate(ctx, "/products")
+//
+// Responses marked with Cache-Control no-store or private, responses setting cookies
+// and responses with a status code other than 200 are never cached. Requests with an
+// Authorization header are never served from the cache, their responses are only stored
+// if marked public, s-maxage or must-revalidate (RFC 9111, section 3.5). A max-age or
+// s-maxage directive of the response takes precedence over the configured TTL.
+// Requests with other methods than GET pass through, but invalidate the cached
+// response of their url. As responses are buffered completely before they are sent
+// to the client, do not use the Middleware for streaming responses.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"github.com/go-gum/gum"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a cached response.
+type Entry struct {
+	// Vary lists the request headers that select a variant of the response. An Entry
+	// stored under the primary key of a url only holds the names of these headers
+	// and the keys of the stored variants.
+	Vary []string
+
+	// Variants lists the keys of the variants stored for the url of a primary entry
+	Variants []string
+
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// StoredAt is the time the response was stored, used for the Age header
+	StoredAt time.Time
+
+	// ExpiresAt is the time the entry expires
+	ExpiresAt time.Time
+}
+
+// maxVariants limits the number of variants stored for a single url.
+// If the limit is reached, the oldest variant is removed.
+const maxVariants = 64
+
+// Store stores cached entries.
+type Store interface {
+	// Get returns the entry stored for the key, or nil if there is none.
+	Get(ctx context.Context, key string) (*Entry, error)
+
+	// Set stores the entry for the key for the given time to live.
+	Set(ctx context.Context, key string, entry Entry, ttl time.Duration) error
+
+	// Delete removes the entry of the key.
+	Delete(ctx context.Context, key string) error
+}
+
+// Option configures a Cache.
+type Option func(c *Cache)
+
+// TTL sets how long responses without a max-age directive are cached. Defaults to one minute.
+func TTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// KeyHeaders adds request headers to the cache key, even if the response
+// does not list them in its Vary header.
+func KeyHeaders(names ...string) Option {
+	return func(c *Cache) {
+		for _, name := range names {
+			c.keyHeaders = append(c.keyHeaders, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+// Cache caches responses in a Store.
+type Cache struct {
+	store      Store
+	ttl        time.Duration
+	keyHeaders []string
+
+	// mu guards updates of the variant index of primary entries
+	mu      sync.Mutex
+	flights flightGroup
+}
+
+// New creates a new Cache storing responses in the given Store.
+func New(store Store, options ...Option) *Cache {
+	c := &Cache{store: store, ttl: time.Minute}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// Invalidate removes all cached variants of the response for the given url, e.g. "/products?page=1".
+func (c *Cache) Invalidate(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	return c.invalidate(ctx, primaryKey(parsed))
+}
+
+// invalidate removes the primary entry and all variants indexed by it.
+func (c *Cache) invalidate(ctx context.Context, primary string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	marker, err := c.store.Get(ctx, primary)
+	if err != nil {
+		return err
+	}
+
+	if marker != nil {
+		for _, key := range marker.Variants {
+			if err := c.store.Delete(ctx, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return c.store.Delete(ctx, primary)
+}
+
+// Middleware serves cached responses and caches the responses of the wrapped handler.
+// Responses carry an X-Cache header with the value HIT or MISS.
+func (c *Cache) Middleware() gum.Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if r.Method != http.MethodGet {
+				delegate.ServeHTTP(w, r)
+
+				// unsafe methods invalidate the cached response of their url (RFC 9111, section 4.4)
+				if r.Method != http.MethodHead && r.Method != http.MethodOptions {
+					_ = c.invalidate(context.WithoutCancel(ctx), primaryKey(r.URL))
+				}
+
+				return
+			}
+
+			if requestNoCache(r) {
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Header.Get("Authorization") != "" {
+				// never serve a response stored for a different user
+				entry := c.record(delegate, r, primaryKey(r.URL))
+				replay(w, entry, "MISS")
+				return
+			}
+
+			primary := primaryKey(r.URL)
+
+			// the names of the headers selecting the variant are stored under the primary key
+			vary := c.keyHeaders
+			if marker, err := c.store.Get(ctx, primary); err == nil && marker != nil {
+				vary = marker.Vary
+			}
+
+			if entry, err := c.store.Get(ctx, variantKey(primary, vary, r.Header)); err == nil && entry != nil {
+				replay(w, *entry, "HIT")
+				return
+			}
+
+			// Only one request per variant calls the handler, concurrent requests share its response.
+			// As the Vary header of the response is not known yet, the response of the leading
+			// request is only shared with requests that select the same variant.
+			result, leader := c.flights.Do(variantKey(primary, vary, r.Header), func() flightResult {
+				entry := c.record(delegate, r, primary)
+
+				vary, ok := c.responseVary(entry.Header)
+				shareable := ok && storable(r, entry)
+				return flightResult{entry: entry, vary: vary, shareable: shareable, variant: variantKey(primary, vary, r.Header)}
+			})
+
+			switch {
+			case leader:
+				replay(w, result.entry, "MISS")
+
+			case result.entry.StatusCode == 0:
+				// the leading request panicked, try on our own
+				delegate.ServeHTTP(w, r)
+
+			case !result.shareable || variantKey(primary, result.vary, r.Header) != result.variant:
+				// the response of the leading request must not be shared or is a different variant
+				delegate.ServeHTTP(w, r)
+
+			default:
+				replay(w, result.entry, "HIT")
+			}
+		})
+	}
+}
+
+// record calls the handler and stores its response, if it is cacheable.
+func (c *Cache) record(delegate http.Handler, r *http.Request, primary string) Entry {
+	rec := &recorder{header: http.Header{}}
+	delegate.ServeHTTP(rec, r)
+
+	entry := Entry{
+		StatusCode: rec.StatusCode(),
+		Header:     rec.header,
+		Body:       rec.body.Bytes(),
+		StoredAt:   time.Now(),
+	}
+
+	c.storeEntry(context.WithoutCancel(r.Context()), r, primary, entry)
+
+	return entry
+}
+
+func (c *Cache) storeEntry(ctx context.Context, r *http.Request, primary string, entry Entry) {
+	if !storable(r, entry) {
+		return
+	}
+
+	ttl, ok := c.ttlOf(entry)
+	if !ok {
+		return
+	}
+
+	vary, ok := c.responseVary(entry.Header)
+	if !ok {
+		return
+	}
+
+	entry.ExpiresAt = entry.StoredAt.Add(ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	marker := Entry{Vary: vary, StoredAt: entry.StoredAt, ExpiresAt: entry.ExpiresAt}
+
+	// keep the index of the variants stored before, the primary entry must live as long as they do
+	if existing, err := c.store.Get(ctx, primary); err != nil {
+		return
+	} else if existing != nil {
+		marker.Variants = existing.Variants
+		marker.ExpiresAt = latest(marker.ExpiresAt, existing.ExpiresAt)
+	}
+
+	key := variantKey(primary, vary, r.Header)
+	if key == primary {
+		// without a Vary header, the response is stored as the primary entry
+		entry.Variants, entry.ExpiresAt = marker.Variants, marker.ExpiresAt
+		_ = c.store.Set(ctx, primary, entry, time.Until(entry.ExpiresAt))
+		return
+	}
+
+	if !slices.Contains(marker.Variants, key) {
+		marker.Variants = append(slices.Clone(marker.Variants), key)
+	}
+
+	if len(marker.Variants) > maxVariants {
+		_ = c.store.Delete(ctx, marker.Variants[0])
+		marker.Variants = marker.Variants[1:]
+	}
+
+	if err := c.store.Set(ctx, primary, marker, time.Until(marker.ExpiresAt)); err != nil {
+		return
+	}
+
+	_ = c.store.Set(ctx, key, entry, ttl)
+}
+
+// responseVary returns the sorted names of the request headers that select the variant of
+// a response. It returns false if the response has a Vary header with the value "*".
+func (c *Cache) responseVary(header http.Header) ([]string, bool) {
+	vary := slices.Clone(c.keyHeaders)
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+
+			if name == "*" {
+				// the response depends on something other than the request headers
+				return nil, false
+			}
+
+			if name != "" && !slices.Contains(vary, name) {
+				vary = append(vary, name)
+			}
+		}
+	}
+
+	slices.Sort(vary)
+
+	return vary, true
+}
+
+// storable checks if the response may be stored by a shared cache and thereby be served
+// to other clients, regardless of how long it may be cached.
+func storable(r *http.Request, entry Entry) bool {
+	if entry.StatusCode != http.StatusOK || entry.Header.Get("Set-Cookie") != "" {
+		return false
+	}
+
+	for _, value := range entry.Header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+			switch strings.ToLower(name) {
+			case "no-store", "private", "no-cache":
+				return false
+			}
+		}
+	}
+
+	return r.Header.Get("Authorization") == "" || storableWithAuthorization(entry.Header)
+}
+
+// storableWithAuthorization checks if the response to a request with an Authorization
+// header may be stored by a shared cache (RFC 9111, section 3.5).
+func storableWithAuthorization(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+			switch strings.ToLower(name) {
+			case "public", "s-maxage", "must-revalidate":
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
+
+// ttlOf returns how long a storable response may be cached, or false if it expires immediately.
+func (c *Cache) ttlOf(entry Entry) (time.Duration, bool) {
+	ttl := c.ttl
+
+	var maxAge, sharedMaxAge string
+	for _, value := range entry.Header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+			switch strings.ToLower(name) {
+			case "max-age":
+				maxAge = value
+
+			case "s-maxage":
+				sharedMaxAge = value
+			}
+		}
+	}
+
+	for _, value := range []string{maxAge, sharedMaxAge} {
+		if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return ttl, ttl > 0
+}
+
+// requestNoCache checks if the client asks to bypass the cache.
+func requestNoCache(r *http.Request) bool {
+	for _, value := range r.Header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			switch strings.ToLower(strings.TrimSpace(directive)) {
+			case "no-cache", "no-store":
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func primaryKey(u *url.URL) string {
+	return "GET " + u.RequestURI()
+}
+
+func variantKey(primary string, vary []string, header http.Header) string {
+	if len(vary) == 0 {
+		return primary
+	}
+
+	var buf strings.Builder
+	buf.WriteString(primary)
+
+	for _, name := range vary {
+		buf.WriteString("\n")
+		buf.WriteString(name)
+		buf.WriteString(": ")
+		buf.WriteString(strings.Join(header.Values(name), ", "))
+	}
+
+	return buf.String()
+}
+
+func replay(w http.ResponseWriter, entry Entry, status string) {
+	header := w.Header()
+	for key, values := range entry.Header {
+		header[key] = slices.Clone(values)
+	}
+
+	if status == "HIT" && !entry.StoredAt.IsZero() {
+		header.Set("Age", strconv.Itoa(int(time.Since(entry.StoredAt).Seconds())))
+	}
+
+	header.Set("X-Cache", status)
+	header.Set("Content-Length", strconv.Itoa(len(entry.Body)))
+
+	w.WriteHeader(entry.StatusCode)
+	_, _ = w.Write(entry.Body)
+}
+
+// recorder captures the response of the handler without writing it to the client.
+type recorder struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+}
+
+func (r *recorder) Write(bytes []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+
+	return r.body.Write(bytes)
+}
+
+func (r *recorder) StatusCode() int {
+	if r.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return r.statusCode
+}
+
+// flightGroup runs a function only once for concurrent calls with the same key.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done   chan struct{}
+	result flightResult
+}
+
+// flightResult is the response of the leading request of a flight.
+type flightResult struct {
+	entry Entry
+
+	// vary are the names of the headers selecting the variant of the response
+	vary []string
+
+	// variant is the key of the variant selected by the leading request
+	variant string
+
+	// shareable is false, if the response varies on something other than request headers
+	shareable bool
+}
+
+// Do runs fn, unless a call with the same key is already running. In that case, Do waits
+// for the running call and returns its result. leader is true if fn was run by this call.
+func (g *flightGroup) Do(key string, fn func() flightResult) (result flightResult, leader bool) {
+	g.mu.Lock()
+
+	if f, ok := g.flights[key]; ok {
+		g.mu.Unlock()
+		<-f.done
+		return f.result, false
+	}
+
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+
+	f := &flight{done: make(chan struct{})}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+
+		close(f.done)
+	}()
+
+	f.result = fn()
+
+	return f.result, true
+}
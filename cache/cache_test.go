@@ -0,0 +1,289 @@
+package cache
+
+import (
+	"context"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	var calls atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Vary", "Accept-Language")
+		_, _ = io.WriteString(w, "hello "+r.Header.Get("Accept-Language"))
+	})
+
+	responses := New(NewMemoryStore())
+	cached := responses.Middleware()(handler)
+
+	get := func(language string) gumtest.Response {
+		req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+		req.Header.Set("Accept-Language", language)
+		return gumtest.Serve(cached, req)
+	}
+
+	resp := get("en")
+	AssertEqual(t, resp.Text(), "hello en")
+	AssertEqual(t, resp.Header.Get("X-Cache"), "MISS")
+
+	resp = get("en")
+	AssertEqual(t, resp.Text(), "hello en")
+	AssertEqual(t, resp.Header.Get("X-Cache"), "HIT")
+	AssertEqual(t, calls.Load(), 1)
+
+	// a different variant
+	resp = get("de")
+	AssertEqual(t, resp.Text(), "hello de")
+	AssertEqual(t, resp.Header.Get("X-Cache"), "MISS")
+	AssertEqual(t, calls.Load(), 2)
+
+	AssertEqual(t, responses.Invalidate(context.Background(), "/greeting"), nil)
+
+	resp = get("en")
+	AssertEqual(t, resp.Header.Get("X-Cache"), "MISS")
+	AssertEqual(t, calls.Load(), 3)
+
+	// unsafe methods invalidate the url
+	gumtest.Serve(cached, httptest.NewRequest(http.MethodPost, "/greeting", nil))
+	resp = get("en")
+	AssertEqual(t, resp.Header.Get("X-Cache"), "MISS")
+}
+
+func TestCacheNotCacheable(t *testing.T) {
+	var calls atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "private")
+		_, _ = io.WriteString(w, "secret")
+	})
+
+	cached := New(NewMemoryStore()).Middleware()(handler)
+
+	gumtest.Serve(cached, httptest.NewRequest(http.MethodGet, "/", nil))
+	gumtest.Serve(cached, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, calls.Load(), 2)
+}
+
+func TestCacheCoalescing(t *testing.T) {
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = io.WriteString(w, "slow")
+	})
+
+	// disable caching to check that coalescing works without the store
+	cached := New(NewMemoryStore(), TTL(0)).Middleware()(handler)
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+
+	for idx := range results {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			results[idx] = gumtest.Serve(cached, httptest.NewRequest(http.MethodGet, "/", nil)).Text()
+		}()
+	}
+
+	// give all requests time to join the flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	AssertEqual(t, calls.Load(), 1)
+	AssertEqual(t, results, []string{"slow", "slow", "slow", "slow", "slow"})
+}
+
+func TestCacheCoalescingPrivate(t *testing.T) {
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-release
+		}
+
+		user := r.Header.Get("X-User")
+		w.Header().Set("Cache-Control", "private")
+		w.Header().Set("Set-Cookie", "session="+user)
+		_, _ = io.WriteString(w, user)
+	})
+
+	cached := New(NewMemoryStore()).Middleware()(handler)
+
+	var wg sync.WaitGroup
+	users := []string{"alice", "bob"}
+	results := make([]gumtest.Response, len(users))
+
+	for idx, user := range users {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// both requests select the same variant, only the user differs
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-User", user)
+			results[idx] = gumtest.Serve(cached, req)
+		}()
+
+		// let the first request lead the flight
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	close(release)
+	wg.Wait()
+
+	AssertEqual(t, calls.Load(), 2)
+
+	for idx, user := range users {
+		AssertEqual(t, results[idx].Text(), user)
+		AssertEqual(t, results[idx].Header.Get("Set-Cookie"), "session="+user)
+		AssertNotEqual(t, results[idx].Header.Get("X-Cache"), "HIT")
+	}
+}
+
+func TestCacheAuthorization(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/public" {
+			w.Header().Set("Cache-Control", "public")
+		}
+
+		_, _ = io.WriteString(w, "hello "+r.Header.Get("Authorization"))
+	})
+
+	cached := New(NewMemoryStore()).Middleware()(handler)
+
+	get := func(path, user string) gumtest.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", user)
+		return gumtest.Serve(cached, req)
+	}
+
+	AssertEqual(t, get("/me", "alice").Text(), "hello alice")
+	AssertEqual(t, get("/me", "bob").Text(), "hello bob")
+
+	// the response is not stored for anonymous requests either
+	resp := gumtest.Serve(cached, httptest.NewRequest(http.MethodGet, "/me", nil))
+	AssertEqual(t, resp.Header.Get("X-Cache"), "MISS")
+
+	// public responses are stored, but only served to anonymous requests
+	AssertEqual(t, get("/public", "alice").Text(), "hello alice")
+	AssertEqual(t, get("/public", "bob").Text(), "hello bob")
+
+	resp = gumtest.Serve(cached, httptest.NewRequest(http.MethodGet, "/public", nil))
+	AssertEqual(t, resp.Header.Get("X-Cache"), "HIT")
+	AssertEqual(t, resp.Text(), "hello bob")
+}
+
+func TestCacheInvalidateVariants(t *testing.T) {
+	var calls atomic.Int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, r.Header.Get("X-User"))
+	})
+
+	responses := New(NewMemoryStore(), KeyHeaders("X-User"))
+	cached := responses.Middleware()(handler)
+
+	get := func(user string) gumtest.Response {
+		req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+		req.Header.Set("X-User", user)
+		return gumtest.Serve(cached, req)
+	}
+
+	get("alice")
+	get("bob")
+	AssertEqual(t, get("alice").Header.Get("X-Cache"), "HIT")
+	AssertEqual(t, get("bob").Header.Get("X-Cache"), "HIT")
+
+	AssertEqual(t, responses.Invalidate(context.Background(), "/profile"), nil)
+
+	AssertEqual(t, get("alice").Header.Get("X-Cache"), "MISS")
+	AssertEqual(t, get("bob").Header.Get("X-Cache"), "MISS")
+	AssertEqual(t, calls.Load(), 4)
+}
+
+func TestCacheCoalescingVariants(t *testing.T) {
+	release := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Vary", "Accept-Language")
+		_, _ = io.WriteString(w, r.Header.Get("Accept-Language"))
+	})
+
+	cached := New(NewMemoryStore()).Middleware()(handler)
+
+	var wg sync.WaitGroup
+	languages := []string{"en", "de", "en", "fr"}
+	results := make([]string, len(languages))
+
+	for idx, language := range languages {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", language)
+			results[idx] = gumtest.Serve(cached, req).Text()
+		}()
+	}
+
+	// give all requests time to join the flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	AssertEqual(t, results, languages)
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+
+	store := NewMemoryStore(MaxEntries(2))
+	store.now = func() time.Time { return now }
+
+	AssertEqual(t, store.Set(ctx, "a", Entry{}, time.Minute), nil)
+	AssertEqual(t, store.Set(ctx, "b", Entry{}, time.Hour), nil)
+	AssertEqual(t, store.Set(ctx, "c", Entry{}, time.Hour), nil)
+	AssertEqual(t, len(store.entries), 2)
+
+	// the entry expiring first was evicted
+	entry, _ := store.Get(ctx, "a")
+	AssertTrue(t, entry == nil)
+
+	// expired entries are swept eventually
+	store = NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	AssertEqual(t, store.Set(ctx, "expired", Entry{}, time.Second), nil)
+	now = now.Add(time.Minute)
+
+	for range sweepInterval {
+		AssertEqual(t, store.Set(ctx, "other", Entry{}, time.Second), nil)
+	}
+
+	_, ok := store.entries["expired"]
+	AssertEqual(t, ok, false)
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the number of calls to Set after which expired entries are removed.
+const sweepInterval = 1024
+
+// evictionSamples is the number of entries inspected to find an entry to evict.
+const evictionSamples = 8
+
+// MemoryOption configures a MemoryStore.
+type MemoryOption func(m *MemoryStore)
+
+// MaxEntries limits the number of entries kept by the MemoryStore. If the limit is
+// reached, an entry that expires soon is evicted. Defaults to 10000.
+func MaxEntries(n int) MemoryOption {
+	return func(m *MemoryStore) {
+		m.maxEntries = n
+	}
+}
+
+// MemoryStore is a Store that keeps all entries in memory. Expired entries
+// are removed periodically, the number of entries is limited by MaxEntries.
+type MemoryStore struct {
+	now        func() time.Time
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	calls   int
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+type memoryEntry struct {
+	entry     Entry
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a new, empty MemoryStore.
+func NewMemoryStore(options ...MemoryOption) *MemoryStore {
+	m := &MemoryStore{
+		entries:    map[string]memoryEntry{},
+		now:        time.Now,
+		maxEntries: 10000,
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+func (m *MemoryStore) Get(ctx context.Context, key string) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if !m.now().Before(existing.expiresAt) {
+		delete(m.entries, key)
+		return nil, nil
+	}
+
+	entry := existing.entry
+	return &entry, nil
+}
+
+func (m *MemoryStore) Set(ctx context.Context, key string, entry Entry, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if _, ok := m.entries[key]; !ok && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		m.evict()
+	}
+
+	m.entries[key] = memoryEntry{
+		entry:     entry,
+		expiresAt: now.Add(ttl),
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// sweep removes expired entries.
+func (m *MemoryStore) sweep(now time.Time) {
+	m.calls += 1
+	if m.calls%sweepInterval != 0 {
+		return
+	}
+
+	for key, existing := range m.entries {
+		if !now.Before(existing.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// evict removes the entry that expires first out of a few entries picked by
+// the randomized map iteration. This is cheaper than keeping the entries sorted.
+func (m *MemoryStore) evict() {
+	var victim string
+	var victimExpiresAt time.Time
+
+	var samples int
+	for key, existing := range m.entries {
+		if samples == 0 || existing.expiresAt.Before(victimExpiresAt) {
+			victim, victimExpiresAt = key, existing.expiresAt
+		}
+
+		samples++
+		if samples == evictionSamples {
+			break
+		}
+	}
+
+	delete(m.entries, victim)
+}
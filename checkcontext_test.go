@@ -0,0 +1,123 @@
+package gum
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cancelingValue cancels the requests context during extraction,
+// like a client disconnecting during a slow extraction.
+type cancelingValue struct{}
+
+type cancelKey struct{}
+
+func (cancelingValue) FromRequest(r *http.Request) (cancelingValue, error) {
+	r.Context().Value(cancelKey{}).(context.CancelFunc)()
+	return cancelingValue{}, nil
+}
+
+func TestCheckContext(t *testing.T) {
+	request := func() *http.Request {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx = context.WithValue(ctx, cancelKey{}, cancel)
+		return httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	}
+
+	var called bool
+	fn := func(value cancelingValue) http.Handler {
+		called = true
+		return response.Text("hello")
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		called = false
+
+		rec := gumtest.Serve(Handler(fn), request())
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+		AssertTrue(t, called)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		called = false
+
+		rec := gumtest.Serve(Handler(fn, CheckContext()), request())
+		AssertEqual(t, rec.StatusCode, StatusClientClosedRequest)
+		AssertTrue(t, !called)
+	})
+
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		handler := Handler(func(ctx context.Context) http.Handler {
+			<-ctx.Done()
+			return response.Text("too late")
+		}, CheckContextAfterHandle())
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusRequestTimeout)
+	})
+
+	t.Run("AfterHandleNotDone", func(t *testing.T) {
+		handler := Handler(func() http.Handler {
+			return response.Text("hello")
+		}, CheckContextAfterHandle())
+
+		rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+		AssertEqual(t, rec.Text(), "hello")
+	})
+
+	t.Run("AfterHandleDiscarded", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+		// the client disconnects while the handler function runs
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+		result := &closingHandler{Handler: response.Text("hello")}
+		handler := Handler(func() http.Handler {
+			cancel()
+			return result
+		}, CheckContextAfterHandle(), WithLogger(logger))
+
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, StatusClientClosedRequest)
+		AssertTrue(t, result.closed)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		req = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+		handler = Handler(func() error {
+			cancel()
+			return errors.New("database down")
+		}, CheckContextAfterHandle(), WithLogger(logger))
+
+		rec = gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, StatusClientClosedRequest)
+		AssertTrue(t, strings.Contains(buf.String(), "database down"))
+	})
+}
+
+type closingHandler struct {
+	http.Handler
+	closed bool
+}
+
+func (c *closingHandler) Close() error {
+	c.closed = true
+	return nil
+}
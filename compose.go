@@ -0,0 +1,66 @@
+package gum
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Both extracts an A and a B from the request. Extraction fails if either fails.
+// Use it to group values that always belong together, e.g. in a FromRequest
+// implementation or as a type passed to MapExtractor.
+type Both[A, B any] struct {
+	First  A
+	Second B
+}
+
+var _ = AssertFromRequest[Both[any, any]]()
+
+// Get returns both extracted values.
+func (b Both[A, B]) Get() (A, B) {
+	return b.First, b.Second
+}
+
+func (Both[A, B]) consumesBody() bool { return consumesBodyOf[A]() || consumesBodyOf[B]() }
+
+func (Both[A, B]) FromRequest(r *http.Request) (Both[A, B], error) {
+	first, err := Extract[A](r)
+	if err != nil {
+		return Both[A, B]{}, err
+	}
+
+	second, err := Extract[B](r)
+	if err != nil {
+		return Both[A, B]{}, err
+	}
+
+	return Both[A, B]{First: first, Second: second}, nil
+}
+
+// MapExtractor derives an Extractor of B from an Extractor of A. If base is nil,
+// the A is extracted using Extract. Use it to register new request types
+// built from existing ones:
+//
+//	gum.Register(gum.MapExtractor(nil, func(session Session) (User, error) {
+//		return loadUser(session.UserID)
+//	}))
+func MapExtractor[A, B any](base Extractor[A], fn func(A) (B, error)) Extractor[B] {
+	if base == nil {
+		base = Extract[A]
+	}
+
+	return func(r *http.Request) (B, error) {
+		a, err := base(r)
+		if err != nil {
+			var zero B
+			return zero, err
+		}
+
+		b, err := fn(a)
+		if err != nil {
+			var zero B
+			return zero, fmt.Errorf("map %T: %w", a, err)
+		}
+
+		return b, nil
+	}
+}
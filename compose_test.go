@@ -0,0 +1,42 @@
+package gum
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBoth(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	both, err := Extract[Both[Method, Host]](req)
+	AssertEqual(t, err, nil)
+
+	method, host := both.Get()
+	AssertEqual(t, method, "POST")
+	AssertEqual(t, host, "example.com")
+
+	_, err = Extract[Both[Method, IfModifiedSince]](req)
+	AssertTrue(t, err != nil)
+}
+
+func TestMapExtractor(t *testing.T) {
+	type Verb string
+
+	extractor := MapExtractor(nil, func(method Method) (Verb, error) {
+		if method == http.MethodDelete {
+			return "", errors.New("not allowed")
+		}
+
+		return Verb("verb " + method), nil
+	})
+
+	verb, err := extractor(httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, err, nil)
+	AssertEqual(t, verb, "verb GET")
+
+	_, err = extractor(httptest.NewRequest(http.MethodDelete, "/", nil))
+	AssertTrue(t, err != nil)
+}
@@ -0,0 +1,307 @@
+package gum
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"github.com/go-gum/gum/internal"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CompressOption configures the Compress middleware.
+type CompressOption func(config *compressConfig)
+
+// Compressor creates a writer that compresses everything written to it into w.
+// Close is called after the response was written.
+type Compressor func(w io.Writer) (io.WriteCloser, error)
+
+type compressEncoding struct {
+	name       string
+	compressor Compressor
+}
+
+type compressConfig struct {
+	minSize      int
+	level        int
+	contentTypes []string
+	encodings    []compressEncoding
+}
+
+// Compress returns a Middleware that compresses response bodies using an encoding
+// the client accepts, as negotiated using the Accept-Encoding request header.
+// gzip and deflate are supported by default, other encodings like brotli can be
+// added using CompressEncoding.
+//
+// Only responses with a compressible content type and a body of at least
+// the minimum size are compressed. Responses that already have a Content-Encoding,
+// partial responses and responses to HEAD requests are passed through unchanged.
+// A strong ETag of a compressed response is turned into a weak one.
+func Compress(options ...CompressOption) Middleware {
+	config := compressConfig{
+		minSize: 1024,
+		level:   flate.DefaultCompression,
+		contentTypes: []string{
+			"text/*",
+			"application/json",
+			"application/x-ndjson",
+			"application/xml",
+			"application/javascript",
+			"image/svg+xml",
+		},
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	// the default encodings have the lowest priority
+	config.encodings = append(config.encodings,
+		compressEncoding{name: "gzip", compressor: func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, config.level)
+		}},
+		compressEncoding{name: "deflate", compressor: func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, config.level)
+		}},
+	)
+
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the response depends on the Accept-Encoding header,
+			// even if we do not compress this specific one.
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding, ok := config.negotiate(r.Header.Get("Accept-Encoding"))
+			if !ok || r.Method == http.MethodHead {
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, config: &config, encoding: encoding}
+			defer cw.finish()
+
+			delegate.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// CompressMinSize sets the minimum size of a response body in bytes to be compressed.
+// Smaller bodies are written as is. Defaults to 1024.
+func CompressMinSize(size int) CompressOption {
+	return func(config *compressConfig) {
+		config.minSize = size
+	}
+}
+
+// CompressLevel sets the compression level of the default gzip and deflate encodings,
+// see compress/flate. Defaults to flate.DefaultCompression.
+func CompressLevel(level int) CompressOption {
+	return func(config *compressConfig) {
+		config.level = level
+	}
+}
+
+// CompressContentTypes replaces the list of content types that are compressed.
+// A type can use a wildcard subtype like "text/*".
+func CompressContentTypes(contentTypes ...string) CompressOption {
+	return func(config *compressConfig) {
+		config.contentTypes = contentTypes
+	}
+}
+
+// CompressEncoding adds support for a content encoding, e.g. brotli using
+// the github.com/andybalholm/brotli package:
+//
+//	gum.CompressEncoding("br", func(w io.Writer) (io.WriteCloser, error) {
+//		return brotli.NewWriter(w), nil
+//	})
+//
+// Encodings added using this option are preferred over gzip and deflate if the
+// client accepts them with the same quality.
+func CompressEncoding(name string, compressor Compressor) CompressOption {
+	return func(config *compressConfig) {
+		config.encodings = append(config.encodings, compressEncoding{name: name, compressor: compressor})
+	}
+}
+
+// negotiate selects the encoding with the highest quality in the Accept-Encoding header.
+func (c *compressConfig) negotiate(acceptEncoding string) (compressEncoding, bool) {
+	names := make([]string, len(c.encodings))
+	for idx, encoding := range c.encodings {
+		names[idx] = encoding.name
+	}
+
+	accepted := internal.AcceptedEncodings(acceptEncoding, names)
+	if len(accepted) == 0 {
+		return compressEncoding{}, false
+	}
+
+	idx := slices.Index(names, accepted[0])
+	return c.encodings[idx], true
+}
+
+// compressible checks if the content type is in the list of compressible types.
+func (c *compressConfig) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(c.contentTypes, func(allowed string) bool {
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+			return strings.HasPrefix(mediaType, prefix+"/")
+		}
+
+		return mediaType == allowed
+	})
+}
+
+// compressWriter buffers the first bytes of a response until it can decide
+// if the response should be compressed.
+type compressWriter struct {
+	http.ResponseWriter
+
+	config   *compressConfig
+	encoding compressEncoding
+
+	statusCode int
+	buf        []byte
+
+	decided    bool
+	compressor io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(statusCode int) {
+	if w.decided {
+		// let the underlying writer complain about superfluous calls
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	if w.statusCode != 0 {
+		// superfluous call, the first status code wins
+		return
+	}
+
+	if statusCode < 200 {
+		// informational headers are sent immediately
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	w.statusCode = statusCode
+
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified {
+		w.decide()
+	}
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if w.statusCode == 0 {
+			w.statusCode = http.StatusOK
+		}
+
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.config.minSize {
+			return len(p), nil
+		}
+
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+
+		return len(p), nil
+	}
+
+	if w.compressor != nil {
+		return w.compressor.Write(p)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush writes everything buffered so far to the client.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide()
+	}
+
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide writes the headers and the buffered body, either compressed or as is.
+func (w *compressWriter) decide() error {
+	w.decided = true
+
+	if w.statusCode == 0 {
+		// nothing was written at all
+		return nil
+	}
+
+	header := w.Header()
+
+	if len(w.buf) > 0 && header.Get("Content-Type") == "" {
+		// do the same as net/http would do on the first write
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	compress := len(w.buf) >= w.config.minSize &&
+		w.statusCode != http.StatusPartialContent &&
+		header.Get("Content-Encoding") == "" &&
+		w.config.compressible(header.Get("Content-Type"))
+
+	if compress {
+		compressor, err := w.encoding.compressor(w.ResponseWriter)
+		if err == nil {
+			header.Set("Content-Encoding", w.encoding.name)
+			header.Del("Content-Length")
+			w.compressor = compressor
+
+			// the compressed representation is not byte-for-byte identical
+			// to the uncompressed one, a strong ETag would claim so.
+			if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+				header.Set("ETag", "W/"+etag)
+			}
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
+	buf := w.buf
+	w.buf = nil
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	if w.compressor != nil {
+		_, err := w.compressor.Write(buf)
+		return err
+	}
+
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish writes any remaining buffered data and closes the compressor.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.decide()
+	}
+
+	if w.compressor != nil {
+		_ = w.compressor.Close()
+	}
+}
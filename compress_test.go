@@ -0,0 +1,136 @@
+package gum
+
+import (
+	"compress/gzip"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	body := strings.Repeat("hello world ", 200)
+
+	handler := Compress()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, body)
+	}))
+
+	t.Run("Gzip", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "deflate;q=0.5, gzip")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		AssertEqual(t, rec.Header().Get("Content-Encoding"), "gzip")
+		AssertEqual(t, rec.Header().Get("Vary"), "Accept-Encoding")
+
+		reader, err := gzip.NewReader(rec.Body)
+		AssertEqual(t, err, nil)
+
+		decoded, err := io.ReadAll(reader)
+		AssertEqual(t, err, nil)
+		AssertEqual(t, string(decoded), body)
+	})
+
+	t.Run("NotAccepted", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip;q=0, identity")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		AssertEqual(t, rec.Header().Get("Content-Encoding"), "")
+		AssertEqual(t, rec.Header().Get("Vary"), "Accept-Encoding")
+		AssertEqual(t, rec.Body.String(), body)
+	})
+}
+
+func TestCompressWeakensETag(t *testing.T) {
+	handler := Compress(CompressMinSize(16))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(w, strings.Repeat("x", 64))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	AssertEqual(t, rec.Header().Get("Content-Encoding"), "gzip")
+	AssertEqual(t, rec.Header().Get("ETag"), `W/"v1"`)
+
+	// the uncompressed representation keeps its strong ETag
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Header().Get("Content-Encoding"), "")
+	AssertEqual(t, rec.Header().Get("ETag"), `"v1"`)
+}
+
+func TestCompressSkipped(t *testing.T) {
+	serve := func(handler http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		rec := httptest.NewRecorder()
+		Compress(CompressMinSize(16))(handler).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("Small", func(t *testing.T) {
+		rec := serve(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, "tiny")
+		})
+
+		AssertEqual(t, rec.Header().Get("Content-Encoding"), "")
+		AssertEqual(t, rec.Body.String(), "tiny")
+	})
+
+	t.Run("ContentType", func(t *testing.T) {
+		rec := serve(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = io.WriteString(w, strings.Repeat("x", 64))
+		})
+
+		AssertEqual(t, rec.Header().Get("Content-Encoding"), "")
+		AssertEqual(t, rec.Body.Len(), 64)
+	})
+
+	t.Run("StatusCode", func(t *testing.T) {
+		rec := serve(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		AssertEqual(t, rec.Code, http.StatusNoContent)
+		AssertEqual(t, rec.Header().Get("Content-Encoding"), "")
+	})
+}
+
+func TestCompressEncoding(t *testing.T) {
+	var used bool
+
+	custom := CompressEncoding("x-custom", func(w io.Writer) (io.WriteCloser, error) {
+		used = true
+		return gzip.NewWriter(w), nil
+	})
+
+	handler := Compress(custom, CompressMinSize(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, x-custom")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	AssertTrue(t, used)
+	AssertEqual(t, rec.Code, http.StatusCreated)
+	AssertEqual(t, rec.Header().Get("Content-Encoding"), "x-custom")
+}
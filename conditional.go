@@ -0,0 +1,242 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/internal"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag is an entity tag as defined in RFC 9110, section 8.8.3.
+// The wildcard * of If-Match and If-None-Match is reported by IsWildcard. A quoted "*"
+// is a regular entity tag and not the wildcard.
+type ETag struct {
+	// Tag is the opaque tag without quotes
+	Tag  string
+	Weak bool
+
+	wildcard bool
+}
+
+// StrongETag creates a strong ETag from the given tag
+func StrongETag(tag string) ETag {
+	return ETag{Tag: tag}
+}
+
+// WeakETag creates a weak ETag from the given tag
+func WeakETag(tag string) ETag {
+	return ETag{Tag: tag, Weak: true}
+}
+
+// WildcardETag creates the * wildcard of If-Match and If-None-Match
+func WildcardETag() ETag {
+	return ETag{wildcard: true}
+}
+
+// ParseETag parses a single entity tag like "xyz" or W/"xyz".
+func ParseETag(value string) (ETag, error) {
+	tags, err := parseETags(value)
+	if err != nil {
+		return ETag{}, err
+	}
+
+	if len(tags) != 1 {
+		return ETag{}, fmt.Errorf("expected a single entity tag in %q", value)
+	}
+
+	return tags[0], nil
+}
+
+// String formats the ETag as used in a header value
+func (e ETag) String() string {
+	if e.IsWildcard() {
+		return "*"
+	}
+
+	if e.Weak {
+		return `W/"` + e.Tag + `"`
+	}
+
+	return `"` + e.Tag + `"`
+}
+
+// IsWildcard checks if this is the unquoted * wildcard
+func (e ETag) IsWildcard() bool {
+	return e.wildcard
+}
+
+// StrongMatch compares two tags using the strong comparison function:
+// both must be strong and have the same tag.
+func (e ETag) StrongMatch(other ETag) bool {
+	return !e.Weak && !other.Weak && e.Tag == other.Tag
+}
+
+// WeakMatch compares two tags using the weak comparison function:
+// only the tags must be equal.
+func (e ETag) WeakMatch(other ETag) bool {
+	return e.Tag == other.Tag
+}
+
+// IfNoneMatch holds the entity tags of the If-None-Match header.
+// It is empty if the request does not have an If-None-Match header.
+type IfNoneMatch []ETag
+
+// Matches checks if the current ETag of the resource matches any of the tags
+// using the weak comparison function, as required for If-None-Match. A GET handler
+// should respond with 304 Not Modified in that case.
+func (m IfNoneMatch) Matches(current ETag) bool {
+	for _, tag := range m {
+		if tag.IsWildcard() || tag.WeakMatch(current) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IfMatch holds the entity tags of the If-Match header.
+// It is empty if the request does not have an If-Match header.
+type IfMatch []ETag
+
+// Matches checks if the current ETag of the resource matches any of the tags
+// using the strong comparison function, as required for If-Match. A handler should respond
+// with 412 Precondition Failed if the header is present but does not match.
+func (m IfMatch) Matches(current ETag) bool {
+	for _, tag := range m {
+		if tag.IsWildcard() || tag.StrongMatch(current) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrPreconditionFailed is wrapped by the errors returned by IfMatch.Check and IfMatch.Require.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// Check implements optimistic concurrency control for updates: it returns an HTTPError
+// with status 412 Precondition Failed, if the If-Match header is present but does
+// not match the current ETag of the resource. The error response carries the current
+// ETag, so the client can refetch the resource. Requests without an If-Match header pass.
+//
+//	func updateUser(ifMatch gum.IfMatch, body gum.JSON[User]) (response.Lazy, error) {
+//		user := loadUser()
+//		if err := ifMatch.Check(gum.VersionETag(user.Version)); err != nil {
+//			return response.Lazy{}, err
+//		}
+//		...
+//	}
+func (m IfMatch) Check(current ETag) error {
+	if len(m) == 0 || m.Matches(current) {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: If-Match does not match %s", ErrPreconditionFailed, current)
+	return NewHTTPError(http.StatusPreconditionFailed, err).WithHeader("ETag", current.String())
+}
+
+// Require works like Check, but also rejects requests without an If-Match header
+// with 428 Precondition Required, to prevent lost updates by clients that do not
+// send the header.
+func (m IfMatch) Require(current ETag) error {
+	if len(m) == 0 {
+		err := fmt.Errorf("%w: no If-Match header in request", ErrPreconditionFailed)
+		return NewHTTPError(http.StatusPreconditionRequired, err)
+	}
+
+	return m.Check(current)
+}
+
+// VersionETag creates a strong ETag from the version of an entity, e.g. a version
+// column incremented on every update. Use it both for the ETag header of responses
+// and to check the If-Match header of updates.
+func VersionETag(version any) ETag {
+	return StrongETag(fmt.Sprint(version))
+}
+
+// IfModifiedSince holds the date of the If-Modified-Since header.
+// Extraction fails if the header is missing or not a valid http date, use an Option
+// for requests where the header is optional.
+type IfModifiedSince struct {
+	time.Time
+}
+
+// Modified checks if the resource was modified after the date of the header.
+// The comparison uses a precision of one second, as http dates do.
+func (m IfModifiedSince) Modified(lastModified time.Time) bool {
+	return lastModified.Truncate(time.Second).After(m.Time)
+}
+
+// IfUnmodifiedSince holds the date of the If-Unmodified-Since header.
+// Extraction fails if the header is missing or not a valid http date.
+type IfUnmodifiedSince struct {
+	time.Time
+}
+
+// Modified checks if the resource was modified after the date of the header.
+// A handler should respond with 412 Precondition Failed in that case.
+func (m IfUnmodifiedSince) Modified(lastModified time.Time) bool {
+	return lastModified.Truncate(time.Second).After(m.Time)
+}
+
+func init() {
+	Register(func(r *http.Request) (IfNoneMatch, error) {
+		tags, err := parseETags(strings.Join(r.Header.Values("If-None-Match"), ","))
+		if err != nil {
+			return nil, fmt.Errorf("parse If-None-Match: %w", err)
+		}
+
+		return tags, nil
+	})
+
+	Register(func(r *http.Request) (IfMatch, error) {
+		tags, err := parseETags(strings.Join(r.Header.Values("If-Match"), ","))
+		if err != nil {
+			return nil, fmt.Errorf("parse If-Match: %w", err)
+		}
+
+		return tags, nil
+	})
+
+	Register(func(r *http.Request) (IfModifiedSince, error) {
+		date, err := parseHTTPDate(r, "If-Modified-Since")
+		return IfModifiedSince{Time: date}, err
+	})
+
+	Register(func(r *http.Request) (IfUnmodifiedSince, error) {
+		date, err := parseHTTPDate(r, "If-Unmodified-Since")
+		return IfUnmodifiedSince{Time: date}, err
+	})
+}
+
+func parseHTTPDate(r *http.Request, header string) (time.Time, error) {
+	value := r.Header.Get(header)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("no %s header in request", header)
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse %s: %w", header, err)
+	}
+
+	return date, nil
+}
+
+// parseETags parses a comma separated list of entity tags. Commas within
+// the quoted tags are allowed.
+func parseETags(value string) ([]ETag, error) {
+	parsed, err := internal.ParseETags(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []ETag
+	for _, tag := range parsed {
+		tags = append(tags, ETag{Tag: tag.Tag, Weak: tag.Weak, wildcard: tag.Wildcard})
+	}
+
+	return tags, nil
+}
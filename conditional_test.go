@@ -0,0 +1,95 @@
+package gum
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseETags(t *testing.T) {
+	tags, err := parseETags(`"foo", W/"bar", "a,b"`)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, tags, []ETag{StrongETag("foo"), WeakETag("bar"), StrongETag("a,b")})
+
+	tags, err = parseETags(`*`)
+	AssertEqual(t, err, nil)
+	AssertTrue(t, tags[0].IsWildcard())
+	AssertEqual(t, tags[0].String(), "*")
+
+	// a quoted "*" is a regular entity tag
+	tags, err = parseETags(`"*"`)
+	AssertEqual(t, err, nil)
+	AssertTrue(t, !tags[0].IsWildcard())
+	AssertEqual(t, tags[0].String(), `"*"`)
+	AssertTrue(t, !IfMatch(tags).Matches(StrongETag("v1")))
+
+	_, err = parseETags(`foo`)
+	AssertTrue(t, err != nil)
+
+	_, err = parseETags(`"foo`)
+	AssertTrue(t, err != nil)
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("If-None-Match", `W/"v1", "v2"`)
+
+	var extractedValue IfNoneMatch
+	Handler(func(v IfNoneMatch) { extractedValue = v }).ServeHTTP(nil, req)
+
+	AssertTrue(t, extractedValue.Matches(StrongETag("v1")))
+	AssertTrue(t, extractedValue.Matches(WeakETag("v2")))
+	AssertTrue(t, !extractedValue.Matches(StrongETag("v3")))
+}
+
+func TestIfMatch(t *testing.T) {
+	req := httptest.NewRequest("PUT", "/", nil)
+	req.Header.Set("If-Match", `W/"v1", "v2"`)
+
+	var extractedValue IfMatch
+	Handler(func(v IfMatch) { extractedValue = v }).ServeHTTP(nil, req)
+
+	AssertTrue(t, !extractedValue.Matches(StrongETag("v1")))
+	AssertTrue(t, extractedValue.Matches(StrongETag("v2")))
+}
+
+func TestIfModifiedSince(t *testing.T) {
+	lastModified := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+
+	var extractedValue Option[IfModifiedSince]
+	Handler(func(v Option[IfModifiedSince]) { extractedValue = v }).ServeHTTP(nil, req)
+
+	AssertTrue(t, extractedValue.IsSet)
+	AssertTrue(t, !extractedValue.Value.Modified(lastModified.Add(500*time.Millisecond)))
+	AssertTrue(t, extractedValue.Value.Modified(lastModified.Add(time.Second)))
+}
+
+func TestIfMatchCheck(t *testing.T) {
+	current := VersionETag(3)
+	AssertEqual(t, current.String(), `"3"`)
+
+	AssertEqual(t, IfMatch(nil).Check(current), nil)
+	AssertEqual(t, IfMatch{StrongETag("3")}.Check(current), nil)
+	AssertEqual(t, IfMatch{WildcardETag()}.Check(current), nil)
+	AssertTrue(t, IfMatch{StrongETag("*")}.Check(current) != nil)
+
+	var httpErr *HTTPError
+
+	err := IfMatch{StrongETag("2")}.Check(current)
+	AssertTrue(t, errors.Is(err, ErrPreconditionFailed))
+	AssertTrue(t, errors.As(err, &httpErr))
+	AssertEqual(t, httpErr.StatusCode, http.StatusPreconditionFailed)
+	AssertEqual(t, httpErr.Header.Get("ETag"), `"3"`)
+
+	err = IfMatch(nil).Require(current)
+	AssertTrue(t, errors.As(err, &httpErr))
+	AssertEqual(t, httpErr.StatusCode, http.StatusPreconditionRequired)
+
+	AssertEqual(t, IfMatch{StrongETag("3")}.Require(current), nil)
+}
@@ -0,0 +1,83 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrExtractorCycle is returned by Extract if extracting a value requires extracting
+// the same value again, e.g. if the FromRequest method of A extracts a B, and the
+// FromRequest method of B extracts an A.
+var ErrExtractorCycle = errors.New("extractor cycle")
+
+type extractionStackKey struct{}
+
+// extractionStack records the types that are currently being extracted by nested calls
+// to Extract within one request. It is its own context.Context, so that installing it
+// into the requests context only needs a single allocation.
+type extractionStack struct {
+	context.Context
+
+	mu    sync.Mutex
+	types []reflect.Type
+}
+
+// withExtractionStack returns a context holding a new, empty extractionStack.
+func withExtractionStack(ctx context.Context) context.Context {
+	return &extractionStack{Context: ctx}
+}
+
+func (s *extractionStack) Value(key any) any {
+	if key == (extractionStackKey{}) {
+		return s
+	}
+
+	return s.Context.Value(key)
+}
+
+// extractionStackOf returns the extractionStack of the request, or nil,
+// if the request was not passed in by a Handler.
+func extractionStackOf(r *http.Request) *extractionStack {
+	stack, _ := r.Context().Value(extractionStackKey{}).(*extractionStack)
+	return stack
+}
+
+// push records that ty is being extracted. It fails with an error wrapping
+// ErrExtractorCycle, if ty is already being extracted.
+func (s *extractionStack) push(ty reflect.Type) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx, active := range s.types {
+		if active != ty {
+			continue
+		}
+
+		var path []string
+		for _, ty := range s.types[idx:] {
+			path = append(path, ty.String())
+		}
+
+		path = append(path, ty.String())
+
+		err := fmt.Errorf("%w: %s", ErrExtractorCycle, strings.Join(path, " -> "))
+
+		// a cycle is an error in the program, not in the request
+		return NewHTTPError(http.StatusInternalServerError, err)
+	}
+
+	s.types = append(s.types, ty)
+	return nil
+}
+
+func (s *extractionStack) pop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.types = s.types[:len(s.types)-1]
+}
@@ -0,0 +1,94 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type cycleA struct{}
+
+func (cycleA) FromRequest(r *http.Request) (cycleA, error) {
+	_, err := Extract[cycleB](r)
+	return cycleA{}, err
+}
+
+type cycleB struct{}
+
+func (cycleB) FromRequest(r *http.Request) (cycleB, error) {
+	_, err := Extract[cycleA](r)
+	return cycleB{}, err
+}
+
+type optionalSelf struct{}
+
+func (optionalSelf) FromRequest(r *http.Request) (optionalSelf, error) {
+	_, err := Extract[Option[optionalSelf]](r)
+	return optionalSelf{}, err
+}
+
+type nestedValue string
+
+func (nestedValue) FromRequest(r *http.Request) (nestedValue, error) {
+	// extracting the same type twice in sequence is not a cycle
+	first, err := Extract[Method](r)
+	if err != nil {
+		return "", err
+	}
+
+	second, err := Extract[Method](r)
+	return nestedValue(first + second), err
+}
+
+func TestExtractorCycle(t *testing.T) {
+	var extractErr error
+
+	handler := Handler(func(r *http.Request) {
+		_, extractErr = Extract[cycleA](r)
+	})
+
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+
+	AssertTrue(t, errors.Is(extractErr, ErrExtractorCycle))
+	AssertTrue(t, strings.Contains(extractErr.Error(), "gum.cycleA -> gum.cycleB -> gum.cycleA"))
+
+	// as a parameter, the cycle fails the request
+	for _, options := range [][]HandlerOption{nil, {ParallelExtraction()}} {
+		handler = Handler(func(a cycleA) {}, options...)
+
+		rec = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusInternalServerError)
+	}
+}
+
+func TestExtractorCycleOption(t *testing.T) {
+	var extracted Try[optionalSelf]
+	var extractErr error
+
+	handler := Handler(func(r *http.Request) {
+		extracted, extractErr = Extract[Try[optionalSelf]](r)
+	})
+
+	// the Option fails to extract its Try, as the Try is already being extracted
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertEqual(t, extractErr, nil)
+	AssertTrue(t, errors.Is(extracted.Error, ErrExtractorCycle))
+}
+
+func TestExtractorNoCycle(t *testing.T) {
+	var value nestedValue
+
+	handler := Handler(func(v1 nestedValue, v2 Option[nestedValue]) {
+		value = v1 + v2.Value
+	}, ParallelExtraction())
+
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertEqual(t, value, nestedValue("GETGETGETGET"))
+}
@@ -0,0 +1,189 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deadline holds the deadline of the requests context. Use it to budget calls
+// to downstream services. The deadline is narrowed by the RequestTimeout middleware.
+type Deadline struct {
+	// At is the time at which the request times out. Zero if the request has no deadline.
+	At time.Time
+}
+
+func init() {
+	Register(func(r *http.Request) (Deadline, error) {
+		at, _ := r.Context().Deadline()
+		return Deadline{At: at}, nil
+	})
+}
+
+// IsSet reports whether the request has a deadline.
+func (d Deadline) IsSet() bool {
+	return !d.At.IsZero()
+}
+
+// Remaining returns the time left until the deadline. The result is negative if the
+// deadline has passed. Returns false if the request has no deadline.
+func (d Deadline) Remaining() (time.Duration, bool) {
+	if !d.IsSet() {
+		return 0, false
+	}
+
+	return time.Until(d.At), true
+}
+
+// RequestTimeoutOption configures the RequestTimeout middleware.
+type RequestTimeoutOption func(config *requestTimeoutConfig)
+
+type requestTimeoutConfig struct {
+	header         string
+	defaultTimeout time.Duration
+	maxTimeout     time.Duration
+}
+
+// RequestTimeoutHeader sets the request header holding the timeout. Defaults to X-Request-Timeout.
+// If the header is Grpc-Timeout, its value is parsed using the grpc-timeout format.
+func RequestTimeoutHeader(name string) RequestTimeoutOption {
+	return func(config *requestTimeoutConfig) {
+		config.header = name
+	}
+}
+
+// RequestTimeoutDefault sets the timeout of requests without the timeout header.
+// Defaults to zero, which does not set a deadline for such requests.
+func RequestTimeoutDefault(timeout time.Duration) RequestTimeoutOption {
+	return func(config *requestTimeoutConfig) {
+		config.defaultTimeout = timeout
+	}
+}
+
+// RequestTimeoutMax limits the timeout a client can request.
+func RequestTimeoutMax(timeout time.Duration) RequestTimeoutOption {
+	return func(config *requestTimeoutConfig) {
+		config.maxTimeout = timeout
+	}
+}
+
+// RequestTimeout returns a Middleware that narrows the deadline of the requests context
+// to the timeout sent by the client. The timeout header accepts go durations like "1.5s"
+// or a plain number of seconds. The Grpc-Timeout header accepts values like "1500m",
+// where "m" means milliseconds, see RequestTimeoutHeader. A deadline that
+// already exists on the context is never extended. Requests with an invalid timeout
+// are rejected with 400 Bad Request. Handlers can access the deadline using the
+// Deadline extractor.
+func RequestTimeout(options ...RequestTimeoutOption) Middleware {
+	config := requestTimeoutConfig{header: "X-Request-Timeout"}
+	for _, option := range options {
+		option(&config)
+	}
+
+	parse := parseTimeout
+	if strings.EqualFold(config.header, "Grpc-Timeout") {
+		parse = parseGRPCTimeout
+	}
+
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := config.defaultTimeout
+
+			if value := r.Header.Get(config.header); value != "" {
+				parsed, err := parse(value)
+				if err != nil {
+					errorResponse(fmt.Errorf("invalid %s header: %w", config.header, err), http.StatusBadRequest).ServeHTTP(w, r)
+					return
+				}
+
+				timeout = parsed
+			}
+
+			if config.maxTimeout > 0 && (timeout <= 0 || timeout > config.maxTimeout) {
+				timeout = config.maxTimeout
+			}
+
+			if timeout <= 0 {
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			// context.WithTimeout keeps an earlier deadline of the parent
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			delegate.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+var errTimeoutNotPositive = errors.New("timeout must be positive")
+
+// parseTimeout parses a go duration or a number of seconds.
+func parseTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if timeout, err := time.ParseDuration(value); err == nil {
+		if timeout <= 0 {
+			return 0, errTimeoutNotPositive
+		}
+
+		return timeout, nil
+	}
+
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither a duration nor a number of seconds", value)
+	}
+
+	// this also rejects NaN and infinite values
+	if !(seconds > 0 && seconds < float64(math.MaxInt64)/float64(time.Second)) {
+		return 0, fmt.Errorf("timeout of %q seconds is out of range", value)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// parseGRPCTimeout parses a value in the format of the grpc-timeout header,
+// a positive integer of at most 8 digits followed by a unit.
+func parseGRPCTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if len(value) < 2 || len(value) > 9 {
+		return 0, fmt.Errorf("invalid grpc timeout %q", value)
+	}
+
+	unit, ok := grpcTimeoutUnits[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid grpc timeout unit in %q", value)
+	}
+
+	amount, err := strconv.ParseUint(value[:len(value)-1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid grpc timeout %q", value)
+	}
+
+	if amount == 0 {
+		return 0, errTimeoutNotPositive
+	}
+
+	if amount > uint64(math.MaxInt64/unit) {
+		return 0, fmt.Errorf("grpc timeout %q is out of range", value)
+	}
+
+	return time.Duration(amount) * unit, nil
+}
@@ -0,0 +1,91 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseTimeout(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"1.5s":  1500 * time.Millisecond,
+		"5m":    5 * time.Minute,
+		"2":     2 * time.Second,
+		"0.25":  250 * time.Millisecond,
+		"100ms": 100 * time.Millisecond,
+	} {
+		parsed, err := parseTimeout(value)
+		AssertEqual(t, err, nil)
+		AssertEqual(t, parsed, expected)
+	}
+
+	for _, value := range []string{"", "0", "-1s", "abc", "3M", "Inf", "NaN", "-Inf", "1e300", "9999999999999999999s"} {
+		_, err := parseTimeout(value)
+		AssertTrue(t, err != nil)
+	}
+}
+
+func TestParseGRPCTimeout(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"1500m": 1500 * time.Millisecond,
+		"3M":    3 * time.Minute,
+		"2S":    2 * time.Second,
+		"10u":   10 * time.Microsecond,
+	} {
+		parsed, err := parseGRPCTimeout(value)
+		AssertEqual(t, err, nil)
+		AssertEqual(t, parsed, expected)
+	}
+
+	for _, value := range []string{"", "m", "0S", "1.5S", "-1S", "123456789S", "99999999H", "10x"} {
+		_, err := parseGRPCTimeout(value)
+		AssertTrue(t, err != nil)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	var remaining time.Duration
+	var isSet bool
+
+	handler := Handler(func(deadline Deadline) {
+		remaining, isSet = deadline.Remaining()
+	})
+
+	middleware := RequestTimeout(RequestTimeoutMax(10 * time.Second))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Timeout", "2s")
+	gumtest.Serve(middleware(handler), req)
+	AssertTrue(t, isSet)
+	AssertTrue(t, remaining > time.Second && remaining <= 2*time.Second)
+
+	// limited by the max timeout
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Timeout", "1h")
+	gumtest.Serve(middleware(handler), req)
+	AssertTrue(t, remaining <= 10*time.Second)
+
+	// no header, but max timeout
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	gumtest.Serve(middleware(handler), req)
+	AssertTrue(t, isSet)
+
+	// no deadline at all
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	gumtest.Serve(RequestTimeout()(handler), req)
+	AssertTrue(t, !isSet)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Timeout", "soon")
+	resp := gumtest.Serve(middleware(handler), req)
+	AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+
+	// the grpc-timeout format is used for the Grpc-Timeout header
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Grpc-Timeout", "1500m")
+	gumtest.Serve(RequestTimeout(RequestTimeoutHeader("Grpc-Timeout"))(handler), req)
+	AssertTrue(t, remaining > time.Second && remaining <= 1500*time.Millisecond)
+}
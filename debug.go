@@ -0,0 +1,195 @@
+package gum
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Route is a route registered using Routes.Handle.
+type Route struct {
+	// Pattern is the http.ServeMux pattern of the route
+	Pattern string
+
+	// Middleware contains the names of the middleware functions, outermost first
+	Middleware []string
+
+	// Handler is the handler as passed to Routes.Handle, without middleware
+	Handler http.Handler
+}
+
+// Routes registers handlers on a http.ServeMux and remembers them,
+// so they can be listed by DebugHandler.
+type Routes struct {
+	mux *http.ServeMux
+
+	mu     sync.Mutex
+	routes []Route
+}
+
+// NewRoutes creates a new Routes registering its handlers on mux.
+func NewRoutes(mux *http.ServeMux) *Routes {
+	return &Routes{mux: mux}
+}
+
+// Handle registers the handler for the given pattern on the underlying http.ServeMux.
+// The middleware is applied to the handler, the first middleware is the outermost one.
+func (r *Routes) Handle(pattern string, handler http.Handler, middleware ...Middleware) {
+	wrapped := handler
+	for _, m := range slices.Backward(middleware) {
+		wrapped = m(wrapped)
+	}
+
+	r.mux.Handle(pattern, wrapped)
+
+	var names []string
+	for _, m := range middleware {
+		names = append(names, funcName(m))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes = append(r.routes, Route{Pattern: pattern, Middleware: names, Handler: handler})
+}
+
+// Routes returns all routes registered so far.
+func (r *Routes) Routes() []Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.routes)
+}
+
+type debugInfo struct {
+	Routes     []debugRoute `json:"routes"`
+	Extractors []string     `json:"extractors"`
+}
+
+type debugRoute struct {
+	Pattern    string           `json:"pattern"`
+	Middleware []string         `json:"middleware,omitempty"`
+	Handler    string           `json:"handler"`
+	Parameters []debugParameter `json:"parameters,omitempty"`
+	Returns    []string         `json:"returns,omitempty"`
+}
+
+type debugParameter struct {
+	Type   string `json:"type"`
+	Origin string `json:"origin"`
+}
+
+// DebugHandler returns a http.Handler that lists the registered routes with their
+// middleware and handler parameters, as well as all extractors registered using Register.
+// The listing is rendered as html, or as json if the client accepts "application/json"
+// or the query parameter "format=json" is set.
+//
+// The listing reveals internals of the application and should
+// not be exposed publicly.
+func DebugHandler(routes *Routes) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := debugInfoOf(routes)
+
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(info)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = debugTemplate.Execute(w, info)
+	})
+}
+
+func debugInfoOf(routes *Routes) debugInfo {
+	info := debugInfo{Routes: []debugRoute{}, Extractors: []string{}}
+
+	for _, route := range routes.Routes() {
+		dr := debugRoute{
+			Pattern:    route.Pattern,
+			Middleware: route.Middleware,
+			Handler:    reflect.TypeOf(route.Handler).String(),
+		}
+
+		if desc, ok := Describe(route.Handler); ok {
+			dr.Handler = desc.Func.String()
+
+			for _, param := range desc.Parameters {
+				dr.Parameters = append(dr.Parameters, debugParameter{
+					Type:   param.Type.String(),
+					Origin: param.Origin.String(),
+				})
+			}
+
+			for idx := range desc.Func.NumOut() {
+				dr.Returns = append(dr.Returns, desc.Func.Out(idx).String())
+			}
+		}
+
+		info.Routes = append(info.Routes, dr)
+	}
+
+	extractors.Range(func(key, value any) bool {
+		info.Extractors = append(info.Extractors, key.(reflect.Type).String())
+		return true
+	})
+
+	slices.Sort(info.Extractors)
+
+	return info
+}
+
+// funcName returns the name of the function fn, e.g. "github.com/go-gum/gum.AccessLog.func1".
+func funcName(fn any) string {
+	rValue := reflect.ValueOf(fn)
+	if rValue.Kind() != reflect.Func || rValue.IsNil() {
+		return "<nil>"
+	}
+
+	if f := runtime.FuncForPC(rValue.Pointer()); f != nil {
+		return f.Name()
+	}
+
+	return "<unknown>"
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>gum routes</title>
+  <style>
+    body { font-family: sans-serif; }
+    table { border-collapse: collapse; }
+    td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+    code { white-space: nowrap; }
+  </style>
+</head>
+<body>
+  <h1>Routes</h1>
+  <table>
+    <tr><th>Pattern</th><th>Middleware</th><th>Handler</th><th>Parameters</th></tr>
+    {{- range .Routes }}
+    <tr>
+      <td><code>{{ .Pattern }}</code></td>
+      <td>{{ range .Middleware }}<code>{{ . }}</code><br>{{ end }}</td>
+      <td><code>{{ .Handler }}</code></td>
+      <td>{{ range .Parameters }}<code>{{ .Type }}</code> ({{ .Origin }})<br>{{ end }}</td>
+    </tr>
+    {{- end }}
+  </table>
+
+  <h1>Registered extractors</h1>
+  <ul>
+    {{- range .Extractors }}
+    <li><code>{{ . }}</code></li>
+    {{- end }}
+  </ul>
+</body>
+</html>
+`))
@@ -0,0 +1,52 @@
+package gum
+
+import (
+	"encoding/json"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	mux := http.NewServeMux()
+	routes := NewRoutes(mux)
+
+	routes.Handle("GET /host", Handler(func(h Host) response.Response { return response.Text(string(h)) }), AccessLog())
+
+	resp := gumtest.Serve(mux, httptest.NewRequest("GET", "http://example.com/host", nil))
+	AssertEqual(t, resp.Text(), "example.com")
+
+	AssertEqual(t, len(routes.Routes()), 1)
+	AssertEqual(t, routes.Routes()[0].Pattern, "GET /host")
+}
+
+func TestDebugHandler(t *testing.T) {
+	routes := NewRoutes(http.NewServeMux())
+	routes.Handle("GET /host", Handler(func(h Host) {}), AccessLog())
+	routes.Handle("GET /raw", http.NotFoundHandler())
+
+	t.Run("JSON", func(t *testing.T) {
+		resp := gumtest.Serve(DebugHandler(routes), httptest.NewRequest("GET", "/debug?format=json", nil))
+		AssertEqual(t, resp.Header.Get("Content-Type"), "application/json")
+
+		var info debugInfo
+		AssertEqual(t, json.Unmarshal(resp.Body, &info), nil)
+
+		AssertEqual(t, len(info.Routes), 2)
+		AssertEqual(t, info.Routes[0].Parameters, []debugParameter{{Type: "gum.Host", Origin: "registered"}})
+		AssertTrue(t, strings.HasPrefix(info.Routes[0].Middleware[0], "github.com/go-gum/gum.AccessLog"))
+		AssertEqual(t, info.Routes[1].Handler, "http.HandlerFunc")
+		AssertTrue(t, slices.Contains(info.Extractors, "gum.Host"))
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		resp := gumtest.Serve(DebugHandler(routes), httptest.NewRequest("GET", "/debug", nil))
+		AssertEqual(t, resp.Header.Get("Content-Type"), "text/html; charset=utf-8")
+		AssertTrue(t, strings.Contains(resp.Text(), "<code>GET /host</code>"))
+	})
+}
@@ -0,0 +1,103 @@
+package gum
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// ExtractorOrigin describes where the extractor of a handler parameter comes from.
+type ExtractorOrigin int
+
+const (
+	// OriginRegistered is an extractor registered using Register.
+	OriginRegistered ExtractorOrigin = iota
+
+	// OriginFromRequest is the FromRequest method of the parameter type.
+	OriginFromRequest
+
+	// OriginOverride is an extractor set for the Handler using WithOverrides.
+	OriginOverride
+)
+
+func (o ExtractorOrigin) String() string {
+	switch o {
+	case OriginRegistered:
+		return "registered"
+	case OriginFromRequest:
+		return "FromRequest"
+	case OriginOverride:
+		return "override"
+	default:
+		return "unknown"
+	}
+}
+
+// ParameterDescription describes a single parameter of a handler function.
+type ParameterDescription struct {
+	// Index is the position of the parameter in the functions signature
+	Index int
+
+	// Type is the type of the parameter
+	Type reflect.Type
+
+	// Origin tells where the extractor for this parameter comes from
+	Origin ExtractorOrigin
+}
+
+// HandlerDescription describes a http.Handler created by Handler.
+type HandlerDescription struct {
+	// Func is the type of the handler function
+	Func reflect.Type
+
+	// Parameters describes the parameters of the handler function
+	Parameters []ParameterDescription
+
+	// ReturnsHandler is true if the function returns a http.Handler or Responder to serve
+	ReturnsHandler bool
+
+	// ReturnsError is true if the function returns an error
+	ReturnsError bool
+
+	// ReturnsValue is true if the function returns a status code and a value to encode
+	ReturnsValue bool
+
+	// Parallel is true if the parameters are extracted concurrently
+	Parallel bool
+}
+
+// Describe returns the description of a http.Handler created by Handler, as computed when
+// the handler was created. Returns false if h was not created by Handler. Handlers wrapped
+// by a Middleware can not be described.
+func Describe(h http.Handler) (HandlerDescription, bool) {
+	described, ok := h.(describedHandler)
+	if !ok {
+		return HandlerDescription{}, false
+	}
+
+	desc := described.description
+	desc.Parameters = append([]ParameterDescription(nil), desc.Parameters...)
+	return desc, true
+}
+
+// describedHandler is the http.Handler returned by Handler.
+type describedHandler struct {
+	http.HandlerFunc
+	description HandlerDescription
+}
+
+func describeOutputs(desc *HandlerDescription, fnType reflect.Type) {
+	if fnType.NumOut() >= 2 && isStatusCode(fnType.Out(0)) {
+		desc.ReturnsValue = true
+		desc.ReturnsError = fnType.NumOut() == 3
+		return
+	}
+
+	for idx := range fnType.NumOut() {
+		switch out := fnType.Out(idx); {
+		case out.Implements(reflect.TypeFor[http.Handler]()), isResponder(out):
+			desc.ReturnsHandler = true
+		case out.Implements(reflect.TypeFor[error]()):
+			desc.ReturnsError = true
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	override := Override(func(r *http.Request) (Host, error) { return "example.com", nil })
+
+	fn := func(m Method, h Host, q QueryValues[struct{}]) (http.Handler, error) { return nil, nil }
+
+	desc, ok := Describe(Handler(fn, WithOverrides(override), ParallelExtraction()))
+	AssertTrue(t, ok)
+
+	AssertEqual(t, desc.Func, reflect.TypeOf(fn))
+	AssertTrue(t, desc.ReturnsHandler)
+	AssertTrue(t, desc.ReturnsError)
+	AssertTrue(t, desc.Parallel)
+
+	AssertEqual(t, desc.Parameters, []ParameterDescription{
+		{Index: 0, Type: reflect.TypeFor[Method](), Origin: OriginRegistered},
+		{Index: 1, Type: reflect.TypeFor[Host](), Origin: OriginOverride},
+		{Index: 2, Type: reflect.TypeFor[QueryValues[struct{}]](), Origin: OriginFromRequest},
+	})
+}
+
+func TestDescribeOther(t *testing.T) {
+	_, ok := Describe(http.NotFoundHandler())
+	AssertTrue(t, !ok)
+}
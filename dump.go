@@ -0,0 +1,316 @@
+package gum
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/go-gum/gum/internal"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DumpOption configures the Dump middleware.
+type DumpOption func(config *dumpConfig)
+
+type dumpConfig struct {
+	logger      *slog.Logger
+	level       slog.Level
+	writer      io.Writer
+	enabled     *atomic.Bool
+	maxBodySize int
+	redact      []string
+}
+
+// DumpLogger sets the logger to write dumps to. Defaults to the logger provided
+// using ProvideLogger, or slog.Default.
+func DumpLogger(logger *slog.Logger) DumpOption {
+	return func(config *dumpConfig) {
+		config.logger = logger
+	}
+}
+
+// DumpLevel sets the level of the log records. Defaults to slog.LevelDebug.
+func DumpLevel(level slog.Level) DumpOption {
+	return func(config *dumpConfig) {
+		config.level = level
+	}
+}
+
+// DumpWriter pretty-prints the dumps to w instead of writing log records, e.g. to os.Stderr
+// during development. Writes to w are serialized.
+func DumpWriter(w io.Writer) DumpOption {
+	return func(config *dumpConfig) {
+		config.writer = &lockedWriter{w: w}
+	}
+}
+
+// DumpEnabled makes dumping depend on the flag. The flag can be changed at runtime,
+// e.g. from an admin endpoint or using DumpToggleOnSignal. By default, dumping is always enabled.
+func DumpEnabled(enabled *atomic.Bool) DumpOption {
+	return func(config *dumpConfig) {
+		config.enabled = enabled
+	}
+}
+
+// DumpMaxBodySize limits the number of bytes of the request and response body included
+// in a dump. Longer bodies are truncated. Defaults to 64KB, a negative value omits the bodies.
+func DumpMaxBodySize(n int) DumpOption {
+	return func(config *dumpConfig) {
+		config.maxBodySize = n
+	}
+}
+
+// DumpRedactHeaders replaces the values of the given headers with RedactedValue.
+// Defaults to the headers of the requests Redaction.
+func DumpRedactHeaders(names ...string) DumpOption {
+	return func(config *dumpConfig) {
+		config.redact = slices.Clone(names)
+		if config.redact == nil {
+			config.redact = []string{}
+		}
+	}
+}
+
+// DumpToggleOnSignal flips the flag each time the process receives one of the signals,
+// e.g. syscall.SIGUSR1. Call the returned function to stop listening for the signals.
+//
+//	var dumping atomic.Bool
+//	defer gum.DumpToggleOnSignal(&dumping, syscall.SIGUSR1)()
+//
+//	handler = gum.Dump(gum.DumpEnabled(&dumping))(handler)
+func DumpToggleOnSignal(enabled *atomic.Bool, signals ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				enabled.Store(!enabled.Load())
+
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Dump returns a Middleware that captures complete requests and responses, including
+// headers and bodies, for debugging. The bodies are captured while they are read and
+// written by the handler, so streaming is not affected.
+//
+// Dumps contain sensitive data. Headers, query parameters and json fields listed
+// in the requests Redaction are redacted. Use DumpEnabled to limit when requests are dumped.
+func Dump(options ...DumpOption) Middleware {
+	config := dumpConfig{
+		level:       slog.LevelDebug,
+		maxBodySize: 64 * 1024,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if config.enabled != nil && !config.enabled.Load() {
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+
+			logger := config.logger
+			if logger == nil {
+				logger = internal.LoggerOf(ctx)
+			}
+
+			if config.writer == nil && !logger.Enabled(ctx, config.level) {
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			requestHeader := r.Header.Clone()
+
+			requestBody := &dumpBuffer{limit: config.maxBodySize}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = dumpReader{ReadCloser: r.Body, buffer: requestBody}
+			}
+
+			responseBody := &dumpBuffer{limit: config.maxBodySize}
+			dw := &dumpResponseWriter{RecordingWriter: internal.NewRecordingWriter(w), buffer: responseBody}
+
+			delegate.ServeHTTP(dw, r)
+
+			redaction := redactionOf(r)
+			if config.redact != nil {
+				redaction.Headers = config.redact
+			}
+
+			requestURL := redaction.RedactURL(r.URL)
+			requestHeader = redaction.RedactHeader(requestHeader)
+			responseHeader := redaction.RedactHeader(w.Header())
+
+			requestBody.redactJSON(redaction, requestHeader)
+			responseBody.redactJSON(redaction, responseHeader)
+
+			if config.writer != nil {
+				var buf bytes.Buffer
+
+				fmt.Fprintf(&buf, "> %s %s %s\n", r.Method, requestURL, r.Proto)
+				fmt.Fprintf(&buf, "> Host: %s\n", r.Host)
+				writeDumpHeader(&buf, "> ", requestHeader)
+				writeDumpBody(&buf, "> ", requestBody)
+
+				fmt.Fprintf(&buf, "< %s %d %s\n", r.Proto, dw.StatusCode(), http.StatusText(dw.StatusCode()))
+				writeDumpHeader(&buf, "< ", responseHeader)
+				writeDumpBody(&buf, "< ", responseBody)
+
+				buf.WriteString("\n")
+
+				_, _ = config.writer.Write(buf.Bytes())
+				return
+			}
+
+			logger.LogAttrs(ctx, config.level, "Request dumped",
+				slog.Group("request",
+					slog.String("method", r.Method),
+					slog.String("url", requestURL),
+					slog.String("host", r.Host),
+					slog.Any("header", requestHeader),
+					slog.String("body", requestBody.String()),
+				),
+				slog.Group("response",
+					slog.Int("status", dw.StatusCode()),
+					slog.Any("header", responseHeader),
+					slog.String("body", responseBody.String()),
+				),
+			)
+		})
+	}
+}
+
+func writeDumpHeader(buf *bytes.Buffer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(buf, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+func writeDumpBody(buf *bytes.Buffer, prefix string, body *dumpBuffer) {
+	if body.buf.Len() == 0 && body.truncated == 0 {
+		return
+	}
+
+	buf.WriteString(prefix + "\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(body.String(), "\n"), "\n") {
+		buf.WriteString(prefix + line + "\n")
+	}
+}
+
+// dumpBuffer keeps the first limit bytes written to it and counts the rest.
+type dumpBuffer struct {
+	limit     int
+	buf       bytes.Buffer
+	truncated int64
+}
+
+func (d *dumpBuffer) Write(p []byte) {
+	remaining := max(d.limit-d.buf.Len(), 0)
+	if len(p) > remaining {
+		d.truncated += int64(len(p) - remaining)
+		p = p[:remaining]
+	}
+
+	d.buf.Write(p)
+}
+
+// redactJSON redacts the json fields of the captured body, if header declares a json body.
+// A body that can not be parsed, e.g. because it was truncated, is replaced completely.
+func (d *dumpBuffer) redactJSON(redaction Redaction, header http.Header) {
+	if len(redaction.JSONFields) == 0 || d.buf.Len() == 0 {
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return
+	}
+
+	redacted, ok := redaction.RedactJSON(d.buf.Bytes())
+	if !ok {
+		redacted = []byte(RedactedValue)
+	}
+
+	d.buf.Reset()
+	d.buf.Write(redacted)
+}
+
+func (d *dumpBuffer) String() string {
+	if d.truncated > 0 {
+		return fmt.Sprintf("%s... (%d bytes truncated)", d.buf.String(), d.truncated)
+	}
+
+	return d.buf.String()
+}
+
+type dumpReader struct {
+	io.ReadCloser
+	buffer *dumpBuffer
+}
+
+func (r dumpReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.buffer.Write(p[:n])
+	return n, err
+}
+
+type dumpResponseWriter struct {
+	*internal.RecordingWriter
+	buffer *dumpBuffer
+}
+
+func (w *dumpResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.RecordingWriter.Write(p)
+	w.buffer.Write(p[:n])
+	return n, err
+}
+
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.w.Write(p)
+}
@@ -0,0 +1,103 @@
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func dumpTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("got " + string(body)))
+	})
+}
+
+func dumpTestRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/items?x=1", strings.NewReader("hello world"))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "text/plain")
+	return req
+}
+
+func TestDumpWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	handler := Dump(DumpWriter(&buf))(dumpTestHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, dumpTestRequest())
+
+	AssertEqual(t, rec.Body.String(), "got hello world")
+
+	expected := strings.Join([]string{
+		"> POST /items?x=1 HTTP/1.1",
+		"> Host: example.com",
+		"> Authorization: [redacted]",
+		"> Content-Type: text/plain",
+		"> ",
+		"> hello world",
+		"< HTTP/1.1 201 Created",
+		"< Content-Type: text/plain",
+		"< Set-Cookie: [redacted]",
+		"< ",
+		"< got hello world",
+		"",
+		"",
+	}, "\n")
+
+	AssertEqual(t, buf.String(), expected)
+}
+
+func TestDumpLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	handler := Dump(DumpLogger(logger), DumpMaxBodySize(5))(dumpTestHandler())
+	handler.ServeHTTP(httptest.NewRecorder(), dumpTestRequest())
+
+	var record struct {
+		Request struct {
+			Method string              `json:"method"`
+			Header map[string][]string `json:"header"`
+			Body   string              `json:"body"`
+		} `json:"request"`
+		Response struct {
+			Status int    `json:"status"`
+			Body   string `json:"body"`
+		} `json:"response"`
+	}
+
+	AssertEqual(t, json.Unmarshal(buf.Bytes(), &record), nil)
+	AssertEqual(t, record.Request.Method, "POST")
+	AssertEqual(t, record.Request.Header["Authorization"], []string{"[redacted]"})
+	AssertEqual(t, record.Request.Body, "hello... (6 bytes truncated)")
+	AssertEqual(t, record.Response.Status, http.StatusCreated)
+	AssertEqual(t, record.Response.Body, "got h... (10 bytes truncated)")
+}
+
+func TestDumpEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	var enabled atomic.Bool
+
+	handler := Dump(DumpWriter(&buf), DumpEnabled(&enabled))(dumpTestHandler())
+
+	handler.ServeHTTP(httptest.NewRecorder(), dumpTestRequest())
+	AssertEqual(t, buf.Len(), 0)
+
+	enabled.Store(true)
+
+	handler.ServeHTTP(httptest.NewRecorder(), dumpTestRequest())
+	AssertTrue(t, buf.Len() > 0)
+}
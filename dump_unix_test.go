@@ -0,0 +1,27 @@
+//go:build unix
+
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDumpToggleOnSignal(t *testing.T) {
+	var enabled atomic.Bool
+
+	stop := DumpToggleOnSignal(&enabled, syscall.SIGUSR1)
+	defer stop()
+
+	AssertEqual(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1), nil)
+
+	deadline := time.Now().Add(time.Second)
+	for !enabled.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	AssertTrue(t, enabled.Load())
+}
@@ -0,0 +1,251 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/internal"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// Job is a unit of work scheduled by a handler using the Enqueuer.
+type Job func(ctx context.Context) error
+
+// Queue runs jobs in the background.
+type Queue interface {
+	// Enqueue schedules the job. The context carries the values of the request
+	// that scheduled the job, but is not canceled when the request ends.
+	Enqueue(ctx context.Context, job Job) error
+}
+
+// Enqueuer schedules jobs to run after the response was sent, e.g. to send an email
+// without delaying the response. Jobs are handed to the Queue after the handler
+// returned and the response was flushed. Requires ProvideQueue.
+//
+//	func(user gum.JSON[User], enqueuer gum.Enqueuer) {
+//	  enqueuer.Enqueue(func(ctx context.Context) error {
+//	    return sendWelcomeMail(ctx, user.Value)
+//	  })
+//	}
+type Enqueuer struct {
+	pending *pendingJobs
+}
+
+// Enqueue schedules the job to run after the response was sent.
+func (e Enqueuer) Enqueue(job Job) {
+	e.pending.mu.Lock()
+	defer e.pending.mu.Unlock()
+
+	e.pending.jobs = append(e.pending.jobs, job)
+}
+
+type pendingJobs struct {
+	mu   sync.Mutex
+	jobs []Job
+}
+
+type pendingJobsKey struct{}
+
+func init() {
+	Register(func(r *http.Request) (Enqueuer, error) {
+		pending, ok := r.Context().Value(pendingJobsKey{}).(*pendingJobs)
+		if !ok {
+			return Enqueuer{}, errors.New("no queue in context, use ProvideQueue")
+		}
+
+		return Enqueuer{pending: pending}, nil
+	})
+}
+
+// ProvideQueue provides a Middleware that hands all jobs scheduled using the Enqueuer
+// to the given Queue, after the response was flushed to the client. Jobs that can not
+// be enqueued are logged and dropped.
+func ProvideQueue(queue Queue) Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pending := &pendingJobs{}
+
+			ctx := context.WithValue(r.Context(), pendingJobsKey{}, pending)
+			delegate.ServeHTTP(w, r.WithContext(ctx))
+
+			pending.mu.Lock()
+			jobs := pending.jobs
+			pending.jobs = nil
+			pending.mu.Unlock()
+
+			if len(jobs) == 0 {
+				return
+			}
+
+			// make sure the client has the response before we start working on the jobs
+			_ = http.NewResponseController(w).Flush()
+
+			jobCtx := context.WithoutCancel(r.Context())
+
+			for _, job := range jobs {
+				if err := queue.Enqueue(jobCtx, job); err != nil {
+					internal.LoggerOf(ctx).WarnContext(ctx, "Enqueue job failed",
+						slog.String("err", err.Error()),
+					)
+				}
+			}
+		})
+	}
+}
+
+var (
+	// ErrQueueFull is returned by LocalQueue.Enqueue if the buffer of the queue is full.
+	ErrQueueFull = errors.New("queue is full")
+
+	// ErrQueueClosed is returned by LocalQueue.Enqueue after the queue was closed.
+	ErrQueueClosed = errors.New("queue is closed")
+)
+
+// LocalQueueOption configures a LocalQueue.
+type LocalQueueOption func(q *LocalQueue)
+
+// LocalQueueWorkers sets the number of jobs that run concurrently. Defaults to 4.
+func LocalQueueWorkers(workers int) LocalQueueOption {
+	return func(q *LocalQueue) {
+		q.workers = workers
+	}
+}
+
+// LocalQueueSize sets the number of jobs that can wait for a worker.
+// Further jobs are rejected with ErrQueueFull. Defaults to 1024.
+func LocalQueueSize(size int) LocalQueueOption {
+	return func(q *LocalQueue) {
+		q.size = size
+	}
+}
+
+// LocalQueue is an in-process Queue running jobs on a fixed number of goroutines.
+// Failed jobs are logged and not retried. Jobs are lost if the process exits, use
+// Close to wait for pending jobs during shutdown:
+//
+//	queue := gum.NewLocalQueue()
+//	server := gum.NewServer(":8080", gum.ProvideQueue(queue)(mux),
+//	  gum.OnShutdown(queue.Close),
+//	)
+type LocalQueue struct {
+	workers int
+	size    int
+
+	jobs chan queuedJob
+
+	// ctx is canceled if Close gives up waiting for the jobs
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var _ Queue = (*LocalQueue)(nil)
+
+type queuedJob struct {
+	ctx context.Context
+	job Job
+}
+
+// NewLocalQueue creates a new LocalQueue and starts its workers.
+func NewLocalQueue(options ...LocalQueueOption) *LocalQueue {
+	q := &LocalQueue{
+		workers: 4,
+		size:    1024,
+	}
+
+	for _, option := range options {
+		option(q)
+	}
+
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	q.jobs = make(chan queuedJob, q.size)
+
+	q.wg.Add(q.workers)
+	for range q.workers {
+		go q.work()
+	}
+
+	return q
+}
+
+// Enqueue schedules the job. It does not block if the queue is full,
+// but returns ErrQueueFull instead.
+func (q *LocalQueue) Enqueue(ctx context.Context, job Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- queuedJob{ctx: ctx, job: job}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting new jobs and waits for all scheduled jobs to finish.
+// If ctx is done first, the context of the running jobs is canceled and
+// the error of ctx is returned.
+func (q *LocalQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+
+	case <-ctx.Done():
+		q.cancel()
+		return ctx.Err()
+	}
+}
+
+func (q *LocalQueue) work() {
+	defer q.wg.Done()
+
+	for queued := range q.jobs {
+		q.run(queued)
+	}
+}
+
+func (q *LocalQueue) run(queued queuedJob) {
+	ctx, cancel := context.WithCancel(queued.ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(q.ctx, cancel)
+	defer stop()
+
+	defer func() {
+		if p := recover(); p != nil {
+			internal.LoggerOf(ctx).ErrorContext(ctx, "Job panicked",
+				slog.String("panic", fmt.Sprint(p)),
+			)
+		}
+	}()
+
+	if err := queued.job(ctx); err != nil {
+		internal.LoggerOf(ctx).WarnContext(ctx, "Job failed",
+			slog.String("err", err.Error()),
+		)
+	}
+}
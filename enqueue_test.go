@@ -0,0 +1,113 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type syncQueue func(ctx context.Context, job Job) error
+
+func (q syncQueue) Enqueue(ctx context.Context, job Job) error {
+	return q(ctx, job)
+}
+
+func TestEnqueuer(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	var bodyWhenRun string
+	var ctxErr error
+
+	queue := syncQueue(func(ctx context.Context, job Job) error {
+		return job(ctx)
+	})
+
+	handler := ProvideQueue(queue)(Handler(func(enqueuer Enqueuer) http.Handler {
+		enqueuer.Enqueue(func(ctx context.Context) error {
+			bodyWhenRun = rec.Body.String()
+			ctxErr = ctx.Err()
+			return nil
+		})
+
+		return response.Text("ok")
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx))
+	cancel()
+
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, bodyWhenRun, "ok")
+	AssertEqual(t, ctxErr, nil)
+	AssertTrue(t, rec.Flushed)
+}
+
+func TestEnqueuerWithoutQueue(t *testing.T) {
+	handler := Handler(func(enqueuer Enqueuer) {})
+
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodPost, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusBadRequest)
+}
+
+func TestLocalQueue(t *testing.T) {
+	queue := NewLocalQueue(LocalQueueWorkers(2))
+
+	var count atomic.Int32
+
+	for range 10 {
+		err := queue.Enqueue(context.Background(), func(ctx context.Context) error {
+			time.Sleep(time.Millisecond)
+			count.Add(1)
+			return nil
+		})
+
+		AssertEqual(t, err, nil)
+	}
+
+	// failing and panicking jobs do not stop the workers
+	_ = queue.Enqueue(context.Background(), func(ctx context.Context) error { return errors.New("failed") })
+	_ = queue.Enqueue(context.Background(), func(ctx context.Context) error { panic("boom") })
+
+	AssertEqual(t, queue.Close(context.Background()), nil)
+	AssertEqual(t, count.Load(), int32(10))
+
+	err := queue.Enqueue(context.Background(), func(ctx context.Context) error { return nil })
+	AssertTrue(t, errors.Is(err, ErrQueueClosed))
+}
+
+func TestLocalQueueFull(t *testing.T) {
+	queue := NewLocalQueue(LocalQueueWorkers(1), LocalQueueSize(1))
+
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+
+	blocking := func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	}
+
+	AssertEqual(t, queue.Enqueue(context.Background(), blocking), nil)
+	<-started
+
+	noop := func(ctx context.Context) error { return nil }
+	AssertEqual(t, queue.Enqueue(context.Background(), noop), nil)
+	AssertTrue(t, errors.Is(queue.Enqueue(context.Background(), noop), ErrQueueFull))
+
+	// closing with a deadline cancels the running job
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	AssertTrue(t, errors.Is(queue.Close(ctx), context.DeadlineExceeded))
+	<-canceled
+}
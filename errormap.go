@@ -0,0 +1,66 @@
+package gum
+
+import (
+	"errors"
+	"sync"
+)
+
+// errorMapping maps errors matching a predicate to a status code.
+type errorMapping struct {
+	matches    func(err error) bool
+	statusCode int
+}
+
+var errorMappings struct {
+	mu       sync.RWMutex
+	mappings []errorMapping
+}
+
+// MapError maps all errors of type E to the given status code. An error matches if
+// errors.As finds an E in its chain. The mapping applies to errors returned by handler
+// functions and extractors of all handlers:
+//
+//	gum.MapError[*ValidationError](http.StatusUnprocessableEntity)
+//
+// An HTTPError in the chain of the error takes precedence over all mappings.
+// This function is threadsafe, but mappings should be registered during initialization.
+func MapError[E error](statusCode int) {
+	addErrorMapping(func(err error) bool {
+		var target E
+		return errors.As(err, &target)
+	}, statusCode)
+}
+
+// MapErrorIs maps all errors matching target using errors.Is to the given status code,
+// e.g. sentinel errors like sql.ErrNoRows:
+//
+//	gum.MapErrorIs(sql.ErrNoRows, http.StatusNotFound)
+//
+// See MapError.
+func MapErrorIs(target error, statusCode int) {
+	addErrorMapping(func(err error) bool {
+		return errors.Is(err, target)
+	}, statusCode)
+}
+
+func addErrorMapping(matches func(err error) bool, statusCode int) {
+	errorMappings.mu.Lock()
+	defer errorMappings.mu.Unlock()
+
+	errorMappings.mappings = append(errorMappings.mappings, errorMapping{matches: matches, statusCode: statusCode})
+}
+
+// mappedStatusCode returns the status code of the first mapping matching err,
+// in the order the mappings were registered.
+func mappedStatusCode(err error) (int, bool) {
+	errorMappings.mu.RLock()
+	defer errorMappings.mu.RUnlock()
+
+	for _, mapping := range errorMappings.mappings {
+		if mapping.matches(err) {
+			return mapping.statusCode, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/serde"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testValidationError struct {
+	Field string
+}
+
+func (e *testValidationError) Error() string {
+	return "invalid " + e.Field
+}
+
+var errTestNotFound = errors.New("not found")
+
+func TestMapError(t *testing.T) {
+	t.Cleanup(func() {
+		errorMappings.mu.Lock()
+		defer errorMappings.mu.Unlock()
+
+		errorMappings.mappings = nil
+	})
+
+	MapErrorIs(errTestNotFound, http.StatusNotFound)
+	MapError[*testValidationError](http.StatusUnprocessableEntity)
+
+	serve := func(err error) int {
+		handler := Handler(func() error { return err })
+		return gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil)).StatusCode
+	}
+
+	AssertEqual(t, serve(errTestNotFound), http.StatusNotFound)
+	AssertEqual(t, serve(fmt.Errorf("load user: %w", errTestNotFound)), http.StatusNotFound)
+	AssertEqual(t, serve(&testValidationError{Field: "name"}), http.StatusUnprocessableEntity)
+	AssertEqual(t, serve(errors.New("other")), http.StatusInternalServerError)
+
+	// mappings see the complete chain, even if it contains an errmsg message
+	messageErr := &serde.MessageError{Field: "id", Message: "unknown id", Err: errors.New("no such row")}
+	AssertEqual(t, serve(fmt.Errorf("%w: %w", errTestNotFound, messageErr)), http.StatusNotFound)
+
+	// an HTTPError takes precedence
+	AssertEqual(t, serve(NewHTTPError(http.StatusGone, errTestNotFound)), http.StatusGone)
+
+	// mappings also apply to errors of gum middleware
+	provide := Provide(func(r *http.Request) (string, error) { return "", errTestNotFound })
+	rec := gumtest.Serve(provide(http.NotFoundHandler()), httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusNotFound)
+}
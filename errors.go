@@ -0,0 +1,161 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/response"
+	"github.com/go-gum/gum/serde"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPError is an error that carries the status code and headers of the
+// response that should be sent to the client. Extractors and handler functions can return
+// an HTTPError (or an error wrapping one) to control the error response of a Handler.
+type HTTPError struct {
+	StatusCode int
+	Header     http.Header
+	Err        error
+}
+
+// NewHTTPError creates a new HTTPError with the given status code wrapping err.
+func NewHTTPError(statusCode int, err error) *HTTPError {
+	return &HTTPError{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+		Err:        err,
+	}
+}
+
+// WithHeader sets a header to be sent with the error response.
+func (e *HTTPError) WithHeader(key, value string) *HTTPError {
+	if e.Header == nil {
+		e.Header = http.Header{}
+	}
+
+	e.Header.Set(key, value)
+	return e
+}
+
+// WithRetryAfter sets the Retry-After header to the given duration in seconds, rounded up.
+// A duration that is not positive removes the header.
+func (e *HTTPError) WithRetryAfter(retryAfter time.Duration) *HTTPError {
+	if retryAfter <= 0 {
+		if e.Header != nil {
+			e.Header.Del("Retry-After")
+		}
+
+		return e
+	}
+
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	return e.WithHeader("Retry-After", strconv.FormatInt(seconds, 10))
+}
+
+// UnauthorizedError creates an HTTPError with status 401 Unauthorized. The
+// WWW-Authenticate header is set to the given challenge, e.g. `Bearer realm="api"`.
+func UnauthorizedError(wwwAuthenticate string, err error) *HTTPError {
+	return NewHTTPError(http.StatusUnauthorized, err).
+		WithHeader("WWW-Authenticate", wwwAuthenticate)
+}
+
+// TooManyRequestsError creates an HTTPError with status 429 Too Many Requests. If retryAfter
+// is positive, the Retry-After header is set to the number of seconds, rounded up.
+func TooManyRequestsError(retryAfter time.Duration, err error) *HTTPError {
+	return NewHTTPError(http.StatusTooManyRequests, err).WithRetryAfter(retryAfter)
+}
+
+// ServiceUnavailableError creates an HTTPError with status 503 Service Unavailable. If retryAfter
+// is positive, the Retry-After header is set to the number of seconds, rounded up.
+func ServiceUnavailableError(retryAfter time.Duration, err error) *HTTPError {
+	return NewHTTPError(http.StatusServiceUnavailable, err).WithRetryAfter(retryAfter)
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("http status %d", e.StatusCode)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorResponse builds the response for err like the Handler does for errors returned by handler
+// functions, using the encoder set by response.SetErrorEncoder, error mappings and the message
+// catalog. Use it to reject requests in a Middleware. If err wraps an HTTPError, its status code
+// and header are used, otherwise the status code of a matching error mapping or statusCode.
+func ErrorResponse(err error, statusCode int) http.Handler {
+	return errorResponse(err, statusCode)
+}
+
+// errorResponse builds the response for the given error. If err wraps an HTTPError,
+// its status code and header are used. Otherwise, the status code of a matching error
+// mapping is used, see MapError, and statusCode if no mapping matches.
+func errorResponse(err error, statusCode int) http.Handler {
+	return errorResponseWith(response.Error, err, statusCode)
+}
+
+// errorResponseWith works like errorResponse, but uses the given encoder
+// to build the response. If err wraps a serde.MessageError, only the message
+// of the errmsg struct tag is passed to the encoder.
+func errorResponseWith(encoder response.ErrorEncoder, err error, statusCode int) http.Handler {
+	var httpErr *HTTPError
+	hasHTTPErr := errors.As(err, &httpErr)
+
+	var header http.Header
+
+	switch {
+	case hasHTTPErr:
+		if httpErr.StatusCode > 0 {
+			statusCode = httpErr.StatusCode
+		}
+
+		header = httpErr.Header
+
+	default:
+		// map the original error, the MessageError below hides the errors it was wrapped in
+		if mapped, ok := mappedStatusCode(err); ok {
+			statusCode = mapped
+		}
+	}
+
+	var messageErr *serde.MessageError
+	if errors.As(err, &messageErr) {
+		err = messageErr
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		catalog := messageCatalogOf(r)
+		err, language := localizeError(r, catalog, err, statusCode)
+
+		resp := encoder(err, statusCode).UpdateWith(0, header)
+		if language != "" {
+			resp = resp.SetHeader("Content-Language", language)
+		}
+
+		if catalog != nil {
+			// the language of the response depends on the Accept-Language header,
+			// even if it was not translated.
+			resp = resp.AddHeader("Vary", "Accept-Language")
+		}
+
+		resp.ServeHTTP(w, r)
+	})
+}
+
+// contextErrorResponse builds the response for a request whose context is done.
+func contextErrorResponse(encoder response.ErrorEncoder, ctx context.Context) http.Handler {
+	statusCode := StatusClientClosedRequest
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		statusCode = http.StatusRequestTimeout
+	}
+
+	err := fmt.Errorf("request context done: %w", context.Cause(ctx))
+	return errorResponseWith(encoder, err, statusCode)
+}
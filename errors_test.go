@@ -0,0 +1,87 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithErrorEncoder(t *testing.T) {
+	handler := Handler(func() error {
+		return NewHTTPError(http.StatusConflict, errors.New("version mismatch")).WithHeader("X-Version", "2")
+	}, WithErrorEncoder(response.ProblemError))
+
+	resp := gumtest.Serve(handler, httptest.NewRequest("PUT", "/", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusConflict)
+	AssertEqual(t, resp.Header.Get("Content-Type"), "application/problem+json")
+	AssertEqual(t, resp.Header.Get("X-Version"), "2")
+
+	problem := gumtest.DecodeJSON[response.Problem](t, resp)
+	AssertEqual(t, problem.Detail, "version mismatch")
+}
+
+func TestErrorHeaders(t *testing.T) {
+	serve := func(err error, options ...HandlerOption) gumtest.Response {
+		handler := Handler(func() error { return err }, options...)
+		return gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	rec := serve(UnauthorizedError(`Bearer realm="api"`, errors.New("no token")))
+	AssertEqual(t, rec.StatusCode, http.StatusUnauthorized)
+	AssertEqual(t, rec.Header.Get("WWW-Authenticate"), `Bearer realm="api"`)
+
+	rec = serve(fmt.Errorf("wrapped: %w", TooManyRequestsError(1500*time.Millisecond, errors.New("slow down"))))
+	AssertEqual(t, rec.StatusCode, http.StatusTooManyRequests)
+	AssertEqual(t, rec.Header.Get("Retry-After"), "2")
+
+	rec = serve(ServiceUnavailableError(0, errors.New("down")), WithErrorEncoder(response.ProblemError))
+	AssertEqual(t, rec.StatusCode, http.StatusServiceUnavailable)
+	AssertEqual(t, rec.Header.Get("Retry-After"), "")
+
+	rec = serve(ServiceUnavailableError(time.Minute, errors.New("down")), WithErrorEncoder(response.ProblemError))
+	AssertEqual(t, rec.Header.Get("Retry-After"), "60")
+}
+
+// closeTracker records whether it was closed by the Handler.
+type closeTracker struct {
+	closed *bool
+}
+
+func (c closeTracker) Close() error {
+	*c.closed = true
+	return nil
+}
+
+func TestHandlerPanic(t *testing.T) {
+	var closed bool
+
+	handler := Handler(func(tracker closeTracker) error {
+		panic("boom")
+	}, WithErrorEncoder(response.ProblemError), WithOverrides(Override(func(r *http.Request) (closeTracker, error) {
+		return closeTracker{closed: &closed}, nil
+	})))
+
+	resp := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusInternalServerError)
+	AssertEqual(t, resp.Header.Get("Content-Type"), "application/problem+json")
+	AssertTrue(t, closed)
+}
+
+func TestHandlerPanicAfterWrite(t *testing.T) {
+	handler := Handler(func(w ResponseWriter) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("partial"))
+		panic("boom")
+	}, WithErrorEncoder(response.ProblemError))
+
+	// the committed response is not extended with an error document
+	resp := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusAccepted)
+	AssertEqual(t, resp.Text(), "partial")
+}
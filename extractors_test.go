@@ -1,9 +1,11 @@
 package gum
 
 import (
-	"bytes"
+	"errors"
+	"github.com/go-gum/gum/gumtest"
 	. "github.com/go-gum/gum/internal/test"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -30,29 +32,48 @@ func TestExtractContentType(t *testing.T) {
 func TestExtractNoContentType(t *testing.T) {
 	req := &http.Request{}
 
-	var rw responseWriter
-	Handler(func(v ContentType) { t.FailNow() }).ServeHTTP(&rw, req)
-	AssertEqual(t, rw.statusCode, http.StatusBadRequest)
+	rw := gumtest.Serve(Handler(func(v ContentType) { t.FailNow() }), req)
+	AssertEqual(t, rw.StatusCode, http.StatusBadRequest)
 }
 
-type responseWriter struct {
-	header     http.Header
-	body       bytes.Buffer
-	statusCode int
+type failingFromRequest struct{}
+
+func (failingFromRequest) FromRequest(r *http.Request) (failingFromRequest, error) {
+	return failingFromRequest{}, errors.New("failed")
 }
 
-func (r *responseWriter) Header() http.Header {
-	if r.header == nil {
-		r.header = http.Header{}
-	}
+func TestExtractFastPaths(t *testing.T) {
+	req := &http.Request{Method: http.MethodPut, Host: "example.com"}
 
-	return r.header
-}
+	method, err := Extract[Method](req)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, method, Method(http.MethodPut))
+
+	both, err := Extract[Option[Both[Method, Host]]](req)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, both.Value.First, Method(http.MethodPut))
+	AssertEqual(t, both.Value.Second, Host("example.com"))
 
-func (r *responseWriter) Write(bytes []byte) (int, error) {
-	return r.body.Write(bytes)
+	_, err = Extract[failingFromRequest](req)
+	AssertEqual(t, err.Error(), `extract "gum.failingFromRequest": failed`)
 }
 
-func (r *responseWriter) WriteHeader(statusCode int) {
-	r.statusCode = statusCode
+func BenchmarkExtract(b *testing.B) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	b.Run("Registered", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			_, _ = Extract[Method](req)
+		}
+	})
+
+	b.Run("FromRequest", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			_, _ = Extract[Option[Both[Method, Host]]](req)
+		}
+	})
 }
@@ -0,0 +1,162 @@
+package gum
+
+import (
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// FilterOp is a comparison operator of a FilterCondition
+type FilterOp string
+
+// Operators supported by Filter
+const (
+	OpEqual          FilterOp = "="
+	OpNotEqual       FilterOp = "!="
+	OpLess           FilterOp = "<"
+	OpLessOrEqual    FilterOp = "<="
+	OpGreater        FilterOp = ">"
+	OpGreaterOrEqual FilterOp = ">="
+	OpContains       FilterOp = "~"
+)
+
+// operators ordered so that longer operators are matched first
+var filterOps = []FilterOp{
+	OpNotEqual, OpLessOrEqual, OpGreaterOrEqual,
+	OpEqual, OpLess, OpGreater, OpContains,
+}
+
+// FilterCondition is a single condition of a Filter, e.g. "age>=21"
+type FilterCondition struct {
+	// Field is the name of the field as defined by the json tag
+	Field string
+
+	Op FilterOp
+
+	// Value is the value decoded into the type of the field
+	Value any
+}
+
+// Filter parses the "filter" query parameters into a list of conditions, e.g.
+// "?filter=age>=21&filter=name~al". All conditions must hold for an item to match.
+//
+// Only the fields of T can be filtered by, resolved the same way as by QueryValues.
+// The values are decoded into the type of the field. By default, all operators are allowed
+// for a field. To restrict the operators, use the filter tag:
+//
+//	type User struct {
+//	  Name string `json:"name" filter:"=,~"`
+//	  Age  int    `json:"age" filter:"=,<,<=,>,>="`
+//	  Hash string `json:"hash" filter:"-"`
+//	}
+//
+// The contains operator "~" is only allowed on string fields. Extraction fails with
+// 400 Bad Request if a condition is malformed or uses a field or operator that is not allowed.
+type Filter[T any] struct {
+	Conditions []FilterCondition
+}
+
+var _ = AssertFromRequest[Filter[any]]()
+
+type filterField struct {
+	Type reflect.Type
+	Ops  []FilterOp
+}
+
+func filterFieldsOf(ty reflect.Type) map[string]filterField {
+	fields := map[string]filterField{}
+
+	if ty.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for _, field := range serde.Fields(ty) {
+		tag := ty.FieldByIndex(field.Index).Tag.Get("filter")
+		if tag == "-" {
+			continue
+		}
+
+		ops := filterOps
+		if tag != "" {
+			ops = nil
+			for _, op := range strings.Split(tag, ",") {
+				ops = append(ops, FilterOp(strings.TrimSpace(op)))
+			}
+		}
+
+		if field.Type.Kind() != reflect.String {
+			ops = slices.DeleteFunc(slices.Clone(ops), func(op FilterOp) bool { return op == OpContains })
+		}
+
+		fields[field.Name] = filterField{Type: field.Type, Ops: ops}
+	}
+
+	return fields
+}
+
+func (Filter[T]) FromRequest(r *http.Request) (Filter[T], error) {
+	fields := filterFieldsOf(reflect.TypeFor[T]())
+
+	var conditions []FilterCondition
+
+	for _, expr := range r.URL.Query()["filter"] {
+		condition, err := parseFilterCondition(fields, expr)
+		if err != nil {
+			err = fmt.Errorf("filter %q: %w", expr, err)
+			return Filter[T]{}, NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return Filter[T]{Conditions: conditions}, nil
+}
+
+func parseFilterCondition(fields map[string]filterField, expr string) (FilterCondition, error) {
+	// find the operator following the field name
+	idx := strings.IndexAny(expr, "=!<>~")
+	if idx <= 0 {
+		return FilterCondition{}, fmt.Errorf("expected field followed by an operator")
+	}
+
+	name, rest := expr[:idx], expr[idx:]
+
+	var op FilterOp
+	for _, candidate := range filterOps {
+		if strings.HasPrefix(rest, string(candidate)) {
+			op = candidate
+			break
+		}
+	}
+
+	if op == "" {
+		return FilterCondition{}, fmt.Errorf("unknown operator in %q", rest)
+	}
+
+	field, ok := fields[name]
+	if !ok {
+		return FilterCondition{}, fmt.Errorf("unknown field %q", name)
+	}
+
+	if !slices.Contains(field.Ops, op) {
+		return FilterCondition{}, fmt.Errorf("operator %q not allowed for field %q", op, name)
+	}
+
+	// decode the value into the type of the field
+	target := reflect.New(field.Type)
+	source := serde.StringValue(strings.TrimPrefix(rest, string(op)))
+	if err := serde.Unmarshal(source, target.Interface()); err != nil {
+		return FilterCondition{}, fmt.Errorf("decode value of field %q: %w", name, err)
+	}
+
+	condition := FilterCondition{
+		Field: name,
+		Op:    op,
+		Value: target.Elem().Interface(),
+	}
+
+	return condition, nil
+}
@@ -0,0 +1,49 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+type filterableUser struct {
+	Name string `json:"name" filter:"=,~"`
+	Age  int    `json:"age"`
+	Hash string `json:"hash" filter:"-"`
+}
+
+func TestFilter(t *testing.T) {
+	query := url.Values{"filter": {"age>=21", "name~al", "age!=30"}}
+	req := httptest.NewRequest("GET", "/users?"+query.Encode(), nil)
+
+	var extractedValue Filter[filterableUser]
+	Handler(func(v Filter[filterableUser]) { extractedValue = v }).ServeHTTP(nil, req)
+
+	AssertEqual(t, extractedValue.Conditions, []FilterCondition{
+		{Field: "age", Op: OpGreaterOrEqual, Value: 21},
+		{Field: "name", Op: OpContains, Value: "al"},
+		{Field: "age", Op: OpNotEqual, Value: 30},
+	})
+}
+
+func TestFilter_Invalid(t *testing.T) {
+	invalid := []string{
+		"hash=abc",    // excluded field
+		"unknown=abc", // unknown field
+		"name>abc",    // operator not allowed
+		"age~2",       // contains on non string field
+		"age>=abc",    // not a number
+		">=21",        // no field
+	}
+
+	for _, expr := range invalid {
+		query := url.Values{"filter": {expr}}
+		req := httptest.NewRequest("GET", "/users?"+query.Encode(), nil)
+
+		rec := httptest.NewRecorder()
+		Handler(func(v Filter[filterableUser]) { t.FailNow() }).ServeHTTP(rec, req)
+		AssertEqual(t, rec.Code, http.StatusBadRequest)
+	}
+}
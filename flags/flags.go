@@ -0,0 +1,351 @@
+// Package flags resolves feature flags per request.
+//
+// A Provider resolves the flags of a Subject, the user and tenant of a request.
+// The Middleware resolves the flags once per request, handlers access them using
+// the Flags extractor:
+//
+//	provider := flags.File("flags.json")
+//	mux.Handle("GET /", flags.Middleware(provider, subjectOf)(gum.Handler(index)))
+//
+//	func index(flags flags.Flags) response.Response {
+//		if flags.Enabled("new-layout") {
+//			...
+//		}
+//	}
+//
+// Flags have string values. A flag is enabled if its value is "true", other values
+// can be used as variants, e.g. for experiments.
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/internal"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Subject identifies who the flags are resolved for.
+type Subject struct {
+	UserID   string
+	TenantID string
+}
+
+// Provider resolves the flags of a subject.
+type Provider interface {
+	Flags(ctx context.Context, subject Subject) (map[string]string, error)
+}
+
+// ProviderFunc adapts a function to a Provider.
+type ProviderFunc func(ctx context.Context, subject Subject) (map[string]string, error)
+
+func (fn ProviderFunc) Flags(ctx context.Context, subject Subject) (map[string]string, error) {
+	return fn(ctx, subject)
+}
+
+// Flags holds the resolved feature flags of a request.
+type Flags struct {
+	values map[string]string
+}
+
+// New creates Flags with the given values, e.g. for tests.
+func New(values map[string]string) Flags {
+	return Flags{values: maps.Clone(values)}
+}
+
+// Enabled reports whether the value of the flag is "true".
+func (f Flags) Enabled(name string) bool {
+	return f.values[name] == "true"
+}
+
+// Variant returns the value of the flag, or an empty string if the flag is not set.
+func (f Flags) Variant(name string) string {
+	return f.values[name]
+}
+
+// All returns a copy of all flag values.
+func (f Flags) All() map[string]string {
+	return maps.Clone(f.values)
+}
+
+type flagsKey struct{}
+
+func init() {
+	gum.Register(func(r *http.Request) (Flags, error) {
+		flags, ok := r.Context().Value(flagsKey{}).(Flags)
+		if !ok {
+			return Flags{}, errors.New("request did not pass flags.Middleware")
+		}
+
+		return flags, nil
+	})
+}
+
+// SubjectFunc derives the Subject from a request, e.g. from the authenticated user.
+type SubjectFunc func(r *http.Request) Subject
+
+// Middleware resolves the flags of each request using the provider. If the provider fails,
+// the error is logged and the request continues without any flags, so every flag has its
+// default value of being disabled.
+func Middleware(provider Provider, subjectOf SubjectFunc) gum.Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			values, err := provider.Flags(ctx, subjectOf(r))
+			if err != nil {
+				internal.LoggerOf(ctx).WarnContext(ctx, "Resolve feature flags failed",
+					slog.String("err", err.Error()),
+				)
+			}
+
+			ctx = context.WithValue(ctx, flagsKey{}, Flags{values: values})
+			delegate.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Static returns a Provider that resolves the same flags for every subject.
+func Static(values map[string]string) Provider {
+	values = maps.Clone(values)
+
+	return ProviderFunc(func(ctx context.Context, subject Subject) (map[string]string, error) {
+		return values, nil
+	})
+}
+
+// Rule defines the value of a flag, with overrides for specific tenants and users.
+// A user override takes precedence over a tenant override.
+type Rule struct {
+	Default string            `json:"default"`
+	Tenants map[string]string `json:"tenants,omitempty"`
+	Users   map[string]string `json:"users,omitempty"`
+}
+
+// Resolve returns the value of the flag for the subject.
+func (r Rule) Resolve(subject Subject) string {
+	if value, ok := r.Users[subject.UserID]; ok && subject.UserID != "" {
+		return value
+	}
+
+	if value, ok := r.Tenants[subject.TenantID]; ok && subject.TenantID != "" {
+		return value
+	}
+
+	return r.Default
+}
+
+// Rules returns a Provider resolving the flags using the given rules, keyed by flag name.
+func Rules(rules map[string]Rule) Provider {
+	rules = maps.Clone(rules)
+
+	return ProviderFunc(func(ctx context.Context, subject Subject) (map[string]string, error) {
+		return resolveRules(rules, subject), nil
+	})
+}
+
+func resolveRules(rules map[string]Rule, subject Subject) map[string]string {
+	values := make(map[string]string, len(rules))
+	for name, rule := range rules {
+		values[name] = rule.Resolve(subject)
+	}
+
+	return values
+}
+
+// File returns a Provider reading the rules from a json file, mapping flag names to Rule
+// values. The file is read again when its modification time changes, so flags can be
+// changed without a restart.
+//
+//	{
+//	  "new-layout": {"default": "false", "tenants": {"acme": "true"}},
+//	  "checkout": {"default": "v1", "users": {"42": "v2"}}
+//	}
+func File(path string) Provider {
+	return &fileProvider{path: path}
+}
+
+type fileProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	rules   map[string]Rule
+}
+
+func (p *fileProvider) Flags(ctx context.Context, subject Subject) (map[string]string, error) {
+	rules, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveRules(rules, subject), nil
+}
+
+func (p *fileProvider) load() (map[string]Rule, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stat, err := os.Stat(p.path)
+	if err != nil {
+		return p.rules, fmt.Errorf("stat flags file: %w", err)
+	}
+
+	if p.rules != nil && stat.ModTime().Equal(p.modTime) {
+		return p.rules, nil
+	}
+
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return p.rules, fmt.Errorf("read flags file: %w", err)
+	}
+
+	var rules map[string]Rule
+	if err := json.Unmarshal(content, &rules); err != nil {
+		// keep the last valid rules
+		return p.rules, fmt.Errorf("parse flags file: %w", err)
+	}
+
+	p.rules, p.modTime = rules, stat.ModTime()
+
+	return rules, nil
+}
+
+// sweepInterval is the number of lookups after which expired entries are removed from the cache.
+const sweepInterval = 1024
+
+// RemoteOption configures the Provider returned by Remote.
+type RemoteOption func(p *remoteProvider)
+
+// RefreshInterval sets how long the flags of a subject are cached before the flag service
+// is queried again. Defaults to 30 seconds. Pass zero to query the service for every request.
+func RefreshInterval(interval time.Duration) RemoteOption {
+	return func(p *remoteProvider) {
+		p.refreshInterval = interval
+	}
+}
+
+// Remote returns a Provider querying a remote flag service. It sends a GET request to
+// the url with the query parameters "user" and "tenant" and expects a json object
+// mapping flag names to values. The flags of a subject are cached for the RefreshInterval.
+// If a refresh fails, the last known flags of the subject are used until the next refresh,
+// so an unavailable service is queried at most once per interval and subject.
+func Remote(client *http.Client, serviceURL string, options ...RemoteOption) Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	p := &remoteProvider{
+		client:          client,
+		serviceURL:      serviceURL,
+		refreshInterval: 30 * time.Second,
+		now:             time.Now,
+		cache:           map[Subject]*remoteEntry{},
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+type remoteProvider struct {
+	client          *http.Client
+	serviceURL      string
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu    sync.Mutex
+	cache map[Subject]*remoteEntry
+	calls int
+}
+
+type remoteEntry struct {
+	values    map[string]string
+	fetchedAt time.Time
+}
+
+func (p *remoteProvider) Flags(ctx context.Context, subject Subject) (map[string]string, error) {
+	p.mu.Lock()
+	now := p.now()
+	p.sweep(now)
+	entry, ok := p.cache[subject]
+	p.mu.Unlock()
+
+	if ok && now.Sub(entry.fetchedAt) < p.refreshInterval {
+		return entry.values, nil
+	}
+
+	values, err := p.fetch(ctx, subject)
+	if err != nil && ok {
+		// keep using the last known flags
+		values = entry.values
+	}
+
+	if p.refreshInterval > 0 {
+		p.mu.Lock()
+		p.cache[subject] = &remoteEntry{values: values, fetchedAt: now}
+		p.mu.Unlock()
+	}
+
+	return values, err
+}
+
+// sweep removes entries that have not been refreshed for a while. Must be called with the lock held.
+func (p *remoteProvider) sweep(now time.Time) {
+	p.calls += 1
+	if p.calls%sweepInterval != 0 {
+		return
+	}
+
+	for subject, entry := range p.cache {
+		// stale entries are kept for a while as a fallback if the service fails
+		if now.Sub(entry.fetchedAt) >= 2*p.refreshInterval {
+			delete(p.cache, subject)
+		}
+	}
+}
+
+func (p *remoteProvider) fetch(ctx context.Context, subject Subject) (map[string]string, error) {
+	target, err := url.Parse(p.serviceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	query := target.Query()
+	query.Set("user", subject.UserID)
+	query.Set("tenant", subject.TenantID)
+	target.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query flag service: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("flag service responded with status %d", resp.StatusCode)
+	}
+
+	var values map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&values); err != nil {
+		return nil, fmt.Errorf("decode flags: %w", err)
+	}
+
+	return values, nil
+}
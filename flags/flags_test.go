@@ -0,0 +1,132 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRules(t *testing.T) {
+	rule := Rule{
+		Default: "false",
+		Tenants: map[string]string{"acme": "true"},
+		Users:   map[string]string{"42": "false"},
+	}
+
+	AssertEqual(t, rule.Resolve(Subject{}), "false")
+	AssertEqual(t, rule.Resolve(Subject{TenantID: "acme"}), "true")
+	AssertEqual(t, rule.Resolve(Subject{TenantID: "acme", UserID: "42"}), "false")
+}
+
+func TestMiddleware(t *testing.T) {
+	provider := Rules(map[string]Rule{
+		"new-layout": {Default: "false", Tenants: map[string]string{"acme": "true"}},
+		"checkout":   {Default: "v2"},
+	})
+
+	subjectOf := func(r *http.Request) Subject {
+		return Subject{TenantID: r.Header.Get("X-Tenant")}
+	}
+
+	handler := Middleware(provider, subjectOf)(gum.Handler(func(flags Flags) response.Response {
+		if flags.Enabled("new-layout") {
+			return response.Text("new " + flags.Variant("checkout"))
+		}
+
+		return response.Text("old " + flags.Variant("checkout"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant", "acme")
+	AssertEqual(t, gumtest.Serve(handler, req).Text(), "new v2")
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	AssertEqual(t, gumtest.Serve(handler, req).Text(), "old v2")
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+
+	content, _ := json.Marshal(map[string]Rule{"beta": {Default: "true"}})
+	AssertEqual(t, os.WriteFile(path, content, 0o600), nil)
+
+	values, err := File(path).Flags(context.Background(), Subject{})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, values, map[string]string{"beta": "true"})
+}
+
+func TestRemote(t *testing.T) {
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"user": r.URL.Query().Get("user")})
+	}))
+
+	defer service.Close()
+
+	values, err := Remote(nil, service.URL).Flags(context.Background(), Subject{UserID: "7"})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, values, map[string]string{"user": "7"})
+}
+
+func TestRemoteCache(t *testing.T) {
+	var calls atomic.Int32
+	var failing atomic.Bool
+
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"beta": "true"})
+	}))
+
+	defer service.Close()
+
+	now := time.Unix(1700000000, 0)
+
+	provider := Remote(nil, service.URL, RefreshInterval(time.Minute)).(*remoteProvider)
+	provider.now = func() time.Time { return now }
+
+	subject := Subject{UserID: "7"}
+
+	for range 3 {
+		values, err := provider.Flags(context.Background(), subject)
+		AssertEqual(t, err, nil)
+		AssertEqual(t, values, map[string]string{"beta": "true"})
+	}
+
+	AssertEqual(t, calls.Load(), int32(1))
+
+	// the cached flags are used while the service fails
+	now = now.Add(2 * time.Minute)
+	failing.Store(true)
+
+	values, err := provider.Flags(context.Background(), subject)
+	AssertTrue(t, err != nil)
+	AssertEqual(t, values, map[string]string{"beta": "true"})
+	AssertEqual(t, calls.Load(), int32(2))
+
+	// the failing service is not queried again until the next refresh
+	values, err = provider.Flags(context.Background(), subject)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, values, map[string]string{"beta": "true"})
+	AssertEqual(t, calls.Load(), int32(2))
+
+	// other subjects are cached separately
+	values, err = provider.Flags(context.Background(), Subject{UserID: "8"})
+	AssertTrue(t, err != nil)
+	AssertEqual(t, len(values), 0)
+	AssertEqual(t, calls.Load(), int32(3))
+}
@@ -14,6 +14,8 @@ type FormValues[T any] struct {
 
 var _ = AssertFromRequest[FormValues[any]]()
 
+func (FormValues[T]) consumesBody() bool { return true }
+
 func (FormValues[T]) FromRequest(r *http.Request) (FormValues[T], error) {
 	form, err := Extract[Form](r)
 	if err != nil {
@@ -25,6 +27,10 @@ func (FormValues[T]) FromRequest(r *http.Request) (FormValues[T], error) {
 		return FormValues[T]{}, fmt.Errorf("deserialize %T: %w", target, err)
 	}
 
+	if err := validateValue(target); err != nil {
+		return FormValues[T]{}, err
+	}
+
 	return FormValues[T]{Value: target}, nil
 }
 
@@ -36,6 +42,8 @@ type PostFormValues[T any] struct {
 
 var _ = AssertFromRequest[PostFormValues[any]]()
 
+func (PostFormValues[T]) consumesBody() bool { return true }
+
 func (PostFormValues[T]) FromRequest(r *http.Request) (PostFormValues[T], error) {
 	form, err := Extract[PostForm](r)
 	if err != nil {
@@ -47,5 +55,9 @@ func (PostFormValues[T]) FromRequest(r *http.Request) (PostFormValues[T], error)
 		return PostFormValues[T]{}, fmt.Errorf("deserialize %T: %w", target, err)
 	}
 
+	if err := validateValue(target); err != nil {
+		return PostFormValues[T]{}, err
+	}
+
 	return PostFormValues[T]{Value: target}, nil
 }
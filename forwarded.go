@@ -0,0 +1,196 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedElement is a single element of the Forwarded header as defined in RFC 7239.
+// Each proxy appends one element. Values are unquoted, fields of parameters missing
+// in the element are empty.
+type ForwardedElement struct {
+	// For identifies the node that made the request to the proxy, e.g. "192.0.2.60",
+	// "[2001:db8:cafe::17]:4711", "unknown" or an obfuscated identifier like "_hidden".
+	For string
+
+	// By identifies the interface the proxy received the request on.
+	By string
+
+	// Host is the Host header of the request received by the proxy.
+	Host string
+
+	// Proto is the protocol of the request received by the proxy, e.g. "https".
+	Proto string
+}
+
+// ForAddr returns the ip address of the For node, if it is an ip address.
+func (e ForwardedElement) ForAddr() (netip.Addr, bool) {
+	return forwardedNodeAddr(e.For)
+}
+
+// ByAddr returns the ip address of the By node, if it is an ip address.
+func (e ForwardedElement) ByAddr() (netip.Addr, bool) {
+	return forwardedNodeAddr(e.By)
+}
+
+// Forwarded holds the elements of all Forwarded headers of the request. The first element
+// was added by the proxy closest to the client, the last one by the proxy closest to the
+// server. The header can be set by any client, only trust the elements that were added
+// by your own proxies. Extraction fails with 400 Bad Request if the header is invalid.
+type Forwarded []ForwardedElement
+
+func init() {
+	Register(func(r *http.Request) (Forwarded, error) {
+		forwarded, err := parseForwarded(r.Header.Values("Forwarded"))
+		if err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, fmt.Errorf("parse Forwarded header: %w", err))
+		}
+
+		return forwarded, nil
+	})
+}
+
+func parseForwarded(values []string) (Forwarded, error) {
+	var forwarded Forwarded
+
+	for _, value := range values {
+		for _, element := range splitQuoted(value, ',') {
+			if strings.TrimSpace(element) == "" {
+				continue
+			}
+
+			parsed, err := parseForwardedElement(element)
+			if err != nil {
+				return nil, err
+			}
+
+			forwarded = append(forwarded, parsed)
+		}
+	}
+
+	return forwarded, nil
+}
+
+func parseForwardedElement(element string) (ForwardedElement, error) {
+	var parsed ForwardedElement
+
+	seen := map[string]bool{}
+
+	for _, pair := range splitQuoted(element, ';') {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return ForwardedElement{}, fmt.Errorf("invalid pair %q", pair)
+		}
+
+		name = strings.ToLower(name)
+		if seen[name] {
+			return ForwardedElement{}, fmt.Errorf("parameter %q occurs more than once", name)
+		}
+
+		seen[name] = true
+
+		value, err := unquoteForwarded(value)
+		if err != nil {
+			return ForwardedElement{}, fmt.Errorf("parameter %q: %w", name, err)
+		}
+
+		switch name {
+		case "for":
+			parsed.For = value
+		case "by":
+			parsed.By = value
+		case "host":
+			parsed.Host = value
+		case "proto":
+			parsed.Proto = strings.ToLower(value)
+		}
+	}
+
+	return parsed, nil
+}
+
+// splitQuoted splits value at sep, ignoring separators within quoted strings.
+func splitQuoted(value string, sep byte) []string {
+	var parts []string
+
+	var quoted, escaped bool
+	start := 0
+
+	for idx := 0; idx < len(value); idx++ {
+		c := value[idx]
+
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == sep:
+			parts = append(parts, value[start:idx])
+			start = idx + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
+
+// unquoteForwarded returns the value of a token or a quoted string.
+func unquoteForwarded(value string) (string, error) {
+	if !strings.HasPrefix(value, `"`) {
+		if value == "" || strings.ContainsAny(value, "\" \t\\") {
+			return "", fmt.Errorf("invalid token %q", value)
+		}
+
+		return value, nil
+	}
+
+	if len(value) < 2 || !strings.HasSuffix(value, `"`) {
+		return "", errors.New("unterminated quoted string")
+	}
+
+	var unquoted strings.Builder
+
+	inner := value[1 : len(value)-1]
+	for idx := 0; idx < len(inner); idx++ {
+		c := inner[idx]
+
+		if c == '\\' {
+			idx++
+			if idx == len(inner) {
+				return "", errors.New("invalid escape in quoted string")
+			}
+
+			c = inner[idx]
+		} else if c == '"' {
+			return "", errors.New("unescaped quote in quoted string")
+		}
+
+		unquoted.WriteByte(c)
+	}
+
+	return unquoted.String(), nil
+}
+
+// forwardedNodeAddr parses the ip address of a node like "192.0.2.60:47011" or "[2001:db8::1]".
+func forwardedNodeAddr(node string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(node); err == nil {
+		return addrPort.Addr(), true
+	}
+
+	host := node
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+
+	addr, err := netip.ParseAddr(host)
+	return addr, err == nil
+}
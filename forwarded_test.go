@@ -0,0 +1,77 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestParseForwarded(t *testing.T) {
+	// examples from RFC 7239
+	forwarded, err := parseForwarded([]string{
+		`for="_gazonk"`,
+		`For="[2001:db8:cafe::17]:4711"`,
+		`for=192.0.2.60;proto=http;by=203.0.113.43`,
+		`for=192.0.2.43, for=198.51.100.17`,
+	})
+
+	AssertEqual(t, err, nil)
+	AssertEqual(t, forwarded, Forwarded{
+		{For: "_gazonk"},
+		{For: "[2001:db8:cafe::17]:4711"},
+		{For: "192.0.2.60", Proto: "http", By: "203.0.113.43"},
+		{For: "192.0.2.43"},
+		{For: "198.51.100.17"},
+	})
+
+	// separators within quoted strings
+	forwarded, err = parseForwarded([]string{`for="a,b;c";host="example.com:8080"`})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, forwarded, Forwarded{{For: "a,b;c", Host: "example.com:8080"}})
+
+	forwarded, err = parseForwarded(nil)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, len(forwarded), 0)
+
+	for _, invalid := range []string{`for`, `for="unterminated`, `for=a;for=b`, `for=a b`, `for=`} {
+		_, err := parseForwarded([]string{invalid})
+		AssertTrue(t, err != nil)
+	}
+}
+
+func TestForwardedElementAddr(t *testing.T) {
+	addr, ok := ForwardedElement{For: "[2001:db8:cafe::17]:4711"}.ForAddr()
+	AssertTrue(t, ok)
+	AssertEqual(t, addr, netip.MustParseAddr("2001:db8:cafe::17"))
+
+	addr, ok = ForwardedElement{For: "[2001:db8:cafe::17]"}.ForAddr()
+	AssertTrue(t, ok)
+	AssertEqual(t, addr, netip.MustParseAddr("2001:db8:cafe::17"))
+
+	addr, ok = ForwardedElement{By: "192.0.2.60"}.ByAddr()
+	AssertTrue(t, ok)
+	AssertEqual(t, addr, netip.MustParseAddr("192.0.2.60"))
+
+	_, ok = ForwardedElement{For: "unknown"}.ForAddr()
+	AssertTrue(t, !ok)
+}
+
+func TestForwardedExtractor(t *testing.T) {
+	var extracted Forwarded
+
+	handler := Handler(func(forwarded Forwarded) { extracted = forwarded })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Forwarded", "for=192.0.2.60;proto=https")
+
+	rec := gumtest.Serve(handler, req)
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertEqual(t, extracted, Forwarded{{For: "192.0.2.60", Proto: "https"}})
+
+	req.Header.Set("Forwarded", "for")
+	rec = gumtest.Serve(handler, req)
+	AssertEqual(t, rec.StatusCode, http.StatusBadRequest)
+}
@@ -2,11 +2,14 @@ package gum
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/go-gum/gum/internal"
+	"io"
 	"log/slog"
 	"net/http"
 	"reflect"
+	"sync"
 )
 
 type Middleware = func(delegate http.Handler) http.Handler
@@ -27,6 +30,13 @@ func (ContextValue[T]) FromRequest(r *http.Request) (ContextValue[T], error) {
 		return ContextValue[T]{}, fmt.Errorf("no value of type %q in context", key)
 	}
 
+	if lazy, ok := value.(lazyContextValue); ok {
+		var err error
+		if value, err = lazy.resolve(r); err != nil {
+			return ContextValue[T]{}, fmt.Errorf("provide %s: %w", key, err)
+		}
+	}
+
 	valueT, ok := value.(T)
 	if !ok {
 		return ContextValue[T]{}, fmt.Errorf("expected value of type %q, got %T", key, value)
@@ -48,6 +58,129 @@ func ProvideContextValue[T any](value T) Middleware {
 	}
 }
 
+// Provide provides a Middleware that computes a value of type T once per request and
+// injects it into the requests context, e.g. the tenant or the authenticated principal.
+// The value can later be extracted by using ContextValue. If fn fails, the request is
+// rejected with 400 Bad Request, or the status code of an HTTPError returned by fn.
+func Provide[T any](fn func(r *http.Request) (T, error)) Middleware {
+	key := reflect.TypeFor[T]()
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			value, err := fn(request)
+			if err != nil {
+				err = fmt.Errorf("provide %s: %w", key, err)
+				errorResponse(err, http.StatusBadRequest).ServeHTTP(writer, request)
+				return
+			}
+
+			ctx := context.WithValue(request.Context(), key, value)
+			request = request.WithContext(ctx)
+			delegate.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// ProvideContextValueFunc provides a Middleware that injects a lazily constructed value of
+// type T into the requests context. Unlike Provide, fn is only called when the value is
+// extracted using ContextValue, at most once per request. Use it for values that are
+// expensive to build and not needed by every handler, like a database session:
+//
+//	gum.ProvideContextValueFunc(func(r *http.Request) (*sql.Conn, error) {
+//		return db.Conn(r.Context())
+//	})
+//
+// The result of fn, including an error, is cached for the rest of the request. If fn fails,
+// the extraction fails with its error. If the value implements io.Closer, it is closed once
+// the request was handled, so the connection of the example is returned to the pool.
+func ProvideContextValueFunc[T any](fn func(r *http.Request) (T, error)) Middleware {
+	key := reflect.TypeFor[T]()
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			lazy := &lazyValue[T]{fn: fn}
+			defer lazy.close(request.Context())
+
+			ctx := context.WithValue(request.Context(), key, lazyContextValue(lazy))
+			request = request.WithContext(ctx)
+			delegate.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// contextValueOf looks up the value of type T in the requests context, resolving a value provided
+// by ProvideContextValueFunc. A lazy value that can not be resolved is treated as missing.
+func contextValueOf[T any](r *http.Request) (T, bool) {
+	value := r.Context().Value(reflect.TypeFor[T]())
+
+	if lazy, ok := value.(lazyContextValue); ok {
+		var err error
+		if value, err = lazy.resolve(r); err != nil {
+			var tZero T
+			return tZero, false
+		}
+	}
+
+	valueT, ok := value.(T)
+	return valueT, ok
+}
+
+// lazyContextValue is stored in the context by ProvideContextValueFunc
+// in place of the value itself.
+type lazyContextValue interface {
+	resolve(r *http.Request) (any, error)
+}
+
+type lazyValue[T any] struct {
+	once sync.Once
+	fn   func(r *http.Request) (T, error)
+
+	value T
+	err   error
+}
+
+// resolve calls fn with the request doing the extraction, so fn sees its overrides and
+// injected values. The resolution is recorded on the extraction stack of r: if fn needs
+// the value it is resolving, resolve fails with ErrExtractorCycle instead of waiting for itself.
+func (l *lazyValue[T]) resolve(r *http.Request) (any, error) {
+	stack := extractionStackOf(r)
+	if stack == nil {
+		r = r.WithContext(withExtractionStack(r.Context()))
+		stack = extractionStackOf(r)
+	}
+
+	if err := stack.push(reflect.TypeFor[lazyValue[T]]()); err != nil {
+		return nil, err
+	}
+
+	defer stack.pop()
+
+	l.once.Do(func() {
+		l.value, l.err = l.fn(r)
+	})
+
+	return l.value, l.err
+}
+
+// close closes the value, if it was resolved and implements io.Closer.
+// The value can not be resolved anymore after calling close.
+func (l *lazyValue[T]) close(ctx context.Context) {
+	l.once.Do(func() {
+		l.err = errors.New("request was already handled")
+	})
+
+	if l.err != nil {
+		return
+	}
+
+	if closer, ok := any(l.value).(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			internal.LoggerOf(ctx).WarnContext(ctx, "Call Close() on context value failed",
+				slog.String("type", reflect.TypeFor[T]().String()),
+				slog.String("err", err.Error()),
+			)
+		}
+	}
+}
+
 // ContextValueExtractor returns a gum.Extractor that extracts a value of type T
 // from the context.Context that was previous provided using ProvideContextValue.
 func ContextValueExtractor[T any]() Extractor[T] {
@@ -57,19 +190,25 @@ func ContextValueExtractor[T any]() Extractor[T] {
 	}
 }
 
-// JSON parses the requests body as json
+// JSON parses the requests body as json. The decoder can be configured using JSONOptions.
 type JSON[T any] struct {
 	Value T
 }
 
 var _ = AssertFromRequest[JSON[any]]()
 
+func (JSON[T]) consumesBody() bool { return true }
+
 func (JSON[T]) FromRequest(r *http.Request) (JSON[T], error) {
 	var value T
-	if err := json.NewDecoder(r.Body).Decode(&value); err != nil {
+	if err := decodeJSON(r, &value); err != nil {
 		return JSON[T]{}, fmt.Errorf("deserialize %T: %w", value, err)
 	}
 
+	if err := validateValue(value); err != nil {
+		return JSON[T]{}, err
+	}
+
 	return JSON[T]{Value: value}, nil
 }
 
@@ -88,6 +227,8 @@ func (o Try[T]) Get() (T, error) {
 	return o.Value, o.Error
 }
 
+func (Try[T]) consumesBody() bool { return consumesBodyOf[T]() }
+
 func (Try[T]) FromRequest(r *http.Request) (Try[T], error) {
 	tValue, err := Extract[T](r)
 	if err != nil {
@@ -129,11 +270,13 @@ func (o Option[T]) GetOrZero() T {
 	return o.GetOr(zero)
 }
 
+func (Option[T]) consumesBody() bool { return consumesBodyOf[T]() }
+
 func (Option[T]) FromRequest(r *http.Request) (Option[T], error) {
 	try, err := Extract[Try[T]](r)
 	if err != nil {
-		// Extracting a Try must never fail
-		panic(err)
+		// extracting a Try only fails if it is part of an extractor cycle
+		return Option[T]{}, err
 	}
 
 	if try.Error != nil {
@@ -149,6 +292,9 @@ func (Option[T]) FromRequest(r *http.Request) (Option[T], error) {
 	return result, nil
 }
 
+// Logger provides a *slog.Logger for the current request. It is derived from the logger
+// provided using ProvideLogger or WithLogger and falls back to slog.Default.
+// Attributes with a key listed in the requests Redaction are redacted, see RedactHandler.
 type Logger struct {
 	ctx context.Context
 	*slog.Logger
@@ -159,7 +305,9 @@ var _ = AssertFromRequest[Logger]()
 func (l Logger) FromRequest(r *http.Request) (Logger, error) {
 	ctx := r.Context()
 
-	log := slog.With(slog.String("path", r.URL.Path))
+	handler := RedactHandler(internal.LoggerOf(ctx).Handler(), redactionOf(r))
+
+	log := slog.New(handler).With(slog.String("path", r.URL.Path))
 	log.DebugContext(ctx, "Request started")
 	return Logger{ctx: ctx, Logger: log}, nil
 }
@@ -168,3 +316,14 @@ func (l Logger) Close() error {
 	l.DebugContext(l.ctx, "Request finished")
 	return nil
 }
+
+// ProvideLogger provides a Middleware that sets the logger to use for all requests
+// passing through it. The logger is used by Handler, the response package and the Logger extractor.
+func ProvideLogger(logger *slog.Logger) Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := internal.WithLogger(request.Context(), logger)
+			delegate.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
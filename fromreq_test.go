@@ -2,9 +2,15 @@ package gum
 
 import (
 	"bytes"
+	"errors"
+	"github.com/go-gum/gum/gumtest"
 	. "github.com/go-gum/gum/internal/test"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -71,9 +77,8 @@ func TestJSONParseError(t *testing.T) {
 
 	type BodyStruct struct{ Foo string }
 
-	var rw responseWriter
-	Handler(func(v JSON[BodyStruct]) { t.FailNow() }).ServeHTTP(&rw, req)
-	AssertEqual(t, rw.statusCode, http.StatusBadRequest)
+	rw := gumtest.Serve(Handler(func(v JSON[BodyStruct]) { t.FailNow() }), req)
+	AssertEqual(t, rw.StatusCode, http.StatusBadRequest)
 }
 
 func TestContentValue(t *testing.T) {
@@ -88,3 +93,157 @@ func TestContentValue(t *testing.T) {
 	provideValue(handler).ServeHTTP(nil, req)
 	AssertEqual(t, extractedValue, MyValue("foo bar"))
 }
+
+func TestProvide(t *testing.T) {
+	type Tenant string
+
+	provideTenant := Provide(func(r *http.Request) (Tenant, error) {
+		tenant := r.Header.Get("X-Tenant")
+		if tenant == "" {
+			return "", NewHTTPError(http.StatusUnauthorized, errors.New("no tenant"))
+		}
+
+		return Tenant(tenant), nil
+	})
+
+	var extractedValue Tenant
+	handler := provideTenant(Handler(func(v ContextValue[Tenant]) { extractedValue = v.Value }))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant", "acme")
+	rw := gumtest.Serve(handler, req)
+	AssertEqual(t, rw.StatusCode, http.StatusOK)
+	AssertEqual(t, extractedValue, Tenant("acme"))
+
+	rw = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rw.StatusCode, http.StatusUnauthorized)
+}
+
+func TestProvideContextValueFunc(t *testing.T) {
+	type Session struct {
+		ID int
+	}
+
+	var calls int
+	provideSession := ProvideContextValueFunc(func(r *http.Request) (*Session, error) {
+		if r.Header.Get("X-Fail") != "" {
+			return nil, NewHTTPError(http.StatusServiceUnavailable, errors.New("database down"))
+		}
+
+		calls++
+		return &Session{ID: calls}, nil
+	})
+
+	var sessions []*Session
+	handler := provideSession(Handler(func(first ContextValue[*Session], second ContextValue[*Session]) {
+		sessions = append(sessions, first.Value, second.Value)
+	}))
+
+	rw := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rw.StatusCode, http.StatusOK)
+
+	// the value is constructed once per request
+	AssertEqual(t, calls, 1)
+	AssertTrue(t, sessions[0] == sessions[1])
+
+	_ = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, calls, 2)
+	AssertEqual(t, sessions[2].ID, 2)
+
+	// handlers that do not extract the value do not construct it
+	unused := provideSession(Handler(func() {}))
+	_ = gumtest.Serve(unused, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, calls, 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Fail", "true")
+
+	rw = gumtest.Serve(handler, req)
+	AssertEqual(t, rw.StatusCode, http.StatusServiceUnavailable)
+}
+
+func TestProvideContextValueFuncExtractingRequest(t *testing.T) {
+	provideHost := ProvideContextValueFunc(func(r *http.Request) (string, error) {
+		host, err := Extract[Host](r)
+		return string(host), err
+	})
+
+	override := Override(func(r *http.Request) (Host, error) {
+		return "stub.example.com", nil
+	})
+
+	var extractedValue string
+	handler := provideHost(Handler(func(host ContextValue[string]) {
+		extractedValue = host.Value
+	}, WithOverrides(override)))
+
+	// fn sees the overrides of the handler doing the extraction
+	rw := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rw.StatusCode, http.StatusOK)
+	AssertEqual(t, extractedValue, "stub.example.com")
+}
+
+type selfProvided struct{}
+
+func TestProvideContextValueFuncCycle(t *testing.T) {
+	provideSelf := ProvideContextValueFunc(func(r *http.Request) (selfProvided, error) {
+		value, err := Extract[ContextValue[selfProvided]](r)
+		return value.Value, err
+	})
+
+	handler := provideSelf(Handler(func(value ContextValue[selfProvided]) {}))
+
+	// fails instead of waiting for itself
+	rw := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rw.StatusCode, http.StatusInternalServerError)
+	AssertTrue(t, strings.Contains(rw.Text(), ErrExtractorCycle.Error()))
+}
+
+type closeRecorder struct {
+	closed int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestProvideContextValueFuncClose(t *testing.T) {
+	var conns []*closeRecorder
+	provideConn := ProvideContextValueFunc(func(r *http.Request) (*closeRecorder, error) {
+		conn := &closeRecorder{}
+		conns = append(conns, conn)
+		return conn, nil
+	})
+
+	var closedDuringHandler int
+	handler := provideConn(Handler(func(conn ContextValue[*closeRecorder]) {
+		closedDuringHandler = conn.Value.closed
+	}))
+
+	_ = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, closedDuringHandler, 0)
+	AssertEqual(t, len(conns), 1)
+	AssertEqual(t, conns[0].closed, 1)
+
+	// values that were never resolved are not constructed just to be closed
+	_ = gumtest.Serve(provideConn(Handler(func() {})), httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, len(conns), 1)
+}
+
+func TestLoggerProvided(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	req := &http.Request{URL: &url.URL{Path: "/foo"}}
+
+	handler := Handler(func(log Logger) { log.Info("hello") })
+	ProvideLogger(logger)(handler).ServeHTTP(nil, req)
+	AssertTrue(t, strings.Contains(buf.String(), "msg=hello path=/foo"))
+
+	buf.Reset()
+
+	handler = Handler(func(log Logger) { log.Info("hello") }, WithLogger(logger))
+	handler.ServeHTTP(nil, req)
+	AssertTrue(t, strings.Contains(buf.String(), "msg=hello path=/foo"))
+}
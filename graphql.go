@@ -0,0 +1,117 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// GraphQLRequest extracts a GraphQL request as described by the GraphQL-over-HTTP
+// specification. The request is read from the query parameters of a GET request, or
+// from the body of a POST request, either as application/json or as application/graphql
+// with the query as the raw body. Like the JSON extractor, the size of the body is
+// limited by JSONOptions.MaxBytes.
+//
+// The variables are decoded into V, use map[string]any for untyped variables:
+//
+//	type UserVariables struct {
+//		ID int `json:"id"`
+//	}
+//
+//	func handleGraphQL(req gum.GraphQLRequest[UserVariables]) response.Response {
+//		result := schema.Execute(req.Query, req.OperationName, req.Variables)
+//		return response.JSON(result)
+//	}
+type GraphQLRequest[V any] struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     V              `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
+
+var _ = AssertFromRequest[GraphQLRequest[any]]()
+
+// ErrNoGraphQLQuery is returned if a GraphQL request does not contain a query.
+var ErrNoGraphQLQuery = errors.New("graphql request has no query")
+
+func (GraphQLRequest[V]) consumesBody() bool { return true }
+
+func (GraphQLRequest[V]) FromRequest(r *http.Request) (GraphQLRequest[V], error) {
+	var req GraphQLRequest[V]
+
+	switch r.Method {
+	case http.MethodGet:
+		if err := req.fromQuery(r); err != nil {
+			return GraphQLRequest[V]{}, err
+		}
+
+	case http.MethodPost:
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		switch mediaType {
+		case "application/json":
+			if err := decodeJSON(r, &req); err != nil {
+				return GraphQLRequest[V]{}, fmt.Errorf("deserialize graphql request: %w", err)
+			}
+
+		case "application/graphql":
+			query, err := io.ReadAll(jsonOptionsOf(r).limitBody(r))
+			if err != nil {
+				return GraphQLRequest[V]{}, bodyTooLarge(fmt.Errorf("read graphql query: %w", err))
+			}
+
+			// operationName and variables may still be given as query parameters
+			if err := req.fromQuery(r); err != nil && !errors.Is(err, ErrNoGraphQLQuery) {
+				return GraphQLRequest[V]{}, err
+			}
+
+			req.Query = string(query)
+
+		default:
+			return GraphQLRequest[V]{}, NewHTTPError(http.StatusUnsupportedMediaType,
+				fmt.Errorf("unsupported graphql content type %q", mediaType))
+		}
+
+	default:
+		return GraphQLRequest[V]{}, NewHTTPError(http.StatusMethodNotAllowed,
+			fmt.Errorf("graphql request with method %s", r.Method))
+	}
+
+	if req.Query == "" {
+		return GraphQLRequest[V]{}, ErrNoGraphQLQuery
+	}
+
+	if err := validateValue(req.Variables); err != nil {
+		return GraphQLRequest[V]{}, err
+	}
+
+	return req, nil
+}
+
+func (req *GraphQLRequest[V]) fromQuery(r *http.Request) error {
+	query := r.URL.Query()
+
+	req.OperationName = query.Get("operationName")
+
+	if variables := query.Get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			return fmt.Errorf("deserialize graphql variables: %w", err)
+		}
+	}
+
+	if extensions := query.Get("extensions"); extensions != "" {
+		if err := json.Unmarshal([]byte(extensions), &req.Extensions); err != nil {
+			return fmt.Errorf("deserialize graphql extensions: %w", err)
+		}
+	}
+
+	req.Query = query.Get("query")
+	if req.Query == "" {
+		return ErrNoGraphQLQuery
+	}
+
+	return nil
+}
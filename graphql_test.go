@@ -0,0 +1,83 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLRequest(t *testing.T) {
+	type Variables struct {
+		ID int `json:"id"`
+	}
+
+	fn := func(req GraphQLRequest[Variables]) response.Response {
+		return response.Text(req.OperationName + ":" + strconv.Itoa(req.Variables.ID) + ":" + req.Query)
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		query := url.Values{
+			"query":         {"query User { user(id: $id) { name } }"},
+			"operationName": {"User"},
+			"variables":     {`{"id": 12}`},
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+		AssertEqual(t, resp.Text(), "User:12:query User { user(id: $id) { name } }")
+	})
+
+	t.Run("POSTJSON", func(t *testing.T) {
+		body := `{"query": "{ me }", "operationName": "Me", "variables": {"id": 3}}`
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+		AssertEqual(t, resp.Text(), "Me:3:{ me }")
+	})
+
+	t.Run("POSTGraphQL", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/graphql?operationName=Me", strings.NewReader("{ me }"))
+		req.Header.Set("Content-Type", "application/graphql")
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+		AssertEqual(t, resp.Text(), "Me:0:{ me }")
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		handler := ProvideContextValue(JSONOptions{MaxBytes: 8})(Handler(fn))
+
+		for _, contentType := range []string{"application/graphql", "application/json"} {
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ me { name } }"}`))
+			req.Header.Set("Content-Type", contentType)
+
+			resp := gumtest.Serve(handler, req)
+			AssertEqual(t, resp.StatusCode, http.StatusRequestEntityTooLarge)
+		}
+	})
+
+	t.Run("NoQuery", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+	})
+
+	t.Run("UnsupportedMediaType", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("query={ me }"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp := gumtest.Serve(Handler(fn), req)
+		AssertEqual(t, resp.StatusCode, http.StatusUnsupportedMediaType)
+	})
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/go-gum/gum/internal"
 	"github.com/go-gum/gum/response"
 	"io"
 	"log/slog"
 	"net/http"
 	"reflect"
+	"runtime/debug"
 	"sync"
+	"time"
 )
 
 // FromRequest defines a method that extracts a T from a http.Request.
@@ -48,9 +51,47 @@ func AssertFromRequest[T FromRequest[T]]() T {
 // implement the FromRequest interface, or have an Extractor registered using
 // the Register function.
 //
+// Extract can be called from within FromRequest methods and registered extractors.
+// If the extraction of T requires extracting T again, Extract fails with an error
+// wrapping ErrExtractorCycle instead of recursing forever. Cycles are detected for
+// requests that were passed in by a Handler.
+//
 // TODO document error, maybe panic
 func Extract[T any](r *http.Request) (T, error) {
-	rValue, err := extractorOf(reflect.TypeFor[T]())(r)
+	ty := reflect.TypeFor[T]()
+
+	if stack := extractionStackOf(r); stack != nil {
+		if err := stack.push(ty); err != nil {
+			var tNil T
+			return tNil, err
+		}
+
+		defer stack.pop()
+	}
+
+	ex, ok := overrideOf(r, ty)
+	if !ok {
+		// fast paths without any reflection
+		if fn, ok := typedExtractors.Load(ty); ok {
+			return fn.(Extractor[T])(r)
+		}
+
+		if ty.Kind() != reflect.Pointer {
+			var zero T
+			if fromRequest, ok := any(zero).(FromRequest[T]); ok {
+				value, err := fromRequest.FromRequest(r)
+				if err != nil {
+					return value, fmt.Errorf("extract %q: %w", ty, err)
+				}
+
+				return value, nil
+			}
+		}
+
+		ex, _ = extractorOf(ty)
+	}
+
+	rValue, err := ex(r)
 	if err != nil {
 		var tNil T
 		return tNil, err
@@ -62,6 +103,10 @@ func Extract[T any](r *http.Request) (T, error) {
 // Stores a mapping from reflect.TypeFor[T] to a Extractor[T]
 var extractors sync.Map
 
+// Stores a mapping from reflect.TypeFor[T] to the Extractor[T] passed to Register,
+// used by Extract to call the extractor without reflection.
+var typedExtractors sync.Map
+
 // Extractor extracts a T from a request. This should be used for non
 // generic types. Implement FromRequest for type T if T itself is generic.
 type Extractor[T any] func(r *http.Request) (T, error)
@@ -74,8 +119,13 @@ type extractor Extractor[reflect.Value]
 // This method is threadsafe.
 func Register[T any](fn Extractor[T]) {
 	ty := reflect.TypeFor[T]()
+	extractors.Store(ty, reflectExtractor(fn))
+	typedExtractors.Store(ty, fn)
+}
 
-	ex := func(request *http.Request) (reflect.Value, error) {
+// reflectExtractor converts an Extractor[T] into an extractor
+func reflectExtractor[T any](fn Extractor[T]) extractor {
+	return func(request *http.Request) (reflect.Value, error) {
 		value, err := fn(request)
 		if err != nil {
 			return reflect.Value{}, err
@@ -83,8 +133,6 @@ func Register[T any](fn Extractor[T]) {
 
 		return reflect.ValueOf(value), nil
 	}
-
-	extractors.Store(ty, extractor(ex))
 }
 
 // Handler adapts a gum handler into an http.Handler. If for any of the handlers parameters
@@ -96,7 +144,19 @@ func Register[T any](fn Extractor[T]) {
 //   - a single error value
 //   - a single value that implements http.Handler
 //   - a value that implements http.Handler and an error value
-func Handler(f any) http.Handler {
+//   - a Responder, optionally followed by an error value
+//   - an int status code and a value, optionally followed by an error value
+//
+// A value returned together with a status code is encoded according to the requests
+// Accept header, see response.Encoded. A nil value results in a response without a body:
+//
+//	func createUser(body gum.JSON[User]) (int, User, error) {
+//		user, err := store.Create(body.Value)
+//		return http.StatusCreated, user, err
+//	}
+//
+// The behaviour of the Handler can be customized using HandlerOption values.
+func Handler(f any, options ...HandlerOption) http.Handler {
 	fn := reflect.ValueOf(f)
 	fnType := fn.Type()
 
@@ -105,51 +165,190 @@ func Handler(f any) http.Handler {
 		panic(fmt.Errorf("expected Func, got %q", fn.Type()))
 	}
 
+	var config handlerConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	description := HandlerDescription{Func: fnType, Parallel: config.parallel}
+
 	// build one extractor per argument
 	var extractors []extractor
+	var serial []bool
+
 	for idx := range fnType.NumIn() {
-		extractors = append(extractors, extractorOf(fnType.In(idx)))
+		ty := fnType.In(idx)
+
+		origin := OriginOverride
+
+		ex, ok := config.overrides[ty]
+		if !ok {
+			ex, origin = extractorOf(ty)
+		}
+
+		description.Parameters = append(description.Parameters, ParameterDescription{
+			Index:  idx,
+			Type:   ty,
+			Origin: origin,
+		})
+
+		extractors = append(extractors, instrumentExtractor(ex, ty, config.hooks))
+		serial = append(serial, consumesBody(ty))
+	}
+
+	// used instead of extractors if the request carries an ExtractionTrace
+	tracedExtractors := traceExtractors(extractors, fnType)
+
+	// reuse the parameter slices between requests
+	paramsPool := sync.Pool{
+		New: func() any {
+			params := make([]reflect.Value, 0, len(extractors))
+			return &params
+		},
 	}
 
+	// only record the response if some hook is interested
+	recordResponse := hasOnWrite(config.hooks)
+
 	// build an output mapper
 	mapOutputs := mapOutputsOf(fnType)
+	describeOutputs(&description, fnType)
+
+	errorEncoder := config.errorEncoder
+	if errorEncoder == nil {
+		errorEncoder = response.Error
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// make the matched pattern visible to middlewares like AccessLog
+		internal.RecordPattern(r)
+
+		// track if the response was committed when the handler function panics
+		rw := internal.NewRecordingWriter(w)
+		w = rw
+
+		if recordResponse {
+			defer func() { callOnWrite(config.hooks, r, rw.StatusCode(), rw.BytesWritten()) }()
+		}
+
+		ctx := r.Context()
 
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// TODO do we want to keep this?
 		// inject the ResponseWriter into the requests context so
 		// an Extractor can extract it if needed
-		ctx := context.WithValue(r.Context(), reflect.TypeFor[http.ResponseWriter](), w)
-		r = r.WithContext(ctx)
+		ctx = context.WithValue(ctx, responseWriterKey{}, w)
 
-		var params []reflect.Value
+		if config.logger != nil {
+			ctx = internal.WithLogger(ctx, config.logger)
+		}
+
+		if config.jsonOptions != nil {
+			ctx = context.WithValue(ctx, tyJSONOptions, *config.jsonOptions)
+		}
+
+		if len(config.overrides) > 0 {
+			// make overrides visible to nested calls to Extract
+			ctx = context.WithValue(ctx, overridesKey{}, config.overrides)
+		}
+
+		// detect cycles in nested calls to Extract
+		ctx = withExtractionStack(ctx)
+
+		if ctx != r.Context() {
+			r = r.WithContext(ctx)
+		}
+
+		pooled := paramsPool.Get().(*[]reflect.Value)
+
+		defer func() {
+			// do not keep the values of this request alive, the extracted
+			// params live in the backing array beyond the length of *pooled
+			clear((*pooled)[:cap(*pooled)])
+			paramsPool.Put(pooled)
+		}()
+
+		extractors := extractors
+		if ExtractionTraceOf(r) != nil {
+			extractors = tracedExtractors
+		}
 
 		// extract all values into the params array
-		for idx, extractor := range extractors {
-			param, err := extractor(r)
-			if err != nil {
-				// TODO handle Extractor errors
-				err = fmt.Errorf("extract parameter %d of %q: %w", idx, fnType, err)
-				response.
-					Error(err, http.StatusBadRequest).
-					ServeHTTP(w, r)
+		var params []reflect.Value
+		var idx int
+		var err error
 
-				return
-			}
+		if config.parallel {
+			// the context is canceled as soon as the first extractor fails
+			var cancel context.CancelCauseFunc
+			ctx, cancel = context.WithCancelCause(ctx)
+			defer cancel(nil)
+
+			r = r.WithContext(ctx)
+			params, idx, err = extractParallel(r, cancel, extractors, serial, *pooled)
+		} else {
+			params, idx, err = extractSerial(r, extractors, *pooled)
+		}
 
-			params = append(params, param)
+		if err != nil {
+			// close all values we have extracted so far
+			closeParams(ctx, fnType, params)
+
+			// TODO handle Extractor errors
+			err = fmt.Errorf("extract parameter %d of %q: %w", idx, fnType, err)
+			errorResponseWith(errorEncoder, err, http.StatusBadRequest).ServeHTTP(w, r)
+
+			return
+		}
+
+		if config.checkCtx >= checkContextBeforeHandle && r.Context().Err() != nil {
+			// nobody is waiting for the result anymore, skip the handler function
+			closeParams(ctx, fnType, params)
+			contextErrorResponse(errorEncoder, r.Context()).ServeHTTP(w, r)
+			return
 		}
 
 		// call the handler function with the collected parameters
-		outputs := fn.Call(params)
+		startTime := time.Now()
+		outputs, panicked, stack := callRecover(fn, params)
+
+		if panicked != nil {
+			err := fmt.Errorf("handler function panicked: %v", panicked)
+			callOnHandle(config.hooks, r, time.Since(startTime), err)
+
+			if panicked == http.ErrAbortHandler {
+				// the handler wants to abort the response, let net/http handle it
+				closeParams(ctx, fnType, params)
+				panic(panicked)
+			}
+
+			internal.LoggerOf(ctx).ErrorContext(ctx, "Handler function panicked",
+				slog.String("panic", fmt.Sprint(panicked)),
+				slog.String("stack", string(stack)),
+				slog.Bool("committed", rw.Committed()),
+			)
+
+			if !rw.Committed() {
+				// an error response would be appended to the partial response
+				errorResponseWith(errorEncoder, err, http.StatusInternalServerError).ServeHTTP(w, r)
+			}
+
+			closeParams(ctx, fnType, params)
+
+			return
+		}
 
 		// map the generic output values
 		result, err := mapOutputs(outputs)
+		callOnHandle(config.hooks, r, time.Since(startTime), err)
+
+		if config.checkCtx >= checkContextAfterHandle && r.Context().Err() != nil {
+			discardResult(ctx, result, err)
+			result, err = contextErrorResponse(errorEncoder, r.Context()), nil
+		}
+
 		switch {
 		case err != nil:
 			// TODO handle Handler errors
-			response.
-				Error(err, http.StatusInternalServerError).
-				ServeHTTP(w, r)
+			errorResponseWith(errorEncoder, err, http.StatusInternalServerError).ServeHTTP(w, r)
 
 		case result != nil:
 			result.ServeHTTP(w, r)
@@ -157,19 +356,79 @@ func Handler(f any) http.Handler {
 
 		// if any of the actual parameters implement io.Closer, the
 		// close function will be called now
-		for idx, param := range params {
-			if closer, ok := param.Interface().(io.Closer); ok {
-				err := closer.Close()
-				if err != nil {
-					slog.WarnContext(ctx, "Call Close() on parameter failed",
-						slog.Int("idx", idx),
-						slog.String("fnType", fnType.String()),
-						slog.String("err", err.Error()),
-					)
-				}
+		closeParams(ctx, fnType, params)
+	})
+
+	return describedHandler{HandlerFunc: handler, description: description}
+}
+
+// discardResult releases the result of a handler function that is not sent to the client.
+// A result implementing io.Closer is closed, an error is logged so it does not get lost.
+func discardResult(ctx context.Context, result http.Handler, err error) {
+	if err != nil {
+		internal.LoggerOf(ctx).WarnContext(ctx, "Discard error of handler function, request context is done",
+			slog.String("err", err.Error()),
+		)
+	}
+
+	if closer, ok := result.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			internal.LoggerOf(ctx).WarnContext(ctx, "Call Close() on discarded result failed",
+				slog.String("err", err.Error()),
+			)
+		}
+	}
+}
+
+// callRecover calls fn with the given params. If fn panics, the panic is recovered
+// and returned together with the stack trace of the panicking goroutine.
+func callRecover(fn reflect.Value, params []reflect.Value) (outputs []reflect.Value, panicked any, stack []byte) {
+	defer func() {
+		if panicked = recover(); panicked != nil {
+			stack = debug.Stack()
+		}
+	}()
+
+	return fn.Call(params), nil, nil
+}
+
+// extractSerial runs the extractors one after another, appending the values to params[:0].
+// Extraction stops at the first failing extractor. In that case, the index of the failed
+// extractor and the error are returned, together with all values extracted up to that point.
+func extractSerial(r *http.Request, extractors []extractor, params []reflect.Value) ([]reflect.Value, int, error) {
+	params = params[:0]
+
+	for idx, extractor := range extractors {
+		param, err := extractor(r)
+		if err != nil {
+			return params, idx, err
+		}
+
+		params = append(params, param)
+	}
+
+	return params, 0, nil
+}
+
+// closeParams calls Close on all values in params that implement io.Closer.
+// Values are closed in the order of the handlers parameters, invalid values are skipped.
+func closeParams(ctx context.Context, fnType reflect.Type, params []reflect.Value) {
+	for idx, param := range params {
+		if !param.IsValid() {
+			continue
+		}
+
+		if closer, ok := param.Interface().(io.Closer); ok {
+			err := closer.Close()
+			if err != nil {
+				internal.LoggerOf(ctx).WarnContext(ctx, "Call Close() on parameter failed",
+					slog.Int("idx", idx),
+					slog.String("fnType", fnType.String()),
+					slog.String("err", err.Error()),
+				)
 			}
 		}
-	})
+	}
 }
 
 // newValue returns a new instance of type ty. If ty is a pointer,
@@ -193,6 +452,12 @@ func mapOutputsOf(fnType reflect.Type) func(values []reflect.Value) (http.Handle
 	case 1:
 		isHandler := fnType.Out(0).Implements(reflect.TypeFor[http.Handler]())
 
+		if isResponder(fnType.Out(0)) {
+			return func(values []reflect.Value) (http.Handler, error) {
+				return responderHandler(interfaceOf[Responder](values[0])), nil
+			}
+		}
+
 		if isHandler {
 			return func(values []reflect.Value) (http.Handler, error) {
 				handler := interfaceOf[http.Handler](values[0])
@@ -208,31 +473,80 @@ func mapOutputsOf(fnType reflect.Type) func(values []reflect.Value) (http.Handle
 	case 2:
 		o0, o1 := fnType.Out(0), fnType.Out(1)
 
-		if !o0.Implements(reflect.TypeFor[http.Handler]()) {
-			panic(fmt.Errorf("%s does not implement http.Handler", o0))
+		if isStatusCode(o0) && !o1.Implements(reflect.TypeFor[error]()) {
+			return func(values []reflect.Value) (http.Handler, error) {
+				return statusResponseOf(values[0], values[1]), nil
+			}
+		}
+
+		if !o0.Implements(reflect.TypeFor[http.Handler]()) && !isResponder(o0) {
+			panic(fmt.Errorf("%s does not implement http.Handler or Responder", o0))
 		}
 
 		if !o1.Implements(reflect.TypeFor[error]()) {
 			panic(fmt.Errorf("%s does not implement error", o1))
 		}
 
+		if isResponder(o0) {
+			return func(values []reflect.Value) (http.Handler, error) {
+				responder := interfaceOf[Responder](values[0])
+				err := interfaceOf[error](values[1])
+				return responderHandler(responder), err
+			}
+		}
+
 		return func(values []reflect.Value) (http.Handler, error) {
 			handler := interfaceOf[http.Handler](values[0])
 			err := interfaceOf[error](values[1])
 			return handler, err
 		}
 
+	case 3:
+		o0, o2 := fnType.Out(0), fnType.Out(2)
+
+		if !isStatusCode(o0) {
+			panic(fmt.Errorf("%s is not an int status code", o0))
+		}
+
+		if !o2.Implements(reflect.TypeFor[error]()) {
+			panic(fmt.Errorf("%s does not implement error", o2))
+		}
+
+		return func(values []reflect.Value) (http.Handler, error) {
+			if err := interfaceOf[error](values[2]); err != nil {
+				return nil, err
+			}
+
+			return statusResponseOf(values[0], values[1]), nil
+		}
+
 	default:
 		panic(fmt.Errorf("function has unsupported return type %s", fnType))
 	}
 }
 
-// Builds an extractor for he given type.
+// isStatusCode reports whether ty can hold the status code of a (status, value) tuple.
+func isStatusCode(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Int
+}
+
+// statusResponseOf builds the response for a (status, value) tuple returned by a handler.
+func statusResponseOf(status reflect.Value, value reflect.Value) http.Handler {
+	statusCode := int(status.Int())
+
+	if !value.IsValid() || isNil(value) {
+		return response.NoContent().WithStatusCode(statusCode)
+	}
+
+	return response.Encoded(value.Interface()).WithStatusCode(statusCode)
+}
+
+// Builds an extractor for he given type and reports where it comes from.
 // This method panics if building an extractor is not possible.
-func extractorOf(ty reflect.Type) extractor {
+func extractorOf(ty reflect.Type) (extractor, ExtractorOrigin) {
 	// first check list of registered extractors
 	if ex, ok := extractors.Load(ty); ok && ex != nil {
-		return ex.(extractor)
+		return ex.(extractor), OriginRegistered
 	}
 
 	// ty must implement FromRequest[ty]
@@ -241,7 +555,7 @@ func extractorOf(ty reflect.Type) extractor {
 		panic(fmt.Errorf("lookup FromRequest of %s: %w", ty, err))
 	}
 
-	return func(req *http.Request) (reflect.Value, error) {
+	ex := func(req *http.Request) (reflect.Value, error) {
 		// instantiate a new zero value
 		zeroValue := newValue(ty)
 
@@ -260,6 +574,8 @@ func extractorOf(ty reflect.Type) extractor {
 		// we have successfully extracted a value
 		return value, nil
 	}
+
+	return ex, OriginFromRequest
 }
 
 func lookupFromRequestMethod(ty reflect.Type) (reflect.Method, error) {
@@ -289,10 +605,20 @@ func lookupFromRequestMethod(ty reflect.Type) (reflect.Method, error) {
 // if the reflection value is valid and not nil.
 // Returns nil otherwise and panics, if the value is not assignable to T.
 func interfaceOf[T any](value reflect.Value) T {
-	if !value.IsValid() || value.IsNil() {
+	if !value.IsValid() || isNil(value) {
 		var tNil T
 		return tNil
 	}
 
 	return value.Interface().(T)
 }
+
+// isNil reports whether value is nil. Values of kinds that can not be nil are never nil.
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
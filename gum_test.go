@@ -1,10 +1,17 @@
 package gum
 
 import (
+	"context"
 	"errors"
+	"github.com/go-gum/gum/gumtest"
 	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewValue(t *testing.T) {
@@ -36,3 +43,95 @@ func TestInterfaceOf(t *testing.T) {
 	actual = interfaceOf[error](reflect.Value{})
 	AssertEqual(t, actual, nil)
 }
+
+func TestHandlerStatusValue(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("StatusValue", func(t *testing.T) {
+		handler := Handler(func() (int, User) {
+			return http.StatusCreated, User{Name: "Albert"}
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Accept", "application/json")
+
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusCreated)
+		AssertEqual(t, strings.TrimSpace(rec.Text()), `{"name":"Albert"}`)
+	})
+
+	t.Run("NilValue", func(t *testing.T) {
+		handler := Handler(func() (int, *User, error) {
+			return http.StatusAccepted, nil, nil
+		})
+
+		rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodPost, "/", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusAccepted)
+		AssertEqual(t, rec.Text(), "")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		handler := Handler(func() (int, *User, error) {
+			return http.StatusCreated, nil, NewHTTPError(http.StatusConflict, errors.New("exists"))
+		})
+
+		rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodPost, "/", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusConflict)
+	})
+
+	t.Run("Describe", func(t *testing.T) {
+		desc, _ := Describe(Handler(func() (int, User, error) { return 0, User{}, nil }))
+		AssertTrue(t, desc.ReturnsValue)
+		AssertTrue(t, desc.ReturnsError)
+		AssertTrue(t, !desc.ReturnsHandler)
+	})
+}
+
+func BenchmarkHandler(b *testing.B) {
+	type Query struct {
+		Page int `json:"page"`
+	}
+
+	handler := Handler(func(ctx context.Context, query QueryValues[Query]) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/?page=2", nil)
+	w := httptest.NewRecorder()
+
+	b.ReportAllocs()
+
+	for range b.N {
+		handler.ServeHTTP(w, req)
+	}
+}
+
+type pooledParam struct {
+	payload []byte
+}
+
+func (pooledParam) FromRequest(r *http.Request) (*pooledParam, error) {
+	return &pooledParam{payload: make([]byte, 1024)}, nil
+}
+
+func TestHandlerReleasesParams(t *testing.T) {
+	collected := make(chan struct{}, 1)
+
+	handler := Handler(func(param *pooledParam) {
+		runtime.SetFinalizer(param, func(*pooledParam) { collected <- struct{}{} })
+	})
+
+	gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// the pooled params slice survives the first garbage collection,
+	// but must not keep the extracted value of the request alive
+	runtime.GC()
+
+	select {
+	case <-collected:
+	case <-time.After(time.Second):
+		t.Fatal("extracted value was kept alive after the request")
+	}
+
+	runtime.KeepAlive(handler)
+}
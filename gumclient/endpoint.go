@@ -0,0 +1,181 @@
+package gumclient
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/internal"
+	"github.com/go-gum/gum/serde"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Endpoint is a typed client function for a single route.
+type Endpoint[Req, Resp any] func(ctx context.Context, req Req) (Resp, error)
+
+// NewEndpoint builds a typed client function for the route with the given pattern.
+// Req describes the request as documented in Client.NewRequest. NewEndpoint fails
+// if Req can not be used to build requests for the pattern, e.g. if a wildcard of
+// the pattern has no matching field in the path section of Req.
+//
+//	var getUser = must(gumclient.NewEndpoint[GetUser, User](client, "GET /users/{id}"))
+//	user, err := getUser(ctx, GetUser{Path: UserPath{ID: 1}})
+func NewEndpoint[Req, Resp any](c *Client, pattern string) (Endpoint[Req, Resp], error) {
+	sections, err := sectionsOf(reflect.TypeFor[Req]())
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if pathType := sections["path"]; pathType != nil {
+		names = fieldNames(pathType)
+	}
+
+	for _, wildcard := range wildcardsOf(pattern) {
+		if !slices.Contains(names, wildcard) {
+			return nil, fmt.Errorf("no path value for wildcard %q in %q", wildcard, pattern)
+		}
+	}
+
+	endpoint := func(ctx context.Context, req Req) (Resp, error) {
+		return Do[Resp](ctx, c, pattern, req)
+	}
+
+	return endpoint, nil
+}
+
+// Verify checks that Req matches the inputs of the server side handler of the route.
+// The types of the path, query and body sections of Req must be the same types the
+// handler extracts using gum.PathValues, gum.QueryValues and gum.JSON. Verify is
+// meant to be called in tests, to keep clients in sync with the server:
+//
+//	for _, route := range routes.Routes() {
+//		if route.Pattern == "GET /users/{id}" {
+//			err := gumclient.Verify[GetUser](route)
+//		}
+//	}
+func Verify[Req any](route gum.Route) error {
+	desc, ok := gum.Describe(route.Handler)
+	if !ok {
+		return fmt.Errorf("handler of %q was not created by gum.Handler", route.Pattern)
+	}
+
+	sections, err := sectionsOf(reflect.TypeFor[Req]())
+	if err != nil {
+		return err
+	}
+
+	expected := map[string]reflect.Type{}
+	for _, param := range desc.Parameters {
+		if section, ty, ok := inputOf(param.Type); ok {
+			expected[section] = ty
+		}
+	}
+
+	for _, section := range []string{"path", "query", "body"} {
+		switch actual, want := sections[section], expected[section]; {
+		case actual == want:
+			continue
+
+		case want == nil:
+			return fmt.Errorf("route %q does not accept a %s section", route.Pattern, section)
+
+		case actual == nil:
+			return fmt.Errorf("route %q expects a %s section of type %s", route.Pattern, section, want)
+
+		default:
+			return fmt.Errorf("route %q expects a %s section of type %s, got %s", route.Pattern, section, want, actual)
+		}
+	}
+
+	return nil
+}
+
+// sectionsOf returns the type of each section of the request type ty.
+func sectionsOf(ty reflect.Type) (map[string]reflect.Type, error) {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	if ty.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("request must be a struct, got %s", ty)
+	}
+
+	sections := map[string]reflect.Type{}
+
+	for idx := range ty.NumField() {
+		field := ty.Field(idx)
+
+		switch section := field.Tag.Get("gum"); section {
+		case "path", "query", "header", "body":
+			sections[section] = field.Type
+
+		case "":
+			continue
+
+		default:
+			return nil, fmt.Errorf("field %q: unknown section %q", field.Name, section)
+		}
+	}
+
+	return sections, nil
+}
+
+// inputOf returns the request section and its type extracted by a handler parameter of type ty.
+func inputOf(ty reflect.Type) (string, reflect.Type, bool) {
+	if ty.PkgPath() != internal.GumPackage {
+		return "", nil, false
+	}
+
+	name, _, _ := strings.Cut(ty.Name(), "[")
+
+	field, ok := ty.FieldByName("Value")
+	if !ok {
+		return "", nil, false
+	}
+
+	switch name {
+	case "Option", "Try":
+		return inputOf(field.Type)
+
+	case "PathValues":
+		return "path", field.Type, true
+
+	case "QueryValues":
+		return "query", field.Type, true
+
+	case "JSON":
+		return "body", field.Type, true
+	}
+
+	return "", nil, false
+}
+
+func fieldNames(ty reflect.Type) []string {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	if ty.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for _, field := range serde.Fields(ty) {
+		names = append(names, field.Name)
+	}
+
+	return names
+}
+
+func wildcardsOf(pattern string) []string {
+	_, path := internal.SplitPattern(pattern)
+
+	var names []string
+	for _, wildcard := range internal.Wildcards(path) {
+		names = append(names, wildcard.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,55 @@
+package gumclient
+
+import (
+	"context"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"testing"
+)
+
+func TestNewEndpoint(t *testing.T) {
+	client := newServer(t)
+
+	getUserEndpoint, err := NewEndpoint[getUser, user](client, "GET /users/{id}/{file...}")
+	AssertEqual(t, err, nil)
+
+	result, err := getUserEndpoint(context.Background(), getUser{Path: userPath{ID: 3, File: "x"}})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, result.ID, 3)
+	AssertEqual(t, result.File, "x")
+
+	_, err = NewEndpoint[getUser, user](client, "GET /users/{user}")
+	AssertTrue(t, err != nil)
+
+	_, err = NewEndpoint[createUser, user](client, "GET /users/{id}")
+	AssertTrue(t, err != nil)
+}
+
+func TestVerify(t *testing.T) {
+	routes := gum.NewRoutes(http.NewServeMux())
+
+	routes.Handle("GET /users/{id}/{file...}", gum.Handler(func(path gum.PathValues[userPath], query gum.Option[gum.QueryValues[userQuery]]) response.Lazy {
+		return response.OK(nil)
+	}))
+
+	routes.Handle("POST /users", gum.Handler(func(body gum.JSON[user]) response.Lazy {
+		return response.OK(nil)
+	}))
+
+	getRoute, postRoute := routes.Routes()[0], routes.Routes()[1]
+
+	AssertEqual(t, Verify[getUser](getRoute), nil)
+	AssertEqual(t, Verify[createUser](postRoute), nil)
+
+	AssertTrue(t, Verify[createUser](getRoute) != nil)
+	AssertTrue(t, Verify[getUser](postRoute) != nil)
+
+	type wrongQuery struct {
+		Path  userPath `gum:"path"`
+		Query userPath `gum:"query"`
+	}
+
+	AssertTrue(t, Verify[wrongQuery](getRoute) != nil)
+}
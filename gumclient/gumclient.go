@@ -0,0 +1,320 @@
+// Package gumclient calls http apis using the same typed structs that gum handlers extract.
+//
+// A request is described by a struct whose fields are tagged with the part of the
+// request they describe. The path, query and header sections are flattened using
+// serde.MarshalValues, the body section is encoded as json:
+//
+//	type UserPath struct {
+//		ID int64 `json:"id"`
+//	}
+//
+//	// on the server
+//	func getUser(path gum.PathValues[UserPath]) response.Lazy { ... }
+//
+//	// on the client
+//	type GetUser struct {
+//		Path UserPath `gum:"path"`
+//	}
+//
+//	user, err := gumclient.Do[User](ctx, client, "GET /users/{id}", GetUser{Path: UserPath{ID: 1}})
+package gumclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/internal"
+	"github.com/go-gum/gum/serde"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Client executes typed requests against a base url.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	header     http.Header
+}
+
+// Option configures a Client.
+type Option func(c *Client)
+
+// HTTPClient sets the http.Client used to execute requests. Defaults to http.DefaultClient.
+func HTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// Header adds a header that is sent with every request, e.g. for authentication.
+func Header(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// New creates a new Client sending requests to the given base url.
+func New(baseURL string, options ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: http.DefaultClient,
+		header:     http.Header{},
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+// ResponseError is returned if the server responds with a status code other than 2xx.
+type ResponseError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %q", e.StatusCode, e.Body)
+}
+
+// NewRequest builds a http.Request for the given http.ServeMux style pattern, e.g.
+// "GET /users/{id}". The fields of req must be tagged with `gum:"path"`, `gum:"query"`,
+// `gum:"header"` or `gum:"body"`. req can be nil for requests without any parameters.
+func (c *Client) NewRequest(ctx context.Context, pattern string, req any) (*http.Request, error) {
+	method, path := internal.SplitPattern(pattern)
+
+	parts, err := partsOf(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+
+	// replace wildcards in the path with the path values
+	path = internal.ReplaceWildcards(path, func(wildcard internal.Wildcard) string {
+		value, ok := parts.path[wildcard.Name]
+		if !ok {
+			missing = append(missing, wildcard.Name)
+			return ""
+		}
+
+		if wildcard.Rest {
+			// a wildcard matching the rest of the path may contain slashes
+			segments := strings.Split(value, "/")
+			for idx, segment := range segments {
+				segments[idx] = url.PathEscape(segment)
+			}
+
+			return strings.Join(segments, "/")
+		}
+
+		return url.PathEscape(value)
+	})
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing path values %q", missing)
+	}
+
+	// the path is relative to the path of the base url
+	target := c.baseURL.JoinPath(path)
+
+	if len(parts.query) > 0 {
+		target.RawQuery = url.Values(parts.query).Encode()
+	}
+
+	var body io.Reader
+	if parts.body != nil {
+		body = bytes.NewReader(parts.body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range c.header {
+		httpReq.Header[key] = append(httpReq.Header[key], values...)
+	}
+
+	for key, values := range parts.header {
+		for _, value := range values {
+			httpReq.Header.Add(key, value)
+		}
+	}
+
+	if parts.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	if httpReq.Header.Get("Accept") == "" {
+		httpReq.Header.Set("Accept", "application/json, application/xml;q=0.9")
+	}
+
+	return httpReq, nil
+}
+
+// Do builds the request using Client.NewRequest, executes it and decodes the response
+// into a T based on its Content-Type. json and xml are supported, as well as string
+// and []byte as T to receive the raw body. An empty response body results in the
+// zero value of T. If the server responds with a non 2xx status code, Do returns
+// a *ResponseError.
+func Do[T any](ctx context.Context, c *Client, pattern string, req any) (T, error) {
+	var result T
+
+	httpReq, err := c.NewRequest(ctx, pattern, req)
+	if err != nil {
+		return result, fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return result, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result, fmt.Errorf("read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, &ResponseError{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+	}
+
+	if err := decode(resp.Header.Get("Content-Type"), body, &result); err != nil {
+		return result, fmt.Errorf("decode response: %w", err)
+	}
+
+	return result, nil
+}
+
+func decode(contentType string, body []byte, target any) error {
+	switch target := target.(type) {
+	case *[]byte:
+		*target = body
+		return nil
+
+	case *string:
+		*target = string(body)
+		return nil
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("parse content type %q: %w", contentType, err)
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return json.Unmarshal(body, target)
+
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return xml.Unmarshal(body, target)
+
+	default:
+		return fmt.Errorf("unsupported content type %q", mediaType)
+	}
+}
+
+type requestParts struct {
+	path   map[string]string
+	query  map[string][]string
+	header map[string][]string
+	body   []byte
+}
+
+func partsOf(req any) (requestParts, error) {
+	var parts requestParts
+
+	rValue := reflect.ValueOf(req)
+	for rValue.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return parts, nil
+		}
+
+		rValue = rValue.Elem()
+	}
+
+	if !rValue.IsValid() {
+		return parts, nil
+	}
+
+	if rValue.Kind() != reflect.Struct {
+		return parts, fmt.Errorf("request must be a struct, got %s", rValue.Type())
+	}
+
+	for idx := range rValue.NumField() {
+		field := rValue.Type().Field(idx)
+
+		section := field.Tag.Get("gum")
+		if section == "" {
+			continue
+		}
+
+		if !field.IsExported() {
+			return parts, fmt.Errorf("field %q: must be exported", field.Name)
+		}
+
+		value := rValue.Field(idx).Interface()
+
+		var err error
+
+		switch section {
+		case "path":
+			var values map[string][]string
+			if values, err = serde.MarshalValues(value); err == nil {
+				parts.path = map[string]string{}
+				for key, value := range values {
+					if len(value) != 1 {
+						return parts, fmt.Errorf("path value %q must have exactly one value", key)
+					}
+
+					parts.path[key] = value[0]
+				}
+			}
+
+		case "query":
+			parts.query, err = serde.MarshalValues(value)
+
+		case "header":
+			parts.header, err = serde.MarshalValues(value)
+
+		case "body":
+			parts.body, err = json.Marshal(value)
+
+		default:
+			return parts, fmt.Errorf("field %q: unknown section %q", field.Name, section)
+		}
+
+		if err != nil {
+			return parts, fmt.Errorf("marshal %s: %w", section, err)
+		}
+	}
+
+	return parts, nil
+}
+
+// ErrStatus reports whether err is a *ResponseError with the given status code.
+func ErrStatus(err error, statusCode int) bool {
+	var respErr *ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == statusCode
+}
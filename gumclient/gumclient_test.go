@@ -0,0 +1,117 @@
+package gumclient
+
+import (
+	"context"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type userPath struct {
+	ID   int64  `json:"id"`
+	File string `json:"file"`
+}
+
+type userQuery struct {
+	Fields []string `json:"fields"`
+}
+
+type userHeader struct {
+	Tenant string `json:"X-Tenant"`
+}
+
+type user struct {
+	ID     int64    `json:"id"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+	Tenant string   `json:"tenant"`
+	File   string   `json:"file"`
+}
+
+type getUser struct {
+	Path   userPath   `gum:"path"`
+	Query  userQuery  `gum:"query"`
+	Header userHeader `gum:"header"`
+}
+
+type createUser struct {
+	Body user `gum:"body"`
+}
+
+func newServer(t *testing.T) *Client {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /api/users/{id}/{file...}", gum.Handler(func(path gum.PathValues[userPath], query gum.QueryValues[userQuery], r *http.Request) response.Lazy {
+		return response.Encoded(user{
+			ID:     path.Value.ID,
+			File:   path.Value.File,
+			Fields: query.Value.Fields,
+			Tenant: r.Header.Get("X-Tenant"),
+		})
+	}))
+
+	mux.Handle("POST /api/users", gum.Handler(func(body gum.JSON[user]) response.Lazy {
+		return response.Created("/api/users/1", body.Value)
+	}))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL+"/api", Header("Authorization", "Bearer token"))
+	AssertEqual(t, err, nil)
+
+	return client
+}
+
+func TestDo(t *testing.T) {
+	client := newServer(t)
+
+	req := getUser{
+		Path:   userPath{ID: 12, File: "a b/c.txt"},
+		Query:  userQuery{Fields: []string{"name", "email"}},
+		Header: userHeader{Tenant: "acme"},
+	}
+
+	result, err := Do[user](context.Background(), client, "GET /users/{id}/{file...}", req)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, result, user{ID: 12, File: "a b/c.txt", Fields: []string{"name", "email"}, Tenant: "acme"})
+}
+
+func TestDoBody(t *testing.T) {
+	client := newServer(t)
+
+	result, err := Do[user](context.Background(), client, "POST /users", createUser{Body: user{Name: "Albert"}})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, result.Name, "Albert")
+}
+
+func TestDoError(t *testing.T) {
+	client := newServer(t)
+
+	_, err := Do[user](context.Background(), client, "GET /unknown", nil)
+	AssertTrue(t, ErrStatus(err, http.StatusNotFound))
+
+	_, err = Do[user](context.Background(), client, "GET /users/{id}", nil)
+	AssertTrue(t, err != nil)
+}
+
+func TestPartsOfUnexported(t *testing.T) {
+	type request struct {
+		Body   user `gum:"body"`
+		secret string
+	}
+
+	parts, err := partsOf(request{Body: user{Name: "Albert"}, secret: "ignored"})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, string(parts.body), `{"id":0,"name":"Albert","fields":null,"tenant":"","file":""}`)
+
+	type tagged struct {
+		body user `gum:"body"`
+	}
+
+	_, err = partsOf(tagged{})
+	AssertTrue(t, err != nil)
+}
@@ -0,0 +1,55 @@
+// Package gumecho bridges gum extractors and responses into echo handlers,
+// to adopt gum incrementally in an existing echo application.
+//
+//	e := echo.New()
+//	e.GET("/users/:id", gumecho.Handler(getUser))
+//
+//	e.POST("/users", func(c echo.Context) error {
+//		body, err := gumecho.Extract[gum.JSON[User]](c)
+//		if err != nil {
+//			return err
+//		}
+//
+//		return gumecho.Respond(c, response.Created("/users/1", body.Value))
+//	})
+//
+// Path parameters of the echo route are available to gum.PathValues.
+package gumecho
+
+import (
+	"github.com/go-gum/gum"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// Extract extracts a T from the request of the echo context, see gum.Extract.
+func Extract[T any](c echo.Context) (T, error) {
+	return gum.Extract[T](requestOf(c))
+}
+
+// Handler converts a gum handler function into an echo.HandlerFunc.
+// The function and options are passed to gum.Handler.
+func Handler(fn any, options ...gum.HandlerOption) echo.HandlerFunc {
+	return Wrap(gum.Handler(fn, options...))
+}
+
+// Wrap converts any http.Handler, e.g. a handler created by gum.Handler, into an echo.HandlerFunc.
+// In contrast to echo.WrapHandler, the path parameters of the echo route are available to gum.PathValues.
+func Wrap(handler http.Handler) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		handler.ServeHTTP(c.Response(), requestOf(c))
+		return nil
+	}
+}
+
+// Respond writes a gum response, e.g. a response.Response or response.Lazy, to the echo context.
+func Respond(c echo.Context, resp http.Handler) error {
+	resp.ServeHTTP(c.Response(), requestOf(c))
+	return nil
+}
+
+func requestOf(c echo.Context) *http.Request {
+	return gum.WithPathParams(c.Request(), func(_ *http.Request, name string) string {
+		return c.Param(name)
+	})
+}
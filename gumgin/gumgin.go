@@ -0,0 +1,54 @@
+// Package gumgin bridges gum extractors and responses into gin handlers,
+// to adopt gum incrementally in an existing gin application.
+//
+//	router := gin.Default()
+//	router.GET("/users/:id", gumgin.Handler(getUser))
+//
+//	router.POST("/users", func(c *gin.Context) {
+//		body, err := gumgin.Extract[gum.JSON[User]](c)
+//		if err != nil {
+//			_ = c.AbortWithError(http.StatusBadRequest, err)
+//			return
+//		}
+//
+//		gumgin.Respond(c, response.Created("/users/1", body.Value))
+//	})
+//
+// Path parameters of the gin route are available to gum.PathValues.
+package gumgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-gum/gum"
+	"net/http"
+)
+
+// Extract extracts a T from the request of the gin context, see gum.Extract.
+func Extract[T any](c *gin.Context) (T, error) {
+	return gum.Extract[T](requestOf(c))
+}
+
+// Handler converts a gum handler function into a gin.HandlerFunc.
+// The function and options are passed to gum.Handler.
+func Handler(fn any, options ...gum.HandlerOption) gin.HandlerFunc {
+	return Wrap(gum.Handler(fn, options...))
+}
+
+// Wrap converts any http.Handler, e.g. a handler created by gum.Handler, into a gin.HandlerFunc.
+// In contrast to gin.WrapH, the path parameters of the gin route are available to gum.PathValues.
+func Wrap(handler http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler.ServeHTTP(c.Writer, requestOf(c))
+	}
+}
+
+// Respond writes a gum response, e.g. a response.Response or response.Lazy, to the gin context.
+func Respond(c *gin.Context, resp http.Handler) {
+	resp.ServeHTTP(c.Writer, requestOf(c))
+}
+
+func requestOf(c *gin.Context) *http.Request {
+	return gum.WithPathParams(c.Request, func(_ *http.Request, name string) string {
+		return c.Param(name)
+	})
+}
@@ -0,0 +1,67 @@
+package gumgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type userPath struct {
+	ID int `json:"id"`
+}
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+
+	router.GET("/users/:id", Handler(func(path gum.PathValues[userPath]) response.Lazy {
+		return response.JSON(path.Value)
+	}))
+
+	router.POST("/users", func(c *gin.Context) {
+		body, err := Extract[gum.JSON[user]](c)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		Respond(c, response.Created("/users/1", body.Value))
+	})
+
+	return router
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newRouter().ServeHTTP(rec, httptest.NewRequest("GET", "/users/12", nil))
+
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, strings.TrimSpace(rec.Body.String()), `{"id":12}`)
+}
+
+func TestExtractAndRespond(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"Albert"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	newRouter().ServeHTTP(rec, req)
+
+	AssertEqual(t, rec.Code, http.StatusCreated)
+	AssertEqual(t, rec.Header().Get("Location"), "/users/1")
+	AssertEqual(t, strings.TrimSpace(rec.Body.String()), `{"name":"Albert"}`)
+
+	// a failing extractor
+	rec = httptest.NewRecorder()
+	newRouter().ServeHTTP(rec, httptest.NewRequest("POST", "/users", strings.NewReader(`{`)))
+	AssertEqual(t, rec.Code, http.StatusBadRequest)
+}
@@ -0,0 +1,232 @@
+// Package gumtest provides utilities to test gum handlers with typed inputs and outputs.
+//
+//	type Params struct {
+//	  Name string `json:"name"`
+//	}
+//
+//	resp := gumtest.NewRequest("GET", "/hello").
+//	  WithQuery(Params{Name: "Albert"}).
+//	  Do(t, handler)
+//
+//	greeting := gumtest.DecodeJSON[Greeting](t, resp)
+package gumtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Request describes a http.Request that is built from typed input values.
+// Values are flattened using serde.MarshalValues, so field names are resolved
+// in the same way as by the PathValues and QueryValues extractors.
+type Request struct {
+	method string
+	target string
+
+	pathValues map[string]string
+	query      url.Values
+	header     http.Header
+
+	body        []byte
+	contentType string
+
+	err error
+}
+
+// NewRequest starts building a new Request for the given method and target.
+// The target can be a path or a full url.
+func NewRequest(method, target string) Request {
+	return Request{
+		method:     method,
+		target:     target,
+		pathValues: map[string]string{},
+		query:      url.Values{},
+		header:     http.Header{},
+	}
+}
+
+// WithPathValues sets the fields of value as path values on the request.
+func (r Request) WithPathValues(value any) Request {
+	values, err := serde.MarshalValues(value)
+	if err != nil {
+		return r.withErr(fmt.Errorf("marshal path values: %w", err))
+	}
+
+	r.pathValues = maps.Clone(r.pathValues)
+	for key, value := range values {
+		if len(value) != 1 {
+			return r.withErr(fmt.Errorf("path value %q must have exactly one value", key))
+		}
+
+		r.pathValues[key] = value[0]
+	}
+
+	return r
+}
+
+// WithQuery adds the fields of value to the query of the request.
+func (r Request) WithQuery(value any) Request {
+	values, err := serde.MarshalValues(value)
+	if err != nil {
+		return r.withErr(fmt.Errorf("marshal query values: %w", err))
+	}
+
+	r.query = maps.Clone(r.query)
+	for key, value := range values {
+		r.query[key] = append(r.query[key], value...)
+	}
+
+	return r
+}
+
+// WithHeader adds the fields of value as headers to the request.
+func (r Request) WithHeader(value any) Request {
+	values, err := serde.MarshalValues(value)
+	if err != nil {
+		return r.withErr(fmt.Errorf("marshal header values: %w", err))
+	}
+
+	r.header = r.header.Clone()
+	for key, value := range values {
+		for _, value := range value {
+			r.header.Add(key, value)
+		}
+	}
+
+	return r
+}
+
+// WithBody sets the body of the request and its Content-Type header.
+func (r Request) WithBody(contentType string, body []byte) Request {
+	r.contentType = contentType
+	r.body = body
+	return r
+}
+
+// WithJSON encodes value as json and uses it as the requests body.
+func (r Request) WithJSON(value any) Request {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return r.withErr(fmt.Errorf("marshal json body: %w", err))
+	}
+
+	return r.WithBody("application/json", body)
+}
+
+// WithForm encodes the fields of value as url encoded form and uses it as the requests body.
+func (r Request) WithForm(value any) Request {
+	values, err := serde.MarshalValues(value)
+	if err != nil {
+		return r.withErr(fmt.Errorf("marshal form values: %w", err))
+	}
+
+	body := url.Values(values).Encode()
+	return r.WithBody("application/x-www-form-urlencoded", []byte(body))
+}
+
+func (r Request) withErr(err error) Request {
+	r.err = errors.Join(r.err, err)
+	return r
+}
+
+// Build builds a new http.Request. It returns an error, if any of the
+// provided values could not be encoded.
+func (r Request) Build() (*http.Request, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+
+	req := httptest.NewRequest(r.method, r.target, body)
+
+	if len(r.query) > 0 {
+		query := req.URL.Query()
+		for key, values := range r.query {
+			query[key] = append(query[key], values...)
+		}
+
+		req.URL.RawQuery = query.Encode()
+	}
+
+	for key, value := range r.pathValues {
+		req.SetPathValue(key, value)
+	}
+
+	for key, values := range r.header {
+		req.Header[key] = append(req.Header[key], values...)
+	}
+
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+
+	return req, nil
+}
+
+// Do builds the http.Request and serves it using the given http.Handler.
+// The test fails immediately, if the request could not be built.
+func (r Request) Do(t testing.TB, handler http.Handler) Response {
+	t.Helper()
+
+	req, err := r.Build()
+	if err != nil {
+		t.Fatalf("build request: %s", err)
+	}
+
+	return Serve(handler, req)
+}
+
+// Response is the response recorded while serving a request.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Trailer    http.Header
+	Body       []byte
+}
+
+// Text returns the body of the response as string.
+func (r Response) Text() string {
+	return string(r.Body)
+}
+
+// Serve serves the request using the given handler and records the response.
+// This is useful in tests, as a replacement for hand-written http.ResponseWriter mocks.
+func Serve(handler http.Handler, req *http.Request) Response {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	result := rec.Result()
+
+	return Response{
+		StatusCode: result.StatusCode,
+		Header:     result.Header,
+		Trailer:    result.Trailer,
+		Body:       rec.Body.Bytes(),
+	}
+}
+
+// DecodeJSON decodes the body of the response as json into a new T.
+// The test fails immediately, if the body can not be decoded.
+func DecodeJSON[T any](t testing.TB, resp Response) T {
+	t.Helper()
+
+	var value T
+	if err := json.Unmarshal(resp.Body, &value); err != nil {
+		t.Fatalf("decode response body %q as json: %s", resp.Body, err)
+	}
+
+	return value
+}
@@ -0,0 +1,77 @@
+package gumtest
+
+import (
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_Do(t *testing.T) {
+	type Path struct {
+		ID int `json:"id"`
+	}
+
+	type Query struct {
+		Tags []string `json:"tags"`
+	}
+
+	type Header struct {
+		Language string `json:"Accept-Language"`
+	}
+
+	type Result struct {
+		ID       int
+		Tags     []string
+		Language string
+	}
+
+	handler := gum.Handler(func(path gum.PathValues[Path], query gum.QueryValues[Query], header http.Header) http.Handler {
+		return response.JSON(Result{
+			ID:       path.Value.ID,
+			Tags:     query.Value.Tags,
+			Language: header.Get("Accept-Language"),
+		})
+	})
+
+	resp := NewRequest("GET", "/items/12").
+		WithPathValues(Path{ID: 12}).
+		WithQuery(Query{Tags: []string{"foo", "bar"}}).
+		WithHeader(Header{Language: "de"}).
+		Do(t, handler)
+
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertEqual(t, DecodeJSON[Result](t, resp), Result{ID: 12, Tags: []string{"foo", "bar"}, Language: "de"})
+}
+
+func TestRequest_WithJSON(t *testing.T) {
+	type Body struct {
+		Name string
+	}
+
+	handler := gum.Handler(func(body gum.JSON[Body]) http.Handler {
+		return response.Text("Hello " + body.Value.Name)
+	})
+
+	resp := NewRequest("POST", "/").
+		WithJSON(Body{Name: "Albert"}).
+		Do(t, handler)
+
+	AssertEqual(t, resp.Text(), "Hello Albert")
+}
+
+func TestRequest_BuildError(t *testing.T) {
+	_, err := NewRequest("GET", "/").WithQuery("not a struct").Build()
+	AssertTrue(t, err != nil)
+}
+
+func TestServe(t *testing.T) {
+	handler := response.Text("ok").WithTrailer("X-Checksum", func() string { return "abc" })
+
+	resp := Serve(handler, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertEqual(t, resp.Text(), "ok")
+	AssertEqual(t, resp.Trailer.Get("X-Checksum"), "abc")
+}
@@ -0,0 +1,96 @@
+package gum
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// AutoHead is a Middleware that serves HEAD requests using a handler written for GET requests.
+// The response body is discarded but counted, so that the Content-Length header
+// matches the one of the corresponding GET request. All other requests are passed
+// to the delegate unchanged.
+//
+// Note that http.ServeMux already routes HEAD requests to handlers registered for GET.
+// Responses of the response package handle HEAD requests on their own.
+func AutoHead(delegate http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			delegate.ServeHTTP(w, r)
+			return
+		}
+
+		hw := &headWriter{ResponseWriter: w}
+		delegate.ServeHTTP(hw, r)
+		hw.finish()
+	})
+}
+
+// headWriter delays writing the headers until the body was written,
+// so it can set the Content-Length header.
+type headWriter struct {
+	http.ResponseWriter
+
+	statusCode   int
+	bytesWritten int64
+	wroteHeader  bool
+}
+
+func (w *headWriter) WriteHeader(statusCode int) {
+	if statusCode < 200 {
+		// informational headers are sent immediately
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *headWriter) Write(p []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	w.bytesWritten += int64(len(p))
+	return len(p), nil
+}
+
+// Flush sends the headers to the client. Content-Length is not known at this point.
+func (w *headWriter) Flush() {
+	w.writeHeader()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *headWriter) finish() {
+	if w.wroteHeader {
+		return
+	}
+
+	bodyAllowed := w.statusCode != http.StatusNoContent && w.statusCode != http.StatusNotModified
+	if bodyAllowed && w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.FormatInt(w.bytesWritten, 10))
+	}
+
+	w.writeHeader()
+}
+
+func (w *headWriter) writeHeader() {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+
+	statusCode := w.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
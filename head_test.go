@@ -0,0 +1,29 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAutoHead(t *testing.T) {
+	handler := AutoHead(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, "hello world")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("HEAD", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusAccepted)
+	AssertEqual(t, rec.Header().Get("Content-Length"), "11")
+	AssertEqual(t, rec.Header().Get("Content-Type"), "text/plain")
+	AssertEqual(t, rec.Body.Len(), 0)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Body.String(), "hello world")
+}
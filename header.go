@@ -0,0 +1,126 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMissingHeader is returned by extractors registered using RegisterHeader
+// and RegisterHeaderFunc if the request does not have the header.
+var ErrMissingHeader = errors.New("header is missing")
+
+// RegisterHeader registers an Extractor for T that returns the value of the header with
+// the given name. Extraction fails with 400 Bad Request if the header is missing,
+// use Option[T] for optional headers:
+//
+//	type RequestID string
+//
+//	func init() {
+//	  gum.RegisterHeader[RequestID]("X-Request-ID")
+//	}
+//
+//	func(id gum.Option[RequestID]) { ... }
+func RegisterHeader[T ~string](name string) {
+	RegisterHeaderFunc(name, func(values []string) (T, error) {
+		return T(values[0]), nil
+	})
+}
+
+// RegisterHeaderFunc registers an Extractor for T that parses the values of the header with
+// the given name using parse. parse is only called if the header is present. Extraction
+// fails with 400 Bad Request if the header is missing or can not be parsed.
+// Use HeaderInt, HeaderList and HeaderDate for common header formats:
+//
+//	type MaxForwards int
+//
+//	func init() {
+//	  gum.RegisterHeaderFunc("Max-Forwards", gum.HeaderInt[MaxForwards])
+//	}
+func RegisterHeaderFunc[T any](name string, parse func(values []string) (T, error)) {
+	name = http.CanonicalHeaderKey(name)
+
+	Register(func(r *http.Request) (T, error) {
+		values := r.Header.Values(name)
+		if len(values) == 0 {
+			var zero T
+			return zero, NewHTTPError(http.StatusBadRequest, fmt.Errorf("%w: %s", ErrMissingHeader, name))
+		}
+
+		value, err := parse(values)
+		if err != nil {
+			return value, NewHTTPError(http.StatusBadRequest, fmt.Errorf("parse header %s: %w", name, err))
+		}
+
+		return value, nil
+	})
+}
+
+// HeaderInt parses the first value of a header as a decimal integer.
+func HeaderInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](values []string) (T, error) {
+	value := strings.TrimSpace(values[0])
+
+	minusOne := T(0)
+	minusOne--
+
+	if minusOne < 0 {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		if int64(T(parsed)) != parsed {
+			return 0, fmt.Errorf("value %d out of range", parsed)
+		}
+
+		return T(parsed), nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if uint64(T(parsed)) != parsed {
+		return 0, fmt.Errorf("value %d out of range", parsed)
+	}
+
+	return T(parsed), nil
+}
+
+// HeaderList parses a comma separated list, like the value of Accept-Encoding.
+// All values of the header are combined, elements are trimmed and empty elements
+// are dropped. Quoted elements containing a comma are not supported.
+func HeaderList[T ~[]string](values []string) (T, error) {
+	var list T
+
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			if element = strings.TrimSpace(element); element != "" {
+				list = append(list, element)
+			}
+		}
+	}
+
+	return list, nil
+}
+
+// HeaderDate parses the first value of a header as http date, like the value of
+// Last-Modified. T must be a struct embedding time.Time:
+//
+//	type Since struct{ time.Time }
+//
+//	func init() {
+//	  gum.RegisterHeaderFunc("X-Since", gum.HeaderDate[Since])
+//	}
+func HeaderDate[T ~struct{ time.Time }](values []string) (T, error) {
+	parsed, err := http.ParseTime(strings.TrimSpace(values[0]))
+	if err != nil {
+		return T{}, err
+	}
+
+	return T{Time: parsed}, nil
+}
@@ -0,0 +1,84 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testRequestID string
+
+type testMaxForwards uint8
+
+type testEncodings []string
+
+type testSince struct{ time.Time }
+
+func init() {
+	RegisterHeader[testRequestID]("x-request-id")
+	RegisterHeaderFunc("Max-Forwards", HeaderInt[testMaxForwards])
+	RegisterHeaderFunc("Accept-Encoding", HeaderList[testEncodings])
+	RegisterHeaderFunc("X-Since", HeaderDate[testSince])
+}
+
+func TestRegisterHeader(t *testing.T) {
+	var id testRequestID
+	var maxForwards testMaxForwards
+	var encodings testEncodings
+	var since testSince
+
+	handler := Handler(func(i testRequestID, m testMaxForwards, e testEncodings, s testSince) {
+		id, maxForwards, encodings, since = i, m, e, s
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	req.Header.Set("Max-Forwards", "10")
+	req.Header.Add("Accept-Encoding", "gzip, br")
+	req.Header.Add("Accept-Encoding", " , zstd")
+	req.Header.Set("X-Since", "Tue, 20 Apr 2021 02:07:55 GMT")
+
+	rec := gumtest.Serve(handler, req)
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertEqual(t, id, "abc")
+	AssertEqual(t, maxForwards, 10)
+	AssertEqual(t, encodings, testEncodings{"gzip", "br", "zstd"})
+	AssertEqual(t, since.Time, time.Date(2021, 4, 20, 2, 7, 55, 0, time.UTC))
+
+	// invalid values are rejected
+	req.Header.Set("Max-Forwards", "300")
+	rec = gumtest.Serve(handler, req)
+	AssertEqual(t, rec.StatusCode, http.StatusBadRequest)
+}
+
+func TestRegisterHeaderMissing(t *testing.T) {
+	_, err := Extract[testRequestID](httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertTrue(t, errors.Is(err, ErrMissingHeader))
+
+	var httpErr *HTTPError
+	AssertTrue(t, errors.As(err, &httpErr))
+	AssertEqual(t, httpErr.StatusCode, http.StatusBadRequest)
+
+	id, err := Extract[Option[testRequestID]](httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, err, nil)
+	AssertEqual(t, id.IsSet, false)
+}
+
+func TestHeaderInt(t *testing.T) {
+	value, err := HeaderInt[int]([]string{" -42 "})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, -42)
+
+	_, err = HeaderInt[uint]([]string{"-1"})
+	AssertTrue(t, err != nil)
+
+	_, err = HeaderInt[int8]([]string{"128"})
+	AssertTrue(t, err != nil)
+
+	_, err = HeaderInt[int]([]string{"x"})
+	AssertTrue(t, err != nil)
+}
@@ -0,0 +1,335 @@
+// Package health reports the health of a service and its dependencies.
+//
+// Checks are registered on a Registry. The Livez handler only runs the checks marked
+// with Liveness, the Readyz handler runs all checks. Both respond with a json Report
+// and status 200 if all checks pass, or 503 Service Unavailable otherwise. The Report only
+// contains the overall status, unless the results of the checks are enabled using Details:
+//
+//	checks := health.New()
+//	checks.Add("db", health.Ping(db), health.Timeout(time.Second))
+//	checks.Add("billing", health.HTTP("http://billing/livez"))
+//	checks.Add("goroutines", health.Func(checkGoroutines), health.Liveness())
+//
+//	mux.Handle("GET /livez", checks.Livez())
+//	mux.Handle("GET /readyz", checks.Readyz())
+//
+// Results are cached for a short time, so frequent probes do not overload the dependencies.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Check tests a single dependency. A non nil error marks the check as down.
+type Check func(ctx context.Context) error
+
+// Result is the result of a single check.
+type Result struct {
+	Status Status `json:"status"`
+
+	// Error is the error message of a failed check
+	Error string `json:"error,omitempty"`
+
+	// Duration is the time it took to run the check
+	Duration time.Duration `json:"duration"`
+
+	// CheckedAt is the time the check was run. Can be in the past for cached results.
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Report is the combined result of multiple checks. The status is
+// StatusUp only if the status of every check is StatusUp.
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Registry holds the registered checks and caches their results.
+type Registry struct {
+	cacheTTL       time.Duration
+	defaultTimeout time.Duration
+	details        bool
+
+	mu     sync.Mutex
+	checks []*registeredCheck
+}
+
+type registeredCheck struct {
+	name     string
+	check    Check
+	timeout  time.Duration
+	liveness bool
+
+	mu       sync.Mutex
+	cached   Result
+	cachedOk bool
+}
+
+// Option configures a Registry.
+type Option func(r *Registry)
+
+// CacheFor sets how long the result of a check is reused. Defaults to one second.
+// Pass zero to run the checks for every request.
+func CacheFor(ttl time.Duration) Option {
+	return func(r *Registry) {
+		r.cacheTTL = ttl
+	}
+}
+
+// DefaultTimeout sets the timeout of checks registered without the Timeout option.
+// Defaults to five seconds.
+func DefaultTimeout(timeout time.Duration) Option {
+	return func(r *Registry) {
+		r.defaultTimeout = timeout
+	}
+}
+
+// Details includes the result of every check in the reports of the Livez and Readyz
+// handlers. As the results contain error messages that may reveal internals like hostnames,
+// only enable it if the handlers are not publicly reachable.
+func Details() Option {
+	return func(r *Registry) {
+		r.details = true
+	}
+}
+
+// New creates a new Registry without any checks.
+func New(options ...Option) *Registry {
+	r := &Registry{
+		cacheTTL:       time.Second,
+		defaultTimeout: 5 * time.Second,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// CheckOption configures a single check.
+type CheckOption func(c *registeredCheck)
+
+// Timeout sets the maximum time the check may take. A check exceeding the timeout is down.
+func Timeout(timeout time.Duration) CheckOption {
+	return func(c *registeredCheck) {
+		c.timeout = timeout
+	}
+}
+
+// Liveness includes the check in the liveness report. Only add checks for conditions
+// that are fixed by restarting the process, never for external dependencies.
+func Liveness() CheckOption {
+	return func(c *registeredCheck) {
+		c.liveness = true
+	}
+}
+
+// Add registers a check with the given name. Adding a check with the name of
+// an existing check replaces the existing check.
+func (r *Registry) Add(name string, check Check, options ...CheckOption) {
+	c := &registeredCheck{
+		name:    name,
+		check:   check,
+		timeout: r.defaultTimeout,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for idx, existing := range r.checks {
+		if existing.name == name {
+			r.checks[idx] = c
+			return
+		}
+	}
+
+	r.checks = append(r.checks, c)
+}
+
+// Check runs all checks concurrently and returns the combined report.
+// If liveness is true, only checks registered with the Liveness option are run.
+func (r *Registry) Check(ctx context.Context, liveness bool) Report {
+	r.mu.Lock()
+	var checks []*registeredCheck
+	for _, c := range r.checks {
+		if !liveness || c.liveness {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.Unlock()
+
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for idx, c := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			results[idx] = c.run(ctx, r.cacheTTL)
+		}()
+	}
+
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: map[string]Result{}}
+
+	for idx, c := range checks {
+		report.Checks[c.name] = results[idx]
+
+		if results[idx].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// Livez returns a handler reporting the result of the liveness checks.
+func (r *Registry) Livez() http.Handler {
+	return r.handler(true)
+}
+
+// Readyz returns a handler reporting the result of all checks.
+func (r *Registry) Readyz() http.Handler {
+	return r.handler(false)
+}
+
+func (r *Registry) handler(liveness bool) http.Handler {
+	return gum.Handler(func(ctx context.Context) response.Lazy {
+		report := r.Check(ctx, liveness)
+
+		statusCode := http.StatusOK
+		if report.Status != StatusUp {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		if !r.details {
+			report = Report{Status: report.Status}
+		}
+
+		return response.JSON(report).WithStatusCode(statusCode).CacheNoStore()
+	})
+}
+
+func (c *registeredCheck) run(ctx context.Context, ttl time.Duration) Result {
+	// holding the lock while running the check ensures that
+	// concurrent probes do not run the same check twice
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cachedOk && time.Since(c.cached.CheckedAt) < ttl {
+		return c.cached
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	err := runCheck(ctx, c.check)
+
+	result := Result{
+		Status:    StatusUp,
+		Duration:  time.Since(startTime),
+		CheckedAt: startTime,
+	}
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	// do not cache results of checks canceled by the caller
+	if ctx.Err() == nil || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		c.cached, c.cachedOk = result, true
+	}
+
+	return result
+}
+
+// runCheck runs the check, but returns early if the context is done
+// before the check returns, e.g. because it ignores the context.
+func runCheck(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				done <- fmt.Errorf("check panicked: %v", err)
+			}
+		}()
+
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Func adapts a function without context to a Check.
+func Func(fn func() error) Check {
+	return func(ctx context.Context) error {
+		return fn()
+	}
+}
+
+// Pinger is implemented by *sql.DB and most database clients.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping checks a database connection using PingContext.
+func Ping(db Pinger) Check {
+	return db.PingContext
+}
+
+// HTTP checks a dependency by sending a GET request to the given url.
+// The check passes if the dependency responds with a 2xx status code.
+func HTTP(url string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = resp.Body.Close() }()
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	var dbDown atomic.Bool
+
+	checks := New(CacheFor(0), Details())
+	checks.Add("process", Func(func() error { return nil }), Liveness())
+	checks.Add("db", func(ctx context.Context) error {
+		if dbDown.Load() {
+			return errors.New("connection refused")
+		}
+
+		return nil
+	})
+
+	resp := gumtest.Serve(checks.Readyz(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+
+	report := gumtest.DecodeJSON[Report](t, resp)
+	AssertEqual(t, report.Status, StatusUp)
+	AssertEqual(t, len(report.Checks), 2)
+
+	dbDown.Store(true)
+
+	resp = gumtest.Serve(checks.Readyz(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusServiceUnavailable)
+
+	report = gumtest.DecodeJSON[Report](t, resp)
+	AssertEqual(t, report.Status, StatusDown)
+	AssertEqual(t, report.Checks["db"].Error, "connection refused")
+
+	// liveness is not affected by the database
+	resp = gumtest.Serve(checks.Livez(), httptest.NewRequest(http.MethodGet, "/livez", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+
+	report = gumtest.DecodeJSON[Report](t, resp)
+	AssertEqual(t, len(report.Checks), 1)
+}
+
+func TestRegistryWithoutDetails(t *testing.T) {
+	checks := New(CacheFor(0))
+	checks.Add("db", Func(func() error { return errors.New("dial tcp 10.0.0.12:5432: connection refused") }))
+
+	resp := gumtest.Serve(checks.Readyz(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusServiceUnavailable)
+	AssertEqual(t, resp.Text(), `{"status":"down"}`)
+
+	// the full report is still available programmatically
+	report := checks.Check(context.Background(), false)
+	AssertEqual(t, report.Checks["db"].Status, StatusDown)
+}
+
+func TestTimeout(t *testing.T) {
+	checks := New()
+	checks.Add("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, Timeout(10*time.Millisecond))
+
+	report := checks.Check(context.Background(), false)
+	AssertEqual(t, report.Status, StatusDown)
+	AssertEqual(t, report.Checks["slow"].Error, context.DeadlineExceeded.Error())
+}
+
+func TestCache(t *testing.T) {
+	var calls atomic.Int32
+
+	checks := New(CacheFor(time.Minute))
+	checks.Add("counted", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	checks.Check(context.Background(), false)
+	checks.Check(context.Background(), false)
+	AssertEqual(t, calls.Load(), 1)
+}
@@ -0,0 +1,167 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoBackends is returned by Hedge if no backends are given.
+var ErrNoBackends = errors.New("no backends to hedge against")
+
+// HedgeAttempt describes a finished call of the fetch function of Hedge.
+type HedgeAttempt struct {
+	// Attempt is the number of the attempt, starting at zero
+	Attempt int
+
+	// Backend is the index of the backend the attempt was sent to
+	Backend int
+
+	// Duration is the time the fetch function took
+	Duration time.Duration
+
+	// Err is the error returned by the fetch function, if any
+	Err error
+}
+
+// HedgeOption configures Hedge.
+type HedgeOption func(config *hedgeConfig)
+
+type hedgeConfig struct {
+	delay       time.Duration
+	maxAttempts int
+	onAttempt   func(attempt HedgeAttempt)
+}
+
+// HedgeDelay sets how long Hedge waits for an attempt before it starts the next one.
+// Defaults to 50ms.
+func HedgeDelay(delay time.Duration) HedgeOption {
+	return func(config *hedgeConfig) {
+		config.delay = delay
+	}
+}
+
+// HedgeMaxAttempts limits the number of backends that are tried.
+// Defaults to the number of backends.
+func HedgeMaxAttempts(attempts int) HedgeOption {
+	return func(config *hedgeConfig) {
+		config.maxAttempts = attempts
+	}
+}
+
+// OnHedgeAttempt calls fn for every attempt that finished before Hedge returned,
+// e.g. to record metrics or spans. Attempts that were canceled because another attempt
+// succeeded are not reported. fn is called on the goroutine calling Hedge.
+func OnHedgeAttempt(fn func(attempt HedgeAttempt)) HedgeOption {
+	return func(config *hedgeConfig) {
+		config.onAttempt = fn
+	}
+}
+
+// Hedge calls fetch for the first backend. If the call does not return within the hedge
+// delay, or if it fails, fetch is called for the next backend, and so on. The first
+// successful result is returned and the context of all other attempts is canceled.
+// If all attempts fail, the errors are joined. This is useful for read endpoints
+// backed by multiple replicas, to cut the latency of slow replicas:
+//
+//	func(ctx context.Context, path gum.PathValues[ItemPath]) (Item, error) {
+//	  return gum.Hedge(ctx, replicas, func(ctx context.Context, db *sql.DB) (Item, error) {
+//	    return loadItem(ctx, db, path.Value.ID)
+//	  })
+//	}
+//
+// Pass the context of the request, so that all attempts are bound to its deadline, e.g.
+// the one set by RequestTimeout and reported by Deadline. If the context has a deadline,
+// the hedge delay is shortened so that all attempts can start before the deadline.
+// Hedge returns the error of the context if it is done before any attempt succeeded.
+func Hedge[B, T any](ctx context.Context, backends []B, fetch func(ctx context.Context, backend B) (T, error), options ...HedgeOption) (T, error) {
+	config := hedgeConfig{delay: 50 * time.Millisecond}
+	for _, option := range options {
+		option(&config)
+	}
+
+	var zero T
+
+	attempts := len(backends)
+	if config.maxAttempts > 0 {
+		attempts = min(attempts, config.maxAttempts)
+	}
+
+	if attempts == 0 {
+		return zero, ErrNoBackends
+	}
+
+	delay := config.delay
+	if deadline, ok := ctx.Deadline(); ok {
+		delay = min(delay, time.Until(deadline)/time.Duration(attempts))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		attempt HedgeAttempt
+		value   T
+	}
+
+	// buffered, so attempts finishing after we returned do not block
+	results := make(chan result, attempts)
+
+	launched := 0
+	launch := func() {
+		attempt := HedgeAttempt{Attempt: launched, Backend: launched}
+		launched++
+
+		go func() {
+			startTime := time.Now()
+			value, err := fetch(ctx, backends[attempt.Backend])
+
+			attempt.Duration = time.Since(startTime)
+			attempt.Err = err
+
+			results <- result{attempt: attempt, value: value}
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	launch()
+
+	var errs []error
+
+	for finished := 0; finished < attempts; {
+		select {
+		case res := <-results:
+			finished++
+
+			if config.onAttempt != nil {
+				config.onAttempt(res.attempt)
+			}
+
+			if res.attempt.Err == nil {
+				return res.value, nil
+			}
+
+			errs = append(errs, fmt.Errorf("backend %d: %w", res.attempt.Backend, res.attempt.Err))
+
+			// no need to wait for the delay, the attempt already failed
+			if launched < attempts {
+				launch()
+				timer.Reset(delay)
+			}
+
+		case <-timer.C:
+			if launched < attempts {
+				launch()
+				timer.Reset(delay)
+			}
+
+		case <-ctx.Done():
+			return zero, errors.Join(append(errs, ctx.Err())...)
+		}
+	}
+
+	return zero, errors.Join(errs...)
+}
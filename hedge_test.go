@@ -0,0 +1,104 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	ctx := context.Background()
+
+	// a backend that answers after the given delay with its name
+	type backend struct {
+		name  string
+		delay time.Duration
+		err   error
+	}
+
+	fetch := func(ctx context.Context, b backend) (string, error) {
+		select {
+		case <-time.After(b.delay):
+			return b.name, b.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	t.Run("first succeeds", func(t *testing.T) {
+		var attempts []HedgeAttempt
+
+		value, err := Hedge(ctx, []backend{{name: "a"}, {name: "b"}}, fetch,
+			OnHedgeAttempt(func(attempt HedgeAttempt) { attempts = append(attempts, attempt) }),
+		)
+
+		AssertEqual(t, err, nil)
+		AssertEqual(t, value, "a")
+		AssertEqual(t, len(attempts), 1)
+	})
+
+	t.Run("slow backend is hedged", func(t *testing.T) {
+		backends := []backend{{name: "slow", delay: time.Second}, {name: "fast"}}
+
+		startTime := time.Now()
+		value, err := Hedge(ctx, backends, fetch, HedgeDelay(10*time.Millisecond))
+
+		AssertEqual(t, err, nil)
+		AssertEqual(t, value, "fast")
+		AssertTrue(t, time.Since(startTime) < 500*time.Millisecond)
+	})
+
+	t.Run("failed backend is replaced immediately", func(t *testing.T) {
+		backends := []backend{{name: "a", err: errors.New("down")}, {name: "b"}}
+
+		var attempts []HedgeAttempt
+
+		startTime := time.Now()
+		value, err := Hedge(ctx, backends, fetch,
+			HedgeDelay(time.Second),
+			OnHedgeAttempt(func(attempt HedgeAttempt) { attempts = append(attempts, attempt) }),
+		)
+
+		AssertEqual(t, err, nil)
+		AssertEqual(t, value, "b")
+		AssertTrue(t, time.Since(startTime) < 500*time.Millisecond)
+		AssertEqual(t, len(attempts), 2)
+		AssertEqual(t, attempts[0].Backend, 0)
+		AssertTrue(t, attempts[0].Err != nil)
+		AssertEqual(t, attempts[1].Backend, 1)
+	})
+
+	t.Run("all backends fail", func(t *testing.T) {
+		errDown := errors.New("down")
+		backends := []backend{{err: errDown}, {err: errDown}, {err: errDown}}
+
+		var count int
+		_, err := Hedge(ctx, backends, fetch,
+			HedgeMaxAttempts(2),
+			OnHedgeAttempt(func(attempt HedgeAttempt) { count++ }),
+		)
+
+		AssertTrue(t, errors.Is(err, errDown))
+		AssertEqual(t, count, 2)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		backends := []backend{{delay: time.Second}, {delay: time.Second}}
+
+		startTime := time.Now()
+		_, err := Hedge(ctx, backends, fetch, HedgeDelay(time.Second))
+
+		AssertTrue(t, errors.Is(err, context.DeadlineExceeded))
+		AssertTrue(t, time.Since(startTime) < 500*time.Millisecond)
+	})
+
+	t.Run("no backends", func(t *testing.T) {
+		_, err := Hedge(ctx, []backend{}, fetch)
+		AssertTrue(t, errors.Is(err, ErrNoBackends))
+	})
+}
@@ -0,0 +1,82 @@
+package gum
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// Hooks are called by a Handler at the different stages of handling a request.
+// They can be used to integrate metrics, tracing or auditing systems.
+// All hooks are optional.
+type Hooks struct {
+	// OnExtract is called after the extractor for a parameter of type ty has finished.
+	OnExtract func(r *http.Request, ty reflect.Type, duration time.Duration, err error)
+
+	// OnHandle is called after the handler function has returned. The error is
+	// the error returned by the handler function, if any.
+	OnHandle func(r *http.Request, duration time.Duration, err error)
+
+	// OnWrite is called after the response has been written.
+	OnWrite func(r *http.Request, statusCode int, bytesWritten int64)
+}
+
+// WithHooks adds the given Hooks to a Handler. This option can be
+// applied multiple times, the hooks are called in the order they were added.
+func WithHooks(hooks Hooks) HandlerOption {
+	return func(config *handlerConfig) {
+		config.hooks = append(config.hooks, hooks)
+	}
+}
+
+// instrumentExtractor wraps the extractor for type ty to call the OnExtract hooks.
+func instrumentExtractor(ex extractor, ty reflect.Type, hooks []Hooks) extractor {
+	var onExtract []func(*http.Request, reflect.Type, time.Duration, error)
+	for _, h := range hooks {
+		if h.OnExtract != nil {
+			onExtract = append(onExtract, h.OnExtract)
+		}
+	}
+
+	if len(onExtract) == 0 {
+		return ex
+	}
+
+	return func(r *http.Request) (reflect.Value, error) {
+		startTime := time.Now()
+		value, err := ex(r)
+		duration := time.Since(startTime)
+
+		for _, hook := range onExtract {
+			hook(r, ty, duration, err)
+		}
+
+		return value, err
+	}
+}
+
+func callOnHandle(hooks []Hooks, r *http.Request, duration time.Duration, err error) {
+	for _, h := range hooks {
+		if h.OnHandle != nil {
+			h.OnHandle(r, duration, err)
+		}
+	}
+}
+
+func callOnWrite(hooks []Hooks, r *http.Request, statusCode int, bytesWritten int64) {
+	for _, h := range hooks {
+		if h.OnWrite != nil {
+			h.OnWrite(r, statusCode, bytesWritten)
+		}
+	}
+}
+
+func hasOnWrite(hooks []Hooks) bool {
+	for _, h := range hooks {
+		if h.OnWrite != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package gum
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithHooks(t *testing.T) {
+	var extracted []reflect.Type
+	var handleErr error
+	var statusCode int
+	var bytesWritten int64
+
+	hooks := Hooks{
+		OnExtract: func(r *http.Request, ty reflect.Type, duration time.Duration, err error) {
+			extracted = append(extracted, ty)
+		},
+		OnHandle: func(r *http.Request, duration time.Duration, err error) {
+			handleErr = err
+		},
+		OnWrite: func(r *http.Request, status int, bytes int64) {
+			statusCode, bytesWritten = status, bytes
+		},
+	}
+
+	handler := Handler(func(m Method, h Host) (http.Handler, error) {
+		return response.Text("failed"), errors.New("failed")
+	}, WithHooks(hooks))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, extracted, []reflect.Type{reflect.TypeFor[Method](), reflect.TypeFor[Host]()})
+	AssertEqual(t, handleErr.Error(), "failed")
+	AssertEqual(t, statusCode, http.StatusInternalServerError)
+	AssertEqual(t, bytesWritten, int64(len("failed")))
+}
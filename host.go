@@ -0,0 +1,151 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Hosts routes requests by their Host header. Host patterns consist of dot separated
+// labels, a label of the form "{name}" matches any single label of the host:
+//
+//	hosts := gum.NewHosts()
+//	hosts.Handle("api.{tenant}.example.com", apiMux)
+//	hosts.Handle("www.example.com", siteMux)
+//
+//	http.ListenAndServe(":8080", hosts)
+//
+// If multiple patterns match a host, the pattern with the fewest wildcards wins.
+// Handlers can access the captured labels using HostValues.
+type Hosts struct {
+	patterns []hostPattern
+	fallback http.Handler
+}
+
+type hostPattern struct {
+	labels    []string
+	wildcards int
+	handler   http.Handler
+}
+
+// NewHosts creates a new Hosts without any patterns.
+func NewHosts() *Hosts {
+	return &Hosts{fallback: http.NotFoundHandler()}
+}
+
+// Handle registers the handler for the given host pattern. Panics if the pattern is invalid.
+func (h *Hosts) Handle(pattern string, handler http.Handler) {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(pattern, ".")), ".")
+
+	var wildcards int
+	for _, label := range labels {
+		if label == "" {
+			panic(fmt.Errorf("gum: empty label in host pattern %q", pattern))
+		}
+
+		if strings.HasPrefix(label, "{") {
+			if !strings.HasSuffix(label, "}") || len(label) < 3 {
+				panic(fmt.Errorf("gum: invalid wildcard %q in host pattern %q", label, pattern))
+			}
+
+			wildcards++
+		}
+	}
+
+	h.patterns = append(h.patterns, hostPattern{labels: labels, wildcards: wildcards, handler: handler})
+}
+
+// Fallback sets the handler for requests not matching any pattern. Defaults to http.NotFoundHandler.
+func (h *Hosts) Fallback(handler http.Handler) {
+	h.fallback = handler
+}
+
+type hostValuesKey struct{}
+
+func (h *Hosts) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+
+	var best *hostPattern
+	var bestValues map[string]string
+
+	for idx := range h.patterns {
+		pattern := &h.patterns[idx]
+
+		if best != nil && pattern.wildcards >= best.wildcards {
+			continue
+		}
+
+		if values, ok := pattern.match(labels); ok {
+			best, bestValues = pattern, values
+		}
+	}
+
+	if best == nil {
+		h.fallback.ServeHTTP(w, r)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), hostValuesKey{}, bestValues)
+	best.handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (p *hostPattern) match(labels []string) (map[string]string, bool) {
+	if len(labels) != len(p.labels) {
+		return nil, false
+	}
+
+	values := map[string]string{}
+
+	for idx, label := range p.labels {
+		if name, ok := strings.CutPrefix(label, "{"); ok {
+			values[strings.TrimSuffix(name, "}")] = labels[idx]
+			continue
+		}
+
+		if label != labels[idx] {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+// HostValues parses the host labels captured by the wildcards of a Hosts pattern to a struct T.
+type HostValues[T any] struct {
+	Value T
+}
+
+var _ = AssertFromRequest[HostValues[any]]()
+
+func (HostValues[T]) FromRequest(r *http.Request) (HostValues[T], error) {
+	values, _ := r.Context().Value(hostValuesKey{}).(map[string]string)
+
+	target, err := serde.UnmarshalNew[T](hostSourceValue{values: values})
+	if err != nil {
+		return HostValues[T]{}, fmt.Errorf("deserialize %T: %w", target, err)
+	}
+
+	return HostValues[T]{Value: target}, nil
+}
+
+type hostSourceValue struct {
+	serde.InvalidValue
+	values map[string]string
+}
+
+func (p hostSourceValue) Get(key string) (serde.SourceValue, error) {
+	value, ok := p.values[key]
+	if !ok {
+		return nil, serde.ErrNoValue
+	}
+
+	return serde.StringValue(value), nil
+}
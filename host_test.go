@@ -0,0 +1,38 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHosts(t *testing.T) {
+	type Tenant struct {
+		Tenant string `json:"tenant"`
+	}
+
+	hosts := NewHosts()
+
+	hosts.Handle("api.{tenant}.example.com", Handler(func(host HostValues[Tenant]) response.Response {
+		return response.Text("api " + host.Value.Tenant)
+	}))
+
+	hosts.Handle("api.www.example.com", Handler(func() response.Response {
+		return response.Text("exact")
+	}))
+
+	serve := func(host string) gumtest.Response {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		return gumtest.Serve(hosts, req)
+	}
+
+	AssertEqual(t, serve("api.acme.example.com").Text(), "api acme")
+	AssertEqual(t, serve("API.Acme.example.com:8080").Text(), "api acme")
+	AssertEqual(t, serve("api.www.example.com").Text(), "exact")
+	AssertEqual(t, serve("example.com").StatusCode, http.StatusNotFound)
+	AssertEqual(t, serve("api.a.b.example.com").StatusCode, http.StatusNotFound)
+}
@@ -0,0 +1,211 @@
+package httpsig
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// message is a request or a response whose components are covered by a signature.
+type message struct {
+	request *http.Request
+
+	// header is the header of the message, which differs from
+	// the header of request for responses.
+	header http.Header
+
+	// statusCode is the status code of a response, zero for requests.
+	statusCode int
+}
+
+func requestMessage(r *http.Request) message {
+	return message{request: r, header: r.Header}
+}
+
+func (m message) isResponse() bool {
+	return m.statusCode != 0
+}
+
+// parseComponent parses a component identifier like "@method", "content-type"
+// or `"@query-param";name="id"`.
+func parseComponent(identifier string) (sfItem, error) {
+	if strings.HasPrefix(identifier, `"`) {
+		return parseItem(identifier)
+	}
+
+	name, params, _ := strings.Cut(identifier, ";")
+	if params == "" {
+		return sfItem{value: strings.ToLower(name)}, nil
+	}
+
+	return parseItem(serializeString(strings.ToLower(name)) + ";" + params)
+}
+
+func parseComponents(identifiers []string) ([]sfItem, error) {
+	components := make([]sfItem, 0, len(identifiers))
+
+	for _, identifier := range identifiers {
+		component, err := parseComponent(identifier)
+		if err != nil {
+			return nil, fmt.Errorf("component %q: %w", identifier, err)
+		}
+
+		components = append(components, component)
+	}
+
+	return components, nil
+}
+
+// signatureBase creates the signature base of the message as defined in RFC 9421, section 2.5.
+func signatureBase(m message, components []sfItem, params sfParams) (string, error) {
+	var b strings.Builder
+
+	seen := make(map[string]bool, len(components))
+
+	for _, component := range components {
+		identifier := component.serialize()
+		if seen[identifier] {
+			return "", fmt.Errorf("component %s is covered twice", identifier)
+		}
+
+		seen[identifier] = true
+
+		value, err := m.componentValue(component)
+		if err != nil {
+			return "", fmt.Errorf("component %s: %w", identifier, err)
+		}
+
+		if strings.ContainsAny(value, "\r\n") {
+			return "", fmt.Errorf("component %s contains a newline", identifier)
+		}
+
+		b.WriteString(identifier)
+		b.WriteString(": ")
+		b.WriteString(value)
+		b.WriteByte('\n')
+	}
+
+	b.WriteString(`"@signature-params": `)
+	b.WriteString(sfInnerList{items: components, params: params}.serialize())
+
+	return b.String(), nil
+}
+
+func (m message) componentValue(component sfItem) (string, error) {
+	var name string
+
+	for _, param := range component.params {
+		switch param.key {
+		case "req":
+			if !m.isResponse() {
+				return "", errors.New("parameter req is only valid for responses")
+			}
+
+			// resolve the component using the request the response belongs to
+			m = requestMessage(m.request)
+
+		case "name":
+			name, _ = param.value.(string)
+
+		default:
+			return "", fmt.Errorf("unsupported parameter %q", param.key)
+		}
+	}
+
+	r := m.request
+
+	switch component.value {
+	case "@method":
+		return r.Method, nil
+
+	case "@target-uri":
+		return m.scheme() + "://" + m.authority() + r.URL.RequestURI(), nil
+
+	case "@authority":
+		return m.authority(), nil
+
+	case "@scheme":
+		return m.scheme(), nil
+
+	case "@request-target":
+		return r.URL.RequestURI(), nil
+
+	case "@path":
+		if path := r.URL.EscapedPath(); path != "" {
+			return path, nil
+		}
+
+		return "/", nil
+
+	case "@query":
+		return "?" + r.URL.RawQuery, nil
+
+	case "@query-param":
+		if name == "" {
+			return "", errors.New("parameter name is required")
+		}
+
+		values := r.URL.Query()[name]
+		if len(values) != 1 {
+			return "", fmt.Errorf("query parameter %q must occur exactly once", name)
+		}
+
+		return encodeQueryParam(values[0]), nil
+
+	case "@status":
+		if !m.isResponse() {
+			return "", errors.New("@status is only valid for responses")
+		}
+
+		return strconv.Itoa(m.statusCode), nil
+	}
+
+	if strings.HasPrefix(component.value, "@") {
+		return "", errors.New("unknown derived component")
+	}
+
+	if name != "" {
+		return "", errors.New("parameter name is only valid for @query-param")
+	}
+
+	values := m.header.Values(component.value)
+	if len(values) == 0 {
+		return "", errors.New("header is missing")
+	}
+
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed = append(trimmed, strings.TrimSpace(value))
+	}
+
+	return strings.Join(trimmed, ", "), nil
+}
+
+func (m message) authority() string {
+	host := m.request.Host
+	if host == "" {
+		host = m.request.URL.Host
+	}
+
+	return strings.ToLower(host)
+}
+
+func (m message) scheme() string {
+	if scheme := m.request.URL.Scheme; scheme != "" {
+		return strings.ToLower(scheme)
+	}
+
+	if m.request.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// encodeQueryParam percent-encodes a query parameter value, using %20 for spaces.
+func encodeQueryParam(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
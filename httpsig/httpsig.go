@@ -0,0 +1,353 @@
+// Package httpsig signs and verifies HTTP Message Signatures as defined in RFC 9421.
+//
+// Incoming requests are verified by the Signature extractor, which requires the Middleware
+// of a Verifier. Keys are resolved by their key id using a KeyResolver:
+//
+//	verifier := httpsig.NewVerifier(httpsig.StaticKeys(httpsig.Key{
+//	  ID:        "partner-a",
+//	  Algorithm: httpsig.AlgorithmEd25519,
+//	  Material:  partnerPublicKey,
+//	}))
+//
+//	mux.Handle("POST /orders", gum.Handler(func(sig httpsig.Signature, order gum.JSON[Order]) { ... }))
+//	http.ListenAndServe(":8080", verifier.Middleware()(mux))
+//
+// Responses are signed by wrapping them using Sign, outgoing requests by calling SignRequest:
+//
+//	return httpsig.Sign(response.JSON(order), serverKey)
+//
+// If the content-digest component is covered, the Content-Digest header (RFC 9530) is
+// computed when signing and compared to the body when verifying.
+package httpsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNoSignature is returned if a request does not carry a signature.
+var ErrNoSignature = errors.New("no signature in request")
+
+// Option configures a Verifier.
+type Option func(v *Verifier)
+
+// Label selects the signature to verify by its label. By default, the first signature
+// of the Signature-Input header is verified.
+func Label(label string) Option {
+	return func(v *Verifier) {
+		v.label = label
+	}
+}
+
+// RequireComponents sets the components a signature must cover, e.g. "@method" or
+// "content-digest". Defaults to "@method", "@authority", "@path" and "@query", the
+// components SignRequest covers by default.
+func RequireComponents(components ...string) Option {
+	return func(v *Verifier) {
+		v.required = components
+	}
+}
+
+// MaxAge rejects signatures whose created parameter differs from the current time by
+// more than maxAge. A value of zero disables the check and makes the created parameter
+// optional. Defaults to five minutes.
+func MaxAge(maxAge time.Duration) Option {
+	return func(v *Verifier) {
+		v.maxAge = maxAge
+	}
+}
+
+// Tag only accepts signatures with the given tag parameter, which identifies the
+// application or protocol the signature was created for.
+func Tag(tag string) Option {
+	return func(v *Verifier) {
+		v.tag = tag
+	}
+}
+
+// MaxBodySize limits the number of bytes read from the body to verify the content digest.
+// Larger bodies are rejected with 413 Request Entity Too Large. Defaults to 1MB,
+// a value of zero means no limit.
+func MaxBodySize(size int64) Option {
+	return func(v *Verifier) {
+		v.maxBodySize = size
+	}
+}
+
+// Verifier verifies the signatures of incoming requests.
+type Verifier struct {
+	resolve     KeyResolver
+	label       string
+	required    []string
+	maxAge      time.Duration
+	tag         string
+	maxBodySize int64
+	now         func() time.Time
+}
+
+// NewVerifier creates a new Verifier resolving keys using resolve.
+func NewVerifier(resolve KeyResolver, options ...Option) *Verifier {
+	v := &Verifier{
+		resolve:     resolve,
+		required:    []string{"@method", "@authority", "@path", "@query"},
+		maxAge:      5 * time.Minute,
+		maxBodySize: 1 << 20,
+		now:         time.Now,
+	}
+
+	for _, option := range options {
+		option(v)
+	}
+
+	return v
+}
+
+// Middleware provides the Verifier to the Signature extractor.
+func (v *Verifier) Middleware() gum.Middleware {
+	return gum.ProvideContextValue(v)
+}
+
+// Signature holds the parameters of the verified signature of a request.
+// Extraction fails with 401 Unauthorized if the signature is missing or invalid.
+// Requires the Middleware of a Verifier.
+type Signature struct {
+	Label     string
+	KeyID     string
+	Algorithm string
+
+	// Created and Expires are zero if the signature does not have these parameters.
+	Created time.Time
+	Expires time.Time
+
+	Nonce string
+	Tag   string
+
+	// Components holds the identifiers of the covered components, e.g. "@method".
+	Components []string
+}
+
+var _ = gum.AssertFromRequest[Signature]()
+
+func (Signature) FromRequest(r *http.Request) (Signature, error) {
+	verifier, err := gum.Extract[gum.ContextValue[*Verifier]](r)
+	if err != nil {
+		return Signature{}, fmt.Errorf("no httpsig.Verifier in context: %w", err)
+	}
+
+	signature, err := verifier.Value.Verify(r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return Signature{}, gum.NewHTTPError(http.StatusRequestEntityTooLarge, err)
+		}
+
+		return Signature{}, gum.NewHTTPError(http.StatusUnauthorized, err)
+	}
+
+	return signature, nil
+}
+
+// Verify verifies the signature of the request. If the signature covers the
+// content-digest component, the body is read and replaced with an in-memory copy.
+func (v *Verifier) Verify(r *http.Request) (Signature, error) {
+	signature, components, err := v.verify(requestMessage(r))
+	if err != nil {
+		return Signature{}, err
+	}
+
+	if slices.ContainsFunc(components, func(c sfItem) bool { return c.value == "content-digest" && len(c.params) == 0 }) {
+		var body io.Reader = r.Body
+		if v.maxBodySize > 0 {
+			body = http.MaxBytesReader(nil, r.Body, v.maxBodySize)
+		}
+
+		payload, err := io.ReadAll(body)
+		if err != nil {
+			return Signature{}, fmt.Errorf("read body: %w", err)
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(payload))
+
+		if err := verifyContentDigest(r.Header, payload); err != nil {
+			return Signature{}, err
+		}
+	}
+
+	return signature, nil
+}
+
+func (v *Verifier) verify(m message) (Signature, []sfItem, error) {
+	inputs, err := parseDictionary(strings.Join(m.header.Values("Signature-Input"), ", "))
+	if err != nil {
+		return Signature{}, nil, fmt.Errorf("parse Signature-Input: %w", err)
+	}
+
+	signatures, err := parseDictionary(strings.Join(m.header.Values("Signature"), ", "))
+	if err != nil {
+		return Signature{}, nil, fmt.Errorf("parse Signature: %w", err)
+	}
+
+	if len(inputs) == 0 {
+		return Signature{}, nil, ErrNoSignature
+	}
+
+	label := v.label
+	if label == "" {
+		label = inputs[0].key
+	}
+
+	inputIdx := slices.IndexFunc(inputs, func(m sfMember) bool { return m.key == label })
+	signatureIdx := slices.IndexFunc(signatures, func(m sfMember) bool { return m.key == label })
+	if inputIdx < 0 || signatureIdx < 0 {
+		return Signature{}, nil, fmt.Errorf("%w with label %q", ErrNoSignature, label)
+	}
+
+	input := inputs[inputIdx].list
+	if input == nil {
+		return Signature{}, nil, fmt.Errorf("signature input %q is not an inner list", label)
+	}
+
+	signatureBytes, ok := signatures[signatureIdx].value.([]byte)
+	if !ok {
+		return Signature{}, nil, fmt.Errorf("signature %q is not a byte sequence", label)
+	}
+
+	signature := Signature{Label: label}
+
+	for _, component := range input.items {
+		signature.Components = append(signature.Components, component.serialize())
+	}
+
+	params := input.params
+	signature.KeyID, _ = params.string("keyid")
+	signature.Algorithm, _ = params.string("alg")
+	signature.Nonce, _ = params.string("nonce")
+	signature.Tag, _ = params.string("tag")
+
+	if created, ok := params.int("created"); ok {
+		signature.Created = time.Unix(created, 0)
+	}
+
+	if expires, ok := params.int("expires"); ok {
+		signature.Expires = time.Unix(expires, 0)
+	}
+
+	if err := v.validate(signature, input.items); err != nil {
+		return Signature{}, nil, err
+	}
+
+	key, err := v.resolve(m.request.Context(), signature.KeyID)
+	if err != nil {
+		return Signature{}, nil, fmt.Errorf("resolve key: %w", err)
+	}
+
+	if signature.Algorithm != "" && signature.Algorithm != key.Algorithm {
+		return Signature{}, nil, fmt.Errorf("algorithm %q does not match key %q", signature.Algorithm, key.ID)
+	}
+
+	base, err := signatureBase(m, input.items, params)
+	if err != nil {
+		return Signature{}, nil, err
+	}
+
+	if err := key.Verify([]byte(base), signatureBytes); err != nil {
+		return Signature{}, nil, fmt.Errorf("verify signature %q: %w", label, err)
+	}
+
+	return signature, input.items, nil
+}
+
+// validate checks the parameters and components of a signature before its key is resolved.
+func (v *Verifier) validate(signature Signature, components []sfItem) error {
+	if signature.KeyID == "" {
+		return errors.New("signature has no keyid parameter")
+	}
+
+	if v.tag != "" && signature.Tag != v.tag {
+		return fmt.Errorf("signature has tag %q, expected %q", signature.Tag, v.tag)
+	}
+
+	for _, required := range v.required {
+		covered := slices.ContainsFunc(components, func(c sfItem) bool {
+			return c.value == strings.ToLower(required) && len(c.params) == 0
+		})
+
+		if !covered {
+			return fmt.Errorf("signature does not cover required component %q", required)
+		}
+	}
+
+	now := v.now()
+
+	if v.maxAge > 0 {
+		if signature.Created.IsZero() {
+			return errors.New("signature has no created parameter")
+		}
+
+		if age := now.Sub(signature.Created); age > v.maxAge || age < -v.maxAge {
+			return fmt.Errorf("signature was created %s ago", age.Truncate(time.Second))
+		}
+	}
+
+	if !signature.Expires.IsZero() && now.After(signature.Expires) {
+		return errors.New("signature expired")
+	}
+
+	return nil
+}
+
+// contentDigest returns the value of a Content-Digest header for the given body.
+func contentDigest(body []byte) string {
+	digest := sha256.Sum256(body)
+	return "sha-256=" + serializeBareItem(digest[:])
+}
+
+// verifyContentDigest compares all supported digests of the Content-Digest header to the body.
+func verifyContentDigest(header http.Header, body []byte) error {
+	members, err := parseDictionary(strings.Join(header.Values("Content-Digest"), ", "))
+	if err != nil {
+		return fmt.Errorf("parse Content-Digest: %w", err)
+	}
+
+	var verified bool
+
+	for _, member := range members {
+		var actual []byte
+
+		switch member.key {
+		case "sha-256":
+			digest := sha256.Sum256(body)
+			actual = digest[:]
+
+		case "sha-512":
+			digest := sha512.Sum512(body)
+			actual = digest[:]
+
+		default:
+			continue
+		}
+
+		expected, ok := member.value.([]byte)
+		if !ok || subtle.ConstantTimeCompare(expected, actual) != 1 {
+			return fmt.Errorf("content digest %s does not match body", member.key)
+		}
+
+		verified = true
+	}
+
+	if !verified {
+		return errors.New("no supported content digest")
+	}
+
+	return nil
+}
@@ -0,0 +1,301 @@
+package httpsig
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func rfcRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/foo?param=Value&Pet=dog", strings.NewReader(`{"hello": "world"}`))
+	req.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Digest", "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:")
+	return req
+}
+
+func TestSignatureBase(t *testing.T) {
+	// example from RFC 9421, appendix B.2.2
+	input, err := parseDictionary(`sig-b22=("@authority" "content-digest" "@query-param";name="Pet");created=1618884473;keyid="test-key-rsa-pss";tag="header-example"`)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, len(input), 1)
+
+	base, err := signatureBase(requestMessage(rfcRequest()), input[0].list.items, input[0].list.params)
+	AssertEqual(t, err, nil)
+
+	expected := strings.Join([]string{
+		`"@authority": example.com`,
+		`"content-digest": sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:`,
+		`"@query-param";name="Pet": dog`,
+		`"@signature-params": ("@authority" "content-digest" "@query-param";name="Pet");created=1618884473;keyid="test-key-rsa-pss";tag="header-example"`,
+	}, "\n")
+
+	AssertEqual(t, base, expected)
+
+	// derived components of the request
+	components, err := parseComponents([]string{"@method", "@target-uri", "@scheme", "@request-target", "@path", "@query", "Content-Type"})
+	AssertEqual(t, err, nil)
+
+	base, err = signatureBase(requestMessage(rfcRequest()), components, nil)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, base, strings.Join([]string{
+		`"@method": POST`,
+		`"@target-uri": http://example.com/foo?param=Value&Pet=dog`,
+		`"@scheme": http`,
+		`"@request-target": /foo?param=Value&Pet=dog`,
+		`"@path": /foo`,
+		`"@query": ?param=Value&Pet=dog`,
+		`"content-type": application/json`,
+		`"@signature-params": ("@method" "@target-uri" "@scheme" "@request-target" "@path" "@query" "content-type")`,
+	}, "\n"))
+
+	_, err = signatureBase(requestMessage(rfcRequest()), []sfItem{{value: "x-missing"}}, nil)
+	AssertTrue(t, err != nil)
+
+	_, err = signatureBase(requestMessage(rfcRequest()), []sfItem{{value: "@status"}}, nil)
+	AssertTrue(t, err != nil)
+}
+
+func TestVerifyContentDigest(t *testing.T) {
+	body := []byte(`{"hello": "world"}`)
+
+	AssertEqual(t, verifyContentDigest(rfcRequest().Header, body), nil)
+	AssertTrue(t, verifyContentDigest(rfcRequest().Header, []byte(`{}`)) != nil)
+
+	header := http.Header{}
+	header.Set("Content-Digest", contentDigest(body))
+	AssertEqual(t, header.Get("Content-Digest"), "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:")
+	AssertEqual(t, verifyContentDigest(header, body), nil)
+
+	header.Set("Content-Digest", "md5=:AAAA:")
+	AssertTrue(t, verifyContentDigest(header, body) != nil)
+}
+
+func TestKeys(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	keys := []Key{
+		{Algorithm: AlgorithmHMACSHA256, Material: []byte("secret")},
+		{Algorithm: AlgorithmEd25519, Material: edKey},
+		{Algorithm: AlgorithmECDSAP256SHA256, Material: p256Key},
+		{Algorithm: AlgorithmECDSAP384SHA384, Material: p384Key},
+		{Algorithm: AlgorithmRSAPSSSHA512, Material: rsaKey},
+		{Algorithm: AlgorithmRSAPKCS1v15SHA256, Material: rsaKey},
+	}
+
+	for _, key := range keys {
+		t.Run(key.Algorithm, func(t *testing.T) {
+			signature, err := key.Sign([]byte("base"))
+			AssertEqual(t, err, nil)
+
+			AssertEqual(t, key.Verify([]byte("base"), signature), nil)
+			AssertTrue(t, key.Verify([]byte("other"), signature) != nil)
+		})
+	}
+
+	// public keys can only verify
+	public := Key{Algorithm: AlgorithmEd25519, Material: edKey.Public()}
+	_, err := public.Sign([]byte("base"))
+	AssertTrue(t, err != nil)
+
+	// wrong curve for the algorithm
+	_, err = Key{Algorithm: AlgorithmECDSAP256SHA256, Material: p384Key}.Sign([]byte("base"))
+	AssertTrue(t, err != nil)
+}
+
+func TestSignatureExtractor(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+
+	signingKey := Key{ID: "client", Algorithm: AlgorithmEd25519, Material: priv}
+	verifier := NewVerifier(StaticKeys(Key{ID: "client", Algorithm: AlgorithmEd25519, Material: pub}),
+		RequireComponents("@method", "@path", "content-digest"),
+	)
+
+	handler := verifier.Middleware()(gum.Handler(func(sig Signature, body gum.RawBody) http.Handler {
+		return response.Text(sig.KeyID + " " + string(body))
+	}))
+
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "http://example.com/orders?id=1", strings.NewReader(`{"id":1}`))
+	}
+
+	req := newRequest()
+	err := SignRequest(req, signingKey, Components("@method", "@path", "@query", "content-digest"), WithNonce())
+	AssertEqual(t, err, nil)
+	AssertTrue(t, strings.HasPrefix(req.Header.Get("Content-Digest"), "sha-256=:"))
+
+	rec := gumtest.Serve(handler, req)
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertEqual(t, rec.Text(), `client {"id":1}`)
+
+	t.Run("missing signature", func(t *testing.T) {
+		rec := gumtest.Serve(handler, newRequest())
+		AssertEqual(t, rec.StatusCode, http.StatusUnauthorized)
+	})
+
+	t.Run("tampered query", func(t *testing.T) {
+		req := newRequest()
+		AssertEqual(t, SignRequest(req, signingKey, Components("@method", "@path", "@query", "content-digest")), nil)
+
+		req.URL.RawQuery = "id=2"
+
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusUnauthorized)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		req := newRequest()
+		AssertEqual(t, SignRequest(req, signingKey, Components("@method", "@path", "content-digest")), nil)
+
+		req.Body = http.NoBody
+
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusUnauthorized)
+	})
+
+	t.Run("missing required component", func(t *testing.T) {
+		req := newRequest()
+		AssertEqual(t, SignRequest(req, signingKey), nil)
+
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusUnauthorized)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		verifier := NewVerifier(StaticKeys(Key{ID: "client", Algorithm: AlgorithmEd25519, Material: pub}),
+			RequireComponents("@method", "@path", "content-digest"),
+			MaxBodySize(4),
+		)
+
+		handler := verifier.Middleware()(gum.Handler(func(sig Signature) {}))
+
+		req := newRequest()
+		AssertEqual(t, SignRequest(req, signingKey, Components("@method", "@path", "content-digest")), nil)
+
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("default components", func(t *testing.T) {
+		verifier := NewVerifier(StaticKeys(Key{ID: "client", Algorithm: AlgorithmEd25519, Material: pub}))
+
+		req := newRequest()
+		AssertEqual(t, SignRequest(req, signingKey, Components("@method", "@path")), nil)
+
+		_, err := verifier.Verify(req)
+		AssertTrue(t, err != nil)
+
+		req = newRequest()
+		AssertEqual(t, SignRequest(req, signingKey), nil)
+
+		_, err = verifier.Verify(req)
+		AssertEqual(t, err, nil)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		req := newRequest()
+		AssertEqual(t, SignRequest(req, Key{ID: "other", Algorithm: AlgorithmEd25519, Material: priv}), nil)
+
+		_, err := NewVerifier(StaticKeys()).Verify(req)
+		AssertTrue(t, errors.Is(err, ErrUnknownKey))
+	})
+}
+
+func TestVerifyParameters(t *testing.T) {
+	key := Key{ID: "shared", Algorithm: AlgorithmHMACSHA256, Material: []byte("secret")}
+	now := time.Unix(1700000000, 0)
+
+	sign := func(options ...SignOption) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+
+		config := newSignConfig([]string{"@method", "@authority", "@path", "@query"}, options)
+		config.now = func() time.Time { return now }
+
+		AssertEqual(t, config.sign(requestMessage(req), key, nil), nil)
+		return req
+	}
+
+	verify := func(req *http.Request, at time.Time, options ...Option) (Signature, error) {
+		verifier := NewVerifier(StaticKeys(key), options...)
+		verifier.now = func() time.Time { return at }
+		return verifier.Verify(req)
+	}
+
+	sig, err := verify(sign(WithTag("app"), SignLabel("mine")), now.Add(time.Minute), Tag("app"), Label("mine"))
+	AssertEqual(t, err, nil)
+	AssertEqual(t, sig.Label, "mine")
+	AssertEqual(t, sig.KeyID, "shared")
+	AssertEqual(t, sig.Algorithm, AlgorithmHMACSHA256)
+	AssertEqual(t, sig.Created, now)
+	AssertEqual(t, sig.Tag, "app")
+	AssertEqual(t, sig.Components, []string{`"@method"`, `"@authority"`, `"@path"`, `"@query"`})
+
+	// too old
+	_, err = verify(sign(), now.Add(10*time.Minute))
+	AssertTrue(t, err != nil)
+
+	// too old, but age is not checked
+	_, err = verify(sign(), now.Add(10*time.Minute), MaxAge(0))
+	AssertEqual(t, err, nil)
+
+	// expired
+	_, err = verify(sign(ExpiresIn(time.Second)), now.Add(time.Minute))
+	AssertTrue(t, err != nil)
+
+	// wrong tag
+	_, err = verify(sign(WithTag("other")), now, Tag("app"))
+	AssertTrue(t, err != nil)
+
+	// wrong label
+	_, err = verify(sign(), now, Label("mine"))
+	AssertTrue(t, errors.Is(err, ErrNoSignature))
+}
+
+func TestSign(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+
+	handler := gum.Handler(func() http.Handler {
+		resp := response.Text("created").WithStatusCode(http.StatusCreated)
+		return Sign(resp, Key{ID: "server", Algorithm: AlgorithmEd25519, Material: priv},
+			Components("@status", "content-digest", "@method;req"),
+		)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+
+	rec := gumtest.Serve(handler, req)
+	AssertEqual(t, rec.StatusCode, http.StatusCreated)
+	AssertEqual(t, rec.Text(), "created")
+	AssertTrue(t, strings.HasPrefix(rec.Header.Get("Signature-Input"), `sig1=("@status" "content-digest" "@method";req);created=`))
+
+	verifier := NewVerifier(StaticKeys(Key{ID: "server", Algorithm: AlgorithmEd25519, Material: pub}),
+		RequireComponents("@status", "content-digest"),
+	)
+
+	m := message{request: req, header: rec.Header, statusCode: rec.StatusCode}
+
+	sig, _, err := verifier.verify(m)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, sig.KeyID, "server")
+	AssertEqual(t, verifyContentDigest(rec.Header, rec.Body), nil)
+
+	// a different status code invalidates the signature
+	m.statusCode = http.StatusOK
+	_, _, err = verifier.verify(m)
+	AssertTrue(t, err != nil)
+}
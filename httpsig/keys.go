@@ -0,0 +1,233 @@
+package httpsig
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// The signature algorithms registered in RFC 9421, section 6.2.2.
+const (
+	AlgorithmHMACSHA256        = "hmac-sha256"
+	AlgorithmEd25519           = "ed25519"
+	AlgorithmECDSAP256SHA256   = "ecdsa-p256-sha256"
+	AlgorithmECDSAP384SHA384   = "ecdsa-p384-sha384"
+	AlgorithmRSAPSSSHA512      = "rsa-pss-sha512"
+	AlgorithmRSAPKCS1v15SHA256 = "rsa-v1_5-sha256"
+)
+
+// ErrUnknownKey is returned by a KeyResolver if it does not know the requested key.
+var ErrUnknownKey = errors.New("unknown key")
+
+// Key signs or verifies signatures using one of the Algorithm constants.
+type Key struct {
+	// ID is sent as keyid parameter of signatures created using this key.
+	ID string
+
+	// Algorithm is the algorithm used to sign or verify signatures.
+	Algorithm string
+
+	// Material holds the key itself:
+	//
+	//   - []byte for AlgorithmHMACSHA256
+	//   - ed25519.PrivateKey or ed25519.PublicKey for AlgorithmEd25519
+	//   - *ecdsa.PrivateKey or *ecdsa.PublicKey for the ECDSA algorithms
+	//   - *rsa.PrivateKey or *rsa.PublicKey for the RSA algorithms
+	//
+	// Public keys can only verify signatures, private keys can do both.
+	Material any
+}
+
+// KeyResolver resolves the key with the given id to verify a signature.
+// Returns ErrUnknownKey if the key is not known.
+type KeyResolver func(ctx context.Context, keyID string) (Key, error)
+
+// StaticKeys returns a KeyResolver that resolves the given keys by their ID.
+func StaticKeys(keys ...Key) KeyResolver {
+	byID := make(map[string]Key, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key
+	}
+
+	return func(ctx context.Context, keyID string) (Key, error) {
+		key, ok := byID[keyID]
+		if !ok {
+			return Key{}, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+		}
+
+		return key, nil
+	}
+}
+
+// Sign signs the signature base using the key.
+func (k Key) Sign(base []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case AlgorithmHMACSHA256:
+		secret, ok := k.Material.([]byte)
+		if !ok {
+			return nil, k.materialError()
+		}
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(base)
+		return mac.Sum(nil), nil
+
+	case AlgorithmEd25519:
+		key, ok := k.Material.(ed25519.PrivateKey)
+		if !ok {
+			return nil, k.materialError()
+		}
+
+		return ed25519.Sign(key, base), nil
+
+	case AlgorithmECDSAP256SHA256, AlgorithmECDSAP384SHA384:
+		key, ok := k.Material.(*ecdsa.PrivateKey)
+		if !ok || key.Curve != k.curve() {
+			return nil, k.materialError()
+		}
+
+		r, s, err := ecdsa.Sign(rand.Reader, key, k.digest(base))
+		if err != nil {
+			return nil, err
+		}
+
+		// the signature is the concatenation of r and s, each padded to the size of the curve
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+		return signature, nil
+
+	case AlgorithmRSAPSSSHA512:
+		key, ok := k.Material.(*rsa.PrivateKey)
+		if !ok {
+			return nil, k.materialError()
+		}
+
+		return rsa.SignPSS(rand.Reader, key, crypto.SHA512, k.digest(base), pssOptions)
+
+	case AlgorithmRSAPKCS1v15SHA256:
+		key, ok := k.Material.(*rsa.PrivateKey)
+		if !ok {
+			return nil, k.materialError()
+		}
+
+		return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, k.digest(base))
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", k.Algorithm)
+	}
+}
+
+// Verify verifies the signature of the signature base using the key.
+func (k Key) Verify(base, signature []byte) error {
+	material := k.Material
+	if signer, ok := material.(crypto.Signer); ok {
+		material = signer.Public()
+	}
+
+	switch k.Algorithm {
+	case AlgorithmHMACSHA256:
+		expected, err := k.Sign(base)
+		if err != nil {
+			return err
+		}
+
+		if !hmac.Equal(expected, signature) {
+			return errors.New("signature does not match")
+		}
+
+		return nil
+
+	case AlgorithmEd25519:
+		key, ok := material.(ed25519.PublicKey)
+		if !ok {
+			return k.materialError()
+		}
+
+		if !ed25519.Verify(key, base, signature) {
+			return errors.New("signature does not match")
+		}
+
+		return nil
+
+	case AlgorithmECDSAP256SHA256, AlgorithmECDSAP384SHA384:
+		key, ok := material.(*ecdsa.PublicKey)
+		if !ok || key.Curve != k.curve() {
+			return k.materialError()
+		}
+
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature length")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, k.digest(base), r, s) {
+			return errors.New("signature does not match")
+		}
+
+		return nil
+
+	case AlgorithmRSAPSSSHA512:
+		key, ok := material.(*rsa.PublicKey)
+		if !ok {
+			return k.materialError()
+		}
+
+		return rsa.VerifyPSS(key, crypto.SHA512, k.digest(base), signature, pssOptions)
+
+	case AlgorithmRSAPKCS1v15SHA256:
+		key, ok := material.(*rsa.PublicKey)
+		if !ok {
+			return k.materialError()
+		}
+
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, k.digest(base), signature)
+
+	default:
+		return fmt.Errorf("unsupported algorithm %q", k.Algorithm)
+	}
+}
+
+// pssOptions uses the salt length required by RFC 9421, section 3.3.1.
+var pssOptions = &rsa.PSSOptions{SaltLength: 64}
+
+func (k Key) materialError() error {
+	return fmt.Errorf("key %q: material of type %T can not be used with algorithm %q", k.ID, k.Material, k.Algorithm)
+}
+
+func (k Key) curve() elliptic.Curve {
+	if k.Algorithm == AlgorithmECDSAP384SHA384 {
+		return elliptic.P384()
+	}
+
+	return elliptic.P256()
+}
+
+func (k Key) digest(base []byte) []byte {
+	switch k.Algorithm {
+	case AlgorithmECDSAP384SHA384:
+		digest := sha512.Sum384(base)
+		return digest[:]
+
+	case AlgorithmRSAPSSSHA512:
+		digest := sha512.Sum512(base)
+		return digest[:]
+
+	default:
+		digest := sha256.Sum256(base)
+		return digest[:]
+	}
+}
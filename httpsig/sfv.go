@@ -0,0 +1,491 @@
+package httpsig
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This file implements the subset of structured field values (RFC 8941) used by
+// the Signature-Input, Signature and Content-Digest headers.
+
+// sfToken is a token, which is serialized without quotes.
+type sfToken string
+
+type sfParam struct {
+	key string
+
+	// value is a string, sfToken, int64, bool or []byte
+	value any
+}
+
+type sfParams []sfParam
+
+func (p sfParams) get(key string) (any, bool) {
+	for _, param := range p {
+		if param.key == key {
+			return param.value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (p sfParams) string(key string) (string, bool) {
+	value, _ := p.get(key)
+
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case sfToken:
+		return string(value), true
+	default:
+		return "", false
+	}
+}
+
+func (p sfParams) int(key string) (int64, bool) {
+	value, ok := p.get(key)
+	if !ok {
+		return 0, false
+	}
+
+	integer, ok := value.(int64)
+	return integer, ok
+}
+
+// sfItem is an item of an inner list. Only string items are supported,
+// as component identifiers are strings.
+type sfItem struct {
+	value  string
+	params sfParams
+}
+
+type sfInnerList struct {
+	items  []sfItem
+	params sfParams
+}
+
+// sfMember is a member of a dictionary, holding either an inner list or a bare item.
+type sfMember struct {
+	key   string
+	list  *sfInnerList
+	value any
+}
+
+type sfParser struct {
+	input string
+	pos   int
+}
+
+func (p *sfParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("structured field at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *sfParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *sfParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *sfParser) skip(chars string) {
+	for !p.done() && strings.IndexByte(chars, p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func parseDictionary(input string) ([]sfMember, error) {
+	p := &sfParser{input: input}
+
+	var members []sfMember
+
+	p.skip(" \t")
+	for !p.done() {
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		member := sfMember{key: key}
+
+		if p.peek() == '=' {
+			p.pos++
+
+			if p.peek() == '(' {
+				list, err := p.parseInnerList()
+				if err != nil {
+					return nil, err
+				}
+
+				member.list = &list
+			} else {
+				value, err := p.parseBareItem()
+				if err != nil {
+					return nil, err
+				}
+
+				// parameters of bare items are not used by any of the headers
+				if _, err := p.parseParams(); err != nil {
+					return nil, err
+				}
+
+				member.value = value
+			}
+		} else {
+			if _, err := p.parseParams(); err != nil {
+				return nil, err
+			}
+
+			member.value = true
+		}
+
+		members = append(members, member)
+
+		p.skip(" \t")
+		if p.done() {
+			break
+		}
+
+		if p.peek() != ',' {
+			return nil, p.errorf("expected ','")
+		}
+
+		p.pos++
+		p.skip(" \t")
+
+		if p.done() {
+			return nil, p.errorf("trailing comma")
+		}
+	}
+
+	return members, nil
+}
+
+// parseItem parses a single item with parameters, like `"@query-param";name="id"`.
+func parseItem(input string) (sfItem, error) {
+	p := &sfParser{input: input}
+
+	item, err := p.parseItem()
+	if err != nil {
+		return sfItem{}, err
+	}
+
+	if !p.done() {
+		return sfItem{}, p.errorf("unexpected trailing characters")
+	}
+
+	return item, nil
+}
+
+func (p *sfParser) parseItem() (sfItem, error) {
+	value, err := p.parseBareItem()
+	if err != nil {
+		return sfItem{}, err
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return sfItem{}, p.errorf("expected a string")
+	}
+
+	params, err := p.parseParams()
+	if err != nil {
+		return sfItem{}, err
+	}
+
+	return sfItem{value: str, params: params}, nil
+}
+
+func (p *sfParser) parseInnerList() (sfInnerList, error) {
+	// skip the opening parenthesis
+	p.pos++
+
+	var list sfInnerList
+
+	for {
+		p.skip(" ")
+
+		if p.done() {
+			return sfInnerList{}, p.errorf("unterminated inner list")
+		}
+
+		if p.peek() == ')' {
+			p.pos++
+			break
+		}
+
+		item, err := p.parseItem()
+		if err != nil {
+			return sfInnerList{}, err
+		}
+
+		list.items = append(list.items, item)
+
+		if c := p.peek(); c != ' ' && c != ')' {
+			return sfInnerList{}, p.errorf("expected ' ' or ')' in inner list")
+		}
+	}
+
+	params, err := p.parseParams()
+	if err != nil {
+		return sfInnerList{}, err
+	}
+
+	list.params = params
+	return list, nil
+}
+
+func (p *sfParser) parseParams() (sfParams, error) {
+	var params sfParams
+
+	for p.peek() == ';' {
+		p.pos++
+		p.skip(" ")
+
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var value any = true
+
+		if p.peek() == '=' {
+			p.pos++
+
+			value, err = p.parseBareItem()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		params = append(params, sfParam{key: key, value: value})
+	}
+
+	return params, nil
+}
+
+func isLowerAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (p *sfParser) parseKey() (string, error) {
+	start := p.pos
+
+	if c := p.peek(); !isLowerAlpha(c) && c != '*' {
+		return "", p.errorf("expected a key")
+	}
+
+	for !p.done() {
+		c := p.peek()
+		if !isLowerAlpha(c) && !isDigit(c) && strings.IndexByte("_-.*", c) < 0 {
+			break
+		}
+
+		p.pos++
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+func (p *sfParser) parseBareItem() (any, error) {
+	c := p.peek()
+
+	switch {
+	case c == '"':
+		return p.parseString()
+
+	case c == ':':
+		return p.parseByteSequence()
+
+	case c == '?':
+		p.pos++
+
+		switch p.peek() {
+		case '0':
+			p.pos++
+			return false, nil
+		case '1':
+			p.pos++
+			return true, nil
+		default:
+			return nil, p.errorf("invalid boolean")
+		}
+
+	case c == '-' || isDigit(c):
+		start := p.pos
+		p.pos++
+
+		for isDigit(p.peek()) {
+			p.pos++
+		}
+
+		if p.peek() == '.' {
+			return nil, p.errorf("decimals are not supported")
+		}
+
+		return strconv.ParseInt(p.input[start:p.pos], 10, 64)
+
+	case c == '*' || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+		start := p.pos
+
+		for !p.done() {
+			c := p.peek()
+			if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),;<=>?@[\]{}`, c) >= 0 {
+				break
+			}
+
+			p.pos++
+		}
+
+		return sfToken(p.input[start:p.pos]), nil
+
+	default:
+		return nil, p.errorf("unexpected character %q", c)
+	}
+}
+
+func (p *sfParser) parseString() (string, error) {
+	// skip the opening quote
+	p.pos++
+
+	var value strings.Builder
+
+	for !p.done() {
+		c := p.input[p.pos]
+		p.pos++
+
+		switch {
+		case c == '\\':
+			if next := p.peek(); next == '"' || next == '\\' {
+				value.WriteByte(next)
+				p.pos++
+				continue
+			}
+
+			return "", p.errorf("invalid escape sequence")
+
+		case c == '"':
+			return value.String(), nil
+
+		case c < ' ' || c >= 0x7f:
+			return "", p.errorf("invalid character in string")
+
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *sfParser) parseByteSequence() ([]byte, error) {
+	// skip the opening colon
+	p.pos++
+
+	end := strings.IndexByte(p.input[p.pos:], ':')
+	if end < 0 {
+		return nil, p.errorf("unterminated byte sequence")
+	}
+
+	encoded := p.input[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid byte sequence")
+	}
+
+	return value, nil
+}
+
+func serializeString(value string) string {
+	var b strings.Builder
+
+	b.WriteByte('"')
+
+	for idx := 0; idx < len(value); idx++ {
+		if c := value[idx]; c == '"' || c == '\\' {
+			b.WriteByte('\\')
+		}
+
+		b.WriteByte(value[idx])
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func serializeBareItem(value any) string {
+	switch value := value.(type) {
+	case string:
+		return serializeString(value)
+	case sfToken:
+		return string(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case bool:
+		if value {
+			return "?1"
+		}
+
+		return "?0"
+	case []byte:
+		return ":" + base64.StdEncoding.EncodeToString(value) + ":"
+	default:
+		panic(fmt.Sprintf("unsupported structured field value %T", value))
+	}
+}
+
+func (p sfParams) serialize() string {
+	var b strings.Builder
+
+	for _, param := range p {
+		b.WriteByte(';')
+		b.WriteString(param.key)
+
+		if value, ok := param.value.(bool); ok && value {
+			continue
+		}
+
+		b.WriteByte('=')
+		b.WriteString(serializeBareItem(param.value))
+	}
+
+	return b.String()
+}
+
+func (i sfItem) serialize() string {
+	return serializeString(i.value) + i.params.serialize()
+}
+
+func (l sfInnerList) serialize() string {
+	var b strings.Builder
+
+	b.WriteByte('(')
+
+	for idx, item := range l.items {
+		if idx > 0 {
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(item.serialize())
+	}
+
+	b.WriteByte(')')
+	b.WriteString(l.params.serialize())
+
+	return b.String()
+}
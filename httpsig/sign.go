@@ -0,0 +1,225 @@
+package httpsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// SignOption configures how a message is signed.
+type SignOption func(config *signConfig)
+
+type signConfig struct {
+	label      string
+	components []string
+	expiresIn  time.Duration
+	nonce      bool
+	tag        string
+	now        func() time.Time
+}
+
+// SignLabel sets the label of the signature. Defaults to "sig1".
+func SignLabel(label string) SignOption {
+	return func(config *signConfig) {
+		config.label = label
+	}
+}
+
+// Components sets the components covered by the signature. Components are given by their
+// identifier, e.g. "@method" or "content-type". Parameters use the structured field syntax,
+// e.g. `@query-param;name="id"`, or `@method;req` to cover the method of the request
+// a response belongs to.
+//
+// Requests cover "@method", "@authority", "@path" and "@query" by default,
+// responses cover "@status" and "content-digest".
+func Components(components ...string) SignOption {
+	return func(config *signConfig) {
+		config.components = components
+	}
+}
+
+// ExpiresIn adds an expires parameter to the signature.
+func ExpiresIn(d time.Duration) SignOption {
+	return func(config *signConfig) {
+		config.expiresIn = d
+	}
+}
+
+// WithNonce adds a random nonce parameter to the signature.
+func WithNonce() SignOption {
+	return func(config *signConfig) {
+		config.nonce = true
+	}
+}
+
+// WithTag adds a tag parameter to the signature.
+func WithTag(tag string) SignOption {
+	return func(config *signConfig) {
+		config.tag = tag
+	}
+}
+
+func newSignConfig(components []string, options []SignOption) signConfig {
+	config := signConfig{
+		label:      "sig1",
+		components: components,
+		now:        time.Now,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return config
+}
+
+// SignRequest signs an outgoing request using the given key. If the content-digest
+// component is covered and the request has no Content-Digest header, the body is read
+// to compute the header and replaced with an in-memory copy.
+func SignRequest(r *http.Request, key Key, options ...SignOption) error {
+	config := newSignConfig([]string{"@method", "@authority", "@path", "@query"}, options)
+
+	return config.sign(requestMessage(r), key, func() ([]byte, error) {
+		if r.Body == nil || r.Body == http.NoBody {
+			return nil, nil
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read body: %w", err)
+		}
+
+		_ = r.Body.Close()
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+
+		return body, nil
+	})
+}
+
+// Sign returns a http.Handler that signs the response written by handler using the given key,
+// e.g. a response.Response returned by a handler function. The response is buffered
+// in memory, so Sign should not be used for large or streaming responses.
+//
+// If signing fails, an error response with status 500 is written instead.
+func Sign(handler http.Handler, key Key, options ...SignOption) http.Handler {
+	config := newSignConfig([]string{"@status", "content-digest"}, options)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buffered := &bufferedResponse{header: http.Header{}}
+		handler.ServeHTTP(buffered, r)
+
+		m := message{request: r, header: buffered.header, statusCode: buffered.statusCode}
+		if m.statusCode == 0 {
+			m.statusCode = http.StatusOK
+		}
+
+		body := buffered.body.Bytes()
+
+		err := config.sign(m, key, func() ([]byte, error) { return body, nil })
+		if err != nil {
+			response.Error(fmt.Errorf("sign response: %w", err), http.StatusInternalServerError).ServeHTTP(w, r)
+			return
+		}
+
+		for name, values := range buffered.header {
+			w.Header()[name] = values
+		}
+
+		w.WriteHeader(m.statusCode)
+		_, _ = w.Write(body)
+	})
+}
+
+// sign adds the Signature-Input and Signature headers to the message.
+func (c signConfig) sign(m message, key Key, body func() ([]byte, error)) error {
+	components, err := parseComponents(c.components)
+	if err != nil {
+		return err
+	}
+
+	coversDigest := slices.ContainsFunc(components, func(c sfItem) bool {
+		return c.value == "content-digest" && len(c.params) == 0
+	})
+
+	if coversDigest && m.header.Get("Content-Digest") == "" {
+		payload, err := body()
+		if err != nil {
+			return err
+		}
+
+		m.header.Set("Content-Digest", contentDigest(payload))
+	}
+
+	now := c.now()
+
+	params := sfParams{
+		{key: "created", value: now.Unix()},
+		{key: "keyid", value: key.ID},
+		{key: "alg", value: key.Algorithm},
+	}
+
+	if c.expiresIn > 0 {
+		params = append(params, sfParam{key: "expires", value: now.Add(c.expiresIn).Unix()})
+	}
+
+	if c.nonce {
+		var nonce [16]byte
+		_, _ = rand.Read(nonce[:])
+		params = append(params, sfParam{key: "nonce", value: base64.RawURLEncoding.EncodeToString(nonce[:])})
+	}
+
+	if c.tag != "" {
+		params = append(params, sfParam{key: "tag", value: c.tag})
+	}
+
+	base, err := signatureBase(m, components, params)
+	if err != nil {
+		return err
+	}
+
+	signature, err := key.Sign([]byte(base))
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+
+	input := sfInnerList{items: components, params: params}
+	m.header.Add("Signature-Input", c.label+"="+input.serialize())
+	m.header.Add("Signature", c.label+"="+serializeBareItem(signature))
+
+	return nil
+}
+
+// bufferedResponse is a http.ResponseWriter that keeps the response in memory.
+type bufferedResponse struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(statusCode int) {
+	if b.statusCode == 0 {
+		b.statusCode = statusCode
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.statusCode == 0 {
+		b.statusCode = http.StatusOK
+	}
+
+	return b.body.Write(p)
+}
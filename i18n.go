@@ -0,0 +1,276 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// AcceptLanguage holds the language tags of the requests Accept-Language header,
+// ordered by preference. Tags with a quality of zero are omitted.
+type AcceptLanguage []string
+
+func init() {
+	Register(func(r *http.Request) (AcceptLanguage, error) {
+		return parseAcceptLanguage(r.Header.Get("Accept-Language")), nil
+	})
+}
+
+// Match returns the supported language that matches the preferences best. A preferred tag
+// matches a supported tag if both are equal, or if the primary subtag of the preferred
+// tag is supported, e.g. "de-AT" matches "de". Tags are compared case-insensitively.
+// Returns false if no supported language matches.
+func (a AcceptLanguage) Match(supported ...string) (string, bool) {
+	for _, tag := range a {
+		if tag == "*" && len(supported) > 0 {
+			return supported[0], true
+		}
+
+		if idx := slices.IndexFunc(supported, func(s string) bool { return strings.EqualFold(s, tag) }); idx >= 0 {
+			return supported[idx], true
+		}
+
+		primary, _, found := strings.Cut(tag, "-")
+		if !found {
+			continue
+		}
+
+		if idx := slices.IndexFunc(supported, func(s string) bool { return strings.EqualFold(s, primary) }); idx >= 0 {
+			return supported[idx], true
+		}
+	}
+
+	return "", false
+}
+
+func parseAcceptLanguage(header string) AcceptLanguage {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag = strings.TrimSpace(tag); tag == "" {
+			continue
+		}
+
+		quality := 1.0
+
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+
+			quality = parsed
+		}
+
+		if quality <= 0 {
+			continue
+		}
+
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+
+	// keep the order of the header for tags with equal quality
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	var result AcceptLanguage
+	for _, tag := range tags {
+		result = append(result, tag.tag)
+	}
+
+	return result
+}
+
+// MessageCatalog provides the translations of messages.
+type MessageCatalog interface {
+	// Languages returns the supported languages, the first one is the default.
+	Languages() []string
+
+	// Message returns the translation of the message with the given key.
+	// Returns false if there is no translation.
+	Message(language string, key string) (string, bool)
+}
+
+// MapCatalog is a MessageCatalog backed by a map from language to message key to translation.
+type MapCatalog map[string]map[string]string
+
+// Languages returns the languages of the catalog in alphabetical order, so the language
+// sorting first is the default. Use WithDefault to choose a different default.
+func (c MapCatalog) Languages() []string {
+	var languages []string
+	for language := range c {
+		languages = append(languages, language)
+	}
+
+	slices.Sort(languages)
+	return languages
+}
+
+func (c MapCatalog) Message(language string, key string) (string, bool) {
+	message, ok := c[language][key]
+	return message, ok
+}
+
+// WithDefault returns a MessageCatalog with the translations of c, that uses the given
+// language as default, e.g. for requests with an Accept-Language header of "*".
+func (c MapCatalog) WithDefault(language string) MessageCatalog {
+	return defaultLanguageCatalog{MapCatalog: c, language: language}
+}
+
+type defaultLanguageCatalog struct {
+	MapCatalog
+	language string
+}
+
+// Languages returns the default language, followed by the other languages in alphabetical order.
+func (c defaultLanguageCatalog) Languages() []string {
+	languages := slices.DeleteFunc(c.MapCatalog.Languages(), func(language string) bool {
+		return language == c.language
+	})
+
+	return append([]string{c.language}, languages...)
+}
+
+var messageCatalog atomic.Pointer[MessageCatalog]
+
+// SetMessageCatalog sets the MessageCatalog used to localize error responses.
+//
+// Like JSONOptions, a MessageCatalog is looked up in the requests context first, where it
+// can be set using ProvideContextValue[MessageCatalog]. Otherwise, the catalog set by
+// SetMessageCatalog is used.
+//
+// The language is selected using the Accept-Language header of the request. Errors in
+// languages not supported by the catalog are not localized. Error responses are localized
+// using the following message keys:
+//
+//   - "status.<code>", e.g. "status.404", for the title of a response.ProblemError
+//   - the Key of a LocalizedError, for the message of the error
+//   - the message of a serde.MessageError, i.e. the text of an errmsg struct tag
+//
+// The selected language is sent in the Content-Language header.
+func SetMessageCatalog(catalog MessageCatalog) {
+	messageCatalog.Store(&catalog)
+}
+
+func messageCatalogOf(r *http.Request) MessageCatalog {
+	if catalog, ok := contextValueOf[MessageCatalog](r); ok {
+		return catalog
+	}
+
+	if catalog := messageCatalog.Load(); catalog != nil {
+		return *catalog
+	}
+
+	return nil
+}
+
+// LocalizedError is an error with a message that is translated using the MessageCatalog
+// when the error is sent to the client. The translation is used as a format string for Args.
+//
+//	return gum.NewLocalizedError("user.not_found", userID)
+type LocalizedError struct {
+	Key  string
+	Args []any
+
+	// Err is the error returned by Unwrap, if any
+	Err error
+}
+
+// NewLocalizedError creates a new LocalizedError.
+func NewLocalizedError(key string, args ...any) *LocalizedError {
+	return &LocalizedError{Key: key, Args: args}
+}
+
+// Error returns the key and arguments of the message, as no language is known.
+func (e *LocalizedError) Error() string {
+	if len(e.Args) == 0 {
+		return e.Key
+	}
+
+	return fmt.Sprintf("%s %v", e.Key, e.Args)
+}
+
+func (e *LocalizedError) Unwrap() error {
+	return e.Err
+}
+
+// translatedError is an error whose message was translated.
+type translatedError struct {
+	message string
+	title   string
+	err     error
+}
+
+func (e translatedError) Error() string {
+	return e.message
+}
+
+func (e translatedError) Unwrap() error {
+	return e.err
+}
+
+// ProblemTitle implements response.ProblemTitler.
+func (e translatedError) ProblemTitle() string {
+	return e.title
+}
+
+// localizeError translates err into the language preferred by the request using catalog,
+// which may be nil. Returns the language used, or an empty string if the error was not translated.
+func localizeError(r *http.Request, catalog MessageCatalog, err error, statusCode int) (error, string) {
+	if catalog == nil {
+		return err, ""
+	}
+
+	language, ok := parseAcceptLanguage(r.Header.Get("Accept-Language")).Match(catalog.Languages()...)
+	if !ok {
+		return err, ""
+	}
+
+	translated := translatedError{message: err.Error(), err: err}
+
+	var found bool
+	translated.title, found = catalog.Message(language, "status."+strconv.Itoa(statusCode))
+
+	var localizedErr *LocalizedError
+	var messageErr *serde.MessageError
+
+	switch {
+	case errors.As(err, &localizedErr):
+		if format, ok := catalog.Message(language, localizedErr.Key); ok {
+			translated.message = fmt.Sprintf(format, localizedErr.Args...)
+			found = true
+		}
+
+	case errors.As(err, &messageErr):
+		if message, ok := catalog.Message(language, messageErr.Message); ok {
+			translated.message = message
+			found = true
+		}
+	}
+
+	if !found {
+		// nothing was translated, the response is not in the matched language
+		return err, ""
+	}
+
+	return translated, language
+}
@@ -0,0 +1,132 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	AssertEqual(t, parseAcceptLanguage(""), nil)
+	AssertEqual(t, parseAcceptLanguage("de"), AcceptLanguage{"de"})
+	AssertEqual(t, parseAcceptLanguage("en;q=0.5, de-AT, fr;q=0.8, it;q=0"), AcceptLanguage{"de-AT", "fr", "en"})
+	AssertEqual(t, parseAcceptLanguage("en;q=x, de"), AcceptLanguage{"de"})
+}
+
+func TestAcceptLanguageMatch(t *testing.T) {
+	languages := AcceptLanguage{"de-AT", "fr"}
+
+	lang, ok := languages.Match("en", "de")
+	AssertEqual(t, ok, true)
+	AssertEqual(t, lang, "de")
+
+	lang, ok = languages.Match("en", "FR", "de-at")
+	AssertEqual(t, ok, true)
+	AssertEqual(t, lang, "de-at")
+
+	_, ok = languages.Match("en")
+	AssertEqual(t, ok, false)
+
+	lang, ok = AcceptLanguage{"*"}.Match("en", "de")
+	AssertEqual(t, ok, true)
+	AssertEqual(t, lang, "en")
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	catalog := MapCatalog{
+		"en": {
+			"user.not_found": "user %d not found",
+		},
+		"de": {
+			"status.404":     "Nicht gefunden",
+			"user.not_found": "Benutzer %d nicht gefunden",
+		},
+	}
+
+	handler := ProvideContextValue[MessageCatalog](catalog)(Handler(func() error {
+		return NewHTTPError(http.StatusNotFound, NewLocalizedError("user.not_found", 12))
+	}, WithErrorEncoder(response.ProblemError)))
+
+	serve := func(acceptLanguage string) (gumtest.Response, response.Problem) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+
+		rec := gumtest.Serve(handler, req)
+		problem := gumtest.DecodeJSON[response.Problem](t, rec)
+
+		return rec, problem
+	}
+
+	rec, problem := serve("de-DE, en;q=0.5")
+	AssertEqual(t, rec.StatusCode, http.StatusNotFound)
+	AssertEqual(t, rec.Header.Get("Content-Language"), "de")
+	AssertEqual(t, rec.Header.Get("Vary"), "Accept-Language")
+	AssertEqual(t, problem.Title, "Nicht gefunden")
+	AssertEqual(t, problem.Detail, "Benutzer 12 nicht gefunden")
+
+	// no translated title for english, falls back to the status text
+	rec, problem = serve("en")
+	AssertEqual(t, rec.Header.Get("Content-Language"), "en")
+	AssertEqual(t, problem.Title, "Not Found")
+	AssertEqual(t, problem.Detail, "user 12 not found")
+
+	// unsupported language, the error is not localized
+	rec, problem = serve("fr")
+	AssertEqual(t, rec.Header.Get("Content-Language"), "")
+	AssertEqual(t, rec.Header.Get("Vary"), "Accept-Language")
+	AssertEqual(t, problem.Detail, "user.not_found [12]")
+}
+
+func TestLocalizedMessageError(t *testing.T) {
+	type Query struct {
+		Page int `json:"page" errmsg:"page.invalid"`
+	}
+
+	catalog := MapCatalog{
+		"de": {"page.invalid": "Ungültige Seite"},
+	}
+
+	handler := ProvideContextValue[MessageCatalog](catalog)(Handler(func(query QueryValues[Query]) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/?page=x", nil)
+	req.Header.Set("Accept-Language", "de")
+
+	rec := gumtest.Serve(handler, req)
+	AssertEqual(t, rec.StatusCode, http.StatusBadRequest)
+	AssertEqual(t, rec.Header.Get("Content-Language"), "de")
+	AssertEqual(t, string(rec.Body), "Ungültige Seite")
+}
+
+func TestLocalizedErrorsNotTranslated(t *testing.T) {
+	catalog := MapCatalog{
+		"de": {"user.not_found": "Benutzer %d nicht gefunden"},
+	}
+
+	handler := ProvideContextValue[MessageCatalog](catalog)(Handler(func() error {
+		return NewHTTPError(http.StatusConflict, errors.New("conflict"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de")
+
+	// neither the title nor the message were translated
+	rec := gumtest.Serve(handler, req)
+	AssertEqual(t, rec.Header.Get("Content-Language"), "")
+	AssertEqual(t, rec.Header.Get("Vary"), "Accept-Language")
+	AssertEqual(t, string(rec.Body), "conflict")
+}
+
+func TestMapCatalogLanguages(t *testing.T) {
+	catalog := MapCatalog{"en": {}, "de": {}, "fr": {}}
+	AssertEqual(t, catalog.Languages(), []string{"de", "en", "fr"})
+	AssertEqual(t, catalog.WithDefault("en").Languages(), []string{"en", "de", "fr"})
+
+	// a wildcard selects the default language
+	lang, ok := AcceptLanguage{"*"}.Match(catalog.WithDefault("fr").Languages()...)
+	AssertEqual(t, ok, true)
+	AssertEqual(t, lang, "fr")
+}
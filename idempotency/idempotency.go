@@ -0,0 +1,283 @@
+// Package idempotency makes retries of non-idempotent requests safe.
+//
+// Clients send a unique Idempotency-Key header with a request. The Middleware stores the
+// response of the first request with that key in a Store, and replays the stored response
+// for every retry of the same request within the configured time to live:
+//
+//	store := idempotency.NewMemoryStore()
+//	http.ListenAndServe(":8080", idempotency.Middleware(store)(mux))
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// HeaderName is the name of the request header holding the idempotency key.
+const HeaderName = "Idempotency-Key"
+
+// Record is a stored response for an idempotency key.
+type Record struct {
+	// Fingerprint identifies the request that created this record. A retry
+	// with the same key must have the same fingerprint.
+	Fingerprint string
+
+	// Pending is true while the first request is still being processed.
+	Pending bool
+
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Store stores the Record of each idempotency key.
+type Store interface {
+	// Reserve atomically creates a pending Record for the key, if no record exists yet.
+	// If a record already exists, it is returned and no new record is created.
+	// Returns a nil record if the key was reserved successfully.
+	Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*Record, error)
+
+	// Complete replaces the pending record of the key with the final record.
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+
+	// Release removes the record of the key, so that a retry can be processed again.
+	Release(ctx context.Context, key string) error
+}
+
+// Key is the value of the Idempotency-Key header.
+// Extraction fails if the request does not have an Idempotency-Key header.
+type Key string
+
+func init() {
+	gum.Register(func(r *http.Request) (Key, error) {
+		key := r.Header.Get(HeaderName)
+		if key == "" {
+			return "", fmt.Errorf("no %s header in request", HeaderName)
+		}
+
+		return Key(key), nil
+	})
+}
+
+// Option configures the Middleware.
+type Option func(config *config)
+
+type config struct {
+	ttl      time.Duration
+	methods  []string
+	required bool
+	scope    func(r *http.Request) string
+	maxBytes int64
+}
+
+// TTL sets how long responses are stored. Defaults to 24 hours.
+func TTL(ttl time.Duration) Option {
+	return func(config *config) {
+		config.ttl = ttl
+	}
+}
+
+// Methods sets the request methods the middleware applies to. Defaults to POST and PATCH.
+func Methods(methods ...string) Option {
+	return func(config *config) {
+		config.methods = methods
+	}
+}
+
+// Required rejects requests without an Idempotency-Key header with 400 Bad Request.
+func Required() Option {
+	return func(config *config) {
+		config.required = true
+	}
+}
+
+// Scope sets a function that derives a scope from the request, e.g. the id of the
+// authenticated user. Keys are only unique within their scope.
+func Scope(scope func(r *http.Request) string) Option {
+	return func(config *config) {
+		config.scope = scope
+	}
+}
+
+// MaxBodySize limits the size of request bodies, which are read into memory to compute
+// the fingerprint of the request. Larger bodies are rejected with 413 Request Entity
+// Too Large. Defaults to 1MB.
+func MaxBodySize(n int64) Option {
+	return func(config *config) {
+		config.maxBytes = n
+	}
+}
+
+// Middleware stores responses in the Store and replays them for retries.
+//
+//   - A retry of a request that is still processed is rejected with 409 Conflict.
+//   - Reusing a key for a different request is rejected with 422 Unprocessable Entity.
+//   - Server errors (5xx) are not stored, so the request can be retried.
+//
+// Replayed responses carry the header "Idempotent-Replayed: true".
+func Middleware(store Store, options ...Option) gum.Middleware {
+	config := config{
+		ttl:      24 * time.Hour,
+		methods:  []string{http.MethodPost, http.MethodPatch},
+		maxBytes: 1 << 20,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !slices.Contains(config.methods, r.Method) {
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			key := r.Header.Get(HeaderName)
+			if key == "" {
+				if config.required {
+					http.Error(w, fmt.Sprintf("no %s header in request", HeaderName), http.StatusBadRequest)
+					return
+				}
+
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			if config.scope != nil {
+				key = config.scope(r) + ":" + key
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.maxBytes))
+			if err != nil {
+				statusCode := http.StatusBadRequest
+
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					statusCode = http.StatusRequestEntityTooLarge
+				}
+
+				http.Error(w, "reading body: "+err.Error(), statusCode)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			fingerprint := fingerprintOf(r, body)
+
+			existing, err := store.Reserve(ctx, key, fingerprint, config.ttl)
+			switch {
+			case err != nil:
+				http.Error(w, "idempotency store: "+err.Error(), http.StatusInternalServerError)
+				return
+
+			case existing != nil && existing.Fingerprint != fingerprint:
+				http.Error(w, "idempotency key was used for a different request", http.StatusUnprocessableEntity)
+				return
+
+			case existing != nil && existing.Pending:
+				http.Error(w, "a request with this idempotency key is in progress", http.StatusConflict)
+				return
+
+			case existing != nil:
+				replay(w, *existing)
+				return
+			}
+
+			cw := &captureWriter{ResponseWriter: w}
+
+			completed := false
+			defer func() {
+				if !completed {
+					// the handler panicked, allow a retry
+					_ = store.Release(context.WithoutCancel(ctx), key)
+				}
+			}()
+
+			delegate.ServeHTTP(cw, r)
+			completed = true
+
+			statusCode := cw.StatusCode()
+			if statusCode >= 500 {
+				_ = store.Release(context.WithoutCancel(ctx), key)
+				return
+			}
+
+			record := Record{
+				Fingerprint: fingerprint,
+				StatusCode:  statusCode,
+				Header:      cw.Header().Clone(),
+				Body:        cw.body.Bytes(),
+			}
+
+			_ = store.Complete(context.WithoutCancel(ctx), key, record, config.ttl)
+		})
+	}
+}
+
+func fingerprintOf(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	_, _ = io.WriteString(hash, r.Method+" "+r.URL.String()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, record Record) {
+	for key, values := range record.Header {
+		w.Header()[key] = slices.Clone(values)
+	}
+
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.Header().Set("Content-Length", strconv.Itoa(len(record.Body)))
+
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// captureWriter records the status code and body of a response.
+type captureWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *captureWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *captureWriter) Write(bytes []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	w.body.Write(bytes)
+	return w.ResponseWriter.Write(bytes)
+}
+
+func (w *captureWriter) StatusCode() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return w.statusCode
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,120 @@
+package idempotency
+
+import (
+	"context"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	var calls int
+	var extractedKey Key
+
+	handler := Middleware(NewMemoryStore())(gum.Handler(func(key Key) http.Handler {
+		calls += 1
+		extractedKey = key
+		return response.Text("created").WithStatusCode(http.StatusCreated)
+	}))
+
+	serve := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(body))
+		req.Header.Set(HeaderName, key)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("key-1", "order")
+	AssertEqual(t, rec.Code, http.StatusCreated)
+	AssertEqual(t, extractedKey, Key("key-1"))
+
+	// a retry is replayed without calling the handler again
+	rec = serve("key-1", "order")
+	AssertEqual(t, rec.Code, http.StatusCreated)
+	AssertEqual(t, rec.Body.String(), "created")
+	AssertEqual(t, rec.Header().Get("Idempotent-Replayed"), "true")
+	AssertEqual(t, calls, 1)
+
+	// reusing the key for a different request fails
+	rec = serve("key-1", "another order")
+	AssertEqual(t, rec.Code, http.StatusUnprocessableEntity)
+	AssertEqual(t, calls, 1)
+
+	// a new key is processed
+	rec = serve("key-2", "order")
+	AssertEqual(t, rec.Code, http.StatusCreated)
+	AssertEqual(t, calls, 2)
+}
+
+func TestMiddleware_ServerErrorsAreNotStored(t *testing.T) {
+	var calls int
+
+	handler := Middleware(NewMemoryStore())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls += 1
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	for range 2 {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set(HeaderName, "key")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	AssertEqual(t, calls, 2)
+}
+
+func TestMiddleware_Pending(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.Reserve(context.Background(), "key", fingerprintOf(httptest.NewRequest("POST", "/", nil), nil), time.Hour)
+	AssertEqual(t, err, nil)
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set(HeaderName, "key")
+
+	rec := httptest.NewRecorder()
+	Middleware(store)(http.NotFoundHandler()).ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusConflict)
+}
+
+func TestMiddleware_MaxBodySize(t *testing.T) {
+	handler := Middleware(NewMemoryStore(), MaxBodySize(4))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader("too large"))
+	req.Header.Set(HeaderName, "key")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusRequestEntityTooLarge)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, err := store.Reserve(ctx, "expired", "fingerprint", time.Second)
+	AssertEqual(t, err, nil)
+
+	now = now.Add(time.Minute)
+
+	for range sweepInterval {
+		_, err := store.Reserve(ctx, "other", "fingerprint", time.Second)
+		AssertEqual(t, err, nil)
+	}
+
+	_, ok := store.records["expired"]
+	AssertEqual(t, ok, false)
+}
@@ -0,0 +1,96 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the number of calls to Reserve after which expired records are removed.
+const sweepInterval = 1024
+
+// ErrNotReserved is returned by MemoryStore.Complete if the key was not reserved.
+var ErrNotReserved = errors.New("idempotency key is not reserved")
+
+// MemoryStore is a Store that keeps all records in memory.
+// Expired records are removed periodically.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+	now     func() time.Time
+	calls   int
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+type memoryRecord struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: map[string]memoryRecord{},
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryStore) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if existing, ok := m.records[key]; ok && now.Before(existing.expiresAt) {
+		record := existing.record
+		return &record, nil
+	}
+
+	m.records[key] = memoryRecord{
+		record:    Record{Fingerprint: fingerprint, Pending: true},
+		expiresAt: now.Add(ttl),
+	}
+
+	return nil, nil
+}
+
+func (m *MemoryStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.records[key]; !ok {
+		return ErrNotReserved
+	}
+
+	m.records[key] = memoryRecord{
+		record:    record,
+		expiresAt: m.now().Add(ttl),
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, key)
+	return nil
+}
+
+// sweep removes expired records.
+func (m *MemoryStore) sweep(now time.Time) {
+	m.calls += 1
+	if m.calls%sweepInterval != 0 {
+		return
+	}
+
+	for key, existing := range m.records {
+		if !now.Before(existing.expiresAt) {
+			delete(m.records, key)
+		}
+	}
+}
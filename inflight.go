@@ -0,0 +1,119 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrShuttingDown is sent to clients whose requests arrive after the shutdown started.
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// InFlight tracks the requests that are currently handled. Once Shutdown was called,
+// new requests are rejected with 503 Service Unavailable, and Wait blocks until all
+// requests that were already accepted are finished.
+//
+// Unlike http.Server.Shutdown, InFlight also waits for requests whose connection
+// was hijacked, e.g. websockets, and for handlers that keep running after their
+// connection was closed. Pass it to a Server using ServerInFlight:
+//
+//	inFlight := gum.NewInFlight()
+//	server := gum.NewServer(":8080", mux, gum.ServerInFlight(inFlight))
+type InFlight struct {
+	mu       sync.Mutex
+	count    int
+	draining bool
+	waiters  []chan struct{}
+}
+
+// NewInFlight creates a new InFlight tracker.
+func NewInFlight() *InFlight {
+	return &InFlight{}
+}
+
+// Middleware returns a Middleware that tracks the requests passing through it.
+func (t *InFlight) Middleware() Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !t.acquire() {
+				err := NewHTTPError(http.StatusServiceUnavailable, ErrShuttingDown).
+					WithHeader("Connection", "close")
+
+				errorResponse(err, http.StatusServiceUnavailable).ServeHTTP(w, r)
+				return
+			}
+
+			defer t.release()
+
+			delegate.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Count returns the number of requests currently in flight.
+func (t *InFlight) Count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.count
+}
+
+// Shutdown starts rejecting new requests. Requests that are already in flight
+// are not affected.
+func (t *InFlight) Shutdown() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.draining = true
+}
+
+// Wait blocks until no request is in flight or ctx is done.
+// It returns the error of ctx, if requests were still in flight.
+func (t *InFlight) Wait(ctx context.Context) error {
+	t.mu.Lock()
+
+	if t.count == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+
+	idle := make(chan struct{})
+	t.waiters = append(t.waiters, idle)
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *InFlight) acquire() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return false
+	}
+
+	t.count++
+	return true
+}
+
+func (t *InFlight) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.count--
+
+	if t.count == 0 {
+		for _, idle := range t.waiters {
+			close(idle)
+		}
+
+		t.waiters = nil
+	}
+}
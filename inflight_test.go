@@ -0,0 +1,53 @@
+package gum
+
+import (
+	"context"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInFlight(t *testing.T) {
+	inFlight := NewInFlight()
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+
+	handler := inFlight.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	done := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		done <- rec.Code
+	}()
+
+	<-started
+	AssertEqual(t, inFlight.Count(), 1)
+
+	inFlight.Shutdown()
+
+	// new requests are rejected
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.Code, http.StatusServiceUnavailable)
+	AssertEqual(t, rec.Header().Get("Connection"), "close")
+
+	// waiting times out while the request is in flight
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	AssertEqual(t, inFlight.Wait(ctx), context.DeadlineExceeded)
+
+	close(finish)
+	AssertEqual(t, <-done, http.StatusNoContent)
+
+	AssertEqual(t, inFlight.Wait(context.Background()), nil)
+	AssertEqual(t, inFlight.Count(), 0)
+}
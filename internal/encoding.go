@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AcceptedEncodings returns the encodings of supported the Accept-Encoding header accepts,
+// ordered by their quality. Encodings with the same quality keep the order of supported.
+// Encodings not listed in the header are accepted with the quality of the "*" wildcard.
+func AcceptedEncodings(acceptEncoding string, supported []string) []string {
+	if acceptEncoding == "" {
+		return nil
+	}
+
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		qualities[name] = q
+	}
+
+	qualityOf := func(encoding string) float64 {
+		if q, ok := qualities[encoding]; ok {
+			return q
+		}
+
+		return qualities["*"]
+	}
+
+	var accepted []string
+	for _, encoding := range supported {
+		if qualityOf(encoding) > 0 {
+			accepted = append(accepted, encoding)
+		}
+	}
+
+	slices.SortStableFunc(accepted, func(a, b string) int {
+		// highest quality first
+		return cmp.Compare(qualityOf(b), qualityOf(a))
+	})
+
+	return accepted
+}
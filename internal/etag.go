@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ETag is a parsed entity tag. Only an unquoted "*" is the wildcard,
+// a quoted "*" is a regular entity tag.
+type ETag struct {
+	Tag      string
+	Weak     bool
+	Wildcard bool
+}
+
+// ParseETags parses a comma separated list of entity tags as used in the
+// If-Match and If-None-Match headers. Commas within the quoted tags are allowed.
+func ParseETags(value string) ([]ETag, error) {
+	var tags []ETag
+
+	for {
+		value = strings.TrimLeft(value, " \t,")
+		if value == "" {
+			return tags, nil
+		}
+
+		if value[0] == '*' {
+			tags = append(tags, ETag{Wildcard: true})
+			value = value[1:]
+			continue
+		}
+
+		var weak bool
+		if strings.HasPrefix(value, "W/") {
+			weak = true
+			value = value[2:]
+		}
+
+		if !strings.HasPrefix(value, `"`) {
+			return nil, errors.New("entity tag must be quoted")
+		}
+
+		end := strings.IndexByte(value[1:], '"')
+		if end == -1 {
+			return nil, errors.New("unterminated entity tag")
+		}
+
+		tags = append(tags, ETag{Tag: value[1 : end+1], Weak: weak})
+		value = value[end+2:]
+
+		// the next character must be the end of the list or a separator
+		rest := strings.TrimLeft(value, " \t")
+		if rest != "" && rest[0] != ',' {
+			return nil, fmt.Errorf("unexpected character %q after entity tag", rest[0])
+		}
+	}
+}
+
+// NoneMatch evaluates the If-None-Match header value against the etag of the resource
+// using the weak comparison function. Returns true if the header matches the etag,
+// meaning the client already has the current representation.
+func NoneMatch(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+
+	current, err := ParseETags(etag)
+	if err != nil || len(current) != 1 || current[0].Wildcard {
+		return false
+	}
+
+	tags, err := ParseETags(ifNoneMatch)
+	if err != nil {
+		return false
+	}
+
+	for _, tag := range tags {
+		if tag.Wildcard || tag.Tag == current[0].Tag {
+			return true
+		}
+	}
+
+	return false
+}
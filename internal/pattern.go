@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// GumPackage is the import path of the gum package. It is used by the
+// openapi and gumclient packages to recognize the extractor types of gum.
+const GumPackage = "github.com/go-gum/gum"
+
+// Wildcard is a wildcard of a http.ServeMux pattern, like "{id}" or "{path...}".
+type Wildcard struct {
+	Name string
+
+	// Rest is true for wildcards matching the rest of the path, like "{path...}"
+	Rest bool
+}
+
+var rePatternWildcard = regexp.MustCompile(`\{([^}.]*)(\.\.\.)?}`)
+
+// SplitPattern splits a http.ServeMux pattern into method and path. The method
+// defaults to GET. The host and a trailing "{$}" are removed from the path.
+func SplitPattern(pattern string) (string, string) {
+	method, path, ok := strings.Cut(strings.TrimSpace(pattern), " ")
+	if !ok {
+		method, path = http.MethodGet, pattern
+	}
+
+	path = strings.TrimSpace(path)
+
+	// strip the host part
+	if idx := strings.IndexByte(path, '/'); idx > 0 {
+		path = path[idx:]
+	}
+
+	return method, strings.TrimSuffix(path, "{$}")
+}
+
+// Wildcards returns the wildcards of the path of a pattern in order.
+func Wildcards(path string) []Wildcard {
+	var wildcards []Wildcard
+	for _, match := range rePatternWildcard.FindAllStringSubmatch(path, -1) {
+		wildcards = append(wildcards, Wildcard{Name: match[1], Rest: match[2] != ""})
+	}
+
+	return wildcards
+}
+
+// ReplaceWildcards replaces each wildcard in the path of a pattern with the result of fn.
+func ReplaceWildcards(path string, fn func(wildcard Wildcard) string) string {
+	return rePatternWildcard.ReplaceAllStringFunc(path, func(text string) string {
+		match := rePatternWildcard.FindStringSubmatch(text)
+		return fn(Wildcard{Name: match[1], Rest: match[2] != ""})
+	})
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+type routeKey struct{}
+
+// Route holds the pattern of the route that handled a request. Middlewares install it into
+// the requests context before calling the next handler, the matched handler records its
+// pattern. This way the pattern is visible to the middleware, even if a middleware in between
+// passed on a copy of the request, e.g. using WithContext.
+type Route struct {
+	pattern atomic.Pointer[string]
+}
+
+// WithRoute returns a request carrying a Route, reusing the Route of r, if it has one.
+func WithRoute(r *http.Request) (*http.Request, *Route) {
+	if route, ok := r.Context().Value(routeKey{}).(*Route); ok {
+		return r, route
+	}
+
+	route := &Route{}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, route)), route
+}
+
+// RecordPattern records the pattern of r in the Route of its context, if r was routed
+// by a http.ServeMux and passed through a middleware that installed a Route.
+func RecordPattern(r *http.Request) {
+	if r.Pattern == "" {
+		return
+	}
+
+	if route, ok := r.Context().Value(routeKey{}).(*Route); ok {
+		route.pattern.Store(&r.Pattern)
+	}
+}
+
+// Pattern returns the recorded pattern. It falls back to the pattern of r,
+// the request the Route was installed into, if no pattern was recorded.
+func (route *Route) Pattern(r *http.Request) string {
+	if pattern := route.pattern.Load(); pattern != nil {
+		return *pattern
+	}
+
+	return r.Pattern
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"net/http"
+)
+
+// RecordingWriter wraps a http.ResponseWriter and records the status code
+// and the number of bytes written.
+type RecordingWriter struct {
+	http.ResponseWriter
+
+	statusCode   int
+	bytesWritten int64
+}
+
+func NewRecordingWriter(w http.ResponseWriter) *RecordingWriter {
+	return &RecordingWriter{ResponseWriter: w}
+}
+
+func (w *RecordingWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *RecordingWriter) Write(bytes []byte) (int, error) {
+	if w.statusCode == 0 {
+		// an implicit call to WriteHeader
+		w.statusCode = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(bytes)
+	w.bytesWritten += int64(n)
+	return n, err
+}
+
+// StatusCode returns the status code written to the response.
+// Defaults to 200 if nothing has been written yet.
+func (w *RecordingWriter) StatusCode() int {
+	if w.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return w.statusCode
+}
+
+// Committed reports whether the status code was written, either explicitly, by writing
+// the body or by flushing. The headers can not be changed anymore after that.
+func (w *RecordingWriter) Committed() bool {
+	return w.statusCode != 0
+}
+
+// BytesWritten returns the number of bytes written to the response body.
+func (w *RecordingWriter) BytesWritten() int64 {
+	return w.bytesWritten
+}
+
+// Flush flushes the underlying writer if it supports flushing.
+func (w *RecordingWriter) Flush() {
+	if w.statusCode == 0 {
+		// flushing commits the headers with an implicit status code
+		w.statusCode = http.StatusOK
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController
+func (w *RecordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
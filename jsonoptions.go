@@ -0,0 +1,113 @@
+package gum
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-gum/gum/serde"
+	"io"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+)
+
+// JSONOptions configures how the JSON extractor decodes request bodies.
+//
+// Options are looked up in the requests context first, where they can be set for a group
+// of routes using ProvideContextValue or for a single Handler using WithJSONOptions.
+// Otherwise, the options set by SetJSONOptions are used.
+type JSONOptions struct {
+	// DisallowUnknownFields rejects bodies containing object keys that
+	// do not match any field of the target type.
+	DisallowUnknownFields bool
+
+	// UseNumber decodes numbers into interface values as json.Number instead of float64.
+	UseNumber bool
+
+	// UseSerde decodes the body using serde.ParseJSON instead of encoding/json. Fields are
+	// then bound exactly like QueryValues and PathValues do, including the norm, enum and
+	// errmsg struct tags. DisallowUnknownFields and UseNumber have no effect in this mode.
+	UseSerde bool
+
+	// MaxBytes limits the size of the request body. Larger bodies are rejected
+	// with 413 Request Entity Too Large. Zero means no limit.
+	MaxBytes int64
+}
+
+var jsonOptions atomic.Pointer[JSONOptions]
+
+// SetJSONOptions sets the JSONOptions used by all handlers that do not
+// provide their own options in the requests context.
+func SetJSONOptions(options JSONOptions) {
+	jsonOptions.Store(&options)
+}
+
+// WithJSONOptions sets the JSONOptions used by the JSON extractor of the Handler.
+func WithJSONOptions(options JSONOptions) HandlerOption {
+	return func(config *handlerConfig) {
+		config.jsonOptions = &options
+	}
+}
+
+var tyJSONOptions = reflect.TypeFor[JSONOptions]()
+
+// jsonOptionsOf returns the JSONOptions for the request.
+func jsonOptionsOf(r *http.Request) JSONOptions {
+	if options, ok := contextValueOf[JSONOptions](r); ok {
+		return options
+	}
+
+	if options := jsonOptions.Load(); options != nil {
+		return *options
+	}
+
+	return JSONOptions{}
+}
+
+// decodeJSON decodes the body of r into target using the JSONOptions of the request.
+func decodeJSON(r *http.Request, target any) error {
+	options := jsonOptionsOf(r)
+	return bodyTooLarge(decodeJSONBody(options.limitBody(r), target, options))
+}
+
+// limitBody returns the body of r, limited to MaxBytes if set.
+func (o JSONOptions) limitBody(r *http.Request) io.Reader {
+	if o.MaxBytes > 0 {
+		return http.MaxBytesReader(nil, r.Body, o.MaxBytes)
+	}
+
+	return r.Body
+}
+
+// bodyTooLarge turns an error caused by a body exceeding its limit
+// into an HTTPError with status 413 Request Entity Too Large.
+func bodyTooLarge(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return NewHTTPError(http.StatusRequestEntityTooLarge, err)
+	}
+
+	return err
+}
+
+func decodeJSONBody(body io.Reader, target any, options JSONOptions) error {
+	if options.UseSerde {
+		source, err := serde.ParseJSON(body)
+		if err != nil {
+			return err
+		}
+
+		return serde.Unmarshal(source, target)
+	}
+
+	decoder := json.NewDecoder(body)
+
+	if options.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if options.UseNumber {
+		decoder.UseNumber()
+	}
+
+	return decoder.Decode(target)
+}
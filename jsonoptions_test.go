@@ -0,0 +1,113 @@
+package gum
+
+import (
+	"encoding/json"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONOptions(t *testing.T) {
+	type Payload struct {
+		Name  string `json:"name"`
+		Extra any    `json:"extra"`
+	}
+
+	fn := func(body JSON[Payload]) response.Response {
+		_, isNumber := body.Value.Extra.(json.Number)
+		if isNumber {
+			return response.Text("number")
+		}
+
+		return response.Text(body.Value.Name)
+	}
+
+	request := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		resp := gumtest.Serve(Handler(fn), request(`{"name": "Albert", "unknown": 1}`))
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+		AssertEqual(t, resp.Text(), "Albert")
+	})
+
+	t.Run("DisallowUnknownFields", func(t *testing.T) {
+		handler := Handler(fn, WithJSONOptions(JSONOptions{DisallowUnknownFields: true}))
+
+		resp := gumtest.Serve(handler, request(`{"name": "Albert", "unknown": 1}`))
+		AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+		AssertTrue(t, strings.Contains(resp.Text(), `unknown field "unknown"`))
+	})
+
+	t.Run("UseNumber", func(t *testing.T) {
+		handler := Handler(fn, WithJSONOptions(JSONOptions{UseNumber: true}))
+
+		resp := gumtest.Serve(handler, request(`{"extra": 12}`))
+		AssertEqual(t, resp.Text(), "number")
+	})
+
+	t.Run("MaxBytes", func(t *testing.T) {
+		handler := ProvideContextValue(JSONOptions{MaxBytes: 16})(Handler(fn))
+
+		resp := gumtest.Serve(handler, request(`{"name": "Albert Einstein"}`))
+		AssertEqual(t, resp.StatusCode, http.StatusRequestEntityTooLarge)
+
+		resp = gumtest.Serve(handler, request(`{"name": "Al"}`))
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+	})
+
+	t.Run("Lazy", func(t *testing.T) {
+		provide := ProvideContextValueFunc(func(r *http.Request) (JSONOptions, error) {
+			return JSONOptions{MaxBytes: 16}, nil
+		})
+
+		resp := gumtest.Serve(provide(Handler(fn)), request(`{"name": "Albert Einstein"}`))
+		AssertEqual(t, resp.StatusCode, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("Global", func(t *testing.T) {
+		SetJSONOptions(JSONOptions{DisallowUnknownFields: true})
+		defer SetJSONOptions(JSONOptions{})
+
+		resp := gumtest.Serve(Handler(fn), request(`{"unknown": 1}`))
+		AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+
+		// options in the context take precedence
+		handler := Handler(fn, WithJSONOptions(JSONOptions{}))
+		resp = gumtest.Serve(handler, request(`{"unknown": 1}`))
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+	})
+}
+
+func TestJSONOptionsUseSerde(t *testing.T) {
+	type Signup struct {
+		Email string `json:"email" norm:"trim,lower"`
+		Plan  string `json:"plan" enum:"free,pro"`
+		Age   int    `json:"age" errmsg:"age must be a number"`
+	}
+
+	handler := Handler(func(body JSON[Signup]) response.Response {
+		return response.Text(body.Value.Email + " " + body.Value.Plan)
+	}, WithJSONOptions(JSONOptions{UseSerde: true}))
+
+	request := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	}
+
+	resp := gumtest.Serve(handler, request(`{"email": " Albert@Example.COM ", "plan": "pro"}`))
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertEqual(t, resp.Text(), "albert@example.com pro")
+
+	resp = gumtest.Serve(handler, request(`{"plan": "enterprise"}`))
+	AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+	AssertTrue(t, strings.Contains(resp.Text(), `invalid value "enterprise"`))
+
+	resp = gumtest.Serve(handler, request(`{"age": "old"}`))
+	AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+	AssertEqual(t, resp.Text(), "age must be a number")
+}
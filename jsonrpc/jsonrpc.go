@@ -0,0 +1,333 @@
+// Package jsonrpc serves JSON-RPC 2.0 methods over http.
+//
+// Methods are plain functions taking a context and typed parameters. The parameters are
+// decoded from the request using serde, so they support the same field tags as
+// gum.QueryValues and gum.JSON:
+//
+//	type AddParams struct {
+//		A int `json:"a"`
+//		B int `json:"b"`
+//	}
+//
+//	server := jsonrpc.New()
+//
+//	jsonrpc.Method(server, "add", func(ctx context.Context, params AddParams) (int, error) {
+//		return params.A + params.B, nil
+//	})
+//
+//	http.Handle("POST /rpc", server)
+//
+// The Server supports batch requests and notifications. Return an *Error from a method
+// to send a specific error code to the client.
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"io"
+	"net/http"
+)
+
+// Standard error codes as defined by the JSON-RPC 2.0 specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Error is a JSON-RPC error object. Methods can return an *Error to control
+// the error sent to the client.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// NewError creates a new Error with the given code and message.
+func NewError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+type method func(ctx context.Context, params serde.SourceValue) (any, error)
+
+// Option configures a Server.
+type Option func(config *config)
+
+type config struct {
+	maxBatchSize int
+	maxBytes     int64
+	exposeErrors bool
+}
+
+// MaxBatchSize limits the number of requests in a batch. Defaults to 100.
+func MaxBatchSize(n int) Option {
+	return func(config *config) {
+		config.maxBatchSize = n
+	}
+}
+
+// MaxBytes limits the size of the request body. Defaults to 1MB.
+func MaxBytes(n int64) Option {
+	return func(config *config) {
+		config.maxBytes = n
+	}
+}
+
+// ExposeErrors sends the message of errors returned by a method to the client.
+// By default, errors that are not an *Error are reported as "Internal error"
+// without any details.
+func ExposeErrors() Option {
+	return func(config *config) {
+		config.exposeErrors = true
+	}
+}
+
+// Server is an http.Handler serving JSON-RPC 2.0 methods.
+// Register methods using Method before serving requests.
+type Server struct {
+	config  config
+	methods map[string]method
+}
+
+// New creates a new Server without any methods.
+func New(options ...Option) *Server {
+	config := config{
+		maxBatchSize: 100,
+		maxBytes:     1 << 20,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &Server{config: config, methods: map[string]method{}}
+}
+
+// Method registers fn as the JSON-RPC method with the given name. Named parameters are
+// decoded into a struct, positional parameters into a slice. A missing params member
+// leaves P at its zero value.
+func Method[P, R any](server *Server, name string, fn func(ctx context.Context, params P) (R, error)) {
+	server.methods[name] = func(ctx context.Context, source serde.SourceValue) (any, error) {
+		var params P
+
+		if source != nil {
+			if err := serde.Unmarshal(source, &params); err != nil {
+				return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: err.Error()}
+			}
+		}
+
+		return fn(ctx, params)
+	}
+}
+
+type request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+var null = json.RawMessage("null")
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "jsonrpc requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.config.maxBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(w, "reading body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), requestKey{}, r)
+
+	body = bytes.TrimSpace(body)
+	if !bytes.HasPrefix(body, []byte("[")) {
+		resp, ok := s.handleMessage(ctx, body)
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		writeJSON(w, resp)
+		return
+	}
+
+	var messages []json.RawMessage
+	if err := json.Unmarshal(body, &messages); err != nil {
+		writeJSON(w, errorResponse(null, CodeParseError, "Parse error"))
+		return
+	}
+
+	switch {
+	case len(messages) == 0:
+		writeJSON(w, errorResponse(null, CodeInvalidRequest, "Invalid Request"))
+		return
+
+	case len(messages) > s.config.maxBatchSize:
+		writeJSON(w, errorResponse(null, CodeInvalidRequest, "Batch too large"))
+		return
+	}
+
+	var responses []response
+
+	for _, message := range messages {
+		if resp, ok := s.handleMessage(ctx, message); ok {
+			responses = append(responses, resp)
+		}
+	}
+
+	if len(responses) == 0 {
+		// the batch contained only notifications
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, responses)
+}
+
+// handleMessage processes a single request. Returns false, if the request
+// was a notification and no response must be sent.
+func (s *Server) handleMessage(ctx context.Context, message json.RawMessage) (response, bool) {
+	var req request
+	if err := json.Unmarshal(message, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return errorResponse(null, CodeParseError, "Parse error"), true
+		}
+
+		return errorResponse(null, CodeInvalidRequest, "Invalid Request"), true
+	}
+
+	if req.Version != "2.0" || req.Method == "" || !validID(req.ID) {
+		return errorResponse(idOrNull(req.ID), CodeInvalidRequest, "Invalid Request"), true
+	}
+
+	isNotification := req.ID == nil
+
+	result, err := s.call(ctx, req)
+	if isNotification {
+		return response{}, false
+	}
+
+	if err != nil {
+		return response{Version: "2.0", Error: s.errorOf(err), ID: req.ID}, true
+	}
+
+	// marshal the result here, so that zero values are not dropped by omitempty
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return response{Version: "2.0", Error: s.errorOf(fmt.Errorf("encode result: %w", err)), ID: req.ID}, true
+	}
+
+	return response{Version: "2.0", Result: encoded, ID: req.ID}, true
+}
+
+func (s *Server) call(ctx context.Context, req request) (any, error) {
+	method, ok := s.methods[req.Method]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: "Method not found"}
+	}
+
+	var source serde.SourceValue
+
+	params := bytes.TrimSpace(req.Params)
+	if len(params) > 0 && !bytes.Equal(params, null) {
+		if params[0] != '{' && params[0] != '[' {
+			return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params"}
+		}
+
+		parsed, err := serde.ParseJSON(bytes.NewReader(params))
+		if err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params"}
+		}
+
+		source = parsed
+	}
+
+	return method(ctx, source)
+}
+
+func (s *Server) errorOf(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	if s.config.exposeErrors {
+		return &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+
+	return &Error{Code: CodeInternalError, Message: "Internal error"}
+}
+
+// validID checks that the id is absent, a string, a number or null.
+func validID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+
+	var value any
+	if err := json.Unmarshal(id, &value); err != nil {
+		return false
+	}
+
+	switch value.(type) {
+	case nil, string, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if id == nil || !validID(id) {
+		return null
+	}
+
+	return id
+}
+
+func errorResponse(id json.RawMessage, code int, message string) response {
+	return response{Version: "2.0", Error: NewError(code, message), ID: id}
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+type requestKey struct{}
+
+// Request returns the http request that carried the JSON-RPC call. Use it in
+// methods to access headers or to call gum.Extract.
+func Request(ctx context.Context) *http.Request {
+	r, _ := ctx.Value(requestKey{}).(*http.Request)
+	return r
+}
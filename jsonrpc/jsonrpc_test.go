@@ -0,0 +1,173 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer() *Server {
+	type AddParams struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+
+	server := New()
+
+	Method(server, "add", func(ctx context.Context, params AddParams) (int, error) {
+		return params.A + params.B, nil
+	})
+
+	Method(server, "sum", func(ctx context.Context, params []int) (int, error) {
+		var sum int
+		for _, value := range params {
+			sum += value
+		}
+
+		return sum, nil
+	})
+
+	Method(server, "fail", func(ctx context.Context, params struct{}) (any, error) {
+		return nil, errors.New("secret details")
+	})
+
+	Method(server, "teapot", func(ctx context.Context, params struct{}) (any, error) {
+		return nil, &Error{Code: 418, Message: "I'm a teapot"}
+	})
+
+	Method(server, "method", func(ctx context.Context, params struct{}) (string, error) {
+		return Request(ctx).Method, nil
+	})
+
+	return server
+}
+
+func call(server http.Handler, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body)))
+	return rec
+}
+
+func TestServer(t *testing.T) {
+	server := newTestServer()
+
+	cases := []struct {
+		Name     string
+		Request  string
+		Response string
+	}{
+		{
+			Name:     "NamedParams",
+			Request:  `{"jsonrpc": "2.0", "method": "add", "params": {"a": 1, "b": 2}, "id": 1}`,
+			Response: `{"jsonrpc":"2.0","result":3,"id":1}`,
+		},
+		{
+			Name:     "PositionalParams",
+			Request:  `{"jsonrpc": "2.0", "method": "sum", "params": [1, 2, 3], "id": "abc"}`,
+			Response: `{"jsonrpc":"2.0","result":6,"id":"abc"}`,
+		},
+		{
+			Name:     "ZeroResult",
+			Request:  `{"jsonrpc": "2.0", "method": "sum", "id": 1}`,
+			Response: `{"jsonrpc":"2.0","result":0,"id":1}`,
+		},
+		{
+			Name:     "Request",
+			Request:  `{"jsonrpc": "2.0", "method": "method", "id": 1}`,
+			Response: `{"jsonrpc":"2.0","result":"POST","id":1}`,
+		},
+		{
+			Name:     "MethodNotFound",
+			Request:  `{"jsonrpc": "2.0", "method": "unknown", "id": 1}`,
+			Response: `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":1}`,
+		},
+		{
+			Name:     "InvalidParams",
+			Request:  `{"jsonrpc": "2.0", "method": "sum", "params": 12, "id": 1}`,
+			Response: `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params"},"id":1}`,
+		},
+		{
+			Name:     "InternalError",
+			Request:  `{"jsonrpc": "2.0", "method": "fail", "id": 1}`,
+			Response: `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":1}`,
+		},
+		{
+			Name:     "CustomError",
+			Request:  `{"jsonrpc": "2.0", "method": "teapot", "id": 1}`,
+			Response: `{"jsonrpc":"2.0","error":{"code":418,"message":"I'm a teapot"},"id":1}`,
+		},
+		{
+			Name:     "ParseError",
+			Request:  `{"jsonrpc": "2.0", "method"`,
+			Response: `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`,
+		},
+		{
+			Name:     "InvalidRequest",
+			Request:  `{"jsonrpc": "1.0", "method": "add", "id": 7}`,
+			Response: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":7}`,
+		},
+		{
+			Name:     "EmptyBatch",
+			Request:  `[]`,
+			Response: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+		},
+		{
+			Name: "Batch",
+			Request: `[
+				{"jsonrpc": "2.0", "method": "add", "params": {"a": 1, "b": 2}, "id": 1},
+				{"jsonrpc": "2.0", "method": "add", "params": {"a": 5}},
+				{"jsonrpc": "2.0", "method": "unknown", "id": 2}
+			]`,
+			Response: `[{"jsonrpc":"2.0","result":3,"id":1},{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":2}]`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			rec := call(server, tc.Request)
+			AssertEqual(t, rec.Code, http.StatusOK)
+			AssertEqual(t, strings.TrimSpace(rec.Body.String()), tc.Response)
+		})
+	}
+}
+
+func TestServerNotifications(t *testing.T) {
+	server := newTestServer()
+
+	rec := call(server, `{"jsonrpc": "2.0", "method": "add", "params": {"a": 1}}`)
+	AssertEqual(t, rec.Code, http.StatusNoContent)
+	AssertEqual(t, rec.Body.Len(), 0)
+
+	rec = call(server, `[{"jsonrpc": "2.0", "method": "add"}, {"jsonrpc": "2.0", "method": "fail"}]`)
+	AssertEqual(t, rec.Code, http.StatusNoContent)
+}
+
+func TestServerOptions(t *testing.T) {
+	t.Run("ExposeErrors", func(t *testing.T) {
+		server := New(ExposeErrors())
+
+		Method(server, "fail", func(ctx context.Context, params struct{}) (any, error) {
+			return nil, errors.New("details")
+		})
+
+		rec := call(server, `{"jsonrpc": "2.0", "method": "fail", "id": 1}`)
+		AssertEqual(t, strings.TrimSpace(rec.Body.String()), `{"jsonrpc":"2.0","error":{"code":-32603,"message":"details"},"id":1}`)
+	})
+
+	t.Run("MaxBatchSize", func(t *testing.T) {
+		server := New(MaxBatchSize(1))
+
+		rec := call(server, `[{"jsonrpc": "2.0", "method": "a", "id": 1}, {"jsonrpc": "2.0", "method": "b", "id": 2}]`)
+		AssertEqual(t, strings.TrimSpace(rec.Body.String()), `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Batch too large"},"id":null}`)
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		New().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rpc", nil))
+		AssertEqual(t, rec.Code, http.StatusMethodNotAllowed)
+	})
+}
@@ -0,0 +1,339 @@
+// Package jsonschema generates JSON Schemas from go types.
+//
+// Struct fields are resolved exactly like the serde package does, using the json struct tag
+// for names. A field is required unless it is a pointer or its json tag has the omitempty
+// option. Additional struct tags refine the schema of a field:
+//
+//	type Order struct {
+//		Status   string `json:"status" enum:"open,paid,shipped" default:"open"`
+//		Comment  string `json:"comment,omitempty" description:"Free text comment"`
+//	}
+//
+// Values in enum and default tags are parsed according to the type of the field.
+package jsonschema
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the schemas generated by For and Of.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. Only the keywords produced by this package are modeled.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// For returns a self-contained schema of T. Named struct types are placed in $defs.
+func For[T any]() (*Schema, error) {
+	return Of(reflect.TypeFor[T]())
+}
+
+// Of returns a self-contained schema of ty. Named struct types are placed in $defs.
+func Of(ty reflect.Type) (*Schema, error) {
+	gen := NewGenerator("#/$defs/")
+
+	schema, err := gen.Schema(ty)
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Schema = Draft
+
+	if len(gen.Definitions) > 0 {
+		schema.Defs = gen.Definitions
+	}
+
+	return schema, nil
+}
+
+var (
+	tyTime          = reflect.TypeFor[time.Time]()
+	tyTextMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Generator generates schemas for multiple types sharing the same definitions.
+// Named struct types are added to Definitions and referenced using $ref with the
+// configured prefix, e.g. "#/components/schemas/" for OpenAPI documents.
+type Generator struct {
+	RefPrefix   string
+	Definitions map[string]*Schema
+
+	names map[reflect.Type]string
+}
+
+// NewGenerator creates a new Generator using the given $ref prefix.
+func NewGenerator(refPrefix string) *Generator {
+	return &Generator{
+		RefPrefix:   refPrefix,
+		Definitions: map[string]*Schema{},
+		names:       map[reflect.Type]string{},
+	}
+}
+
+// Schema returns the schema of ty. The allowed values of types
+// implementing serde.Enum are added to the schema as enum.
+func (g *Generator) Schema(ty reflect.Type) (*Schema, error) {
+	schema, err := g.baseSchema(ty)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.Ref == "" {
+		if err := applyEnum(schema, ty, serde.EnumValuesOf(serde.Field{Type: ty})); err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
+
+func (g *Generator) baseSchema(ty reflect.Type) (*Schema, error) {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	switch {
+	case ty == tyTime:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+
+	case ty.Implements(tyTextMarshaler) || reflect.PointerTo(ty).Implements(tyTextMarshaler):
+		return &Schema{Type: "string"}, nil
+	}
+
+	switch ty.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}, nil
+
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}, nil
+
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}, nil
+
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+
+	case reflect.Slice, reflect.Array:
+		if ty.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+
+		items, err := g.Schema(ty.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: "array", Items: items}, nil
+
+	case reflect.Map:
+		values, err := g.Schema(ty.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+
+	case reflect.Struct:
+		if ty.Name() == "" {
+			return g.structSchema(ty)
+		}
+
+		name, err := g.definitionOf(ty)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Ref: g.RefPrefix + name}, nil
+
+	default:
+		// interfaces and everything we can not describe accept any value
+		return &Schema{}, nil
+	}
+}
+
+// definitionOf adds the named struct type ty to the definitions and returns its name.
+func (g *Generator) definitionOf(ty reflect.Type) (string, error) {
+	if name, ok := g.names[ty]; ok {
+		return name, nil
+	}
+
+	name := definitionName(ty.Name())
+	for idx := 2; g.Definitions[name] != nil; idx++ {
+		// a different type with the same name, e.g. from another package
+		name = fmt.Sprintf("%s%d", definitionName(ty.Name()), idx)
+	}
+
+	// register the name before walking the fields to support recursive types
+	g.names[ty] = name
+	g.Definitions[name] = &Schema{}
+
+	schema, err := g.structSchema(ty)
+	if err != nil {
+		delete(g.names, ty)
+		delete(g.Definitions, name)
+		return "", err
+	}
+
+	*g.Definitions[name] = *schema
+
+	return name, nil
+}
+
+func (g *Generator) structSchema(ty reflect.Type) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for _, field := range serde.Fields(ty) {
+		fieldSchema, err := g.FieldSchema(field)
+		if err != nil {
+			return nil, fmt.Errorf("field %q of %s: %w", field.Name, ty, err)
+		}
+
+		schema.Properties[field.Name] = fieldSchema
+
+		if isRequired(field) {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+
+	return schema, nil
+}
+
+// FieldSchema returns the schema of a struct field,
+// including the keywords defined by its struct tags.
+func (g *Generator) FieldSchema(field serde.Field) (*Schema, error) {
+	schema, err := g.Schema(field.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyTags(schema, field); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+func isRequired(field serde.Field) bool {
+	if field.Type.Kind() == reflect.Pointer {
+		return false
+	}
+
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" || option == "omitzero" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// applyTags applies the description, enum and default tags of the field to its schema.
+func applyTags(schema *Schema, field serde.Field) error {
+	if schema.Ref != "" && (field.Tag.Get("enum") != "" || field.Tag.Get("default") != "") {
+		return fmt.Errorf("enum and default are not supported on struct types")
+	}
+
+	schema.Description = field.Tag.Get("description")
+
+	if _, ok := field.Tag.Lookup("enum"); ok {
+		if err := applyEnum(schema, field.Type, serde.EnumValuesOf(field)); err != nil {
+			return err
+		}
+	}
+
+	if text, ok := field.Tag.Lookup("default"); ok {
+		value, err := parseValue(field.Type, text)
+		if err != nil {
+			return fmt.Errorf("default value %q: %w", text, err)
+		}
+
+		schema.Default = value
+	}
+
+	return nil
+}
+
+// applyEnum sets the enum keyword of the schema to the values parsed into ty.
+func applyEnum(schema *Schema, ty reflect.Type, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	if ty.Kind() == reflect.Slice || ty.Kind() == reflect.Array {
+		// the values restrict the items of the slice
+		if schema.Items == nil {
+			return nil
+		}
+
+		schema, ty = schema.Items, ty.Elem()
+	}
+
+	schema.Enum = nil
+
+	for _, text := range values {
+		value, err := parseValue(ty, text)
+		if err != nil {
+			return fmt.Errorf("enum value %q: %w", text, err)
+		}
+
+		schema.Enum = append(schema.Enum, value)
+	}
+
+	return nil
+}
+
+// parseValue parses text into a value of type ty, the same way serde parses query parameters.
+func parseValue(ty reflect.Type, text string) (any, error) {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	target := reflect.New(ty)
+	if err := serde.Unmarshal(serde.StringValue(text), target.Interface()); err != nil {
+		return nil, err
+	}
+
+	return target.Elem().Interface(), nil
+}
+
+var reInvalidDefinitionChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// definitionName converts a type name like "Page[main.User]" into a valid definition name.
+func definitionName(name string) string {
+	return strings.Trim(reInvalidDefinitionChars.ReplaceAllString(name, "_"), "_")
+}
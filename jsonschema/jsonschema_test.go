@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	. "github.com/go-gum/gum/internal/test"
+	"testing"
+)
+
+type Status string
+
+type Item struct {
+	SKU      string `json:"sku" description:"Stock keeping unit"`
+	Quantity uint   `json:"quantity" default:"1"`
+}
+
+type Order struct {
+	ID      int64   `json:"id"`
+	Status  Status  `json:"status" enum:"open, paid, shipped" default:"open"`
+	Items   []Item  `json:"items"`
+	Comment string  `json:"comment,omitempty"`
+	Parent  *Order  `json:"parent"`
+	Rating  float64 `json:"rating" enum:"1,2.5"`
+}
+
+func TestFor(t *testing.T) {
+	schema, err := For[Order]()
+	AssertEqual(t, err, nil)
+
+	AssertEqual(t, schema.Schema, Draft)
+	AssertEqual(t, schema.Ref, "#/$defs/Order")
+
+	order := schema.Defs["Order"]
+	AssertEqual(t, order.Type, "object")
+	AssertEqual(t, order.Required, []string{"id", "status", "items", "rating"})
+	AssertEqual(t, order.Properties["status"], &Schema{Type: "string", Enum: []any{Status("open"), Status("paid"), Status("shipped")}, Default: Status("open")})
+	AssertEqual(t, order.Properties["items"], &Schema{Type: "array", Items: &Schema{Ref: "#/$defs/Item"}})
+	AssertEqual(t, order.Properties["parent"], &Schema{Ref: "#/$defs/Order"})
+	AssertEqual(t, order.Properties["rating"].Enum, []any{1.0, 2.5})
+
+	item := schema.Defs["Item"]
+	AssertEqual(t, item.Properties["sku"].Description, "Stock keeping unit")
+	AssertEqual[any](t, item.Properties["quantity"].Default, uint(1))
+
+	// the schema serializes to valid json
+	encoded, err := json.Marshal(schema)
+	AssertEqual(t, err, nil)
+	AssertTrue(t, json.Valid(encoded))
+}
+
+func TestForInvalidTag(t *testing.T) {
+	type Invalid struct {
+		Count int `json:"count" enum:"one"`
+	}
+
+	_, err := For[Invalid]()
+	AssertTrue(t, err != nil)
+}
+
+func TestForAnonymous(t *testing.T) {
+	schema, err := For[struct {
+		Values map[string]bool `json:"values"`
+		Data   []byte          `json:"data"`
+	}]()
+
+	AssertEqual(t, err, nil)
+	AssertEqual(t, schema.Type, "object")
+	AssertEqual(t, schema.Properties["values"], &Schema{Type: "object", AdditionalProperties: &Schema{Type: "boolean"}})
+	AssertEqual(t, schema.Properties["data"], &Schema{Type: "string", Format: "byte"})
+	AssertEqual(t, len(schema.Defs), 0)
+}
@@ -0,0 +1,108 @@
+package gum
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrMaintenance is the error sent to clients while maintenance mode is active.
+var ErrMaintenance = errors.New("service is under maintenance")
+
+// MaintenanceOption configures the Maintenance middleware.
+type MaintenanceOption func(config *maintenanceConfig)
+
+type maintenanceConfig struct {
+	retryAfter time.Duration
+	paths      []string
+	networks   []netip.Prefix
+}
+
+// MaintenanceRetryAfter sets the value of the Retry-After header. Defaults to 5 minutes.
+func MaintenanceRetryAfter(retryAfter time.Duration) MaintenanceOption {
+	return func(config *maintenanceConfig) {
+		config.retryAfter = retryAfter
+	}
+}
+
+// MaintenanceAllowPaths lets requests with a path starting with one of the prefixes pass
+// while maintenance mode is active, e.g. health checks or an admin endpoint to switch
+// maintenance mode off again.
+func MaintenanceAllowPaths(prefixes ...string) MaintenanceOption {
+	return func(config *maintenanceConfig) {
+		config.paths = append(config.paths, prefixes...)
+	}
+}
+
+// MaintenanceAllowNetworks lets requests from clients within one of the networks pass
+// while maintenance mode is active, e.g. the network of the operators.
+// The client address is taken from http.Request.RemoteAddr.
+func MaintenanceAllowNetworks(networks ...netip.Prefix) MaintenanceOption {
+	return func(config *maintenanceConfig) {
+		config.networks = append(config.networks, networks...)
+	}
+}
+
+// Maintenance returns a Middleware that rejects requests with 503 Service Unavailable
+// and a Retry-After header while enabled is set. The flag can be changed at runtime, so
+// operators can drain traffic without a redeployment:
+//
+//	var maintenance atomic.Bool
+//
+//	handler = gum.Maintenance(&maintenance,
+//		gum.MaintenanceAllowPaths("/healthz", "/admin/"),
+//	)(handler)
+//
+//	// later
+//	maintenance.Store(true)
+func Maintenance(enabled *atomic.Bool, options ...MaintenanceOption) Middleware {
+	config := maintenanceConfig{
+		retryAfter: 5 * time.Minute,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !enabled.Load() || config.allows(r) {
+				delegate.ServeHTTP(w, r)
+				return
+			}
+
+			err := ServiceUnavailableError(config.retryAfter, ErrMaintenance)
+
+			errorResponse(err, http.StatusServiceUnavailable).ServeHTTP(w, r)
+		})
+	}
+}
+
+func (config *maintenanceConfig) allows(r *http.Request) bool {
+	if slices.ContainsFunc(config.paths, func(prefix string) bool { return strings.HasPrefix(r.URL.Path, prefix) }) {
+		return true
+	}
+
+	if len(config.networks) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+
+	return slices.ContainsFunc(config.networks, func(network netip.Prefix) bool { return network.Contains(addr) })
+}
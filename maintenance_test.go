@@ -0,0 +1,48 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaintenance(t *testing.T) {
+	var enabled atomic.Bool
+
+	handler := Maintenance(&enabled,
+		MaintenanceRetryAfter(time.Minute),
+		MaintenanceAllowPaths("/healthz"),
+		MaintenanceAllowNetworks(netip.MustParsePrefix("10.0.0.0/8")),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(path string, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	AssertEqual(t, serve("/items", "192.0.2.1:1234").Code, http.StatusNoContent)
+
+	enabled.Store(true)
+
+	rec := serve("/items", "192.0.2.1:1234")
+	AssertEqual(t, rec.Code, http.StatusServiceUnavailable)
+	AssertEqual(t, rec.Header().Get("Retry-After"), "60")
+
+	AssertEqual(t, serve("/healthz", "192.0.2.1:1234").Code, http.StatusNoContent)
+	AssertEqual(t, serve("/items", "10.1.2.3:1234").Code, http.StatusNoContent)
+	AssertEqual(t, serve("/items", "[::ffff:10.1.2.3]:1234").Code, http.StatusNoContent)
+
+	enabled.Store(false)
+
+	AssertEqual(t, serve("/items", "192.0.2.1:1234").Code, http.StatusNoContent)
+}
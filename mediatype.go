@@ -0,0 +1,120 @@
+package gum
+
+import (
+	"fmt"
+	"github.com/timewasted/go-accept-headers"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MediaRange is a single media range of an Accept header, e.g. "text/*;q=0.5"
+type MediaRange struct {
+	Type    string
+	Subtype string
+	Q       float64
+	Params  map[string]string
+}
+
+// matches checks if the media range matches the given type and subtype.
+// Returns the specificity of the match, or -1 if it does not match.
+func (m MediaRange) matches(ty, subtype string) int {
+	switch {
+	case m.Type == ty && m.Subtype == subtype:
+		return 2
+	case m.Type == ty && m.Subtype == "*":
+		return 1
+	case m.Type == "*" && m.Subtype == "*":
+		return 0
+	default:
+		return -1
+	}
+}
+
+// Accept holds the media ranges of the requests Accept header, ordered by preference.
+// A request without an Accept header accepts any media type.
+type Accept []MediaRange
+
+// Negotiate chooses the offered media type preferred by the client. For each offer,
+// the most specific matching media range determines its quality. Offers with a quality of
+// zero are never chosen, ties are resolved by the order of the offers.
+// Returns false if the client does not accept any of the offers.
+func (a Accept) Negotiate(offers ...string) (string, bool) {
+	var best string
+	var bestQ float64
+
+	for _, offer := range offers {
+		ty, subtype, ok := strings.Cut(strings.ToLower(offer), "/")
+		if !ok {
+			continue
+		}
+
+		q, specificity := 0.0, -1
+		for _, mediaRange := range a {
+			if s := mediaRange.matches(ty, subtype); s > specificity {
+				q, specificity = mediaRange.Q, s
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+// Accepts checks if the client accepts the given media type.
+func (a Accept) Accepts(mediaType string) bool {
+	_, ok := a.Negotiate(mediaType)
+	return ok
+}
+
+// MediaType is the parsed Content-Type header of the request.
+type MediaType struct {
+	// Type is the lower case media type without parameters, e.g. "application/json"
+	Type string
+
+	// Params holds the parameters of the media type, e.g. "charset"
+	Params map[string]string
+}
+
+// Is checks if the media type equals the given type, ignoring case and parameters.
+func (m MediaType) Is(mediaType string) bool {
+	return strings.EqualFold(m.Type, mediaType)
+}
+
+func init() {
+	Register(func(r *http.Request) (Accept, error) {
+		header := strings.Join(r.Header.Values("Accept"), ",")
+		if strings.TrimSpace(header) == "" {
+			return Accept{{Type: "*", Subtype: "*", Q: 1}}, nil
+		}
+
+		var result Accept
+		for _, value := range accept.Parse(header) {
+			result = append(result, MediaRange{
+				Type:    strings.ToLower(value.Type),
+				Subtype: strings.ToLower(value.Subtype),
+				Q:       value.Q,
+				Params:  value.Extensions,
+			})
+		}
+
+		return result, nil
+	})
+
+	Register(func(r *http.Request) (MediaType, error) {
+		contentType := r.Header.Get("Content-Type")
+		if contentType == "" {
+			return MediaType{}, fmt.Errorf("no Content-Type header in request")
+		}
+
+		mediaType, params, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return MediaType{}, fmt.Errorf("parse Content-Type: %w", err)
+		}
+
+		return MediaType{Type: mediaType, Params: params}, nil
+	})
+}
@@ -0,0 +1,51 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccept_Negotiate(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "text/*;q=0.5, application/json, application/xml;q=0")
+
+	var extractedValue Accept
+	Handler(func(v Accept) { extractedValue = v }).ServeHTTP(nil, req)
+
+	offer, ok := extractedValue.Negotiate("application/xml", "text/html", "application/json")
+	AssertEqual(t, offer, "application/json")
+	AssertTrue(t, ok)
+
+	offer, ok = extractedValue.Negotiate("application/xml", "text/html")
+	AssertEqual(t, offer, "text/html")
+	AssertTrue(t, ok)
+
+	AssertTrue(t, !extractedValue.Accepts("application/xml"))
+	AssertTrue(t, !extractedValue.Accepts("image/png"))
+}
+
+func TestAccept_Missing(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+
+	var extractedValue Accept
+	Handler(func(v Accept) { extractedValue = v }).ServeHTTP(nil, req)
+	AssertTrue(t, extractedValue.Accepts("image/png"))
+}
+
+func TestMediaType(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Content-Type", "Application/JSON; charset=utf-8")
+
+	var extractedValue MediaType
+	Handler(func(v MediaType) { extractedValue = v }).ServeHTTP(nil, req)
+	AssertEqual(t, extractedValue, MediaType{Type: "application/json", Params: map[string]string{"charset": "utf-8"}})
+	AssertTrue(t, extractedValue.Is("application/json"))
+
+	req.Header.Set("Content-Type", "invalid/")
+
+	rec := httptest.NewRecorder()
+	Handler(func(v MediaType) { t.FailNow() }).ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusBadRequest)
+}
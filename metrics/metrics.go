@@ -0,0 +1,176 @@
+// Package metrics provides Prometheus instrumentation for gum applications.
+//
+// Middleware records request counters, duration histograms and in-flight gauges for all
+// requests passing through it. Hooks records per-parameter extraction metrics
+// of individual gum handlers:
+//
+//	m, err := metrics.New(prometheus.DefaultRegisterer)
+//
+//	mux.Handle("GET /users", gum.Handler(listUsers, gum.WithHooks(m.Hooks())))
+//	http.ListenAndServe(":8080", m.Middleware()(mux))
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/internal"
+	"github.com/prometheus/client_golang/prometheus"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Metrics holds the prometheus collectors used by Middleware and Hooks.
+type Metrics struct {
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	inFlight  *prometheus.GaugeVec
+	extract   *prometheus.HistogramVec
+	responses *prometheus.CounterVec
+}
+
+// Option configures the metrics created by New.
+type Option func(config *config)
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+// Namespace sets the namespace of all metrics. Defaults to "gum".
+func Namespace(namespace string) Option {
+	return func(config *config) {
+		config.namespace = namespace
+	}
+}
+
+// Buckets sets the buckets of the duration histograms.
+// Defaults to prometheus.DefBuckets.
+func Buckets(buckets ...float64) Option {
+	return func(config *config) {
+		config.buckets = buckets
+	}
+}
+
+// New creates the collectors and registers them with the given prometheus.Registerer.
+func New(registerer prometheus.Registerer, options ...Option) (*Metrics, error) {
+	config := config{
+		namespace: "gum",
+		buckets:   prometheus.DefBuckets,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of handled http requests.",
+		}, []string{"method", "route", "status"}),
+
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time it took to handle a http request.",
+			Buckets:   config.buckets,
+		}, []string{"method", "route", "status"}),
+
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: config.namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of http requests currently being handled.",
+		}, []string{"method"}),
+
+		extract: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.namespace,
+			Subsystem: "handler",
+			Name:      "extract_duration_seconds",
+			Help:      "Time it took to extract a handler parameter.",
+			Buckets:   config.buckets,
+		}, []string{"route", "type", "success"}),
+
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.namespace,
+			Subsystem: "handler",
+			Name:      "response_bytes_total",
+			Help:      "Number of bytes written by gum handlers.",
+		}, []string{"status"}),
+	}
+
+	collectors := []prometheus.Collector{m.requests, m.duration, m.inFlight, m.extract, m.responses}
+
+	var errs []error
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("register collectors: %w", err)
+	}
+
+	return m, nil
+}
+
+// Middleware returns a gum.Middleware recording request counters, durations and
+// in-flight requests. Requests are labeled by method, route and status.
+// The route is the pattern matched by a http.ServeMux, or "unmatched". The pattern
+// is recorded by the gum.Handler serving the request, so it is also available if a
+// middleware between this one and the http.ServeMux replaces the request.
+func (m *Metrics) Middleware() gum.Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inFlight := m.inFlight.WithLabelValues(r.Method)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			startTime := time.Now()
+
+			r, route := internal.WithRoute(r)
+
+			rw := internal.NewRecordingWriter(w)
+			delegate.ServeHTTP(rw, r)
+
+			pattern := route.Pattern(r)
+			if pattern == "" {
+				pattern = "unmatched"
+			}
+			status := strconv.Itoa(rw.StatusCode())
+
+			m.requests.WithLabelValues(r.Method, pattern, status).Inc()
+			m.duration.WithLabelValues(r.Method, pattern, status).Observe(time.Since(startTime).Seconds())
+		})
+	}
+}
+
+// Hooks returns gum.Hooks recording the extraction duration per route and parameter type,
+// and the number of bytes written by a gum handler.
+func (m *Metrics) Hooks() gum.Hooks {
+	return gum.Hooks{
+		OnExtract: func(r *http.Request, ty reflect.Type, duration time.Duration, err error) {
+			success := strconv.FormatBool(err == nil)
+			m.extract.WithLabelValues(routeOf(r), ty.String(), success).Observe(duration.Seconds())
+		},
+
+		OnWrite: func(r *http.Request, statusCode int, bytesWritten int64) {
+			m.responses.WithLabelValues(strconv.Itoa(statusCode)).Add(float64(bytesWritten))
+		},
+	}
+}
+
+// routeOf returns the pattern matched by a http.ServeMux, or "unmatched".
+func routeOf(r *http.Request) string {
+	if r.Pattern == "" {
+		return "unmatched"
+	}
+
+	return r.Pattern
+}
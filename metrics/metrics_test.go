@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	m, err := New(registry)
+	AssertEqual(t, err, nil)
+
+	handler := gum.Handler(func(host gum.Host) http.Handler {
+		return response.Text("hello")
+	}, gum.WithHooks(m.Hooks()))
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /hello", handler)
+
+	req := httptest.NewRequest("GET", "/hello", nil)
+	m.Middleware()(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	AssertEqual(t, testutil.ToFloat64(m.requests.WithLabelValues("GET", "GET /hello", "200")), 1)
+	AssertEqual(t, testutil.ToFloat64(m.inFlight.WithLabelValues("GET")), 0)
+	AssertEqual(t, testutil.ToFloat64(m.responses.WithLabelValues("200")), 5)
+	AssertEqual(t, testutil.CollectAndCount(m.extract), 1)
+	AssertEqual(t, testutil.CollectAndCount(m.extract.MustCurryWith(prometheus.Labels{"route": "GET /hello"})), 1)
+}
+
+func TestMetricsRouteBehindMiddleware(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	m, err := New(registry)
+	AssertEqual(t, err, nil)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /items/{id}", gum.Handler(func() http.Handler {
+		return response.Text("hello")
+	}))
+
+	// the middleware passes a copy of the request to the mux
+	handler := m.Middleware()(gum.ProvideContextValue("value")(mux))
+
+	req := httptest.NewRequest("GET", "/items/12", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	AssertEqual(t, testutil.ToFloat64(m.requests.WithLabelValues("GET", "GET /items/{id}", "200")), 1)
+	AssertEqual(t, testutil.ToFloat64(m.requests.WithLabelValues("GET", "unmatched", "200")), 0)
+}
+
+func TestNewRegistersOnce(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	_, err := New(registry)
+	AssertEqual(t, err, nil)
+
+	_, err = New(registry)
+	AssertTrue(t, err != nil)
+}
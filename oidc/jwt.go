@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned if a token is malformed, its signature is invalid or its claims do not validate.
+var ErrInvalidToken = errors.New("invalid token")
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Audience holds the "aud" claim, which can either be a single string or a list of strings.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(bytes []byte) error {
+	var single string
+	if err := json.Unmarshal(bytes, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(bytes, &multiple); err != nil {
+		return fmt.Errorf("decode audience: %w", err)
+	}
+
+	*a = multiple
+	return nil
+}
+
+// StandardClaims are the registered claims of a JWT as defined in RFC 7519.
+// Embed StandardClaims into your own claims type to access them.
+type StandardClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  Audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	IssuedAt  int64    `json:"iat"`
+	ID        string   `json:"jti"`
+}
+
+// parsedToken is a JWT that was split into its parts
+type parsedToken struct {
+	header       jwtHeader
+	claims       StandardClaims
+	payload      []byte
+	signingInput string
+	signature    []byte
+}
+
+func parseToken(token string) (parsedToken, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return parsedToken{}, fmt.Errorf("%w: expected three parts", ErrInvalidToken)
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return parsedToken{}, fmt.Errorf("%w: decode header: %w", ErrInvalidToken, err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return parsedToken{}, fmt.Errorf("%w: decode payload: %w", ErrInvalidToken, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return parsedToken{}, fmt.Errorf("%w: decode signature: %w", ErrInvalidToken, err)
+	}
+
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return parsedToken{}, fmt.Errorf("%w: parse header: %w", ErrInvalidToken, err)
+	}
+
+	var claims StandardClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return parsedToken{}, fmt.Errorf("%w: parse claims: %w", ErrInvalidToken, err)
+	}
+
+	parsed := parsedToken{
+		header:       header,
+		claims:       claims,
+		payload:      payload,
+		signingInput: parts[0] + "." + parts[1],
+		signature:    signature,
+	}
+
+	return parsed, nil
+}
+
+// verifySignature verifies the tokens signature using the given key.
+// The "alg" header must match the type of the key.
+func (t parsedToken) verifySignature(key crypto.PublicKey) error {
+	var hash crypto.Hash
+	switch t.header.Alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, t.header.Alg)
+	}
+
+	hasher := hash.New()
+	hasher.Write([]byte(t.signingInput))
+	digest := hasher.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(t.header.Alg, "RS") {
+			return fmt.Errorf("%w: algorithm %q does not match rsa key", ErrInvalidToken, t.header.Alg)
+		}
+
+		if err := rsa.VerifyPKCS1v15(key, hash, digest, t.signature); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+
+		return nil
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(t.header.Alg, "ES") {
+			return fmt.Errorf("%w: algorithm %q does not match ecdsa key", ErrInvalidToken, t.header.Alg)
+		}
+
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(t.signature) != 2*size {
+			return fmt.Errorf("%w: invalid signature length", ErrInvalidToken)
+		}
+
+		r := new(big.Int).SetBytes(t.signature[:size])
+		s := new(big.Int).SetBytes(t.signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return fmt.Errorf("%w: signature verification failed", ErrInvalidToken)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("%w: unsupported key type %T", ErrInvalidToken, key)
+	}
+}
+
+// validateClaims validates issuer, audience and the time based claims.
+func (t parsedToken) validateClaims(issuer, audience string, now time.Time, leeway time.Duration) error {
+	claims := t.claims
+
+	if claims.Issuer != issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+
+	if audience != "" && !slices.Contains(claims.Audience, audience) {
+		return fmt.Errorf("%w: audience %q not in token", ErrInvalidToken, audience)
+	}
+
+	if claims.ExpiresAt == 0 {
+		return fmt.Errorf("%w: token has no expiration", ErrInvalidToken)
+	}
+
+	if now.Add(-leeway).After(time.Unix(claims.ExpiresAt, 0)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+
+	if claims.NotBefore != 0 && now.Add(leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+
+	return nil
+}
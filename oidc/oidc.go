@@ -0,0 +1,271 @@
+// Package oidc protects gum handlers with OpenID Connect access tokens.
+//
+// A Provider fetches the issuers discovery document and its JSON Web Key Set. Keys are
+// cached and refreshed automatically when the issuer rotates them. Install the
+// Provider using its Middleware and declare the Claims extractor in a handler:
+//
+//	provider, err := oidc.NewProvider(ctx, "https://accounts.example.com", "my-api")
+//
+//	type MyClaims struct {
+//	  oidc.StandardClaims
+//	  Email string `json:"email"`
+//	}
+//
+//	mux.Handle("GET /me", gum.Handler(func(claims oidc.Claims[MyClaims]) { ... }))
+//	http.ListenAndServe(":8080", provider.Middleware()(mux))
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Option configures a Provider
+type Option func(p *Provider)
+
+// HTTPClient sets the http.Client used to fetch the discovery document and keys.
+func HTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// CacheTTL sets how long fetched keys are used before they are fetched again.
+// Defaults to one hour.
+func CacheTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.cacheTTL = ttl
+	}
+}
+
+// MinRefreshInterval limits how often keys are fetched, e.g. when tokens reference an
+// unknown key id or the issuer is not available. Defaults to one minute.
+func MinRefreshInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		p.minRefresh = interval
+	}
+}
+
+// Leeway sets the allowed clock skew when validating exp and nbf claims.
+// Defaults to one minute.
+func Leeway(leeway time.Duration) Option {
+	return func(p *Provider) {
+		p.leeway = leeway
+	}
+}
+
+// Provider verifies tokens issued by an OpenID Connect issuer.
+type Provider struct {
+	issuer   string
+	audience string
+	jwksURI  string
+
+	client     *http.Client
+	cacheTTL   time.Duration
+	minRefresh time.Duration
+	leeway     time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+
+	// refreshedAt is the time of the last attempt to fetch the keys, successful or not
+	refreshedAt time.Time
+}
+
+// NewProvider fetches the discovery document of the issuer and creates a new Provider.
+// Tokens must be issued by the issuer and, if audience is not empty, contain
+// audience in their "aud" claim.
+func NewProvider(ctx context.Context, issuer, audience string, options ...Option) (*Provider, error) {
+	p := &Provider{
+		issuer:     issuer,
+		audience:   audience,
+		client:     http.DefaultClient,
+		cacheTTL:   time.Hour,
+		minRefresh: time.Minute,
+		leeway:     time.Minute,
+		now:        time.Now,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.fetchJSON(ctx, discoveryURL, &discovery); err != nil {
+		return nil, fmt.Errorf("fetch discovery document: %w", err)
+	}
+
+	if discovery.Issuer != issuer {
+		return nil, fmt.Errorf("discovery document is for issuer %q, expected %q", discovery.Issuer, issuer)
+	}
+
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("discovery document has no jwks_uri")
+	}
+
+	p.jwksURI = discovery.JWKSURI
+
+	if err := p.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Verify verifies the signature and claims of the given token and returns its raw claims payload.
+func (p *Provider) Verify(ctx context.Context, token string) ([]byte, error) {
+	parsed, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := p.keyOf(ctx, parsed.header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parsed.verifySignature(key); err != nil {
+		return nil, err
+	}
+
+	if err := parsed.validateClaims(p.issuer, p.audience, p.now(), p.leeway); err != nil {
+		return nil, err
+	}
+
+	return parsed.payload, nil
+}
+
+// keyOf returns the key with the given id. Keys are fetched again if the cache is
+// outdated or if the key is unknown, e.g. after the issuer rotated its keys. Fetches
+// are limited to one per MinRefreshInterval, failed fetches included.
+func (p *Provider) keyOf(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+
+	now := p.now()
+	outdated := now.Sub(p.fetchedAt) >= p.cacheTTL
+	mayRefresh := now.Sub(p.refreshedAt) >= p.minRefresh
+
+	refresh := (!ok || outdated) && mayRefresh
+	if refresh {
+		// claim the refresh, so concurrent requests do not fetch the keys too
+		p.refreshedAt = now
+	}
+	p.mu.Unlock()
+
+	if !refresh {
+		if ok {
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+	}
+
+	if err := p.refreshKeys(ctx); err != nil {
+		if ok {
+			// keep using the cached key if the issuer is not available
+			return key, nil
+		}
+
+		return nil, err
+	}
+
+	p.mu.Lock()
+	key, ok = p.keys[kid]
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+	}
+
+	return key, nil
+}
+
+func (p *Provider) refreshKeys(ctx context.Context) error {
+	var keySet jwkSet
+	if err := p.fetchJSON(ctx, p.jwksURI, &keySet); err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.keys = keySet.publicKeys()
+	p.fetchedAt = p.now()
+	p.refreshedAt = p.fetchedAt
+
+	return nil
+}
+
+func (p *Provider) fetchJSON(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %q", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Middleware provides the Provider to the Claims extractor.
+func (p *Provider) Middleware() gum.Middleware {
+	return gum.ProvideContextValue(p)
+}
+
+// Claims holds the verified claims of the requests bearer token.
+// Extraction fails with 401 Unauthorized if the token is missing or invalid.
+// Requires the Middleware of a Provider.
+type Claims[T any] struct {
+	Value T
+}
+
+var _ = gum.AssertFromRequest[Claims[any]]()
+
+func (Claims[T]) FromRequest(r *http.Request) (Claims[T], error) {
+	provider, err := gum.Extract[gum.ContextValue[*Provider]](r)
+	if err != nil {
+		return Claims[T]{}, fmt.Errorf("no oidc.Provider in context: %w", err)
+	}
+
+	token, err := gum.Extract[gum.BearerToken](r)
+	if err != nil {
+		return Claims[T]{}, err
+	}
+
+	payload, err := provider.Value.Verify(r.Context(), string(token))
+	if err != nil {
+		return Claims[T]{}, gum.UnauthorizedError(`Bearer error="invalid_token"`, err)
+	}
+
+	var claims T
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims[T]{}, gum.NewHTTPError(http.StatusUnauthorized, fmt.Errorf("decode claims: %w", err))
+	}
+
+	return Claims[T]{Value: claims}, nil
+}
@@ -0,0 +1,186 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	jwksFetches atomic.Int32
+	jwksDown    atomic.Bool
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	AssertEqual(t, err, nil)
+
+	issuer := &testIssuer{key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.jwksFetches.Add(1)
+
+		if issuer.jwksDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(jwkSet{Keys: []jwk{{
+			Kty: "RSA",
+			Kid: issuer.kid,
+			N:   base64.RawURLEncoding.EncodeToString(issuer.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.key.E)).Bytes()),
+		}}})
+	})
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testIssuer) sign(t *testing.T, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": i.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	AssertEqual(t, err, nil)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestClaims(t *testing.T) {
+	issuer := newTestIssuer(t)
+
+	provider, err := NewProvider(context.Background(), issuer.server.URL, "my-api")
+	AssertEqual(t, err, nil)
+
+	type MyClaims struct {
+		StandardClaims
+		Email string `json:"email"`
+	}
+
+	var extractedValue MyClaims
+	handler := provider.Middleware()(gum.Handler(func(claims Claims[MyClaims]) {
+		extractedValue = claims.Value
+	}))
+
+	serve := func(token string) int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	valid := issuer.sign(t, map[string]any{
+		"iss":   issuer.server.URL,
+		"aud":   []string{"my-api"},
+		"sub":   "albert",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"email": "albert@example.com",
+	})
+
+	AssertEqual(t, serve(valid), http.StatusOK)
+	AssertEqual(t, extractedValue.Subject, "albert")
+	AssertEqual(t, extractedValue.Email, "albert@example.com")
+
+	wrongAudience := issuer.sign(t, map[string]any{
+		"iss": issuer.server.URL,
+		"aud": "other-api",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	AssertEqual(t, serve(wrongAudience), http.StatusUnauthorized)
+
+	expired := issuer.sign(t, map[string]any{
+		"iss": issuer.server.URL,
+		"aud": "my-api",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+
+	AssertEqual(t, serve(expired), http.StatusUnauthorized)
+	AssertEqual(t, serve(valid[:len(valid)-4]+"AAAA"), http.StatusUnauthorized)
+}
+
+func TestKeyRotation(t *testing.T) {
+	issuer := newTestIssuer(t)
+
+	provider, err := NewProvider(context.Background(), issuer.server.URL, "", MinRefreshInterval(0))
+	AssertEqual(t, err, nil)
+
+	// rotate the key
+	issuer.key, _ = rsa.GenerateKey(rand.Reader, 2048)
+	issuer.kid = "key-2"
+
+	token := issuer.sign(t, map[string]any{
+		"iss": issuer.server.URL,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	_, err = provider.Verify(context.Background(), token)
+	AssertEqual(t, err, nil)
+}
+
+func TestRefreshRateLimit(t *testing.T) {
+	issuer := newTestIssuer(t)
+
+	provider, err := NewProvider(context.Background(), issuer.server.URL, "")
+	AssertEqual(t, err, nil)
+	AssertEqual(t, issuer.jwksFetches.Load(), int32(1))
+
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	issuer.jwksDown.Store(true)
+	issuer.kid = "unknown"
+
+	token := issuer.sign(t, map[string]any{
+		"iss": issuer.server.URL,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	// failed fetches count towards the limit, too
+	now = now.Add(2 * time.Minute)
+
+	for range 5 {
+		_, err = provider.Verify(context.Background(), token)
+		AssertTrue(t, err != nil)
+	}
+
+	AssertEqual(t, issuer.jwksFetches.Load(), int32(2))
+
+	now = now.Add(2 * time.Minute)
+
+	_, err = provider.Verify(context.Background(), token)
+	AssertTrue(t, err != nil)
+	AssertEqual(t, issuer.jwksFetches.Load(), int32(3))
+}
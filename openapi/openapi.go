@@ -0,0 +1,362 @@
+// Package openapi generates OpenAPI 3 specifications from gum handler functions.
+//
+// The parameters of a handler function describe its inputs: PathValues and QueryValues
+// become path and query parameters, JSON becomes the request body. Schemas are derived
+// from the struct fields using the same field resolution as the serde package.
+// As http.ServeMux can not be inspected, routes are added to the Spec explicitly:
+//
+//	spec := openapi.New(openapi.Info{Title: "Users", Version: "1.0"})
+//
+//	mux.Handle("GET /users/{id}", gum.Handler(getUser))
+//	spec.Add("GET /users/{id}", getUser, openapi.Returns(200, User{}))
+//
+//	mux.Handle("GET /openapi.json", spec)
+//	mux.Handle("GET /docs", openapi.SwaggerUI("/openapi.json"))
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/internal"
+	"github.com/go-gum/gum/jsonschema"
+	"github.com/go-gum/gum/serde"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Spec collects the operations of an API and generates its OpenAPI Document.
+// Spec implements http.Handler and serves the Document as json.
+type Spec struct {
+	info Info
+
+	mu         sync.Mutex
+	operations []operation
+}
+
+type operation struct {
+	method string
+	path   string
+	fnType reflect.Type
+	config operationConfig
+}
+
+// OperationOption adds information to an operation that can not be
+// derived from the handler function.
+type OperationOption func(config *operationConfig)
+
+type operationConfig struct {
+	operationID string
+	summary     string
+	description string
+	tags        []string
+	deprecated  bool
+	responses   []declaredResponse
+}
+
+type declaredResponse struct {
+	statusCode  int
+	description string
+	body        any
+}
+
+// New creates a new Spec.
+func New(info Info) *Spec {
+	return &Spec{info: info}
+}
+
+// Add documents the handler function fn, as passed to gum.Handler, for the given
+// http.ServeMux pattern, e.g. "GET /users/{id}". Instead of the function, the
+// http.Handler returned by gum.Handler can be passed, see gum.Describe.
+// Patterns without a method are documented as GET. Add panics if fn is neither
+// a function nor a handler created by gum.Handler.
+func (s *Spec) Add(pattern string, fn any, options ...OperationOption) {
+	fnType := reflect.TypeOf(fn)
+
+	if handler, ok := fn.(http.Handler); ok {
+		if desc, ok := gum.Describe(handler); ok {
+			fnType = desc.Func
+		}
+	}
+
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		panic("openapi: handler must be a function")
+	}
+
+	var config operationConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	method, path := splitPattern(pattern)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.operations = append(s.operations, operation{
+		method: method,
+		path:   path,
+		fnType: fnType,
+		config: config,
+	})
+}
+
+// Document generates the OpenAPI Document for all operations added so far.
+// Fails if the schema of a type can not be generated, e.g. due to an invalid enum tag.
+func (s *Spec) Document() (Document, error) {
+	s.mu.Lock()
+	operations := slices.Clone(s.operations)
+	s.mu.Unlock()
+
+	schemas := newSchemas()
+
+	doc := Document{
+		OpenAPI: "3.1.0",
+		Info:    s.info,
+		Paths:   map[string]PathItem{},
+	}
+
+	for _, op := range operations {
+		item := doc.Paths[op.path]
+		if item == nil {
+			item = PathItem{}
+			doc.Paths[op.path] = item
+		}
+
+		described, err := op.describe(schemas)
+		if err != nil {
+			return Document{}, fmt.Errorf("describe %s %s: %w", op.method, op.path, err)
+		}
+
+		item[strings.ToLower(op.method)] = described
+	}
+
+	if len(schemas.Definitions) > 0 {
+		doc.Components = &Components{Schemas: schemas.Definitions}
+	}
+
+	return doc, nil
+}
+
+// ServeHTTP serves the Document as json.
+func (s *Spec) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	doc, err := s.Document()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(encoded)
+}
+
+// OperationID sets the unique id of the operation.
+func OperationID(id string) OperationOption {
+	return func(config *operationConfig) {
+		config.operationID = id
+	}
+}
+
+// Summary sets a short summary of the operation.
+func Summary(summary string) OperationOption {
+	return func(config *operationConfig) {
+		config.summary = summary
+	}
+}
+
+// Description sets a longer description of the operation.
+func Description(description string) OperationOption {
+	return func(config *operationConfig) {
+		config.description = description
+	}
+}
+
+// Tags adds tags to group operations.
+func Tags(tags ...string) OperationOption {
+	return func(config *operationConfig) {
+		config.tags = append(config.tags, tags...)
+	}
+}
+
+// Deprecated marks the operation as deprecated.
+func Deprecated() OperationOption {
+	return func(config *operationConfig) {
+		config.deprecated = true
+	}
+}
+
+// Returns declares a response of the operation. body is an example value of the
+// json response body, only its type is used to generate the schema. Pass nil for
+// responses without a body. If no response is declared, a 200 response without
+// schema is documented.
+func Returns(statusCode int, body any) OperationOption {
+	return ReturnsDescribed(statusCode, http.StatusText(statusCode), body)
+}
+
+// ReturnsDescribed works like Returns, but with a custom description of the response.
+func ReturnsDescribed(statusCode int, description string, body any) OperationOption {
+	return func(config *operationConfig) {
+		config.responses = append(config.responses, declaredResponse{
+			statusCode:  statusCode,
+			description: description,
+			body:        body,
+		})
+	}
+}
+
+func (op operation) describe(schemas *jsonschema.Generator) (*Operation, error) {
+	result := &Operation{
+		OperationID: op.config.operationID,
+		Summary:     op.config.summary,
+		Description: op.config.description,
+		Tags:        op.config.tags,
+		Deprecated:  op.config.deprecated,
+		Responses:   map[string]Response{},
+	}
+
+	for idx := range op.fnType.NumIn() {
+		if err := describeInput(result, schemas, op.fnType.In(idx), true); err != nil {
+			return nil, fmt.Errorf("parameter %d: %w", idx, err)
+		}
+	}
+
+	// ensure path parameters are always documented, even if the handler does not use them
+	for _, name := range pathParameterNames(op.path) {
+		if !slices.ContainsFunc(result.Parameters, func(p Parameter) bool { return p.In == "path" && p.Name == name }) {
+			result.Parameters = append(result.Parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	for _, resp := range op.config.responses {
+		described := Response{Description: resp.description}
+
+		if resp.body != nil {
+			schema, err := schemas.Schema(reflect.TypeOf(resp.body))
+			if err != nil {
+				return nil, fmt.Errorf("response %d: %w", resp.statusCode, err)
+			}
+
+			described.Content = map[string]MediaType{
+				"application/json": {Schema: schema},
+			}
+		}
+
+		result.Responses[strconv.Itoa(resp.statusCode)] = described
+	}
+
+	if len(result.Responses) == 0 {
+		result.Responses["200"] = Response{Description: "OK"}
+	}
+
+	return result, nil
+}
+
+// describeInput adds the parameters or request body described by the
+// handler parameter type ty to the operation.
+func describeInput(op *Operation, schemas *jsonschema.Generator, ty reflect.Type, required bool) error {
+	if ty.PkgPath() != internal.GumPackage {
+		return nil
+	}
+
+	name, _, _ := strings.Cut(ty.Name(), "[")
+
+	switch name {
+	case "Option", "Try":
+		// the wrapped value is optional
+		if field, ok := ty.FieldByName("Value"); ok {
+			return describeInput(op, schemas, field.Type, false)
+		}
+
+	case "PathValues", "QueryValues":
+		field, _ := ty.FieldByName("Value")
+
+		valueType := field.Type
+		for valueType.Kind() == reflect.Pointer {
+			valueType = valueType.Elem()
+		}
+
+		if valueType.Kind() != reflect.Struct {
+			return nil
+		}
+
+		in := "query"
+		if name == "PathValues" {
+			in = "path"
+		}
+
+		for _, field := range serde.Fields(valueType) {
+			schema, err := schemas.FieldSchema(field)
+			if err != nil {
+				return err
+			}
+
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:     field.Name,
+				In:       in,
+				Required: in == "path",
+				Schema:   schema,
+			})
+		}
+
+	case "JSON":
+		field, _ := ty.FieldByName("Value")
+
+		schema, err := schemas.Schema(field.Type)
+		if err != nil {
+			return err
+		}
+
+		op.RequestBody = &RequestBody{
+			Required: required,
+			Content: map[string]MediaType{
+				"application/json": {Schema: schema},
+			},
+		}
+
+	case "RawBody", "VerifiedBody":
+		op.RequestBody = &RequestBody{
+			Required: required,
+			Content: map[string]MediaType{
+				"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}},
+			},
+		}
+	}
+
+	return nil
+}
+
+// splitPattern splits a http.ServeMux pattern into method and OpenAPI path.
+func splitPattern(pattern string) (string, string) {
+	method, path := internal.SplitPattern(pattern)
+
+	// "{name...}" matches the rest of the path, OpenAPI only knows "{name}"
+	path = internal.ReplaceWildcards(path, func(wildcard internal.Wildcard) string {
+		return "{" + wildcard.Name + "}"
+	})
+
+	return strings.ToUpper(method), path
+}
+
+func pathParameterNames(path string) []string {
+	var names []string
+	for _, wildcard := range internal.Wildcards(path) {
+		names = append(names, wildcard.Name)
+	}
+
+	return names
+}
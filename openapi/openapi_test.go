@@ -0,0 +1,95 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type User struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	Tags    []string  `json:"tags"`
+	Created time.Time `json:"created"`
+	Manager *User     `json:"manager"`
+}
+
+type userPath struct {
+	ID int64 `json:"id"`
+}
+
+type userQuery struct {
+	Expand bool `json:"expand" default:"false"`
+}
+
+func getUser(path gum.PathValues[userPath], query gum.Option[gum.QueryValues[userQuery]]) (response.Lazy, error) {
+	return response.JSON(User{}), nil
+}
+
+func createUser(body gum.JSON[User]) response.Lazy {
+	return response.Created("/users/1", body.Value)
+}
+
+func TestSpec(t *testing.T) {
+	spec := New(Info{Title: "Users", Version: "1.0"})
+	spec.Add("GET /users/{id}", getUser, OperationID("getUser"), Returns(200, User{}), Returns(404, nil))
+	spec.Add("POST example.com/users/{$}", gum.Handler(createUser), Tags("users"))
+	spec.Add("/files/{path...}", func() {})
+
+	doc, err := spec.Document()
+	AssertEqual(t, err, nil)
+	AssertEqual(t, doc.OpenAPI, "3.1.0")
+	AssertEqual(t, doc.Info.Title, "Users")
+
+	get := doc.Paths["/users/{id}"]["get"]
+	AssertEqual(t, get.OperationID, "getUser")
+	AssertEqual(t, get.Parameters, []Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer", Format: "int64"}},
+		{Name: "expand", In: "query", Schema: &Schema{Type: "boolean", Default: false}},
+	})
+
+	AssertEqual(t, get.Responses["200"].Content["application/json"].Schema.Ref, "#/components/schemas/User")
+	AssertEqual(t, get.Responses["404"], Response{Description: "Not Found"})
+
+	post := doc.Paths["/users/"]["post"]
+	AssertEqual(t, post.Tags, []string{"users"})
+	AssertTrue(t, post.RequestBody.Required)
+	AssertEqual(t, post.RequestBody.Content["application/json"].Schema.Ref, "#/components/schemas/User")
+	AssertEqual(t, post.Responses["200"], Response{Description: "OK"})
+
+	files := doc.Paths["/files/{path}"]["get"]
+	AssertEqual(t, files.Parameters, []Parameter{{Name: "path", In: "path", Required: true, Schema: &Schema{Type: "string"}}})
+
+	user := doc.Components.Schemas["User"]
+	AssertEqual(t, user.Type, "object")
+	AssertEqual(t, user.Required, []string{"id", "name", "tags", "created"})
+	AssertEqual(t, user.Properties["tags"], &Schema{Type: "array", Items: &Schema{Type: "string"}})
+	AssertEqual(t, user.Properties["created"], &Schema{Type: "string", Format: "date-time"})
+	AssertEqual(t, user.Properties["manager"], &Schema{Ref: "#/components/schemas/User"})
+}
+
+func TestSpecServeHTTP(t *testing.T) {
+	spec := New(Info{Title: "Users", Version: "1.0"})
+	spec.Add("GET /users/{id}", getUser)
+
+	resp := gumtest.Serve(spec, httptest.NewRequest("GET", "/openapi.json", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertEqual(t, resp.Header.Get("Content-Type"), "application/json")
+
+	var doc map[string]any
+	AssertEqual(t, json.Unmarshal(resp.Body, &doc), nil)
+	AssertEqual(t, doc["openapi"], "3.1.0")
+}
+
+func TestSwaggerUI(t *testing.T) {
+	resp := gumtest.Serve(SwaggerUI("/openapi.json"), httptest.NewRequest("GET", "/docs", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertTrue(t, strings.Contains(resp.Text(), `url: "/openapi.json"`))
+}
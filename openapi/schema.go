@@ -0,0 +1,12 @@
+package openapi
+
+import (
+	"github.com/go-gum/gum/jsonschema"
+)
+
+// Schema is a JSON Schema, as used by OpenAPI 3.1.
+type Schema = jsonschema.Schema
+
+func newSchemas() *jsonschema.Generator {
+	return jsonschema.NewGenerator("#/components/schemas/")
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// SwaggerUIVersion is the version of swagger-ui loaded from the CDN by SwaggerUI.
+const SwaggerUIVersion = "5.17.14"
+
+var swaggerTemplate = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API documentation</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@{{ .Version }}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@{{ .Version }}/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: {{ .SpecURL }}, dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`))
+
+// SwaggerUI returns a http.Handler serving a page that renders the OpenAPI
+// specification at specURL using swagger-ui. The assets of swagger-ui are
+// loaded from the unpkg.com CDN by the browser.
+func SwaggerUI(specURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		_ = swaggerTemplate.Execute(w, map[string]string{
+			"Version": SwaggerUIVersion,
+			"SpecURL": specURL,
+		})
+	})
+}
@@ -0,0 +1,85 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/response"
+	"log/slog"
+	"reflect"
+)
+
+// HandlerOption configures a http.Handler created by Handler.
+type HandlerOption func(config *handlerConfig)
+
+type handlerConfig struct {
+	parallel  bool
+	checkCtx  checkContext
+	overrides map[reflect.Type]extractor
+	logger    *slog.Logger
+	hooks     []Hooks
+
+	jsonOptions *JSONOptions
+
+	errorEncoder response.ErrorEncoder
+}
+
+// ParallelExtraction configures the Handler to run the extractors of all parameters
+// concurrently. This is useful if a handler has multiple slow, independent extractors,
+// e.g. a session lookup and an auth introspection.
+//
+// The extractors of the body consuming types of this package, like JSON, RawBody or Form,
+// still run one after another, concurrently to the other extractors. Custom extractors
+// that share state must not be used with this option.
+func ParallelExtraction() HandlerOption {
+	return func(config *handlerConfig) {
+		config.parallel = true
+	}
+}
+
+// WithLogger sets the logger used by the Handler, the responses it serves
+// and the Logger extractor. Use ProvideLogger to set a logger for multiple handlers at once.
+func WithLogger(logger *slog.Logger) HandlerOption {
+	return func(config *handlerConfig) {
+		config.logger = logger
+	}
+}
+
+// WithErrorEncoder sets the response.ErrorEncoder used by the Handler for failing extractors,
+// errors returned by and panics of the handler function. Defaults to the encoder set using
+// response.SetErrorEncoder.
+func WithErrorEncoder(encoder response.ErrorEncoder) HandlerOption {
+	return func(config *handlerConfig) {
+		config.errorEncoder = encoder
+	}
+}
+
+// StatusClientClosedRequest is the non-standard status code 499 used by the Handler
+// if the client closed the connection before the response was written.
+const StatusClientClosedRequest = 499
+
+type checkContext int
+
+const (
+	checkContextNever checkContext = iota
+	checkContextBeforeHandle
+	checkContextAfterHandle
+)
+
+// CheckContext configures the Handler to check the requests context after all parameters
+// were extracted. If the context is already done, e.g. because the client disconnected
+// during a slow extraction, the handler function is not called. The Handler responds with
+// StatusClientClosedRequest if the context was canceled, or with 408 Request Timeout,
+// if its deadline was exceeded.
+func CheckContext() HandlerOption {
+	return func(config *handlerConfig) {
+		config.checkCtx = max(config.checkCtx, checkContextBeforeHandle)
+	}
+}
+
+// CheckContextAfterHandle works like CheckContext, but also checks the context after the
+// handler function returned. If the context is done, the response of the handler
+// function is discarded and the error response of CheckContext is sent instead.
+// A discarded response implementing io.Closer is closed, a discarded error is logged.
+func CheckContextAfterHandle() HandlerOption {
+	return func(config *handlerConfig) {
+		config.checkCtx = checkContextAfterHandle
+	}
+}
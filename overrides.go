@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"maps"
+	"net/http"
+	"reflect"
+)
+
+// ExtractorOverride replaces the extractor of a single type. Use Override to create one
+// and WithOverrides to apply it to a Handler.
+type ExtractorOverride struct {
+	ty        reflect.Type
+	extractor extractor
+}
+
+// Override creates an ExtractorOverride that uses fn to extract values of type T,
+// regardless of any Extractor registered for T or T implementing FromRequest.
+func Override[T any](fn Extractor[T]) ExtractorOverride {
+	return ExtractorOverride{
+		ty:        reflect.TypeFor[T](),
+		extractor: reflectExtractor(fn),
+	}
+}
+
+// WithOverrides applies the given overrides to a single Handler without touching the
+// global registry. This is useful in tests to stub extractors like authentication,
+// database connections or a clock:
+//
+//	handler := gum.Handler(listUsers, gum.WithOverrides(
+//	  gum.Override(func(*http.Request) (*sql.DB, error) { return testDB, nil }),
+//	))
+//
+// Overrides are also honored by calls to Extract made while the Handler
+// extracts its parameters, e.g. for an Option[T] parameter.
+func WithOverrides(overrides ...ExtractorOverride) HandlerOption {
+	return func(config *handlerConfig) {
+		config.overrides = maps.Clone(config.overrides)
+		if config.overrides == nil {
+			config.overrides = map[reflect.Type]extractor{}
+		}
+
+		for _, override := range overrides {
+			config.overrides[override.ty] = override.extractor
+		}
+	}
+}
+
+// overridesKey is the context key to store the overrides of the current Handler.
+type overridesKey struct{}
+
+// overrideOf looks up an override for type ty in the requests context.
+func overrideOf(r *http.Request, ty reflect.Type) (extractor, bool) {
+	overrides, _ := r.Context().Value(overridesKey{}).(map[reflect.Type]extractor)
+	ex, ok := overrides[ty]
+	return ex, ok
+}
@@ -0,0 +1,50 @@
+package gum
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"testing"
+)
+
+func TestWithOverrides(t *testing.T) {
+	req := &http.Request{Host: "example.com"}
+
+	override := Override(func(r *http.Request) (Host, error) {
+		return "stub.example.com", nil
+	})
+
+	var extractedValue Host
+	Handler(func(v Host) { extractedValue = v }, WithOverrides(override)).ServeHTTP(nil, req)
+	AssertEqual(t, extractedValue, "stub.example.com")
+
+	// the global registry is not touched
+	Handler(func(v Host) { extractedValue = v }).ServeHTTP(nil, req)
+	AssertEqual(t, extractedValue, "example.com")
+}
+
+func TestWithOverrides_UnregisteredType(t *testing.T) {
+	type Clock struct{ Now string }
+
+	req := &http.Request{}
+
+	override := Override(func(r *http.Request) (Clock, error) {
+		return Clock{Now: "now"}, nil
+	})
+
+	var extractedValue Clock
+	Handler(func(v Clock) { extractedValue = v }, WithOverrides(override)).ServeHTTP(nil, req)
+	AssertEqual(t, extractedValue, Clock{Now: "now"})
+}
+
+func TestWithOverrides_NestedExtract(t *testing.T) {
+	req := &http.Request{}
+
+	override := Override(func(r *http.Request) (ContentType, error) {
+		return "", errors.New("stubbed")
+	})
+
+	var extractedValue Try[ContentType]
+	Handler(func(v Try[ContentType]) { extractedValue = v }, WithOverrides(override)).ServeHTTP(nil, req)
+	AssertEqual(t, extractedValue.Error.Error(), "stubbed")
+}
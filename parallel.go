@@ -0,0 +1,135 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// bodyConsumer is implemented by extractable types that read the body of the request,
+// either directly or through the values they extract. Generic wrappers like Try report
+// whether their type parameters consume the body.
+type bodyConsumer interface {
+	consumesBody() bool
+}
+
+// bodyTypes lists the registered types whose extractors read the body of the request.
+var bodyTypes = map[reflect.Type]bool{
+	reflect.TypeFor[io.Reader]():       true,
+	reflect.TypeFor[io.ReadCloser]():   true,
+	reflect.TypeFor[RawBody]():         true,
+	reflect.TypeFor[Form]():            true,
+	reflect.TypeFor[PostForm]():        true,
+	reflect.TypeFor[*multipart.Form](): true,
+}
+
+// consumesBody checks if extracting a value of type ty reads the body of the request.
+func consumesBody(ty reflect.Type) bool {
+	if bodyTypes[ty] {
+		return true
+	}
+
+	if ty.Kind() == reflect.Pointer {
+		// do not call methods on a nil pointer
+		return false
+	}
+
+	consumer, ok := reflect.Zero(ty).Interface().(bodyConsumer)
+	return ok && consumer.consumesBody()
+}
+
+// consumesBodyOf is the generic version of consumesBody, used by the wrapper types.
+func consumesBodyOf[T any]() bool {
+	return consumesBody(reflect.TypeFor[T]())
+}
+
+// extractParallel runs all extractors concurrently. The first failing extractor
+// calls cancel, which is expected to cancel the context of the request.
+//
+// Extractors marked in serial read the body of the request. They share one copy of the
+// request and run one after another in the order of the parameters, as they would without
+// parallel extraction, while the other extractors run concurrently to them.
+//
+// To keep error reporting deterministic, the error of the failed extractor with the lowest
+// index is returned, independent of the order in which the extractors finish.
+// The returned slice always has one entry per extractor, entries of failed extractors are
+// left as invalid reflect.Value. The values are stored in params, which must have a
+// capacity of at least len(extractors).
+//
+// A panic within an extractor is recovered and raised again on the calling goroutine.
+func extractParallel(r *http.Request, cancel context.CancelCauseFunc, extractors []extractor, serial []bool, params []reflect.Value) ([]reflect.Value, int, error) {
+	params = params[:len(extractors)]
+	errs := make([]error, len(extractors))
+	panics := make([]any, len(extractors))
+
+	run := func(r *http.Request, idx int) {
+		defer func() {
+			if p := recover(); p != nil {
+				panics[idx] = p
+				cancel(fmt.Errorf("extractor panicked: %v", p))
+			}
+		}()
+
+		param, err := extractors[idx](r)
+		if err != nil {
+			errs[idx] = err
+			cancel(err)
+			return
+		}
+
+		params[idx] = param
+	}
+
+	var wg sync.WaitGroup
+	var serialIndices []int
+
+	for idx := range extractors {
+		if serial[idx] {
+			serialIndices = append(serialIndices, idx)
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// each extractor gets its own stack, as they run concurrently
+			run(r.WithContext(withExtractionStack(r.Context())), idx)
+		}()
+	}
+
+	if len(serialIndices) > 0 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// the body consumers share their request, e.g. for the parsed form
+			r := r.WithContext(withExtractionStack(r.Context()))
+			for _, idx := range serialIndices {
+				run(r, idx)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, p := range panics {
+		if p != nil {
+			panic(p)
+		}
+	}
+
+	for idx, err := range errs {
+		if err != nil {
+			return params, idx, err
+		}
+	}
+
+	return params, 0, nil
+}
@@ -0,0 +1,112 @@
+package gum
+
+import (
+	"bytes"
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type slowA string
+type slowB string
+
+func TestParallelExtraction(t *testing.T) {
+	// both extractors wait for each other, this only succeeds if
+	// they are executed concurrently
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	Register(func(r *http.Request) (slowA, error) {
+		wg.Done()
+		wg.Wait()
+		return "a", nil
+	})
+
+	Register(func(r *http.Request) (slowB, error) {
+		wg.Done()
+		wg.Wait()
+		return "b", nil
+	})
+
+	req := &http.Request{}
+
+	var extractedValue string
+	handler := Handler(func(a slowA, b slowB) { extractedValue = string(a) + string(b) }, ParallelExtraction())
+	handler.ServeHTTP(nil, req)
+	AssertEqual(t, extractedValue, "ab")
+}
+
+type failA string
+type failB string
+
+func TestParallelExtractionReportsFirstError(t *testing.T) {
+	Register(func(r *http.Request) (failA, error) {
+		// wait until the second extractor has failed
+		<-r.Context().Done()
+		return "", errors.New("failA")
+	})
+
+	Register(func(r *http.Request) (failB, error) {
+		return "", errors.New("failB")
+	})
+
+	req := &http.Request{}
+
+	rw := gumtest.Serve(Handler(func(a failA, b failB) { t.FailNow() }, ParallelExtraction()), req)
+	AssertEqual(t, rw.StatusCode, http.StatusBadRequest)
+	AssertTrue(t, bytes.Contains(rw.Body, []byte("parameter 0")))
+}
+
+func TestConsumesBody(t *testing.T) {
+	AssertTrue(t, consumesBody(reflect.TypeFor[JSON[int]]()))
+	AssertTrue(t, consumesBody(reflect.TypeFor[RawBody]()))
+	AssertTrue(t, consumesBody(reflect.TypeFor[Option[Form]]()))
+	AssertTrue(t, consumesBody(reflect.TypeFor[Both[Method, Try[JSON[int]]]]()))
+
+	AssertTrue(t, !consumesBody(reflect.TypeFor[Method]()))
+	AssertTrue(t, !consumesBody(reflect.TypeFor[Option[Method]]()))
+	AssertTrue(t, !consumesBody(reflect.TypeFor[*JSON[int]]()))
+}
+
+func TestParallelExtractionBody(t *testing.T) {
+	type Payload struct{ Name string }
+
+	// run this with -race, concurrent reads of the body would be reported
+	for range 50 {
+		var payload Payload
+		var raw RawBody
+		var form Form
+		var postForm PostForm
+
+		handler := Handler(func(m Method, json JSON[Payload], body RawBody) {
+			payload, raw = json.Value, body
+		}, ParallelExtraction())
+
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"Name": "gum"}`))
+		rec := gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+
+		// the body is consumed in the order of the parameters
+		AssertEqual(t, payload, Payload{Name: "gum"})
+		AssertEqual(t, len(raw), 0)
+
+		handler = Handler(func(values Form, post PostForm) {
+			form, postForm = values, post
+		}, ParallelExtraction())
+
+		req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=gum"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec = gumtest.Serve(handler, req)
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+
+		// both extractors see the same parsed form
+		AssertEqual(t, form.Get("name"), "gum")
+		AssertEqual(t, postForm.Get("name"), "gum")
+	}
+}
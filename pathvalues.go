@@ -1,12 +1,15 @@
 package gum
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-gum/gum/serde"
 	"net/http"
 )
 
-// PathValues parses the path parameters to a struct T
+// PathValues parses the path parameters to a struct T.
+// By default, the values are read using http.Request.PathValue. Use the
+// PathParams middleware when mounting handlers in a different router.
 type PathValues[T any] struct {
 	Value T
 }
@@ -19,6 +22,10 @@ func (PathValues[T]) FromRequest(r *http.Request) (PathValues[T], error) {
 		return PathValues[T]{}, fmt.Errorf("deserialize %T: %w", target, err)
 	}
 
+	if err := validateValue(target); err != nil {
+		return PathValues[T]{}, err
+	}
+
 	return PathValues[T]{Value: target}, nil
 }
 
@@ -28,10 +35,55 @@ type pathSourceValue struct {
 }
 
 func (p pathSourceValue) Get(key string) (serde.SourceValue, error) {
-	value := p.req.PathValue(key)
+	source, ok := p.req.Context().Value(pathParamSourceKey{}).(PathParamSource)
+	if !ok {
+		source = (*http.Request).PathValue
+	}
+
+	value := source(p.req, key)
 	if value == "" {
 		return nil, serde.ErrNoValue
 	}
 
 	return serde.StringValue(value), nil
 }
+
+// PathParamSource looks up the value of a path parameter in a request.
+// It returns an empty string, if the parameter does not exist.
+type PathParamSource func(r *http.Request, name string) string
+
+type pathParamSourceKey struct{}
+
+// PathParams returns a Middleware that makes PathValues read the path parameters
+// from the given source instead of http.Request.PathValue. Use it to mount gum
+// handlers in third party routers, e.g. chi:
+//
+//	router := chi.NewRouter()
+//	router.Use(gum.PathParams(chi.URLParam))
+//
+// For gorilla/mux, use PathParamsFromMap:
+//
+//	router := mux.NewRouter()
+//	router.Use(gum.PathParams(gum.PathParamsFromMap(mux.Vars)))
+func PathParams(source PathParamSource) Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			delegate.ServeHTTP(w, WithPathParams(r, source))
+		})
+	}
+}
+
+// WithPathParams returns a shallow copy of r, where PathValues reads
+// the path parameters from the given source. See PathParams.
+func WithPathParams(r *http.Request, source PathParamSource) *http.Request {
+	ctx := context.WithValue(r.Context(), pathParamSourceKey{}, source)
+	return r.WithContext(ctx)
+}
+
+// PathParamsFromMap adapts a function returning all path parameters of
+// a request, like mux.Vars from gorilla/mux, to a PathParamSource.
+func PathParamsFromMap(params func(r *http.Request) map[string]string) PathParamSource {
+	return func(r *http.Request, name string) string {
+		return params(r)[name]
+	}
+}
@@ -3,6 +3,7 @@ package gum
 import (
 	. "github.com/go-gum/gum/internal/test"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -20,3 +21,20 @@ func TestPathValues(t *testing.T) {
 	Handler(func(v PathValues[ValueStruct]) { extractedValue = v.Value }).ServeHTTP(nil, req)
 	AssertEqual(t, extractedValue, ValueStruct{Name: "Albert", Age: 21})
 }
+
+func TestPathParams(t *testing.T) {
+	type ValueStruct struct {
+		Name string
+		Age  int
+	}
+
+	params := map[string]string{"Name": "Albert", "Age": "21"}
+	source := PathParamsFromMap(func(r *http.Request) map[string]string { return params })
+
+	var extractedValue ValueStruct
+	handler := Handler(func(v PathValues[ValueStruct]) { extractedValue = v.Value })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	PathParams(source)(handler).ServeHTTP(nil, req)
+	AssertEqual(t, extractedValue, ValueStruct{Name: "Albert", Age: 21})
+}
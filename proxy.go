@@ -0,0 +1,142 @@
+package gum
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"reflect"
+)
+
+// ProxyOption configures a Proxy.
+type ProxyOption func(config *proxyConfig)
+
+type proxyConfig struct {
+	proxy          *httputil.ReverseProxy
+	rewrite        []func(r *httputil.ProxyRequest)
+	handlerOptions []HandlerOption
+}
+
+// ProxyTransport sets the http.RoundTripper used to send requests to the target.
+func ProxyTransport(transport http.RoundTripper) ProxyOption {
+	return func(config *proxyConfig) {
+		config.proxy.Transport = transport
+	}
+}
+
+// ProxyRewrite adds a function that modifies the outgoing request after its url was set
+// to the target, e.g. to add or remove headers.
+func ProxyRewrite(rewrite func(r *httputil.ProxyRequest)) ProxyOption {
+	return func(config *proxyConfig) {
+		config.rewrite = append(config.rewrite, rewrite)
+	}
+}
+
+// ProxyModifyResponse sets a function that modifies the response of the target before
+// it is copied to the client, see httputil.ReverseProxy.ModifyResponse.
+func ProxyModifyResponse(modify func(resp *http.Response) error) ProxyOption {
+	return func(config *proxyConfig) {
+		config.proxy.ModifyResponse = modify
+	}
+}
+
+// ProxyErrorHandler sets the function handling errors while proxying
+// the request, see httputil.ReverseProxy.ErrorHandler.
+func ProxyErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) ProxyOption {
+	return func(config *proxyConfig) {
+		config.proxy.ErrorHandler = handler
+	}
+}
+
+// ProxyHandlerOptions sets the options of the Handler running the director.
+func ProxyHandlerOptions(options ...HandlerOption) ProxyOption {
+	return func(config *proxyConfig) {
+		config.handlerOptions = append(config.handlerOptions, options...)
+	}
+}
+
+type proxyTargetKey struct{}
+
+var tyURLPointer = reflect.TypeFor[*url.URL]()
+
+// Proxy returns a reverse proxy that forwards requests to a target computed by the
+// director function. The parameters of director are extracted like the parameters
+// of a Handler, it must return a *url.URL and an error:
+//
+//	gum.Proxy(func(path gum.PathValues[Tenant], user User) (*url.URL, error) {
+//		if !user.CanAccess(path.Value.ID) {
+//			return nil, gum.NewHTTPError(http.StatusForbidden, errors.New("forbidden"))
+//		}
+//
+//		return url.Parse("http://" + path.Value.ID + ".tenants.internal")
+//	})
+//
+// The target is applied using httputil.ProxyRequest.SetURL: the path of the incoming
+// request is appended to the path of the target, query parameters are merged.
+// X-Forwarded headers are set on the outgoing request. If director fails, the error
+// is handled like the error of a Handler function.
+func Proxy(director any, options ...ProxyOption) http.Handler {
+	fn := reflect.ValueOf(director)
+	fnType := fn.Type()
+
+	if fnType.Kind() != reflect.Func ||
+		fnType.NumOut() != 2 ||
+		fnType.Out(0) != tyURLPointer ||
+		fnType.Out(1) != reflect.TypeFor[error]() {
+
+		panic(fmt.Errorf("director must return (*url.URL, error), got %s", fnType))
+	}
+
+	config := proxyConfig{proxy: &httputil.ReverseProxy{}}
+	for _, option := range options {
+		option(&config)
+	}
+
+	config.proxy.Rewrite = func(r *httputil.ProxyRequest) {
+		target := r.In.Context().Value(proxyTargetKey{}).(*url.URL)
+
+		r.SetURL(target)
+		r.SetXForwarded()
+
+		for _, rewrite := range config.rewrite {
+			rewrite(r)
+		}
+	}
+
+	proxy := config.proxy
+
+	// wrap the director into a handler function with the same parameters,
+	// so extraction and error handling work exactly like for any other Handler
+	var ins []reflect.Type
+	for idx := range fnType.NumIn() {
+		ins = append(ins, fnType.In(idx))
+	}
+
+	outs := []reflect.Type{reflect.TypeFor[http.Handler](), reflect.TypeFor[error]()}
+	handlerType := reflect.FuncOf(ins, outs, false)
+
+	handlerFn := reflect.MakeFunc(handlerType, func(args []reflect.Value) []reflect.Value {
+		results := fn.Call(args)
+
+		target := interfaceOf[*url.URL](results[0])
+		err := interfaceOf[error](results[1])
+
+		if err == nil && target == nil {
+			err = fmt.Errorf("director %s returned no target", fnType)
+		}
+
+		if err != nil {
+			return []reflect.Value{reflect.Zero(outs[0]), reflect.ValueOf(&err).Elem()}
+		}
+
+		var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), proxyTargetKey{}, target)
+			proxy.ServeHTTP(w, r.WithContext(ctx))
+		})
+
+		return []reflect.Value{reflect.ValueOf(&handler).Elem(), reflect.Zero(outs[1])}
+	})
+
+	return Handler(handlerFn.Interface(), config.handlerOptions...)
+}
@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+)
+
+func TestProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Backend", "yes")
+		_, _ = io.WriteString(w, r.URL.Path+" "+r.Header.Get("X-Tenant")+" "+r.Header.Get("X-Forwarded-Host"))
+	}))
+
+	defer backend.Close()
+
+	type Params struct {
+		Tenant string `json:"tenant"`
+	}
+
+	proxy := Proxy(
+		func(path PathValues[Params]) (*url.URL, error) {
+			if path.Value.Tenant == "blocked" {
+				return nil, NewHTTPError(http.StatusForbidden, errors.New("tenant blocked"))
+			}
+
+			return url.Parse(backend.URL + "/tenants/" + path.Value.Tenant)
+		},
+		ProxyRewrite(func(r *httputil.ProxyRequest) {
+			r.Out.Header.Set("X-Tenant", r.In.PathValue("tenant"))
+		}),
+		ProxyModifyResponse(func(resp *http.Response) error {
+			resp.Header.Del("X-Backend")
+			return nil
+		}),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/{tenant}/", proxy)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/acme/users", nil)
+	resp := gumtest.Serve(mux, req)
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertEqual(t, resp.Text(), "/tenants/acme/acme/users acme example.com")
+	AssertEqual(t, resp.Header.Get("X-Backend"), "")
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/blocked/users", nil)
+	resp = gumtest.Serve(mux, req)
+	AssertEqual(t, resp.StatusCode, http.StatusForbidden)
+}
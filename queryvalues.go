@@ -22,6 +22,10 @@ func (QueryValues[T]) FromRequest(r *http.Request) (QueryValues[T], error) {
 		return QueryValues[T]{}, fmt.Errorf("deserialize %T: %w", target, err)
 	}
 
+	if err := validateValue(target); err != nil {
+		return QueryValues[T]{}, err
+	}
+
 	return QueryValues[T]{Value: target}, nil
 }
 
@@ -0,0 +1,156 @@
+package gum
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrRangeNotSatisfiable is returned by Ranges.Resolve if none of
+// the requested ranges overlaps with the content.
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// RangeSpec is a single byte range of a Range header as defined in RFC 9110, section 14.1.2.
+// For a range like "100-" Last is -1, for a suffix range like "-500", First is -1 and
+// Last holds the suffix length.
+type RangeSpec struct {
+	First int64
+	Last  int64
+}
+
+// ByteRange is a range of bytes resolved against the length of the content.
+type ByteRange struct {
+	Start  int64
+	Length int64
+}
+
+// End returns the position of the last byte of the range.
+func (b ByteRange) End() int64 {
+	return b.Start + b.Length - 1
+}
+
+// ContentRange formats the value of a Content-Range header for this range.
+func (b ByteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", b.Start, b.End(), size)
+}
+
+// maxRanges is the maximum number of ranges accepted in a single Range header.
+// Many small ranges are expensive to serve and are not used by legitimate clients.
+const maxRanges = 100
+
+// Ranges holds the byte ranges requested by the Range header.
+// It is empty if the request does not have a Range header or uses a unit other than bytes.
+// Malformed headers and headers with more than 100 ranges are ignored as well, as allowed
+// by RFC 9110, so that the full content is served.
+type Ranges []RangeSpec
+
+// Resolve resolves the ranges against content of the given size. Ranges that are
+// not satisfiable are dropped. If none of the ranges is satisfiable, an HTTPError with status
+// 416 Range Not Satisfiable wrapping ErrRangeNotSatisfiable is returned, which a handler
+// can return as is.
+func (r Ranges) Resolve(size int64) ([]ByteRange, error) {
+	var result []ByteRange
+
+	for _, spec := range r {
+		switch {
+		case spec.First == -1:
+			// suffix range
+			length := min(spec.Last, size)
+			if length > 0 {
+				result = append(result, ByteRange{Start: size - length, Length: length})
+			}
+
+		case spec.First < size:
+			last := size - 1
+			if spec.Last != -1 && spec.Last < last {
+				last = spec.Last
+			}
+
+			result = append(result, ByteRange{Start: spec.First, Length: last - spec.First + 1})
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, NewHTTPError(http.StatusRequestedRangeNotSatisfiable, ErrRangeNotSatisfiable).
+			WithHeader("Content-Range", fmt.Sprintf("bytes */%d", size))
+	}
+
+	return result, nil
+}
+
+// Satisfiable checks if at least one of the ranges can be satisfied for content of the given size.
+func (r Ranges) Satisfiable(size int64) bool {
+	_, err := r.Resolve(size)
+	return err == nil
+}
+
+func init() {
+	Register(func(r *http.Request) (Ranges, error) {
+		ranges, err := parseRanges(r.Header.Get("Range"))
+		if err != nil {
+			// an invalid Range header is ignored
+			return nil, nil
+		}
+
+		return ranges, nil
+	})
+}
+
+func parseRanges(value string) (Ranges, error) {
+	unit, specs, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(unit) != "bytes" {
+		// ignore missing header and unknown units
+		return nil, nil
+	}
+
+	var ranges Ranges
+
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		firstValue, lastValue, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid range %q", spec)
+		}
+
+		if firstValue == "" {
+			suffix, err := strconv.ParseInt(lastValue, 10, 64)
+			if err != nil || suffix < 0 {
+				return nil, fmt.Errorf("invalid suffix range %q", spec)
+			}
+
+			ranges = append(ranges, RangeSpec{First: -1, Last: suffix})
+			continue
+		}
+
+		first, err := strconv.ParseInt(firstValue, 10, 64)
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("invalid range %q", spec)
+		}
+
+		last := int64(-1)
+		if lastValue != "" {
+			last, err = strconv.ParseInt(lastValue, 10, 64)
+			if err != nil || last < first {
+				return nil, fmt.Errorf("invalid range %q", spec)
+			}
+		}
+
+		ranges = append(ranges, RangeSpec{First: first, Last: last})
+	}
+
+	if len(ranges) == 0 {
+		return nil, errors.New("no ranges in header")
+	}
+
+	if len(ranges) > maxRanges {
+		return nil, fmt.Errorf("too many ranges: %d", len(ranges))
+	}
+
+	return ranges, nil
+}
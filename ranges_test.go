@@ -0,0 +1,66 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRanges(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Range", "bytes=0-99, 950-, -20, 2000-3000")
+
+	var extractedValue Ranges
+	Handler(func(v Ranges) { extractedValue = v }).ServeHTTP(nil, req)
+
+	AssertEqual(t, extractedValue, Ranges{{0, 99}, {950, -1}, {-1, 20}, {2000, 3000}})
+
+	resolved, err := extractedValue.Resolve(1000)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, resolved, []ByteRange{{0, 100}, {950, 50}, {980, 20}})
+	AssertEqual(t, resolved[1].ContentRange(1000), "bytes 950-999/1000")
+}
+
+func TestRanges_NotSatisfiable(t *testing.T) {
+	ranges := Ranges{{2000, 3000}}
+
+	_, err := ranges.Resolve(1000)
+	AssertTrue(t, errors.Is(err, ErrRangeNotSatisfiable))
+	AssertTrue(t, !ranges.Satisfiable(1000))
+
+	var httpErr *HTTPError
+	AssertTrue(t, errors.As(err, &httpErr))
+	AssertEqual(t, httpErr.StatusCode, http.StatusRequestedRangeNotSatisfiable)
+	AssertEqual(t, httpErr.Header.Get("Content-Range"), "bytes */1000")
+}
+
+func TestRanges_Malformed(t *testing.T) {
+	_, err := parseRanges("bytes=10-5")
+	AssertTrue(t, err != nil)
+
+	ranges, err := parseRanges("items=0-5")
+	AssertEqual(t, err, nil)
+	AssertEqual(t, len(ranges), 0)
+
+	_, err = parseRanges("bytes=" + strings.Repeat("0-0,", maxRanges+1))
+	AssertTrue(t, err != nil)
+
+	// invalid headers are ignored, the full content is served
+	for _, value := range []string{"bytes=10-5", "bytes=", "bytes=" + strings.Repeat("0-0,", maxRanges+1)} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", value)
+
+		rec := gumtest.Serve(Handler(func(v Ranges) http.Handler {
+			return response.Text(strconv.Itoa(len(v)))
+		}), req)
+
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+		AssertEqual(t, rec.Text(), "0")
+	}
+}
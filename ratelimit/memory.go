@@ -0,0 +1,186 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the number of calls to Allow after which expired entries are removed.
+const sweepInterval = 1024
+
+// TokenBucket is an in-memory Limiter using the token bucket algorithm.
+// Each key has a bucket of Burst tokens that is refilled at Rate tokens per second.
+type TokenBucket struct {
+	rate  float64
+	burst int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+}
+
+var _ Limiter = (*TokenBucket)(nil)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewTokenBucket creates a new TokenBucket allowing rate requests per second
+// with bursts of up to burst requests.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{
+		rate:    rate,
+		burst:   burst,
+		now:     time.Now,
+		buckets: map[string]*bucket{},
+	}
+}
+
+func (t *TokenBucket) Allow(ctx context.Context, key string) (State, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+
+	b, ok := t.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(t.burst), updatedAt: now}
+		t.buckets[key] = b
+	}
+
+	// refill the bucket
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	b.tokens = math.Min(float64(t.burst), b.tokens+elapsed*t.rate)
+	b.updatedAt = now
+
+	state := State{Limit: t.burst}
+
+	if b.tokens >= 1 {
+		b.tokens -= 1
+		state.Allowed = true
+	} else {
+		state.RetryAfter = t.durationOf(1 - b.tokens)
+	}
+
+	state.Remaining = int(b.tokens)
+	state.Reset = t.durationOf(float64(t.burst) - b.tokens)
+
+	return state, nil
+}
+
+func (t *TokenBucket) durationOf(tokens float64) time.Duration {
+	return time.Duration(tokens / t.rate * float64(time.Second))
+}
+
+// sweep removes buckets that are full again, they behave the same as a new bucket.
+func (t *TokenBucket) sweep(now time.Time) {
+	t.calls += 1
+	if t.calls%sweepInterval != 0 {
+		return
+	}
+
+	for key, b := range t.buckets {
+		if b.tokens+now.Sub(b.updatedAt).Seconds()*t.rate >= float64(t.burst) {
+			delete(t.buckets, key)
+		}
+	}
+}
+
+// SlidingWindow is an in-memory Limiter allowing Limit requests per Window.
+// It approximates a sliding window by weighting the count of the previous
+// fixed window with its overlap with the sliding window.
+type SlidingWindow struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*window
+	calls   int
+}
+
+var _ Limiter = (*SlidingWindow)(nil)
+
+type window struct {
+	start    time.Time
+	previous int
+	current  int
+}
+
+// NewSlidingWindow creates a new SlidingWindow allowing limit requests per window.
+func NewSlidingWindow(limit int, duration time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		limit:   limit,
+		window:  duration,
+		now:     time.Now,
+		windows: map[string]*window{},
+	}
+}
+
+func (s *SlidingWindow) Allow(ctx context.Context, key string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	start := now.Truncate(s.window)
+
+	w, ok := s.windows[key]
+	if !ok {
+		w = &window{start: start}
+		s.windows[key] = w
+	}
+
+	// advance to the current fixed window
+	switch {
+	case w.start.Equal(start):
+		// still the same window
+
+	case w.start.Add(s.window).Equal(start):
+		w.previous, w.current, w.start = w.current, 0, start
+
+	default:
+		w.previous, w.current, w.start = 0, 0, start
+	}
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(s.window)
+	count := int(math.Floor(float64(w.previous)*weight)) + w.current
+
+	state := State{
+		Limit: s.limit,
+		Reset: s.window - elapsed,
+	}
+
+	if count < s.limit {
+		w.current += 1
+		count += 1
+		state.Allowed = true
+	} else {
+		state.RetryAfter = s.window - elapsed
+	}
+
+	state.Remaining = max(0, s.limit-count)
+
+	return state, nil
+}
+
+// sweep removes windows that do not influence the limit anymore.
+func (s *SlidingWindow) sweep(now time.Time) {
+	s.calls += 1
+	if s.calls%sweepInterval != 0 {
+		return
+	}
+
+	for key, w := range s.windows {
+		if now.Sub(w.start) >= 2*s.window {
+			delete(s.windows, key)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package ratelimit limits the rate of requests per client.
+//
+// A Limiter decides if a request is allowed, based on a key derived from the request
+// using a KeyFunc. The Middleware sets the RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers on every response and rejects requests exceeding
+// the limit with 429 Too Many Requests:
+//
+//	limiter := ratelimit.NewTokenBucket(10, 100)
+//	http.ListenAndServe(":8080", ratelimit.Middleware(limiter, ratelimit.ClientIP)(mux))
+//
+// Handlers can access the current State using the State extractor.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// State is the state of the rate limit for a single key after a request.
+type State struct {
+	// Allowed is true if the request is within the limit
+	Allowed bool
+
+	// Limit is the maximum number of requests
+	Limit int
+
+	// Remaining is the number of requests still allowed
+	Remaining int
+
+	// Reset is the time until the quota is fully restored
+	Reset time.Duration
+
+	// RetryAfter is the time until the next request is allowed, if the request was rejected
+	RetryAfter time.Duration
+}
+
+// Limiter decides if a request identified by a key is allowed.
+type Limiter interface {
+	// Allow consumes one request for the key and returns the resulting state.
+	Allow(ctx context.Context, key string) (State, error)
+}
+
+// KeyFunc derives the key to limit by from a request.
+type KeyFunc func(r *http.Request) (string, error)
+
+// ClientIP uses the ip address of the remote end of the connection as key.
+// Header like X-Forwarded-For are not taken into account.
+func ClientIP(r *http.Request) (string, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr, nil
+	}
+
+	return host, nil
+}
+
+// Header uses the value of the given request header as key, e.g. an api key.
+// Requests without the header are rejected.
+func Header(name string) KeyFunc {
+	return func(r *http.Request) (string, error) {
+		value := r.Header.Get(name)
+		if value == "" {
+			return "", fmt.Errorf("no %s header in request", name)
+		}
+
+		return value, nil
+	}
+}
+
+// Extracted uses a value extracted from the request as key, e.g. an authenticated user.
+func Extracted[T any](keyOf func(T) string) KeyFunc {
+	return func(r *http.Request) (string, error) {
+		value, err := gum.Extract[T](r)
+		if err != nil {
+			return "", err
+		}
+
+		return keyOf(value), nil
+	}
+}
+
+type stateKey struct{}
+
+func init() {
+	gum.Register(func(r *http.Request) (State, error) {
+		state, ok := r.Context().Value(stateKey{}).(State)
+		if !ok {
+			return State{}, errors.New("request did not pass ratelimit.Middleware")
+		}
+
+		return state, nil
+	})
+}
+
+// Middleware limits requests using the given Limiter. Requests for which the key
+// can not be derived are rejected with 400 Bad Request. If the Limiter fails,
+// the request is allowed, and the State only reports that it is allowed.
+func Middleware(limiter Limiter, keyOf KeyFunc) gum.Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, err := keyOf(r)
+			if err != nil {
+				err = gum.NewHTTPError(http.StatusBadRequest, fmt.Errorf("rate limit key: %w", err))
+				gum.ErrorResponse(err, http.StatusBadRequest).ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+
+			state, err := limiter.Allow(ctx, key)
+			if err != nil {
+				// fail open, a broken limiter should not take down the service
+				ctx = context.WithValue(ctx, stateKey{}, State{Allowed: true})
+				delegate.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(state.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(state.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(seconds(state.Reset)))
+
+			if !state.Allowed {
+				err := gum.TooManyRequestsError(state.RetryAfter, errors.New("rate limit exceeded"))
+				gum.ErrorResponse(err, http.StatusTooManyRequests).ServeHTTP(w, r)
+				return
+			}
+
+			ctx = context.WithValue(ctx, stateKey{}, state)
+			delegate.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// seconds rounds the duration up to full seconds
+func seconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	limiter := NewTokenBucket(1, 2)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	state, _ := limiter.Allow(ctx, "a")
+	AssertEqual(t, state, State{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second})
+
+	state, _ = limiter.Allow(ctx, "a")
+	AssertTrue(t, state.Allowed)
+
+	state, _ = limiter.Allow(ctx, "a")
+	AssertEqual(t, state, State{Allowed: false, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second})
+
+	// other keys are not affected
+	state, _ = limiter.Allow(ctx, "b")
+	AssertTrue(t, state.Allowed)
+
+	now = now.Add(time.Second)
+	state, _ = limiter.Allow(ctx, "a")
+	AssertTrue(t, state.Allowed)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	limiter := NewSlidingWindow(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	state, _ := limiter.Allow(ctx, "a")
+	AssertTrue(t, state.Allowed)
+
+	state, _ = limiter.Allow(ctx, "a")
+	AssertTrue(t, state.Allowed)
+
+	state, _ = limiter.Allow(ctx, "a")
+	AssertTrue(t, !state.Allowed)
+
+	// half of the previous window still counts
+	now = now.Truncate(time.Minute).Add(time.Minute + 30*time.Second)
+	state, _ = limiter.Allow(ctx, "a")
+	AssertTrue(t, state.Allowed)
+
+	state, _ = limiter.Allow(ctx, "a")
+	AssertTrue(t, !state.Allowed)
+}
+
+func TestMiddleware(t *testing.T) {
+	var extractedValue State
+	handler := Middleware(NewTokenBucket(1, 1), ClientIP)(gum.Handler(func(state State) {
+		extractedValue = state
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, rec.Header().Get("RateLimit-Limit"), "1")
+	AssertEqual(t, rec.Header().Get("RateLimit-Remaining"), "0")
+	AssertEqual(t, extractedValue.Remaining, 0)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusTooManyRequests)
+	AssertEqual(t, rec.Header().Get("Retry-After"), "1")
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string) (State, error) {
+	return State{}, errors.New("limiter down")
+}
+
+func TestMiddlewareFailOpen(t *testing.T) {
+	var extractedValue State
+	handler := Middleware(failingLimiter{}, ClientIP)(gum.Handler(func(state State) {
+		extractedValue = state
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, extractedValue, State{Allowed: true})
+}
+
+func TestMiddlewareNoKey(t *testing.T) {
+	handler := Middleware(NewTokenBucket(1, 1), Header("X-Api-Key"))(http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusBadRequest)
+	AssertTrue(t, strings.Contains(rec.Body.String(), "no X-Api-Key header in request"))
+}
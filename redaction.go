@@ -0,0 +1,244 @@
+package gum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// RedactedValue replaces redacted values.
+const RedactedValue = "[redacted]"
+
+// Redaction lists the values that must never be written to logs. It is used by the
+// Logger extractor, the AccessLog middleware and the Dump middleware.
+//
+// Like JSONOptions, a Redaction is looked up in the requests context first, where it can
+// be set using ProvideContextValue. Otherwise, the Redaction set by SetRedaction is used,
+// which defaults to DefaultRedaction.
+type Redaction struct {
+	// Headers are the names of the headers to redact. Names are case-insensitive.
+	Headers []string
+
+	// QueryParams are the names of the query parameters to redact.
+	QueryParams []string
+
+	// JSONFields are paths of fields to redact in json documents, with dots separating
+	// the object keys, e.g. "user.password". The segment "*" matches any key and
+	// any element of an array.
+	JSONFields []string
+}
+
+// DefaultRedaction redacts credentials in the common headers, the access_token
+// query parameter and top level password fields.
+func DefaultRedaction() Redaction {
+	return Redaction{
+		Headers:     []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+		QueryParams: []string{"access_token"},
+		JSONFields:  []string{"password"},
+	}
+}
+
+var redaction atomic.Pointer[Redaction]
+
+// SetRedaction sets the Redaction used for all requests that do not
+// provide their own Redaction in the requests context.
+func SetRedaction(r Redaction) {
+	redaction.Store(&r)
+}
+
+// redactionOf returns the Redaction for the given request.
+func redactionOf(req *http.Request) Redaction {
+	if r, ok := contextValueOf[Redaction](req); ok {
+		return r
+	}
+
+	if r := redaction.Load(); r != nil {
+		return *r
+	}
+
+	return DefaultRedaction()
+}
+
+// RedactHeader returns a copy of header with the values of all redacted headers replaced.
+func (r Redaction) RedactHeader(header http.Header) http.Header {
+	header = header.Clone()
+
+	for _, name := range r.Headers {
+		name = http.CanonicalHeaderKey(name)
+		if _, ok := header[name]; ok {
+			header[name] = []string{RedactedValue}
+		}
+	}
+
+	return header
+}
+
+// RedactHeaderValue returns value, or RedactedValue if the header is redacted.
+func (r Redaction) RedactHeaderValue(name string, value string) string {
+	if slices.ContainsFunc(r.Headers, func(h string) bool { return strings.EqualFold(h, name) }) {
+		return RedactedValue
+	}
+
+	return value
+}
+
+// RedactURL returns the url as a string with the values of all redacted query parameters replaced.
+func (r Redaction) RedactURL(u *url.URL) string {
+	if u.RawQuery == "" || len(r.QueryParams) == 0 {
+		return u.String()
+	}
+
+	query := u.Query()
+
+	changed := false
+	for _, name := range r.QueryParams {
+		if _, ok := query[name]; ok {
+			query[name] = []string{RedactedValue}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return u.String()
+	}
+
+	copied := *u
+	copied.RawQuery = query.Encode()
+
+	return copied.String()
+}
+
+// RedactJSON returns a copy of the json document with the redacted fields replaced.
+// The second return value is false, if body is not a valid json document.
+func (r Redaction) RedactJSON(body []byte) ([]byte, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, false
+	}
+
+	if len(r.JSONFields) == 0 {
+		return body, true
+	}
+
+	for _, path := range r.JSONFields {
+		document = redactJSONPath(document, strings.Split(path, "."))
+	}
+
+	redacted, err := json.Marshal(document)
+	if err != nil {
+		return nil, false
+	}
+
+	return redacted, true
+}
+
+func redactJSONPath(value any, path []string) any {
+	if len(path) == 0 {
+		return RedactedValue
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			if path[0] == "*" || path[0] == key {
+				value[key] = redactJSONPath(child, path[1:])
+			}
+		}
+
+	case []any:
+		if path[0] == "*" {
+			for idx, child := range value {
+				value[idx] = redactJSONPath(child, path[1:])
+			}
+		}
+	}
+
+	return value
+}
+
+// redactsKey returns true, if a log attribute with the given key must be redacted. Keys are
+// matched against the header and query parameter names, and the last segment of each
+// json field path.
+func (r Redaction) redactsKey(key string) bool {
+	for _, name := range r.Headers {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+
+	if slices.Contains(r.QueryParams, key) {
+		return true
+	}
+
+	for _, path := range r.JSONFields {
+		if idx := strings.LastIndexByte(path, '.'); path[idx+1:] == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactHandler wraps a slog.Handler and replaces the values of all attributes
+// whose key is a redacted header, query parameter or json field name.
+// The Logger extractor wraps the request logger with a RedactHandler.
+func RedactHandler(handler slog.Handler, r Redaction) slog.Handler {
+	return redactHandler{Handler: handler, redaction: r}
+}
+
+type redactHandler struct {
+	slog.Handler
+	redaction Redaction
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for idx, attr := range attrs {
+		redacted[idx] = h.redactAttr(attr)
+	}
+
+	return redactHandler{Handler: h.Handler.WithAttrs(redacted), redaction: h.redaction}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{Handler: h.Handler.WithGroup(name), redaction: h.redaction}
+}
+
+func (h redactHandler) redactAttr(attr slog.Attr) slog.Attr {
+	if h.redaction.redactsKey(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+
+		redacted := make([]any, len(group))
+		for idx, child := range group {
+			redacted[idx] = h.redactAttr(child)
+		}
+
+		return slog.Group(attr.Key, redacted...)
+	}
+
+	return attr
+}
@@ -0,0 +1,125 @@
+package gum
+
+import (
+	"bytes"
+	"encoding/json"
+	. "github.com/go-gum/gum/internal/test"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRedaction(t *testing.T) {
+	redaction := Redaction{
+		Headers:     []string{"authorization"},
+		QueryParams: []string{"token"},
+		JSONFields:  []string{"password", "users.*.secret"},
+	}
+
+	t.Run("Header", func(t *testing.T) {
+		header := http.Header{"Authorization": {"Bearer abc"}, "Accept": {"*/*"}}
+
+		redacted := redaction.RedactHeader(header)
+		AssertEqual(t, redacted.Get("Authorization"), RedactedValue)
+		AssertEqual(t, redacted.Get("Accept"), "*/*")
+
+		// the original header is not modified
+		AssertEqual(t, header.Get("Authorization"), "Bearer abc")
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		u, _ := url.Parse("/items?token=abc&page=2")
+		AssertEqual(t, redaction.RedactURL(u), "/items?page=2&token=%5Bredacted%5D")
+
+		u, _ = url.Parse("/items?page=2")
+		AssertEqual(t, redaction.RedactURL(u), "/items?page=2")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		body := `{"password": "a", "users": [{"name": "Albert", "secret": 12}], "count": 1}`
+
+		redacted, ok := redaction.RedactJSON([]byte(body))
+		AssertTrue(t, ok)
+		AssertEqual(t, string(redacted), `{"count":1,"password":"[redacted]","users":[{"name":"Albert","secret":"[redacted]"}]}`)
+
+		_, ok = redaction.RedactJSON([]byte(`{"password": "a`))
+		AssertTrue(t, !ok)
+	})
+}
+
+func TestRedactHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	handler := RedactHandler(slog.NewJSONHandler(&buf, nil), DefaultRedaction())
+
+	slog.New(handler).
+		With(slog.String("Authorization", "Bearer abc")).
+		Info("message",
+			slog.String("user", "albert"),
+			slog.Group("login", slog.String("password", "secret")),
+		)
+
+	var record map[string]any
+	AssertEqual(t, json.Unmarshal(buf.Bytes(), &record), nil)
+
+	AssertEqual(t, record["Authorization"], any(RedactedValue))
+	AssertEqual(t, record["user"], any("albert"))
+	AssertEqual(t, record["login"], any(map[string]any{"password": RedactedValue}))
+}
+
+func TestRedactionConsumers(t *testing.T) {
+	t.Run("AccessLog", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		accessLog := AccessLog(AccessLogLogger(logger), AccessLogHeader("auth", "Authorization"))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer abc")
+
+		accessLog(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), req)
+
+		var record map[string]any
+		AssertEqual(t, json.Unmarshal(buf.Bytes(), &record), nil)
+		AssertEqual(t, record["auth"], any(RedactedValue))
+	})
+
+	t.Run("Logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		handler := Handler(func(log Logger) http.Handler {
+			log.Info("login", slog.String("password", "secret"))
+			return http.NotFoundHandler()
+		})
+
+		ProvideLogger(logger)(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		AssertTrue(t, strings.Contains(buf.String(), `"password":"[redacted]"`))
+		AssertTrue(t, !strings.Contains(buf.String(), "secret"))
+	})
+
+	t.Run("Dump", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		redaction := Redaction{QueryParams: []string{"token"}, JSONFields: []string{"password"}}
+
+		handler := ProvideContextValue(redaction)(Dump(DumpWriter(&buf))(dumpTestHandler()))
+
+		req := httptest.NewRequest(http.MethodPost, "/login?token=abc", strings.NewReader(`{"user": "albert", "password": "secret"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer abc")
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		dump := buf.String()
+		AssertTrue(t, strings.Contains(dump, "/login?token=%5Bredacted%5D"))
+		AssertTrue(t, strings.Contains(dump, `{"password":"[redacted]","user":"albert"}`))
+
+		// the redaction in the context does not list the Authorization header
+		AssertTrue(t, strings.Contains(dump, "Authorization: Bearer abc"))
+	})
+}
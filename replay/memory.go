@@ -0,0 +1,67 @@
+package replay
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the number of calls to Remember after which expired nonces are removed.
+const sweepInterval = 1024
+
+// Memory is an in-memory Store. Nonces are not shared between
+// multiple instances of a service.
+type Memory struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	calls  int
+}
+
+var _ Store = (*Memory)(nil)
+
+// NewMemory creates a new, empty Memory store.
+func NewMemory() *Memory {
+	return &Memory{
+		now:    time.Now,
+		nonces: map[string]time.Time{},
+	}
+}
+
+func (m *Memory) Remember(ctx context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if existing, ok := m.nonces[nonce]; ok && existing.After(now) {
+		return false, nil
+	}
+
+	m.nonces[nonce] = expiresAt
+	return true, nil
+}
+
+func (m *Memory) Forget(ctx context.Context, nonce string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.nonces, nonce)
+	return nil
+}
+
+// sweep removes expired nonces.
+func (m *Memory) sweep(now time.Time) {
+	m.calls += 1
+	if m.calls%sweepInterval != 0 {
+		return
+	}
+
+	for nonce, expiresAt := range m.nonces {
+		if !expiresAt.After(now) {
+			delete(m.nonces, nonce)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Package replay rejects replayed requests, e.g. to secure webhook and machine-to-machine
+// endpoints. Every request carries a unique nonce and the time it was created. Requests
+// created outside a time window are rejected, as are requests whose nonce was already
+// seen within the window:
+//
+//	store := replay.NewMemory()
+//	mux.Handle("POST /webhooks/acme", gum.VerifyHMAC(signature)(
+//	  replay.Middleware(store, replay.Headers("X-Acme-Delivery", "X-Acme-Timestamp"))(webhookHandler),
+//	))
+//
+// Requests without a timestamp are rejected, unless WithoutTimestamp is used. As their age
+// is unknown, their nonces must be remembered for much longer than the window:
+//
+//	replay.Middleware(store, replay.Headers("X-GitHub-Delivery", ""), replay.WithoutTimestamp(30*24*time.Hour))
+//
+// The nonce and timestamp must be covered by the signature of the request, otherwise an
+// attacker could simply replace them. With HMAC signatures over the body, include them in
+// the body or use a header the sender guarantees to be unique per delivery. With HTTP
+// Message Signatures, use HTTPSignature, which reads the signed nonce and created
+// parameters of the signature.
+//
+// If the handler responds with a 5xx status code, the nonce is forgotten again, so the
+// sender can retry the request.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/httpsig"
+	"github.com/go-gum/gum/internal"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNoNonce is returned if a request does not carry a nonce.
+	ErrNoNonce = errors.New("no nonce in request")
+
+	// ErrNoTimestamp is returned if a request does not carry a timestamp.
+	ErrNoTimestamp = errors.New("no timestamp in request")
+
+	// ErrStale is returned if the timestamp of a request is outside the window.
+	ErrStale = errors.New("request timestamp is outside the allowed window")
+
+	// ErrReplayed is returned if the nonce of a request was already seen.
+	ErrReplayed = errors.New("request was replayed")
+)
+
+// Store remembers the nonces of processed requests.
+type Store interface {
+	// Remember records the nonce until expiresAt. Returns false if the nonce
+	// is already recorded and has not expired yet.
+	Remember(ctx context.Context, nonce string, expiresAt time.Time) (bool, error)
+
+	// Forget removes the nonce, so a request with the same nonce is accepted again.
+	Forget(ctx context.Context, nonce string) error
+}
+
+// NonceFunc returns the nonce of a request and the time the request was created.
+// A zero timestamp means the request does not carry a timestamp, see WithoutTimestamp.
+type NonceFunc func(r *http.Request) (nonce string, timestamp time.Time, err error)
+
+// Headers reads the nonce and the timestamp from request headers. The timestamp is
+// expected in seconds since the unix epoch. Pass an empty timestampHeader if the
+// requests do not carry a timestamp.
+func Headers(nonceHeader, timestampHeader string) NonceFunc {
+	return func(r *http.Request) (string, time.Time, error) {
+		nonce := r.Header.Get(nonceHeader)
+		if nonce == "" {
+			return "", time.Time{}, fmt.Errorf("%w: %s header is missing", ErrNoNonce, nonceHeader)
+		}
+
+		if timestampHeader == "" {
+			return nonce, time.Time{}, nil
+		}
+
+		value := r.Header.Get(timestampHeader)
+		if value == "" {
+			return "", time.Time{}, fmt.Errorf("%s header is missing", timestampHeader)
+		}
+
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("parse %s header: %w", timestampHeader, err)
+		}
+
+		return nonce, time.Unix(seconds, 0), nil
+	}
+}
+
+// HTTPSignature uses the nonce and created parameters of the requests httpsig.Signature.
+// Nonces are scoped to the key id of the signature. Requires the Middleware of a
+// httpsig.Verifier.
+func HTTPSignature() NonceFunc {
+	return func(r *http.Request) (string, time.Time, error) {
+		signature, err := gum.Extract[httpsig.Signature](r)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+
+		if signature.Nonce == "" {
+			return "", time.Time{}, fmt.Errorf("%w: signature has no nonce parameter", ErrNoNonce)
+		}
+
+		return signature.KeyID + ":" + signature.Nonce, signature.Created, nil
+	}
+}
+
+// Option configures the Middleware.
+type Option func(config *config)
+
+type config struct {
+	window    time.Duration
+	retention time.Duration
+	now       func() time.Time
+}
+
+// Window sets how far the timestamp of a request may differ from the current time.
+// Nonces are remembered until the timestamp of their request leaves the window.
+// Defaults to five minutes.
+func Window(window time.Duration) Option {
+	return func(config *config) {
+		config.window = window
+	}
+}
+
+// WithoutTimestamp accepts requests that do not carry a timestamp. Their nonces are
+// remembered for the given retention, after which a replay of the request is accepted
+// again. Choose a retention much longer than the time a sender retries its requests.
+func WithoutTimestamp(retention time.Duration) Option {
+	return func(config *config) {
+		config.retention = retention
+	}
+}
+
+// Middleware rejects replayed requests. The status codes of the rejections are:
+//
+//   - 400 Bad Request if the request has no nonce or an invalid or missing timestamp.
+//   - 401 Unauthorized if the timestamp is outside the window.
+//   - 409 Conflict if the nonce was already seen.
+//   - 503 Service Unavailable if the Store fails, as requests can not be checked.
+//
+// If the NonceFunc returns a gum.HTTPError, its status code is used instead.
+func Middleware(store Store, nonceOf NonceFunc, options ...Option) gum.Middleware {
+	config := config{
+		window: 5 * time.Minute,
+		now:    time.Now,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce, timestamp, err := nonceOf(r)
+			if err == nil && nonce == "" {
+				err = ErrNoNonce
+			}
+
+			if err != nil {
+				statusCode := http.StatusBadRequest
+
+				var httpErr *gum.HTTPError
+				if errors.As(err, &httpErr) && httpErr.StatusCode > 0 {
+					statusCode = httpErr.StatusCode
+				}
+
+				response.Error(err, statusCode).ServeHTTP(w, r)
+				return
+			}
+
+			now := config.now()
+
+			var expiresAt time.Time
+
+			switch {
+			case !timestamp.IsZero():
+				if age := now.Sub(timestamp); age > config.window || age < -config.window {
+					response.Error(ErrStale, http.StatusUnauthorized).ServeHTTP(w, r)
+					return
+				}
+
+				expiresAt = timestamp.Add(config.window)
+
+			case config.retention > 0:
+				expiresAt = now.Add(config.retention)
+
+			default:
+				response.Error(ErrNoTimestamp, http.StatusBadRequest).ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+
+			fresh, err := store.Remember(ctx, nonce, expiresAt)
+			if err != nil {
+				response.Error(fmt.Errorf("remember nonce: %w", err), http.StatusServiceUnavailable).ServeHTTP(w, r)
+				return
+			}
+
+			if !fresh {
+				response.Error(ErrReplayed, http.StatusConflict).ServeHTTP(w, r)
+				return
+			}
+
+			rw := internal.NewRecordingWriter(w)
+			delegate.ServeHTTP(rw, r)
+
+			if rw.StatusCode() >= 500 {
+				// let the sender retry the failed request
+				_ = store.Forget(context.WithoutCancel(ctx), nonce)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package replay
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/go-gum/gum/httpsig"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	store := NewMemory()
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	fresh, _ := store.Remember(ctx, "a", now.Add(time.Minute))
+	AssertTrue(t, fresh)
+
+	fresh, _ = store.Remember(ctx, "a", now.Add(time.Minute))
+	AssertTrue(t, !fresh)
+
+	fresh, _ = store.Remember(ctx, "b", now.Add(time.Minute))
+	AssertTrue(t, fresh)
+
+	// expired nonces are accepted again
+	now = now.Add(time.Minute)
+	fresh, _ = store.Remember(ctx, "a", now.Add(time.Minute))
+	AssertTrue(t, fresh)
+
+	_ = store.Forget(ctx, "a")
+	fresh, _ = store.Remember(ctx, "a", now.Add(time.Minute))
+	AssertTrue(t, fresh)
+}
+
+func TestMiddleware(t *testing.T) {
+	statusCode := http.StatusOK
+
+	handler := Middleware(NewMemory(), Headers("X-Nonce", "X-Timestamp"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+	}))
+
+	serve := func(nonce string, timestamp time.Time) int {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if nonce != "" {
+			req.Header.Set("X-Nonce", nonce)
+		}
+
+		req.Header.Set("X-Timestamp", strconv.FormatInt(timestamp.Unix(), 10))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	now := time.Now()
+
+	AssertEqual(t, serve("a", now), http.StatusOK)
+	AssertEqual(t, serve("a", now), http.StatusConflict)
+	AssertEqual(t, serve("b", now.Add(-time.Minute)), http.StatusOK)
+
+	AssertEqual(t, serve("", now), http.StatusBadRequest)
+	AssertEqual(t, serve("c", now.Add(-10*time.Minute)), http.StatusUnauthorized)
+	AssertEqual(t, serve("c", now.Add(10*time.Minute)), http.StatusUnauthorized)
+
+	// failed requests can be retried
+	statusCode = http.StatusServiceUnavailable
+	AssertEqual(t, serve("d", now), http.StatusServiceUnavailable)
+
+	statusCode = http.StatusOK
+	AssertEqual(t, serve("d", now), http.StatusOK)
+	AssertEqual(t, serve("d", now), http.StatusConflict)
+}
+
+func TestHeadersWithoutTimestamp(t *testing.T) {
+	now := time.Now()
+	clock := func(config *config) { config.now = func() time.Time { return now } }
+
+	store := NewMemory()
+	store.now = func() time.Time { return now }
+
+	serve := func(handler http.Handler) int {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("X-Delivery", "1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	noop := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	// a timestamp is required by default
+	AssertEqual(t, serve(Middleware(store, Headers("X-Delivery", ""), clock)(noop)), http.StatusBadRequest)
+
+	handler := Middleware(store, Headers("X-Delivery", ""), clock, WithoutTimestamp(24*time.Hour))(noop)
+
+	AssertEqual(t, serve(handler), http.StatusOK)
+	AssertEqual(t, serve(handler), http.StatusConflict)
+
+	// the nonce is remembered for longer than the window
+	now = now.Add(time.Hour)
+	AssertEqual(t, serve(handler), http.StatusConflict)
+
+	now = now.Add(24 * time.Hour)
+	AssertEqual(t, serve(handler), http.StatusOK)
+}
+
+func TestHTTPSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+
+	verifier := httpsig.NewVerifier(httpsig.StaticKeys(httpsig.Key{ID: "client", Algorithm: httpsig.AlgorithmEd25519, Material: pub}))
+
+	handler := verifier.Middleware()(
+		Middleware(NewMemory(), HTTPSignature())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})),
+	)
+
+	serve := func(req *http.Request) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	key := httpsig.Key{ID: "client", Algorithm: httpsig.AlgorithmEd25519, Material: priv}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	AssertEqual(t, httpsig.SignRequest(req, key, httpsig.WithNonce()), nil)
+
+	AssertEqual(t, serve(req), http.StatusOK)
+	AssertEqual(t, serve(req), http.StatusConflict)
+
+	// a signature without a nonce
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	AssertEqual(t, httpsig.SignRequest(req, key), nil)
+	AssertEqual(t, serve(req), http.StatusBadRequest)
+
+	// an unsigned request fails with the status of the extractor
+	AssertEqual(t, serve(httptest.NewRequest(http.MethodPost, "/", nil)), http.StatusUnauthorized)
+}
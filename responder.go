@@ -0,0 +1,50 @@
+package gum
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Responder is implemented by types that define their own http representation. A handler
+// function can return a Responder instead of a http.Handler, which keeps the http details
+// out of the domain type until the response is written:
+//
+//	type Invoice struct { ... }
+//
+//	func (inv Invoice) Respond(r *http.Request) http.Handler {
+//		if inv.Paid {
+//			return response.Encoded(inv)
+//		}
+//
+//		return response.Encoded(inv).WithStatusCode(http.StatusPaymentRequired)
+//	}
+//
+//	func getInvoice(id gum.PathValues[InvoiceID]) (Invoice, error) { ... }
+//
+// If a type implements both http.Handler and Responder, it is served as a http.Handler.
+type Responder interface {
+	// Respond returns the http.Handler writing the response to r.
+	// A nil http.Handler writes an empty 200 OK response.
+	Respond(r *http.Request) http.Handler
+}
+
+var tyResponder = reflect.TypeFor[Responder]()
+
+// isResponder reports whether values of type ty are served by calling Respond.
+func isResponder(ty reflect.Type) bool {
+	return !ty.Implements(reflect.TypeFor[http.Handler]()) && ty.Implements(tyResponder)
+}
+
+// responderHandler adapts a Responder to a http.Handler. Respond is called
+// with the request that is served.
+func responderHandler(responder Responder) http.Handler {
+	if responder == nil {
+		return nil
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler := responder.Respond(r); handler != nil {
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
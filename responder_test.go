@@ -0,0 +1,64 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testInvoice struct {
+	Paid bool
+}
+
+func (inv testInvoice) Respond(r *http.Request) http.Handler {
+	if !inv.Paid {
+		return response.Text("unpaid " + r.URL.Path).WithStatusCode(http.StatusPaymentRequired)
+	}
+
+	return response.Text("paid " + r.URL.Path)
+}
+
+func TestResponder(t *testing.T) {
+	t.Run("Single", func(t *testing.T) {
+		handler := Handler(func() testInvoice { return testInvoice{Paid: false} })
+
+		rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/invoice", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusPaymentRequired)
+		AssertEqual(t, rec.Text(), "unpaid /invoice")
+	})
+
+	t.Run("WithError", func(t *testing.T) {
+		fail := false
+
+		handler := Handler(func() (testInvoice, error) {
+			if fail {
+				return testInvoice{}, errors.New("failed")
+			}
+
+			return testInvoice{Paid: true}, nil
+		})
+
+		rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/invoice", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+		AssertEqual(t, rec.Text(), "paid /invoice")
+
+		fail = true
+
+		rec = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/invoice", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusInternalServerError)
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		handler := Handler(func() Responder { return nil })
+
+		rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+
+		desc, _ := Describe(handler)
+		AssertTrue(t, desc.ReturnsHandler)
+	})
+}
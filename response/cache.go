@@ -0,0 +1,97 @@
+package response
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheDirective is a single directive of a Cache-Control header,
+// e.g. "public" or "max-age=60".
+type CacheDirective string
+
+const (
+	Public          CacheDirective = "public"
+	Private         CacheDirective = "private"
+	NoCache         CacheDirective = "no-cache"
+	NoStore         CacheDirective = "no-store"
+	NoTransform     CacheDirective = "no-transform"
+	MustRevalidate  CacheDirective = "must-revalidate"
+	ProxyRevalidate CacheDirective = "proxy-revalidate"
+	Immutable       CacheDirective = "immutable"
+)
+
+// MaxAge returns a "max-age" directive. The duration is truncated to full seconds.
+func MaxAge(d time.Duration) CacheDirective {
+	return durationDirective("max-age", d)
+}
+
+// SharedMaxAge returns a "s-maxage" directive that applies to shared caches only.
+func SharedMaxAge(d time.Duration) CacheDirective {
+	return durationDirective("s-maxage", d)
+}
+
+// StaleWhileRevalidate returns a "stale-while-revalidate" directive, see RFC 5861.
+func StaleWhileRevalidate(d time.Duration) CacheDirective {
+	return durationDirective("stale-while-revalidate", d)
+}
+
+// StaleIfError returns a "stale-if-error" directive, see RFC 5861.
+func StaleIfError(d time.Duration) CacheDirective {
+	return durationDirective("stale-if-error", d)
+}
+
+func durationDirective(name string, d time.Duration) CacheDirective {
+	seconds := max(int64(d/time.Second), 0)
+	return CacheDirective(name + "=" + strconv.FormatInt(seconds, 10))
+}
+
+// CacheControl joins the directives into the value of a Cache-Control header.
+func CacheControl(directives ...CacheDirective) string {
+	var sb strings.Builder
+
+	for idx, directive := range directives {
+		if idx > 0 {
+			sb.WriteString(", ")
+		}
+
+		sb.WriteString(string(directive))
+	}
+
+	return sb.String()
+}
+
+// immutableMaxAge is the max-age used by the CacheImmutable preset.
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// Cache sets the Cache-Control header to the given directives.
+func (r Response) Cache(directives ...CacheDirective) Response {
+	return r.SetHeader("Cache-Control", CacheControl(directives...))
+}
+
+// CacheNoStore forbids any cache to store the response.
+func (r Response) CacheNoStore() Response {
+	return r.Cache(NoStore)
+}
+
+// CacheImmutable marks the response as never changing, e.g. for assets with
+// a content hash in their name. It may be cached by any cache for a year.
+func (r Response) CacheImmutable() Response {
+	return r.Cache(Public, MaxAge(immutableMaxAge), Immutable)
+}
+
+// Cache sets the Cache-Control header to the given directives.
+func (l Lazy) Cache(directives ...CacheDirective) Lazy {
+	return l.SetHeader("Cache-Control", CacheControl(directives...))
+}
+
+// CacheNoStore forbids any cache to store the response.
+func (l Lazy) CacheNoStore() Lazy {
+	return l.Cache(NoStore)
+}
+
+// CacheImmutable marks the response as never changing, e.g. for assets with
+// a content hash in their name. It may be cached by any cache for a year.
+func (l Lazy) CacheImmutable() Lazy {
+	return l.Cache(Public, MaxAge(immutableMaxAge), Immutable)
+}
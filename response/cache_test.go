@@ -0,0 +1,29 @@
+package response
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheControl(t *testing.T) {
+	value := CacheControl(Public, MaxAge(5*time.Minute), StaleWhileRevalidate(90*time.Second))
+	AssertEqual(t, value, "public, max-age=300, stale-while-revalidate=90")
+
+	AssertEqual(t, CacheControl(MaxAge(-time.Second)), "max-age=0")
+}
+
+func TestCache(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON(1).Cache(Private, NoCache).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Header().Get("Cache-Control"), "private, no-cache")
+
+	rec = httptest.NewRecorder()
+	Text("x").CacheNoStore().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Header().Get("Cache-Control"), "no-store")
+
+	rec = httptest.NewRecorder()
+	Text("x").CacheImmutable().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Header().Get("Cache-Control"), "public, max-age=31536000, immutable")
+}
@@ -0,0 +1,26 @@
+package response
+
+import (
+	"io"
+	"maps"
+	"net/http"
+	"time"
+)
+
+// Content serves content using http.ServeContent. It answers Range requests with single
+// ranges as well as multipart/byteranges responses, and evaluates If-Range, If-Match,
+// If-None-Match, If-Modified-Since and If-Unmodified-Since against the ETag header
+// and modTime. name is used to detect the Content-Type from the file extension, if
+// no Content-Type header is set. Use this for custom content sources, e.g. blobs
+// fetched from an object storage.
+//
+// A zero modTime disables Last-Modified handling. The status code is determined
+// by http.ServeContent, a status code set on the Lazy is ignored.
+func Content(content io.ReadSeeker, modTime time.Time, name string) Lazy {
+	return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			maps.Copy(w.Header(), headers)
+			http.ServeContent(w, r, name, modTime, content)
+		})
+	})
+}
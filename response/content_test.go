@@ -0,0 +1,73 @@
+package response
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContent(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	content := func() Lazy {
+		return Content(strings.NewReader("0123456789"), modTime, "blob.bin").
+			SetHeader("ETag", `"v1"`)
+	}
+
+	t.Run("Multipart", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=0-1,8-")
+
+		resp := gumtest.Serve(content(), req)
+		AssertEqual(t, resp.StatusCode, http.StatusPartialContent)
+
+		mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		AssertEqual(t, err, nil)
+		AssertEqual(t, mediaType, "multipart/byteranges")
+
+		reader := multipart.NewReader(strings.NewReader(resp.Text()), params["boundary"])
+
+		part, err := reader.NextPart()
+		AssertEqual(t, err, nil)
+		AssertEqual(t, part.Header.Get("Content-Range"), "bytes 0-1/10")
+
+		part, err = reader.NextPart()
+		AssertEqual(t, err, nil)
+		AssertEqual(t, part.Header.Get("Content-Range"), "bytes 8-9/10")
+	})
+
+	t.Run("IfRangeMatch", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=2-4")
+		req.Header.Set("If-Range", `"v1"`)
+
+		resp := gumtest.Serve(content(), req)
+		AssertEqual(t, resp.StatusCode, http.StatusPartialContent)
+		AssertEqual(t, resp.Text(), "234")
+	})
+
+	t.Run("IfRangeMismatch", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=2-4")
+		req.Header.Set("If-Range", `"v0"`)
+
+		resp := gumtest.Serve(content(), req)
+		AssertEqual(t, resp.StatusCode, http.StatusOK)
+		AssertEqual(t, resp.Text(), "0123456789")
+	})
+
+	t.Run("NotSatisfiable", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=20-")
+
+		resp := gumtest.Serve(content(), req)
+		AssertEqual(t, resp.StatusCode, http.StatusRequestedRangeNotSatisfiable)
+		AssertEqual(t, resp.Header.Get("Content-Range"), "bytes */10")
+	})
+}
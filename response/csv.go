@@ -0,0 +1,111 @@
+package response
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// CSVOption configures the output of CSV.
+type CSVOption func(config *csvConfig)
+
+type csvConfig struct {
+	delimiter rune
+	bom       bool
+}
+
+// CSVDelimiter sets the field delimiter, defaults to a comma.
+func CSVDelimiter(delimiter rune) CSVOption {
+	return func(config *csvConfig) {
+		config.delimiter = delimiter
+	}
+}
+
+// CSVByteOrderMark prefixes the output with a UTF-8 byte order mark.
+// Some spreadsheet applications need it to detect the encoding of the file.
+func CSVByteOrderMark() CSVOption {
+	return func(config *csvConfig) {
+		config.bom = true
+	}
+}
+
+// CSV writes rows as comma separated values using encoding/csv. rows must be a slice
+// or array of structs or pointers to structs. The header row contains the field names
+// as they are used by the serde package, values are formatted using serde.MarshalValues.
+// Fields with multiple values, e.g. slices, are joined using a comma.
+//
+// Rows are written directly to the client. If a row can not be formatted,
+// the response is truncated.
+func CSV(rows any, options ...CSVOption) Lazy {
+	config := csvConfig{delimiter: ','}
+	for _, option := range options {
+		option(&config)
+	}
+
+	return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+		rValue := reflect.ValueOf(rows)
+		if rValue.Kind() != reflect.Slice && rValue.Kind() != reflect.Array {
+			err := fmt.Errorf("csv rows must be a slice, got %T", rows)
+			return Error(err, http.StatusInternalServerError)
+		}
+
+		rowType := rValue.Type().Elem()
+		for rowType.Kind() == reflect.Pointer {
+			rowType = rowType.Elem()
+		}
+
+		if rowType.Kind() != reflect.Struct {
+			err := fmt.Errorf("csv rows must be structs, got %s", rowType)
+			return Error(err, http.StatusInternalServerError)
+		}
+
+		fields := serde.Fields(rowType)
+
+		body := func(w io.Writer) error {
+			if config.bom {
+				if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+					return err
+				}
+			}
+
+			writer := csv.NewWriter(w)
+			writer.Comma = config.delimiter
+
+			record := make([]string, len(fields))
+
+			for idx, field := range fields {
+				record[idx] = field.Name
+			}
+
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+
+			for rowIdx := range rValue.Len() {
+				values, err := serde.MarshalValues(rValue.Index(rowIdx).Interface())
+				if err != nil {
+					return fmt.Errorf("format row %d: %w", rowIdx, err)
+				}
+
+				for idx, field := range fields {
+					record[idx] = strings.Join(values[field.Name], ",")
+				}
+
+				if err := writer.Write(record); err != nil {
+					return err
+				}
+			}
+
+			writer.Flush()
+			return writer.Error()
+		}
+
+		return New(body).
+			UpdateWith(statusCode, headers).
+			SetHeader("Content-Type", "text/csv; charset=utf-8")
+	})
+}
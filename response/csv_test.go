@@ -0,0 +1,39 @@
+package response
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	type Row struct {
+		ID    int      `json:"id"`
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags"`
+		Notes *string  `json:"notes"`
+	}
+
+	rows := []Row{
+		{ID: 1, Name: "Alice", Tags: []string{"a", "b"}},
+		{ID: 2, Name: "Bob; Jr."},
+	}
+
+	rec := httptest.NewRecorder()
+	CSV(rows).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "text/csv; charset=utf-8")
+	AssertEqual(t, rec.Body.String(), "id,name,tags,notes\n1,Alice,\"a,b\",\n2,Bob; Jr.,,\n")
+
+	rec = httptest.NewRecorder()
+	CSV(rows, CSVDelimiter(';'), CSVByteOrderMark()).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Body.String(), "\uFEFFid;name;tags;notes\n1;Alice;a,b;\n2;\"Bob; Jr.\";;\n")
+}
+
+func TestCSVInvalidRows(t *testing.T) {
+	rec := httptest.NewRecorder()
+	CSV([]int{1, 2}).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusInternalServerError)
+}
@@ -0,0 +1,101 @@
+package response
+
+import (
+	"fmt"
+	"github.com/go-gum/gum/internal"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// encoder produces the Lazy response for a value encoded as a specific media type.
+type encoder struct {
+	mediaType string
+	respond   func(value any) Lazy
+}
+
+var encoders = struct {
+	sync.RWMutex
+	entries []encoder
+}{
+	entries: []encoder{
+		{mediaType: "application/json", respond: func(value any) Lazy { return JSON(value) }},
+		{mediaType: "application/xml", respond: func(value any) Lazy { return XML(value) }},
+	},
+}
+
+// RegisterEncoder registers a function that encodes values for the given media type,
+// e.g. "application/msgpack". The media type takes part in the content negotiation of
+// Encoded. Media types are offered in the order of registration, after the default
+// types "application/json" and "application/xml". Registering an existing media type
+// replaces its encoder, keeping its position.
+//
+// The value is encoded into memory before writing the response. If encoding fails,
+// the client receives a 500 Internal Server Error.
+func RegisterEncoder(mediaType string, fn func(value any) ([]byte, error)) {
+	registerEncoder(encoder{
+		mediaType: mediaType,
+		respond: func(value any) Lazy {
+			return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+				encoded, err := fn(value)
+				if err != nil {
+					internal.LoggerOf(req.Context()).WarnContext(req.Context(),
+						"Failed to encode response",
+						slog.String("mediaType", mediaType),
+						slog.String("err", err.Error()),
+					)
+
+					err = fmt.Errorf("encoding %s: %w", mediaType, err)
+					return Error(err, http.StatusInternalServerError)
+				}
+
+				return Raw(encoded).
+					UpdateWith(statusCode, headers).
+					SetHeader("Content-Type", mediaType)
+			})
+		},
+	})
+}
+
+// RegisterStreamEncoder works like RegisterEncoder, but the function encodes the value
+// directly into the http.ResponseWriter. An encoding error can not change the status
+// code anymore and results in a truncated response.
+func RegisterStreamEncoder(mediaType string, fn func(w io.Writer, value any) error) {
+	registerEncoder(encoder{
+		mediaType: mediaType,
+		respond: func(value any) Lazy {
+			return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+				return New(func(w io.Writer) error { return fn(w, value) }).
+					UpdateWith(statusCode, headers).
+					SetHeader("Content-Type", mediaType)
+			})
+		},
+	})
+}
+
+func registerEncoder(enc encoder) {
+	encoders.Lock()
+	defer encoders.Unlock()
+
+	// copy on write, Encoded might still use the previous slice
+	entries := slices.Clone(encoders.entries)
+
+	idx := slices.IndexFunc(entries, func(e encoder) bool { return e.mediaType == enc.mediaType })
+	if idx >= 0 {
+		entries[idx] = enc
+	} else {
+		entries = append(entries, enc)
+	}
+
+	encoders.entries = entries
+}
+
+// registeredEncoders returns a snapshot of the currently registered encoders.
+func registeredEncoders() []encoder {
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	return encoders.entries
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"fmt"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterEncoder(t *testing.T) {
+	RegisterEncoder("text/x-test", func(value any) ([]byte, error) {
+		return []byte(fmt.Sprintf("value=%v", value)), nil
+	})
+
+	RegisterStreamEncoder("text/x-test-stream", func(w io.Writer, value any) error {
+		_, err := fmt.Fprintf(w, "stream=%v", value)
+		return err
+	})
+
+	serve := func(acceptHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", acceptHeader)
+
+		rec := httptest.NewRecorder()
+		Encoded(42).WithStatusCode(201).ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("text/x-test")
+	AssertEqual(t, rec.Code, 201)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "text/x-test")
+	AssertEqual(t, rec.Body.String(), "value=42")
+
+	rec = serve("text/x-test-stream, application/json;q=0.5")
+	AssertEqual(t, rec.Code, 201)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "text/x-test-stream")
+	AssertEqual(t, rec.Body.String(), "stream=42")
+
+	// json is still the default
+	rec = serve("*/*")
+	AssertEqual(t, rec.Header().Get("Content-Type"), "application/json; charset=utf8")
+	AssertEqual(t, rec.Body.String(), "42")
+}
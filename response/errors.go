@@ -0,0 +1,96 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrorEncoder builds the Response for an error. It is used by Error and thereby by all
+// error paths of gum, like failing extractors or responses that can not be encoded.
+type ErrorEncoder func(err error, statusCode int) Response
+
+var errorEncoder atomic.Pointer[ErrorEncoder]
+
+// SetErrorEncoder sets the ErrorEncoder used by Error. Passing nil restores
+// the default encoder TextError.
+func SetErrorEncoder(encoder ErrorEncoder) {
+	errorEncoder.Store(&encoder)
+}
+
+// TextError is the default ErrorEncoder. It writes the error message as plain text.
+func TextError(err error, statusCode int) Response {
+	return Text(err.Error()).
+		WithStatusCode(statusCode)
+}
+
+// Problem is the body of a problem details response as defined in RFC 9457.
+type Problem struct {
+	Type   string `json:"type,omitempty"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ProblemTitler can be implemented by errors to set the title of a problem details
+// response written by ProblemError, e.g. to provide a translated title.
+type ProblemTitler interface {
+	ProblemTitle() string
+}
+
+// ProblemError is an ErrorEncoder that writes the error as "application/problem+json".
+// The title is the text of the status code, the detail contains the error message.
+// If err wraps a ProblemTitler that returns a non-empty title, that title is used instead.
+func ProblemError(err error, statusCode int) Response {
+	problem := Problem{
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: err.Error(),
+	}
+
+	var titler ProblemTitler
+	if errors.As(err, &titler) {
+		if title := titler.ProblemTitle(); title != "" {
+			problem.Title = title
+		}
+	}
+
+	encoded, encErr := DefaultJSONEncoder().Marshal(problem)
+	if encErr != nil {
+		// can not happen with a sane encoder, fall back to plain text
+		return TextError(err, statusCode)
+	}
+
+	return Raw(encoded).
+		WithStatusCode(statusCode).
+		SetHeader("Content-Type", "application/problem+json")
+}
+
+// Redacted wraps an ErrorEncoder and hides the message of server errors (5xx), which
+// might contain internal details. The message is replaced by the text of the status code.
+// Client errors are passed through unchanged.
+func Redacted(encoder ErrorEncoder) ErrorEncoder {
+	return func(err error, statusCode int) Response {
+		if statusCode >= 500 {
+			err = redactedError{statusCode: statusCode}
+		}
+
+		return encoder(err, statusCode)
+	}
+}
+
+type redactedError struct {
+	statusCode int
+}
+
+func (e redactedError) Error() string {
+	return http.StatusText(e.statusCode)
+}
+
+func currentErrorEncoder() ErrorEncoder {
+	if encoder := errorEncoder.Load(); encoder != nil && *encoder != nil {
+		return *encoder
+	}
+
+	return TextError
+}
@@ -0,0 +1,55 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemError(t *testing.T) {
+	resp := gumtest.Serve(ProblemError(errors.New("missing field name"), http.StatusBadRequest), httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+	AssertEqual(t, resp.Header.Get("Content-Type"), "application/problem+json")
+
+	problem := gumtest.DecodeJSON[Problem](t, resp)
+	AssertEqual(t, problem, Problem{Title: "Bad Request", Status: 400, Detail: "missing field name"})
+}
+
+type titledError struct{}
+
+func (titledError) Error() string        { return "user not found" }
+func (titledError) ProblemTitle() string { return "Unknown User" }
+
+func TestProblemErrorTitle(t *testing.T) {
+	err := fmt.Errorf("extract parameter 0: %w", titledError{})
+	resp := gumtest.Serve(ProblemError(err, http.StatusNotFound), httptest.NewRequest("GET", "/", nil))
+
+	problem := gumtest.DecodeJSON[Problem](t, resp)
+	AssertEqual(t, problem, Problem{Title: "Unknown User", Status: 404, Detail: "extract parameter 0: user not found"})
+}
+
+func TestRedacted(t *testing.T) {
+	encoder := Redacted(TextError)
+
+	resp := gumtest.Serve(encoder(errors.New("dial tcp 10.0.0.1: refused"), 502), httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, resp.StatusCode, 502)
+	AssertEqual(t, resp.Text(), "Bad Gateway")
+
+	resp = gumtest.Serve(encoder(errors.New("invalid id"), 400), httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, resp.Text(), "invalid id")
+}
+
+func TestSetErrorEncoder(t *testing.T) {
+	SetErrorEncoder(ProblemError)
+	defer SetErrorEncoder(nil)
+
+	// internal error paths use the encoder too
+	resp := gumtest.Serve(JSON(func() {}), httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusInternalServerError)
+	AssertEqual(t, resp.Header.Get("Content-Type"), "application/problem+json")
+}
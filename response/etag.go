@@ -0,0 +1,72 @@
+package response
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/go-gum/gum/internal"
+	"io"
+	"net/http"
+)
+
+// WithETag sets a strong ETag for the response. If the If-None-Match header of the
+// request matches the ETag, ServeHTTP responds with 304 Not Modified and no body.
+func (r Response) WithETag(tag string) Response {
+	return r.SetHeader("ETag", `"`+tag+`"`)
+}
+
+// WithWeakETag sets a weak ETag for the response. See WithETag.
+func (r Response) WithWeakETag(tag string) Response {
+	return r.SetHeader("ETag", `W/"`+tag+`"`)
+}
+
+// WithAutoETag computes a strong ETag from the body of the response. The body is
+// buffered in memory before it is written, so this should not be used for large or
+// streaming responses. An explicitly set ETag is not replaced.
+func (r Response) WithAutoETag() Response {
+	r.autoETag = true
+	return r
+}
+
+// WithAutoETag calls Response.WithAutoETag on the Response produced by this Lazy.
+func (l Lazy) WithAutoETag() Lazy {
+	return l.mapResponse(Response.WithAutoETag)
+}
+
+// withComputedETag buffers the body and sets the ETag header to a hash of it.
+func (r Response) withComputedETag() (Response, error) {
+	var buf bytes.Buffer
+	if err := r.body(&buf); err != nil {
+		return r, err
+	}
+
+	content := buf.Bytes()
+
+	r.body = func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	}
+
+	if r.header.Get("ETag") == "" {
+		// do not modify the header of a Response that is served again with a different body
+		r.header = r.header.Clone()
+
+		hash := sha256.Sum256(content)
+		r.header.Set("ETag", `"`+base64.RawURLEncoding.EncodeToString(hash[:16])+`"`)
+	}
+
+	return r, nil
+}
+
+// notModified checks if the response should be replaced by 304 Not Modified.
+func (r Response) notModified(request *http.Request) bool {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		return false
+	}
+
+	if r.statusCode != 0 && r.statusCode != http.StatusOK {
+		return false
+	}
+
+	return internal.NoneMatch(request.Header.Get("If-None-Match"), r.header.Get("ETag"))
+}
@@ -0,0 +1,96 @@
+package response
+
+import (
+	"fmt"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestETag(t *testing.T) {
+	t.Run("NotModified", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("If-None-Match", `W/"other", "v1"`)
+
+		rec := httptest.NewRecorder()
+		Text("hello").WithETag("v1").ServeHTTP(rec, req)
+
+		AssertEqual(t, rec.Code, http.StatusNotModified)
+		AssertEqual(t, rec.Header().Get("ETag"), `"v1"`)
+		AssertEqual(t, rec.Header().Get("Content-Type"), "")
+		AssertEqual(t, rec.Body.Len(), 0)
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("If-None-Match", `"v0"`)
+
+		rec := httptest.NewRecorder()
+		Text("hello").WithETag("v1").ServeHTTP(rec, req)
+
+		AssertEqual(t, rec.Code, http.StatusOK)
+		AssertEqual(t, rec.Body.String(), "hello")
+	})
+
+	t.Run("QuotedWildcard", func(t *testing.T) {
+		// only an unquoted * is the wildcard
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("If-None-Match", `"*"`)
+
+		rec := httptest.NewRecorder()
+		Text("hello").WithETag("v1").ServeHTTP(rec, req)
+		AssertEqual(t, rec.Code, http.StatusOK)
+
+		req.Header.Set("If-None-Match", `*`)
+
+		rec = httptest.NewRecorder()
+		Text("hello").WithETag("v1").ServeHTTP(rec, req)
+		AssertEqual(t, rec.Code, http.StatusNotModified)
+	})
+
+	t.Run("IgnoreUnsafeMethods", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set("If-None-Match", "*")
+
+		rec := httptest.NewRecorder()
+		Text("hello").WithETag("v1").ServeHTTP(rec, req)
+
+		AssertEqual(t, rec.Code, http.StatusOK)
+	})
+
+	t.Run("Auto", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		JSON(map[string]int{"a": 1}).WithAutoETag().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		etag := rec.Header().Get("ETag")
+		AssertEqual(t, rec.Code, http.StatusOK)
+		AssertTrue(t, etag != "")
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("If-None-Match", etag)
+
+		rec = httptest.NewRecorder()
+		JSON(map[string]int{"a": 1}).WithAutoETag().ServeHTTP(rec, req)
+		AssertEqual(t, rec.Code, http.StatusNotModified)
+	})
+	t.Run("AutoReused", func(t *testing.T) {
+		var counter int
+
+		resp := New(func(w io.Writer) error {
+			counter += 1
+			_, err := fmt.Fprintf(w, "call %d", counter)
+			return err
+		}).WithAutoETag()
+
+		first := httptest.NewRecorder()
+		resp.ServeHTTP(first, httptest.NewRequest("GET", "/", nil))
+
+		second := httptest.NewRecorder()
+		resp.ServeHTTP(second, httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, second.Body.String(), "call 2")
+		AssertTrue(t, first.Header().Get("ETag") != second.Header().Get("ETag"))
+	})
+}
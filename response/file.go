@@ -0,0 +1,137 @@
+package response
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// File serves the file at the given path of the local filesystem using http.ServeContent.
+// Range requests, conditional requests using If-Modified-Since and the detection of the
+// Content-Type are handled by http.ServeContent. If the path points to a directory, the
+// index.html file within that directory is served.
+func File(name string) Lazy {
+	open := func(name string) (fs.File, error) {
+		return os.Open(name)
+	}
+
+	return serveFile(open, filepath.Join, name)
+}
+
+// FS serves the file with the given name from fsys. See File.
+func FS(fsys fs.FS, name string) Lazy {
+	return serveFile(fsys.Open, path.Join, name)
+}
+
+func serveFile(open func(name string) (fs.File, error), join func(elem ...string) string, name string) Lazy {
+	return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			file, stat, err := openWithIndex(open, join, name)
+			if err != nil {
+				fileError(err).ServeHTTP(w, r)
+				return
+			}
+
+			defer func() { _ = file.Close() }()
+
+			content, ok := file.(io.ReadSeeker)
+			if !ok {
+				// http.ServeContent needs to seek, fall back to reading the file into memory
+				buf, err := io.ReadAll(file)
+				if err != nil {
+					fileError(err).ServeHTTP(w, r)
+					return
+				}
+
+				content = bytes.NewReader(buf)
+			}
+
+			Content(content, stat.ModTime(), stat.Name()).UpdateWith(statusCode, headers).ServeHTTP(w, r)
+		})
+	})
+}
+
+// openWithIndex opens the file with the given name. If it is a directory, its index.html
+// file is opened instead. The index is only resolved once: if index.html is a directory
+// itself, fs.ErrNotExist is returned.
+func openWithIndex(open func(name string) (fs.File, error), join func(elem ...string) string, name string) (fs.File, fs.FileInfo, error) {
+	file, stat, err := openStat(open, name)
+	if err != nil || !stat.IsDir() {
+		return file, stat, err
+	}
+
+	_ = file.Close()
+
+	file, stat, err = openStat(open, join(name, "index.html"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if stat.IsDir() {
+		_ = file.Close()
+		return nil, nil, fs.ErrNotExist
+	}
+
+	return file, stat, nil
+}
+
+func openStat(open func(name string) (fs.File, error), name string) (fs.File, fs.FileInfo, error) {
+	file, err := open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	return file, stat, nil
+}
+
+// fileError maps errors of opening a file to a Response
+// with a matching status code, like http.FileServer does.
+func fileError(err error) Response {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return Error(errors.New("404 page not found"), http.StatusNotFound)
+
+	case errors.Is(err, fs.ErrPermission):
+		return Error(errors.New("403 Forbidden"), http.StatusForbidden)
+
+	default:
+		return Error(fmt.Errorf("open file: %w", err), http.StatusInternalServerError)
+	}
+}
+
+// contentDisposition formats a Content-Disposition header that
+// asks the client to download the response as a file with the given name.
+func contentDisposition(filename string) string {
+	value := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	if value == "" {
+		// filename contains characters that can not be encoded
+		return "attachment"
+	}
+
+	return value
+}
+
+// Attachment sets the Content-Disposition header, so that clients
+// download the response as a file with the given name.
+func (r Response) Attachment(filename string) Response {
+	return r.SetHeader("Content-Disposition", contentDisposition(filename))
+}
+
+// Attachment sets the Content-Disposition header, so that clients
+// download the response as a file with the given name.
+func (l Lazy) Attachment(filename string) Lazy {
+	return l.SetHeader("Content-Disposition", contentDisposition(filename))
+}
@@ -0,0 +1,91 @@
+package response
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	AssertEqual(t, os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello world"), 0o644), nil)
+	AssertEqual(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<p>index</p>"), 0o644), nil)
+
+	t.Run("Content", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		File(filepath.Join(dir, "hello.txt")).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, rec.Code, http.StatusOK)
+		AssertEqual(t, rec.Header().Get("Content-Type"), "text/plain; charset=utf-8")
+		AssertEqual(t, rec.Body.String(), "hello world")
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=6-")
+
+		rec := httptest.NewRecorder()
+		File(filepath.Join(dir, "hello.txt")).ServeHTTP(rec, req)
+
+		AssertEqual(t, rec.Code, http.StatusPartialContent)
+		AssertEqual(t, rec.Body.String(), "world")
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		File(dir).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, rec.Code, http.StatusOK)
+		AssertEqual(t, rec.Body.String(), "<p>index</p>")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		File(filepath.Join(dir, "missing")).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		AssertEqual(t, rec.Code, http.StatusNotFound)
+	})
+}
+
+func TestFS(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	fsys := fstest.MapFS{
+		"data.json": &fstest.MapFile{Data: []byte(`{}`), ModTime: modTime},
+	}
+
+	rec := httptest.NewRecorder()
+	FS(fsys, "data.json").Attachment("export.json").ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "application/json")
+	AssertEqual(t, rec.Header().Get("Content-Disposition"), `attachment; filename=export.json`)
+	AssertEqual(t, rec.Body.String(), "{}")
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("If-Modified-Since", modTime.Format(http.TimeFormat))
+
+	rec = httptest.NewRecorder()
+	FS(fsys, "data.json").ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusNotModified)
+}
+
+func TestFSNestedIndex(t *testing.T) {
+	// index.html being a directory must not be followed again
+	fsys := fstest.MapFS{
+		"site/index.html/index.html/index.html": &fstest.MapFile{Data: []byte("deep")},
+	}
+
+	rec := httptest.NewRecorder()
+	FS(fsys, "site").ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusNotFound)
+
+	rec = httptest.NewRecorder()
+	FS(fsys, "site/index.html/index.html").ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, rec.Body.String(), "deep")
+}
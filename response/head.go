@@ -0,0 +1,42 @@
+package response
+
+import (
+	"github.com/go-gum/gum/internal"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// serveHead writes the headers of the response, including the Content-Length
+// of the body. To compute the length, the body is written into a writer
+// that only counts the bytes.
+func (r Response) serveHead(writer http.ResponseWriter, request *http.Request) {
+	header := writer.Header()
+
+	if header.Get("Content-Length") == "" {
+		var counter countingWriter
+		if err := r.body(&counter); err != nil {
+			internal.LoggerOf(request.Context()).WarnContext(request.Context(),
+				"computing body length",
+				slog.String("err", err.Error()),
+			)
+		} else {
+			header.Set("Content-Length", strconv.FormatInt(int64(counter), 10))
+		}
+	}
+
+	statusCode := r.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	writer.WriteHeader(statusCode)
+}
+
+// countingWriter discards everything written to it, counting the bytes.
+type countingWriter int64
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	*c += countingWriter(len(p))
+	return len(p), nil
+}
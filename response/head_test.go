@@ -0,0 +1,18 @@
+package response
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHead(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON(map[string]int{"a": 1}).WithStatusCode(http.StatusCreated).ServeHTTP(rec, httptest.NewRequest("HEAD", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusCreated)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "application/json; charset=utf8")
+	AssertEqual(t, rec.Header().Get("Content-Length"), "7")
+	AssertEqual(t, rec.Body.Len(), 0)
+}
@@ -0,0 +1,141 @@
+package response
+
+import (
+	"context"
+	"net/http"
+	"slices"
+)
+
+// Interceptor observes or modifies responses written by Response and Lazy.
+// Interceptors are installed for a request using the Intercept middleware.
+type Interceptor struct {
+	// Before is called before the status code and headers are written. It may modify
+	// the header, e.g. to enforce a header policy, and returns the status code to write.
+	Before func(req *http.Request, statusCode int, header http.Header) int
+
+	// After is called after the response was written completely,
+	// with the final status code and the number of body bytes written.
+	After func(req *http.Request, statusCode int, header http.Header, bytesWritten int64)
+}
+
+// OnWrite returns an Interceptor that calls fn after a response was written,
+// e.g. to write an audit log or to count bytes.
+func OnWrite(fn func(statusCode int, header http.Header, bytesWritten int64)) Interceptor {
+	return Interceptor{
+		After: func(req *http.Request, statusCode int, header http.Header, bytesWritten int64) {
+			fn(statusCode, header, bytesWritten)
+		},
+	}
+}
+
+// BeforeWrite returns an Interceptor that calls fn before the status code and headers
+// of a response are written. fn may modify the header and returns the status code to write.
+func BeforeWrite(fn func(statusCode int, header http.Header) int) Interceptor {
+	return Interceptor{
+		Before: func(req *http.Request, statusCode int, header http.Header) int {
+			return fn(statusCode, header)
+		},
+	}
+}
+
+type interceptorsKey struct{}
+
+// Intercept returns a middleware that applies the interceptors to all responses of this
+// package written while handling a request. Interceptors of nested Intercept middlewares
+// are called after the ones of the outer middlewares.
+func Intercept(interceptors ...Interceptor) func(delegate http.Handler) http.Handler {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			existing, _ := r.Context().Value(interceptorsKey{}).([]Interceptor)
+
+			ctx := context.WithValue(r.Context(), interceptorsKey{}, slices.Concat(existing, interceptors))
+			delegate.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// intercept wraps the writer if interceptors are installed for the request.
+// The returned function must be called after the response was written.
+func intercept(writer http.ResponseWriter, request *http.Request) (http.ResponseWriter, func()) {
+	if _, ok := writer.(*interceptWriter); ok {
+		// already intercepted by an outer Response or Lazy
+		return writer, func() {}
+	}
+
+	interceptors, _ := request.Context().Value(interceptorsKey{}).([]Interceptor)
+	if len(interceptors) == 0 {
+		return writer, func() {}
+	}
+
+	iw := &interceptWriter{
+		ResponseWriter: writer,
+		request:        request,
+		interceptors:   interceptors,
+	}
+
+	return iw, iw.finish
+}
+
+type interceptWriter struct {
+	http.ResponseWriter
+
+	request      *http.Request
+	interceptors []Interceptor
+
+	statusCode   int
+	bytesWritten int64
+}
+
+func (w *interceptWriter) WriteHeader(statusCode int) {
+	if statusCode < 200 || w.statusCode != 0 {
+		// informational or superfluous calls are passed through
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	for _, interceptor := range w.interceptors {
+		if interceptor.Before != nil {
+			statusCode = interceptor.Before(w.request, statusCode, w.Header())
+		}
+	}
+
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *interceptWriter) Write(p []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(p)
+	w.bytesWritten += int64(n)
+	return n, err
+}
+
+// Flush flushes the underlying writer if it supports flushing.
+func (w *interceptWriter) Flush() {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController
+func (w *interceptWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *interceptWriter) finish() {
+	if w.statusCode == 0 {
+		// nothing was written, net/http would send 200 OK
+		w.WriteHeader(http.StatusOK)
+	}
+
+	for _, interceptor := range w.interceptors {
+		if interceptor.After != nil {
+			interceptor.After(w.request, w.statusCode, w.Header(), w.bytesWritten)
+		}
+	}
+}
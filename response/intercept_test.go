@@ -0,0 +1,79 @@
+package response
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestIntercept(t *testing.T) {
+	var calls int
+	var status int
+	var bytes int64
+
+	observe := OnWrite(func(statusCode int, header http.Header, bytesWritten int64) {
+		calls++
+		status, bytes = statusCode, bytesWritten
+	})
+
+	policy := BeforeWrite(func(statusCode int, header http.Header) int {
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Del("Server")
+		return statusCode
+	})
+
+	middleware := Intercept(policy, observe)
+
+	t.Run("Response", func(t *testing.T) {
+		calls = 0
+
+		rec := httptest.NewRecorder()
+		middleware(Text("hello").SetHeader("Server", "gum")).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, calls, 1)
+		AssertEqual(t, status, http.StatusOK)
+		AssertEqual(t, bytes, int64(5))
+		AssertEqual(t, rec.Header().Get("X-Content-Type-Options"), "nosniff")
+		AssertEqual(t, rec.Header().Get("Server"), "")
+	})
+
+	t.Run("Lazy", func(t *testing.T) {
+		calls = 0
+
+		fsys := fstest.MapFS{"a.txt": &fstest.MapFile{Data: []byte("abc")}}
+
+		rec := httptest.NewRecorder()
+		middleware(FS(fsys, "a.txt")).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, calls, 1)
+		AssertEqual(t, bytes, int64(3))
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		calls = 0
+
+		// the error response is written by the json response
+		rec := httptest.NewRecorder()
+		middleware(JSON(func() {})).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, calls, 1)
+		AssertEqual(t, status, http.StatusInternalServerError)
+	})
+
+	t.Run("ChangeStatus", func(t *testing.T) {
+		hide := BeforeWrite(func(statusCode int, header http.Header) int {
+			if statusCode == http.StatusForbidden {
+				return http.StatusNotFound
+			}
+
+			return statusCode
+		})
+
+		rec := httptest.NewRecorder()
+		Intercept(hide)(Error(errors.New("forbidden"), http.StatusForbidden)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		AssertEqual(t, rec.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,95 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+)
+
+// JSONEncoder encodes values as json. Implement this interface to use a different json
+// library like jsoniter or sonic, or to customize encoding beyond what StdJSON offers,
+// e.g. a custom time format.
+type JSONEncoder interface {
+	// Marshal returns the json encoding of value.
+	Marshal(value any) ([]byte, error)
+
+	// Encode writes the json encoding of value to w.
+	Encode(w io.Writer, value any) error
+}
+
+// StdJSON is a JSONEncoder using the encoding/json package.
+// The zero value behaves like json.Marshal.
+type StdJSON struct {
+	// Prefix and Indent are passed to json.Encoder.SetIndent
+	Prefix string
+	Indent string
+
+	// DisableHTMLEscape disables escaping of <, > and & in json strings.
+	DisableHTMLEscape bool
+}
+
+func (s StdJSON) Marshal(value any) ([]byte, error) {
+	if s == (StdJSON{}) {
+		return json.Marshal(value)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Encode(&buf, value); err != nil {
+		return nil, err
+	}
+
+	// json.Encoder terminates each value with a newline, json.Marshal does not
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (s StdJSON) Encode(w io.Writer, value any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent(s.Prefix, s.Indent)
+	enc.SetEscapeHTML(!s.DisableHTMLEscape)
+	return enc.Encode(value)
+}
+
+var defaultJSONEncoder atomic.Pointer[JSONEncoder]
+
+// SetDefaultJSONEncoder sets the JSONEncoder used by all json responses
+// that do not specify an encoder using JSONWith.
+func SetDefaultJSONEncoder(encoder JSONEncoder) {
+	defaultJSONEncoder.Store(&encoder)
+}
+
+// DefaultJSONEncoder returns the JSONEncoder set by SetDefaultJSONEncoder,
+// and StdJSON if no encoder was set.
+func DefaultJSONEncoder() JSONEncoder {
+	if encoder := defaultJSONEncoder.Load(); encoder != nil && *encoder != nil {
+		return *encoder
+	}
+
+	return StdJSON{}
+}
+
+// JSONOption configures the encoding of a JSON response.
+type JSONOption func(config *jsonConfig)
+
+type jsonConfig struct {
+	streaming bool
+	encoder   JSONEncoder
+}
+
+// JSONStreaming encodes the value directly to the http.ResponseWriter using a json.Encoder,
+// without buffering the full document in memory. As the status code and headers are sent
+// before encoding starts, an encoding error can not be reported to the client anymore and
+// results in a truncated response. Do not use this mode if the response must not be
+// committed before the value was encoded successfully.
+func JSONStreaming() JSONOption {
+	return func(config *jsonConfig) {
+		config.streaming = true
+	}
+}
+
+// JSONWith encodes the response using the given JSONEncoder instead of the default one.
+func JSONWith(encoder JSONEncoder) JSONOption {
+	return func(config *jsonConfig) {
+		config.encoder = encoder
+	}
+}
@@ -0,0 +1,61 @@
+package response
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON(map[string]int{"a": 1}).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "application/json; charset=utf8")
+	AssertEqual(t, rec.Body.String(), `{"a":1}`)
+}
+
+func TestJSONEncodingError(t *testing.T) {
+	t.Run("Buffered", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		JSON(func() {}).WithStatusCode(http.StatusCreated).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		AssertEqual(t, rec.Code, http.StatusInternalServerError)
+	})
+
+	t.Run("Streaming", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		JSON(func() {}, JSONStreaming()).WithStatusCode(http.StatusCreated).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		AssertEqual(t, rec.Code, http.StatusCreated)
+	})
+}
+
+func TestJSONStreaming(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON([]int{1, 2, 3}, JSONStreaming()).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "application/json; charset=utf8")
+	AssertEqual(t, rec.Body.String(), "[1,2,3]\n")
+}
+
+func TestJSONWith(t *testing.T) {
+	value := map[string]string{"a": "<b>"}
+
+	rec := httptest.NewRecorder()
+	JSON(value, JSONWith(StdJSON{Indent: "  ", DisableHTMLEscape: true})).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Body.String(), "{\n  \"a\": \"<b>\"\n}")
+
+	rec = httptest.NewRecorder()
+	JSON(value, JSONWith(StdJSON{DisableHTMLEscape: true}), JSONStreaming()).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Body.String(), "{\"a\":\"<b>\"}\n")
+}
+
+func TestSetDefaultJSONEncoder(t *testing.T) {
+	SetDefaultJSONEncoder(StdJSON{Indent: "\t"})
+	defer SetDefaultJSONEncoder(nil)
+
+	rec := httptest.NewRecorder()
+	JSON([]int{1}).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Body.String(), "[\n\t1\n]")
+}
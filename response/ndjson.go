@@ -0,0 +1,134 @@
+package response
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ndjsonFlushInterval is the maximum time encoded values stay buffered
+// before they are flushed to the client.
+const ndjsonFlushInterval = 250 * time.Millisecond
+
+// NDJSON streams the values of seq as newline delimited json, one document per line.
+// The output is buffered and flushed to the client periodically, so the full payload
+// is never held in memory. Buffered values are flushed after at most 250ms, even if
+// seq blocks while producing the next value. As the headers are sent before the first
+// value is encoded, an encoding error can only abort the stream, it can not change
+// the status code.
+func NDJSON[T any](seq iter.Seq[T]) Response {
+	body := func(w io.Writer) error {
+		stream := &ndjsonStream{w: w, buffered: bufio.NewWriter(w)}
+		defer stream.close()
+
+		encoder := DefaultJSONEncoder()
+
+		var idx int
+		for value := range seq {
+			err := stream.write(func(buffered io.Writer) error {
+				return encoder.Encode(buffered, value)
+			})
+
+			if err != nil {
+				return fmt.Errorf("encode value idx=%d: %w", idx, err)
+			}
+
+			idx++
+		}
+
+		return stream.close()
+	}
+
+	return New(body).
+		SetHeader("Content-Type", "application/x-ndjson")
+}
+
+// ndjsonStream buffers the encoded values and flushes them using a timer,
+// which fires even while the sequence is waiting for its next value.
+type ndjsonStream struct {
+	mu       sync.Mutex
+	w        io.Writer
+	buffered *bufio.Writer
+	timer    *time.Timer
+	closed   bool
+
+	// err holds the error of a flush triggered by the timer
+	err error
+}
+
+// write calls encode with the buffer and schedules a flush.
+func (s *ndjsonStream) write(encode func(buffered io.Writer) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	if err := encode(s.buffered); err != nil {
+		return err
+	}
+
+	if s.timer == nil {
+		s.timer = time.AfterFunc(ndjsonFlushInterval, s.flushScheduled)
+	}
+
+	return nil
+}
+
+func (s *ndjsonStream) flushScheduled() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timer = nil
+
+	if !s.closed && s.err == nil {
+		s.err = flushNDJSON(s.w, s.buffered)
+	}
+}
+
+// close writes the remaining buffered data. The stream must not be written to
+// after the body function returned, so close also stops a scheduled flush.
+func (s *ndjsonStream) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+
+	return flushNDJSON(s.w, s.buffered)
+}
+
+// flushNDJSON writes the buffered data and flushes the underlying
+// http.ResponseWriter, if supported.
+func flushNDJSON(w io.Writer, buffered *bufio.Writer) error {
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	if rw, ok := w.(http.ResponseWriter); ok {
+		err := http.NewResponseController(rw).Flush()
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package response
+
+import (
+	"bufio"
+	. "github.com/go-gum/gum/internal/test"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNDJSON(t *testing.T) {
+	type Item struct {
+		ID int `json:"id"`
+	}
+
+	items := []Item{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	rec := httptest.NewRecorder()
+	NDJSON(slices.Values(items)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, 200)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "application/x-ndjson")
+	AssertEqual(t, rec.Body.String(), "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n")
+	AssertTrue(t, rec.Flushed)
+}
+
+func TestNDJSONEncodingError(t *testing.T) {
+	values := []any{1, func() {}, 3}
+
+	rec := httptest.NewRecorder()
+	NDJSON(slices.Values(values)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	// the stream stops at the first value that can not be encoded
+	AssertEqual(t, rec.Body.String(), "1\n")
+}
+
+func TestNDJSONFlushesWhileBlocked(t *testing.T) {
+	received := make(chan struct{})
+
+	seq := func(yield func(int) bool) {
+		if !yield(1) {
+			return
+		}
+
+		// the next value takes a while, the client must receive the first one anyway
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+		}
+
+		yield(2)
+	}
+
+	server := httptest.NewServer(NDJSON(iter.Seq[int](seq)))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	resp, err := client.Get(server.URL)
+	AssertEqual(t, err, nil)
+
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+
+	line, err := reader.ReadString('\n')
+	AssertEqual(t, err, nil)
+	AssertEqual(t, line, "1\n")
+
+	close(received)
+
+	line, err = reader.ReadString('\n')
+	AssertEqual(t, err, nil)
+	AssertEqual(t, line, "2\n")
+}
@@ -0,0 +1,59 @@
+package response
+
+import (
+	"maps"
+	"net/http"
+)
+
+// Redirect responds with the given redirect status code and sets the Location header
+// to url. Like http.Redirect, a relative url is resolved against the path of the
+// current request. The response has no body, use RedirectHTML to include a link to
+// the target for clients that do not follow redirects automatically.
+func Redirect(url string, code int) Lazy {
+	return redirect(url, code, false)
+}
+
+// RedirectHTML works like Redirect, but renders a small html body with a link to the
+// target url for GET and HEAD requests, unless a Content-Type header is set explicitly.
+func RedirectHTML(url string, code int) Lazy {
+	return redirect(url, code, true)
+}
+
+// SeeOther redirects to url using 303 See Other. This is the status to use
+// after handling a POST request, the client will follow up with a GET request.
+func SeeOther(url string) Lazy {
+	return Redirect(url, http.StatusSeeOther)
+}
+
+// TemporaryRedirect redirects to url using 307 Temporary Redirect,
+// which keeps the request method and body.
+func TemporaryRedirect(url string) Lazy {
+	return Redirect(url, http.StatusTemporaryRedirect)
+}
+
+// PermanentRedirect redirects to url using 308 Permanent Redirect,
+// which keeps the request method and body.
+func PermanentRedirect(url string) Lazy {
+	return Redirect(url, http.StatusPermanentRedirect)
+}
+
+func redirect(url string, code int, withBody bool) Lazy {
+	return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+		if statusCode <= 0 {
+			statusCode = code
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			maps.Copy(header, headers)
+
+			if !withBody && header["Content-Type"] == nil {
+				// http.Redirect skips the body if the Content-Type key is present,
+				// a nil value prevents the header from being sent at all.
+				header["Content-Type"] = nil
+			}
+
+			http.Redirect(w, r, url, statusCode)
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package response
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	t.Run("Absolute", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SeeOther("/orders/1").ServeHTTP(rec, httptest.NewRequest("POST", "/orders", nil))
+
+		AssertEqual(t, rec.Code, http.StatusSeeOther)
+		AssertEqual(t, rec.Header().Get("Location"), "/orders/1")
+		AssertEqual(t, rec.Header().Get("Content-Type"), "")
+		AssertEqual(t, rec.Body.Len(), 0)
+	})
+
+	t.Run("Relative", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		PermanentRedirect("../other").ServeHTTP(rec, httptest.NewRequest("GET", "/a/b/c", nil))
+
+		AssertEqual(t, rec.Code, http.StatusPermanentRedirect)
+		AssertEqual(t, rec.Header().Get("Location"), "/a/other")
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		RedirectHTML("/target", http.StatusFound).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, rec.Code, http.StatusFound)
+		AssertEqual(t, rec.Header().Get("Content-Type"), "text/html; charset=utf-8")
+		AssertTrue(t, strings.Contains(rec.Body.String(), `href="/target"`))
+	})
+}
@@ -1,9 +1,9 @@
 package response
 
 import (
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"github.com/go-gum/gum/internal"
 	"github.com/timewasted/go-accept-headers"
 	"io"
 	"log/slog"
@@ -34,9 +34,10 @@ func HTML(content string) Response {
 		SetHeader("Content-Type", "text/html; charset=utf8")
 }
 
+// Error builds the Response for an error using the ErrorEncoder
+// set by SetErrorEncoder, or TextError by default.
 func Error(err error, statusCode int) Response {
-	return Text(err.Error()).
-		WithStatusCode(statusCode)
+	return currentErrorEncoder()(err, statusCode)
 }
 
 func Reader(r io.Reader) Response {
@@ -55,13 +56,31 @@ func ReadCloser(r io.ReadCloser) Response {
 	})
 }
 
-// JSON prepares a Response handler that encodes the provided value using json.Encoder and
-// and sets the content type header to "application/json"
-func JSON(value any) Lazy {
+// JSON prepares a Response handler that encodes the provided value using the DefaultJSONEncoder
+// and sets the content type header to "application/json". The value is encoded into memory
+// first, so an encoding error still results in a 500 Internal Server Error. Pass
+// JSONStreaming to encode directly to the http.ResponseWriter instead.
+func JSON(value any, options ...JSONOption) Lazy {
+	var config jsonConfig
+	for _, option := range options {
+		option(&config)
+	}
+
 	return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
-		encoded, err := json.Marshal(value)
+		encoder := config.encoder
+		if encoder == nil {
+			encoder = DefaultJSONEncoder()
+		}
+
+		if config.streaming {
+			return New(func(w io.Writer) error { return encoder.Encode(w, value) }).
+				UpdateWith(statusCode, headers).
+				SetHeader("Content-Type", "application/json; charset=utf8")
+		}
+
+		encoded, err := encoder.Marshal(value)
 		if err != nil {
-			slog.WarnContext(req.Context(),
+			internal.LoggerOf(req.Context()).WarnContext(req.Context(),
 				"Failed to write json response",
 				slog.String("err", err.Error()),
 			)
@@ -72,7 +91,7 @@ func JSON(value any) Lazy {
 
 		return Raw(encoded).
 			UpdateWith(statusCode, headers).
-			SetHeader("Content-Type", "application/xml; charset=utf8")
+			SetHeader("Content-Type", "application/json; charset=utf8")
 	})
 }
 
@@ -82,7 +101,7 @@ func XML(value any) Lazy {
 	return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
 		encoded, err := xml.Marshal(value)
 		if err != nil {
-			slog.WarnContext(req.Context(),
+			internal.LoggerOf(req.Context()).WarnContext(req.Context(),
 				"Failed to write xml response",
 				slog.String("err", err.Error()),
 			)
@@ -98,15 +117,23 @@ func XML(value any) Lazy {
 }
 
 // Encoded prepares a Lazy handler that encodes the provided value according to the
-// http.Request Accept header
+// http.Request Accept header. Besides JSON and XML, all media types registered
+// using RegisterEncoder or RegisterStreamEncoder are offered to the client.
 func Encoded(value any) Lazy {
 	return LazyNew(func(statusCode int, header http.Header, req *http.Request) http.Handler {
 		acceptSlice := accept.Parse(req.Header.Get("Accept"))
 
+		entries := registeredEncoders()
+
+		offers := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			offers = append(offers, entry.mediaType)
+		}
+
 		// decide on the content type
-		ctype, err := acceptSlice.Negotiate("application/json", "application/xml")
+		ctype, err := acceptSlice.Negotiate(offers...)
 		if err != nil {
-			slog.WarnContext(
+			internal.LoggerOf(req.Context()).WarnContext(
 				req.Context(),
 				"negotiate content type",
 				slog.String("err", err.Error()),
@@ -115,12 +142,14 @@ func Encoded(value any) Lazy {
 			return Error(err, http.StatusBadRequest)
 		}
 
-		switch ctype {
-		case "application/xml":
-			return XML(value).UpdateWith(statusCode, header)
-		default:
-			return JSON(value).UpdateWith(statusCode, header)
+		for _, entry := range entries {
+			if entry.mediaType == ctype {
+				return entry.respond(value).UpdateWith(statusCode, header)
+			}
 		}
+
+		// nothing acceptable, default to the first encoder
+		return entries[0].respond(value).UpdateWith(statusCode, header)
 	})
 }
 
@@ -130,6 +159,8 @@ type Response struct {
 	statusCode int
 	header     http.Header
 	body       WriteBody
+	autoETag   bool
+	trailers   []trailer
 }
 
 func New(body WriteBody) Response {
@@ -173,6 +204,29 @@ func (r Response) UpdateWith(statusCode int, header http.Header) Response {
 }
 
 func (r Response) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	writer, done := intercept(writer, request)
+	defer done()
+
+	if r.autoETag && r.body != nil {
+		var err error
+		if r, err = r.withComputedETag(); err != nil {
+			err = fmt.Errorf("buffer body: %w", err)
+			Error(err, http.StatusInternalServerError).ServeHTTP(writer, request)
+			return
+		}
+	}
+
+	if r.notModified(request) {
+		// only send the headers describing the representation, without its content
+		header := writer.Header()
+		maps.Copy(header, r.header)
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	maps.Copy(writer.Header(), r.header)
 
 	if r.statusCode == 0 && r.body == nil {
@@ -181,6 +235,17 @@ func (r Response) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		r.statusCode = http.StatusNoContent
 	}
 
+	if request.Method == http.MethodHead && r.body != nil {
+		// compute the headers as for a GET request, but do not send the body
+		r.serveHead(writer, request)
+		return
+	}
+
+	if len(r.trailers) > 0 {
+		r.serveWithTrailers(writer, request)
+		return
+	}
+
 	if r.statusCode > 0 {
 		writer.WriteHeader(r.statusCode)
 	}
@@ -188,7 +253,7 @@ func (r Response) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	if r.body != nil {
 		err := r.body(writer)
 		if err != nil {
-			slog.WarnContext(request.Context(),
+			internal.LoggerOf(request.Context()).WarnContext(request.Context(),
 				"writing body",
 				slog.String("err", err.Error()),
 			)
@@ -210,6 +275,9 @@ func LazyNew(body func(statusCode int, headers http.Header, req *http.Request) h
 }
 
 func (l Lazy) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	writer, done := intercept(writer, request)
+	defer done()
+
 	l.body(l.statusCode, l.header, request).ServeHTTP(writer, request)
 }
 
@@ -241,3 +309,19 @@ func (l Lazy) UpdateWith(statusCode int, header http.Header) Lazy {
 	maps.Copy(l.header, header)
 	return l
 }
+
+// mapResponse applies fn to the handler produced by this Lazy, if it is a Response.
+func (l Lazy) mapResponse(fn func(r Response) Response) Lazy {
+	body := l.body
+
+	l.body = func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+		handler := body(statusCode, headers, req)
+		if resp, ok := handler.(Response); ok {
+			return fn(resp)
+		}
+
+		return handler
+	}
+
+	return l
+}
@@ -0,0 +1,95 @@
+package response
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Status responds with the given status code. If body is not nil, it is encoded
+// according to the requests Accept header, see Encoded.
+func Status(statusCode int, body any) Lazy {
+	if body == nil {
+		return LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+			return New(nil).UpdateWith(statusCode, headers)
+		}).WithStatusCode(statusCode)
+	}
+
+	return Encoded(body).WithStatusCode(statusCode)
+}
+
+// OK responds with 200 OK and an optional body.
+func OK(body any) Lazy {
+	return Status(http.StatusOK, body)
+}
+
+// Created responds with 201 Created, pointing the Location header to the created resource.
+func Created(location string, body any) Lazy {
+	return Status(http.StatusCreated, body).SetHeader("Location", location)
+}
+
+// Accepted responds with 202 Accepted and an optional body.
+func Accepted(body any) Lazy {
+	return Status(http.StatusAccepted, body)
+}
+
+// BadRequest responds with 400 Bad Request and an optional body.
+func BadRequest(body any) Lazy {
+	return Status(http.StatusBadRequest, body)
+}
+
+// Unauthorized responds with 401 Unauthorized and sets the WWW-Authenticate
+// header to the given challenge, e.g. `Bearer realm="api"`.
+func Unauthorized(wwwAuthenticate string, body any) Lazy {
+	return Status(http.StatusUnauthorized, body).SetHeader("WWW-Authenticate", wwwAuthenticate)
+}
+
+// Forbidden responds with 403 Forbidden and an optional body.
+func Forbidden(body any) Lazy {
+	return Status(http.StatusForbidden, body)
+}
+
+// NotFound responds with 404 Not Found and an optional body.
+func NotFound(body any) Lazy {
+	return Status(http.StatusNotFound, body)
+}
+
+// Conflict responds with 409 Conflict and an optional body.
+func Conflict(body any) Lazy {
+	return Status(http.StatusConflict, body)
+}
+
+// PreconditionFailed responds with 412 Precondition Failed and an optional body.
+// Use it if the If-Match or If-Unmodified-Since header of an update does not match
+// the current state of the resource.
+func PreconditionFailed(body any) Lazy {
+	return Status(http.StatusPreconditionFailed, body)
+}
+
+// PreconditionRequired responds with 428 Precondition Required and an optional body.
+// Use it to reject updates that do not send an If-Match header.
+func PreconditionRequired(body any) Lazy {
+	return Status(http.StatusPreconditionRequired, body)
+}
+
+// TooManyRequests responds with 429 Too Many Requests. If retryAfter is positive,
+// the Retry-After header is set to the number of seconds, rounded up.
+func TooManyRequests(retryAfter time.Duration, body any) Lazy {
+	return withRetryAfter(Status(http.StatusTooManyRequests, body), retryAfter)
+}
+
+// ServiceUnavailable responds with 503 Service Unavailable. If retryAfter is positive,
+// the Retry-After header is set to the number of seconds, rounded up.
+func ServiceUnavailable(retryAfter time.Duration, body any) Lazy {
+	return withRetryAfter(Status(http.StatusServiceUnavailable, body), retryAfter)
+}
+
+func withRetryAfter(l Lazy, retryAfter time.Duration) Lazy {
+	if retryAfter <= 0 {
+		return l
+	}
+
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	return l.SetHeader("Retry-After", strconv.FormatInt(seconds, 10))
+}
@@ -0,0 +1,45 @@
+package response
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreated(t *testing.T) {
+	req := httptest.NewRequest("POST", "/items", nil)
+	req.Header.Set("Accept", "application/json")
+
+	rec := httptest.NewRecorder()
+	Created("/items/1", map[string]int{"id": 1}).ServeHTTP(rec, req)
+
+	AssertEqual(t, rec.Code, http.StatusCreated)
+	AssertEqual(t, rec.Header().Get("Location"), "/items/1")
+	AssertEqual(t, rec.Body.String(), `{"id":1}`)
+}
+
+func TestStatusWithoutBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NotFound(nil).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusNotFound)
+	AssertEqual(t, rec.Body.Len(), 0)
+}
+
+func TestUnauthorized(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Unauthorized(`Bearer realm="api"`, nil).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusUnauthorized)
+	AssertEqual(t, rec.Header().Get("WWW-Authenticate"), `Bearer realm="api"`)
+}
+
+func TestTooManyRequests(t *testing.T) {
+	rec := httptest.NewRecorder()
+	TooManyRequests(1500*time.Millisecond, nil).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusTooManyRequests)
+	AssertEqual(t, rec.Header().Get("Retry-After"), "2")
+}
@@ -0,0 +1,75 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"github.com/go-gum/gum/internal"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Stream copies rc to the client, e.g. the body of an object fetched from an object storage.
+// If size is not negative, it is sent as Content-Length. If contentType is empty, the
+// Content-Type header is left untouched.
+//
+// If rc also implements io.Seeker, the response is served using http.ServeContent, which
+// answers Range requests. In that case, the status code is determined by http.ServeContent
+// and size is ignored in favour of the size reported by the source. Otherwise, Range
+// headers are ignored and the full content is sent.
+//
+// rc is closed once the response was served, even if writing fails. If the client
+// disconnects, rc is closed as soon as the requests context is done, which unblocks
+// a copy that waits on a slow source.
+func Stream(rc io.ReadCloser, size int64, contentType string) Lazy {
+	closeSource := sync.OnceValue(rc.Close)
+
+	lazy := LazyNew(func(statusCode int, headers http.Header, req *http.Request) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			stop := context.AfterFunc(r.Context(), func() { _ = closeSource() })
+
+			defer func() {
+				stop()
+				_ = closeSource()
+			}()
+
+			maps.Copy(w.Header(), headers)
+
+			if content, ok := rc.(io.ReadSeeker); ok {
+				http.ServeContent(w, r, "", time.Time{}, content)
+				return
+			}
+
+			if size >= 0 {
+				w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			}
+
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+
+			w.WriteHeader(statusCode)
+
+			if r.Method == http.MethodHead {
+				return
+			}
+
+			if _, err := io.Copy(w, rc); err != nil && !errors.Is(r.Context().Err(), context.Canceled) {
+				internal.LoggerOf(r.Context()).WarnContext(r.Context(),
+					"streaming body",
+					slog.String("err", err.Error()),
+				)
+			}
+		})
+	})
+
+	if contentType != "" {
+		lazy = lazy.SetHeader("Content-Type", contentType)
+	}
+
+	return lazy
+}
@@ -0,0 +1,123 @@
+package response
+
+import (
+	"context"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// trackingReader records if it was closed.
+type trackingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (t *trackingReader) Close() error {
+	t.closed = true
+	return nil
+}
+
+// trackingReadSeeker is a trackingReader that supports seeking.
+type trackingReadSeeker struct {
+	*strings.Reader
+	closed bool
+}
+
+func (t *trackingReadSeeker) Close() error {
+	t.closed = true
+	return nil
+}
+
+// blockingReader blocks reading until it is closed.
+type blockingReader struct {
+	closed chan struct{}
+}
+
+func (b *blockingReader) Read(p []byte) (int, error) {
+	<-b.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (b *blockingReader) Close() error {
+	close(b.closed)
+	return nil
+}
+
+func TestStream(t *testing.T) {
+	t.Run("Reader", func(t *testing.T) {
+		source := &trackingReader{Reader: strings.NewReader("hello world")}
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=6-")
+
+		rec := gumtest.Serve(Stream(source, 11, "text/plain"), req)
+
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+		AssertEqual(t, rec.Header.Get("Content-Length"), "11")
+		AssertEqual(t, rec.Header.Get("Content-Type"), "text/plain")
+		AssertEqual(t, rec.Text(), "hello world")
+		AssertTrue(t, source.closed)
+	})
+
+	t.Run("UnknownSize", func(t *testing.T) {
+		source := &trackingReader{Reader: strings.NewReader("hello world")}
+		rec := gumtest.Serve(Stream(source, -1, "").WithStatusCode(http.StatusAccepted), httptest.NewRequest("GET", "/", nil))
+
+		AssertEqual(t, rec.StatusCode, http.StatusAccepted)
+		AssertEqual(t, rec.Header.Get("Content-Length"), "")
+		AssertEqual(t, rec.Text(), "hello world")
+		AssertTrue(t, source.closed)
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		source := &trackingReader{Reader: strings.NewReader("hello world")}
+		rec := gumtest.Serve(Stream(source, 11, "text/plain"), httptest.NewRequest("HEAD", "/", nil))
+
+		AssertEqual(t, rec.StatusCode, http.StatusOK)
+		AssertEqual(t, rec.Header.Get("Content-Length"), "11")
+		AssertEqual(t, rec.Text(), "")
+		AssertTrue(t, source.closed)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		source := &trackingReadSeeker{Reader: strings.NewReader("hello world")}
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=6-")
+
+		rec := gumtest.Serve(Stream(source, 11, "text/plain"), req)
+
+		AssertEqual(t, rec.StatusCode, http.StatusPartialContent)
+		AssertEqual(t, rec.Header.Get("Content-Range"), "bytes 6-10/11")
+		AssertEqual(t, rec.Header.Get("Content-Type"), "text/plain")
+		AssertEqual(t, rec.Text(), "world")
+		AssertTrue(t, source.closed)
+	})
+
+	t.Run("ClientDisconnect", func(t *testing.T) {
+		source := &blockingReader{closed: make(chan struct{})}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequestWithContext(ctx, "GET", "/", nil)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			gumtest.Serve(Stream(source, -1, ""), req)
+		}()
+
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("stream did not finish after the client disconnected")
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package response
+
+import (
+	"encoding/base64"
+	"github.com/go-gum/gum/internal"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+type trailer struct {
+	name string
+
+	// newWriter creates a writer that receives a copy of the body
+	// and returns the value of the trailer afterward.
+	newWriter func() trailerWriter
+}
+
+type trailerWriter interface {
+	io.Writer
+	Value() string
+}
+
+// WithTrailer declares a HTTP trailer. The value function is called after the body
+// was written, e.g. to report the processing time or the number of records streamed.
+// Trailers are only sent if the client supports them, e.g. with chunked encoding
+// in HTTP/1.1 or with HTTP/2.
+func (r Response) WithTrailer(name string, value func() string) Response {
+	return r.withTrailer(trailer{
+		name:      name,
+		newWriter: func() trailerWriter { return trailerFunc(value) },
+	})
+}
+
+// WithChecksumTrailer declares a trailer containing the base64 encoded checksum of the body,
+// computed using a new hash.Hash per request, e.g. sha256.New.
+func (r Response) WithChecksumTrailer(name string, newHash func() hash.Hash) Response {
+	return r.withTrailer(trailer{
+		name:      name,
+		newWriter: func() trailerWriter { return hashTrailer{Hash: newHash()} },
+	})
+}
+
+func (r Response) withTrailer(t trailer) Response {
+	// clone to not modify the trailers of a previous copy of this Response
+	r.trailers = append(slices.Clip(r.trailers), t)
+	return r
+}
+
+// WithTrailer calls Response.WithTrailer on the Response produced by this Lazy.
+func (l Lazy) WithTrailer(name string, value func() string) Lazy {
+	return l.mapResponse(func(r Response) Response { return r.WithTrailer(name, value) })
+}
+
+// WithChecksumTrailer calls Response.WithChecksumTrailer on the Response produced by this Lazy.
+func (l Lazy) WithChecksumTrailer(name string, newHash func() hash.Hash) Lazy {
+	return l.mapResponse(func(r Response) Response { return r.WithChecksumTrailer(name, newHash) })
+}
+
+// serveWithTrailers announces the trailers, writes the body and sets
+// the trailer values afterward.
+func (r Response) serveWithTrailers(writer http.ResponseWriter, request *http.Request) {
+	header := writer.Header()
+
+	writers := make([]trailerWriter, len(r.trailers))
+
+	for idx, t := range r.trailers {
+		header.Add("Trailer", t.name)
+		writers[idx] = t.newWriter()
+	}
+
+	if r.statusCode > 0 {
+		writer.WriteHeader(r.statusCode)
+	}
+
+	if r.body != nil {
+		err := r.body(&trailerResponseWriter{ResponseWriter: writer, writers: writers})
+		if err != nil {
+			internal.LoggerOf(request.Context()).WarnContext(request.Context(),
+				"writing body",
+				slog.String("err", err.Error()),
+			)
+		}
+	}
+
+	for idx, t := range r.trailers {
+		header.Set(t.name, writers[idx].Value())
+	}
+}
+
+// trailerResponseWriter copies the body to the trailer writers. Unlike io.MultiWriter,
+// it is still a http.ResponseWriter, so that streaming bodies can flush the response.
+type trailerResponseWriter struct {
+	http.ResponseWriter
+	writers []trailerWriter
+}
+
+func (w *trailerResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+
+	for _, tw := range w.writers {
+		_, _ = tw.Write(p[:n])
+	}
+
+	return n, err
+}
+
+// Flush flushes the wrapped http.ResponseWriter, if supported.
+func (w *trailerResponseWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController
+func (w *trailerResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type trailerFunc func() string
+
+func (t trailerFunc) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (t trailerFunc) Value() string {
+	return t()
+}
+
+type hashTrailer struct {
+	hash.Hash
+}
+
+func (h hashTrailer) Value() string {
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrailer(t *testing.T) {
+	var records int
+
+	resp := Text("hello").
+		WithTrailer("X-Records", func() string { records++; return "5" }).
+		WithChecksumTrailer("X-Checksum", sha256.New)
+
+	rec := httptest.NewRecorder()
+	resp.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	checksum := sha256.Sum256([]byte("hello"))
+
+	result := rec.Result()
+	AssertEqual(t, rec.Body.String(), "hello")
+	AssertEqual(t, result.Header.Values("Trailer"), []string{"X-Records", "X-Checksum"})
+	AssertEqual(t, result.Trailer.Get("X-Records"), "5")
+	AssertEqual(t, result.Trailer.Get("X-Checksum"), base64.StdEncoding.EncodeToString(checksum[:]))
+	AssertEqual(t, records, 1)
+}
+
+func TestLazyTrailer(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSON(1).WithTrailer("X-Done", func() string { return "yes" }).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	AssertEqual(t, rec.Body.String(), "1")
+	AssertEqual(t, rec.Result().Trailer.Get("X-Done"), "yes")
+}
+
+func TestTrailerFlush(t *testing.T) {
+	resp := New(func(w io.Writer) error {
+		_, _ = io.WriteString(w, "first")
+
+		// streaming bodies can still flush the response
+		return http.NewResponseController(w.(http.ResponseWriter)).Flush()
+	}).WithChecksumTrailer("X-Checksum", sha256.New)
+
+	rec := httptest.NewRecorder()
+	resp.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	checksum := sha256.Sum256([]byte("first"))
+
+	AssertTrue(t, rec.Flushed)
+	AssertEqual(t, rec.Result().Trailer.Get("X-Checksum"), base64.StdEncoding.EncodeToString(checksum[:]))
+}
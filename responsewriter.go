@@ -0,0 +1,114 @@
+package gum
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ResponseWriter is the http.ResponseWriter of the request that is currently
+// handled by a Handler. Use it in handlers that need to write the response
+// themselves, e.g. for streaming.
+type ResponseWriter interface {
+	http.ResponseWriter
+}
+
+// Flusher is the http.Flusher of the current requests http.ResponseWriter.
+// Extraction fails if the http.ResponseWriter does not support flushing.
+type Flusher interface {
+	http.Flusher
+}
+
+// Hijacker is the http.Hijacker of the current requests http.ResponseWriter.
+// Extraction fails if the http.ResponseWriter does not support hijacking the connection.
+type Hijacker interface {
+	http.Hijacker
+}
+
+// responseWriterKey is the context key used by Handler to make the
+// http.ResponseWriter available to extractors.
+type responseWriterKey struct{}
+
+// ErrNoResponseWriter is returned when extracting a ResponseWriter outside of a Handler.
+var ErrNoResponseWriter = errors.New("no http.ResponseWriter available in request context")
+
+func init() {
+	Register(func(r *http.Request) (ResponseWriter, error) {
+		return responseWriterOf(r)
+	})
+
+	Register(func(r *http.Request) (http.ResponseWriter, error) {
+		return responseWriterOf(r)
+	})
+
+	Register(func(r *http.Request) (Flusher, error) {
+		w, err := responseWriterOf(r)
+		if err != nil {
+			return nil, err
+		}
+
+		flusher, ok := flusherOf(w)
+		if !ok {
+			// the server does not support this, it is not a problem of the request
+			return nil, NewHTTPError(http.StatusInternalServerError, errors.New("http.ResponseWriter does not implement http.Flusher"))
+		}
+
+		return flusher, nil
+	})
+
+	Register(func(r *http.Request) (Hijacker, error) {
+		w, err := responseWriterOf(r)
+		if err != nil {
+			return nil, err
+		}
+
+		hijacker, ok := lookupResponseWriter[http.Hijacker](w)
+		if !ok {
+			// the server does not support this, it is not a problem of the request
+			return nil, NewHTTPError(http.StatusInternalServerError, errors.New("http.ResponseWriter does not implement http.Hijacker"))
+		}
+
+		return hijacker, nil
+	})
+}
+
+// responseWriterOf returns the http.ResponseWriter that was injected into the
+// requests context by Handler.
+func responseWriterOf(r *http.Request) (http.ResponseWriter, error) {
+	w, ok := r.Context().Value(responseWriterKey{}).(http.ResponseWriter)
+	if !ok || w == nil {
+		return nil, ErrNoResponseWriter
+	}
+
+	return w, nil
+}
+
+// flusherOf looks up the http.Flusher of w. Wrapping writers like the RecordingWriter of the
+// Handler always implement http.Flusher, but only flush if the writer they wrap supports it.
+func flusherOf(w http.ResponseWriter) (http.Flusher, bool) {
+	flusher, ok := lookupResponseWriter[http.Flusher](w)
+	if wrapper, isWrapper := flusher.(interface{ Unwrap() http.ResponseWriter }); ok && isWrapper {
+		if _, ok := flusherOf(wrapper.Unwrap()); !ok {
+			return nil, false
+		}
+	}
+
+	return flusher, ok
+}
+
+// lookupResponseWriter checks if w implements T. If it does not, it follows the chain
+// of wrapped writers using an Unwrap method, the same way http.ResponseController does.
+func lookupResponseWriter[T any](w http.ResponseWriter) (T, bool) {
+	for {
+		if t, ok := w.(T); ok {
+			return t, true
+		}
+
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			var tNil T
+			return tNil, false
+		}
+
+		w = unwrapper.Unwrap()
+	}
+}
@@ -0,0 +1,74 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtractResponseWriter(t *testing.T) {
+	req := &http.Request{}
+
+	rw := gumtest.Serve(Handler(func(w ResponseWriter) { w.WriteHeader(http.StatusTeapot) }), req)
+	AssertEqual(t, rw.StatusCode, http.StatusTeapot)
+}
+
+func TestExtractFlusher(t *testing.T) {
+	t.Run("Supported", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		rec := httptest.NewRecorder()
+
+		Handler(func(f Flusher) { f.Flush() }).ServeHTTP(rec, req)
+		AssertTrue(t, rec.Flushed)
+	})
+
+	t.Run("Unwrap", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		rec := httptest.NewRecorder()
+
+		Handler(func(f Flusher) { f.Flush() }).ServeHTTP(wrappedResponseWriter{rec}, req)
+		AssertTrue(t, rec.Flushed)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		req := &http.Request{}
+		rec := httptest.NewRecorder()
+
+		// hide the Flush method of the recorder
+		Handler(func(f Flusher) { t.FailNow() }).ServeHTTP(struct{ http.ResponseWriter }{rec}, req)
+		AssertEqual(t, rec.Code, http.StatusInternalServerError)
+	})
+}
+
+func TestExtractHijackerUnsupported(t *testing.T) {
+	req := &http.Request{}
+
+	rw := gumtest.Serve(Handler(func(h Hijacker) { t.FailNow() }), req)
+	AssertEqual(t, rw.StatusCode, http.StatusInternalServerError)
+}
+
+type wrappedResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w wrappedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func TestResponseWriterProvided(t *testing.T) {
+	// a provided value can extract the ResponseWriter, which is not visible from the handler
+	provideFlusher := ProvideContextValueFunc(func(r *http.Request) (http.Flusher, error) {
+		return Extract[Flusher](r)
+	})
+
+	var flusher http.Flusher
+	handler := provideFlusher(Handler(func(value ContextValue[http.Flusher]) {
+		flusher = value.Value
+	}))
+
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertTrue(t, flusher != nil)
+}
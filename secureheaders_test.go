@@ -0,0 +1,29 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSecureHeaders(t *testing.T) {
+	noop := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	csp := CSP{}.DefaultSrc(CSPSelf).ImgSrc(CSPSelf, "https://img.example.com").FrameAncestors(CSPNone)
+
+	handler := SecureHeaders(
+		HSTS(time.Hour, false, false),
+		ContentSecurityPolicy(csp),
+	)(SecureHeadersOverride(WithoutSecureHeader("X-Frame-Options"))(noop))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	header := rec.Header()
+	AssertEqual(t, header.Get("Strict-Transport-Security"), "max-age=3600")
+	AssertEqual(t, header.Get("X-Content-Type-Options"), "nosniff")
+	AssertEqual(t, header.Get("X-Frame-Options"), "")
+	AssertEqual(t, header.Get("Content-Security-Policy"), "default-src 'self'; img-src 'self' https://img.example.com; frame-ancestors 'none'")
+}
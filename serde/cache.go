@@ -0,0 +1,66 @@
+package serde
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Cache stores the compiled setters of types. Unmarshal and UnmarshalNew use a process wide
+// cache, which grows with every type that is unmarshalled. Decoders can use their own
+// Cache using the WithCache option, which is freed together with the Decoder.
+type Cache struct {
+	setters      sync.Map
+	compilations compileGroup
+}
+
+// NewCache creates a new, empty Cache.
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// defaultCache is used by Unmarshal, UnmarshalNew and decoders without a custom Cache.
+var defaultCache = NewCache()
+
+// ResetCache removes all compiled setters from the process wide cache. Use it in long
+// running processes that unmarshal many short-lived types, or to isolate tests.
+// Decoders created before the reset keep working.
+func ResetCache() {
+	defaultCache.Reset()
+}
+
+// Reset removes all compiled setters from the cache.
+func (c *Cache) Reset() {
+	c.setters.Clear()
+}
+
+// Len returns the number of types with a compiled setter in the cache.
+func (c *Cache) Len() int {
+	var count int
+	c.setters.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+func (c *Cache) load(ty reflect.Type) (setter, bool) {
+	cached, ok := c.setters.Load(ty)
+	if !ok {
+		return nil, false
+	}
+
+	return cached.(setter), true
+}
+
+// compile returns the setter for ty. If the setter is not yet cached, it is compiled
+// by the first caller, concurrent callers for the same type wait for its result.
+func (c *Cache) compile(ty reflect.Type) (setter, error) {
+	if cached, ok := c.load(ty); ok {
+		return cached, nil
+	}
+
+	return c.compilations.Do(ty, func() (setter, error) {
+		return setterOf(newInConstructionTypes(c), ty)
+	})
+}
@@ -0,0 +1,48 @@
+package serde
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"reflect"
+	"testing"
+)
+
+func TestResetCache(t *testing.T) {
+	type Node struct {
+		Name     string  `json:"name"`
+		Children []*Node `json:"children"`
+	}
+
+	decoder, err := For[Node]()
+	AssertEqual(t, err, nil)
+	AssertTrue(t, defaultCache.Len() > 0)
+
+	ResetCache()
+	AssertEqual(t, defaultCache.Len(), 0)
+
+	// the decoder still works, recursive types are compiled again if needed
+	value, err := decoder.Decode(dummySourceValue{Values: map[string]any{".name": "root", ".children": nil}})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value.Name, "root")
+}
+
+func TestWithCache(t *testing.T) {
+	type Private struct {
+		Value string `json:"value"`
+	}
+
+	cache := NewCache()
+
+	decoder, err := For[Private](WithCache(cache))
+	AssertEqual(t, err, nil)
+	AssertEqual(t, cache.Len(), 2)
+
+	_, cached := defaultCache.load(reflect.TypeFor[Private]())
+	AssertTrue(t, !cached)
+
+	value, err := decoder.Decode(dummySourceValue{Values: map[string]any{".value": "foo"}})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value.Value, "foo")
+
+	cache.Reset()
+	AssertEqual(t, cache.Len(), 0)
+}
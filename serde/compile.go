@@ -0,0 +1,49 @@
+package serde
+
+import (
+	"reflect"
+	"sync"
+)
+
+// compileGroup compiles the setter of a type only once for concurrent calls.
+type compileGroup struct {
+	mu      sync.Mutex
+	running map[reflect.Type]*compilation
+}
+
+type compilation struct {
+	done   chan struct{}
+	setter setter
+	err    error
+}
+
+// Do runs fn, unless a call for the same type is already running. In that case,
+// Do waits for the running call and returns its result.
+func (g *compileGroup) Do(ty reflect.Type, fn func() (setter, error)) (setter, error) {
+	g.mu.Lock()
+
+	if c, ok := g.running[ty]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.setter, c.err
+	}
+
+	if g.running == nil {
+		g.running = map[reflect.Type]*compilation{}
+	}
+
+	c := &compilation{done: make(chan struct{})}
+	g.running[ty] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.running, ty)
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	c.setter, c.err = fn()
+	return c.setter, c.err
+}
@@ -0,0 +1,65 @@
+package serde
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCompileGroup(t *testing.T) {
+	var group compileGroup
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	ty := reflect.TypeFor[string]()
+
+	compile := func() (setter, error) {
+		calls.Add(1)
+		<-release
+		return setString, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			setter, err := group.Do(ty, compile)
+			AssertEqual(t, err, nil)
+			AssertTrue(t, setter != nil)
+		}()
+	}
+
+	// give all goroutines the chance to join the running compilation
+	time.Sleep(50 * time.Millisecond)
+
+	close(release)
+	wg.Wait()
+
+	AssertEqual(t, calls.Load(), int32(1))
+}
+
+func TestCompileSetterConcurrent(t *testing.T) {
+	type Node struct {
+		Name     string  `json:"name"`
+		Children []*Node `json:"children"`
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, err := UnmarshalNew[Node](dummySourceValue{Values: map[string]any{".name": "root", ".children": nil}})
+			AssertEqual(t, err, nil)
+			AssertEqual(t, value.Name, "root")
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,68 @@
+package serde
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// setComplex sets a complex number, either parsed from a string like "1+2i",
+// or from a container with the float children "Re" and "Im".
+func setComplex(source SourceValue, target reflect.Value) error {
+	bitSize := target.Type().Bits()
+
+	text, err := source.String()
+	if err == nil {
+		value, err := strconv.ParseComplex(text, bitSize)
+		if err != nil {
+			return fmt.Errorf("parse complex value %q: %w", text, err)
+		}
+
+		target.SetComplex(value)
+		return nil
+	}
+
+	containerSource, ok := source.(ContainerSourceValue)
+	if !ok {
+		return fmt.Errorf("get complex value: %w", err)
+	}
+
+	re, err := complexPart(containerSource, "Re")
+	if err != nil {
+		return err
+	}
+
+	im, err := complexPart(containerSource, "Im")
+	if err != nil {
+		return err
+	}
+
+	target.SetComplex(complex(re, im))
+	return nil
+}
+
+// complexPart returns the float child with the given name. A missing child is zero.
+func complexPart(source ContainerSourceValue, name string) (float64, error) {
+	child, err := source.Get(name)
+	switch {
+	case errors.Is(err, ErrNoValue):
+		return 0, nil
+	case err != nil:
+		return 0, fmt.Errorf("lookup child %q: %w", name, err)
+	}
+
+	value, err := child.Float()
+	if err != nil {
+		return 0, fmt.Errorf("get float value of %q: %w", name, err)
+	}
+
+	return value, nil
+}
+
+// isUintptrOptIn reports whether a field opted in to unmarshalling uintptr values
+// using the struct tag `serde:"uintptr"`. As a uintptr usually holds an address,
+// uintptr values are not supported otherwise.
+func isUintptrOptIn(field field) bool {
+	return field.Type.Kind() == reflect.Uintptr && field.Tag.Get("serde") == "uintptr"
+}
@@ -0,0 +1,58 @@
+package serde
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"testing"
+)
+
+func TestUnmarshalComplex(t *testing.T) {
+	type Signal struct {
+		Text      complex128 `json:"text"`
+		Parts     complex64  `json:"parts"`
+		Imaginary complex128 `json:"imaginary"`
+	}
+
+	value, err := UnmarshalNew[Signal](dummySourceValue{
+		Values: map[string]any{
+			".text":         "1.5+2i",
+			".parts":        int64(0),
+			".parts.Re":     float64(3),
+			".parts.Im":     float64(-4),
+			".imaginary":    int64(0),
+			".imaginary.Re": nil,
+			".imaginary.Im": float64(1),
+		},
+	})
+
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, Signal{Text: 1.5 + 2i, Parts: 3 - 4i, Imaginary: 1i})
+
+	_, err = UnmarshalNew[complex128](dummySourceValue{Values: map[string]any{"": "not a number"}})
+	AssertTrue(t, err != nil)
+}
+
+func TestUnmarshalUintptr(t *testing.T) {
+	type Pointers struct {
+		Address uintptr `json:"address" serde:"uintptr"`
+	}
+
+	value, err := UnmarshalNew[Pointers](dummySourceValue{Values: map[string]any{".address": int64(4096)}})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value.Address, uintptr(4096))
+
+	type Implicit struct {
+		Address uintptr `json:"address"`
+	}
+
+	_, err = UnmarshalNew[Implicit](dummySourceValue{})
+
+	var notSupported NotSupportedError
+	AssertTrue(t, errors.As(err, &notSupported))
+}
+
+func TestMarshalComplex(t *testing.T) {
+	values, err := MarshalValues(struct{ Value complex128 }{Value: 1.5 + 2i})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, values, map[string][]string{"Value": {"(1.5+2i)"}})
+}
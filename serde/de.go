@@ -7,7 +7,6 @@ import (
 	"iter"
 	"reflect"
 	"strings"
-	"sync"
 )
 
 type NotSupportedError struct {
@@ -87,7 +86,7 @@ func Unmarshal(source SourceValue, target any) error {
 	targetValue := reflect.ValueOf(target).Elem()
 
 	// build the setter for the targets type
-	setter, err := setterOf(inConstructionTypes{}, targetValue.Type())
+	setter, err := defaultCache.compile(targetValue.Type())
 	if err != nil {
 		return err
 	}
@@ -106,36 +105,58 @@ type setter func(SourceValue, reflect.Value) error
 
 var tyTextUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
 
-var cachedSetters sync.Map
+// inConstructionTypes tracks the types whose setters are currently
+// being compiled into the cache.
+type inConstructionTypes struct {
+	cache *Cache
+	types map[reflect.Type]struct{}
+}
 
-type inConstructionTypes map[reflect.Type]struct{}
+func newInConstructionTypes(cache *Cache) inConstructionTypes {
+	return inConstructionTypes{cache: cache, types: map[reflect.Type]struct{}{}}
+}
 
 func setterOf(inConstruction inConstructionTypes, ty reflect.Type) (setter, error) {
-	if cached, ok := cachedSetters.Load(ty); ok {
-		return cached.(setter), nil
+	cache := inConstruction.cache
+
+	if cached, ok := cache.load(ty); ok {
+		return cached, nil
 	}
 
-	if _, ok := inConstruction[ty]; ok {
+	if _, ok := inConstruction.types[ty]; ok {
 		// detected a cycle. return a setter that does a cache lookup when executed.
-		// we assume that the actual setter will be in the cache once this setter is executed.
+		// the actual setter is in the cache once this setter is executed, unless
+		// the cache was reset in the meantime. In that case it is compiled again.
 		lazySetter := func(source SourceValue, target reflect.Value) error {
-			cached, _ := cachedSetters.Load(ty)
-			return cached.(setter)(source, target)
+			cached, err := cache.compile(ty)
+			if err != nil {
+				return err
+			}
+
+			return cached(source, target)
 		}
 
 		return lazySetter, nil
 	}
 
-	inConstruction[ty] = struct{}{}
+	inConstruction.types[ty] = struct{}{}
 
-	setter, err := makeSetterOf(inConstruction, ty)
+	compiled, err := makeSetterOf(inConstruction, ty)
 	if err != nil {
 		return nil, err
 	}
 
-	cachedSetters.Store(ty, setter)
+	if ty.Kind() != reflect.Pointer {
+		if allowed := enumValuesOfType(ty); allowed != nil {
+			compiled = enumSetter(allowed, compiled)
+		}
+	}
 
-	return setter, nil
+	// keep the setter of a concurrent compilation that was faster, so all
+	// lazy setters referencing this type resolve to the same setter
+	actual, _ := cache.setters.LoadOrStore(ty, compiled)
+
+	return actual.(setter), nil
 }
 
 func makeSetterOf(inConstruction inConstructionTypes, ty reflect.Type) (setter, error) {
@@ -156,6 +177,9 @@ func makeSetterOf(inConstruction inConstructionTypes, ty reflect.Type) (setter,
 	case reflect.Float32, reflect.Float64:
 		return setFloat, nil
 
+	case reflect.Complex64, reflect.Complex128:
+		return setComplex, nil
+
 	case reflect.String:
 		return setString, nil
 
@@ -355,17 +379,43 @@ func setTextUnmarshaler(source SourceValue, target reflect.Value) error {
 }
 
 func makeSetStruct(inConstruction inConstructionTypes, ty reflect.Type) (setter, error) {
-	var setters []setter
+	var fields []structField
+
+	for _, field := range fieldsToSerialize(ty) {
+		var de setter
+		var err error
 
-	fields := fieldsToSerialize(ty)
+		if isUintptrOptIn(field) {
+			de = setUint
+		} else {
+			de, err = setterOf(inConstruction, field.Type)
+		}
 
-	for _, field := range fields {
-		de, err := setterOf(inConstruction, field.Type)
 		if err != nil {
 			return nil, fmt.Errorf("setter for field %q: %w", field.Name, err)
 		}
 
-		setters = append(setters, de)
+		normalizer, err := normalizerOf(field.Tag.Get("norm"))
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field.Name, err)
+		}
+
+		if normalizer != nil {
+			de, err = normalizingSetter(field.Type, normalizer, de)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", field.Name, err)
+			}
+		}
+
+		if tag := field.Tag.Get("enum"); tag != "" {
+			de = enumSetter(parseEnumTag(tag), de)
+		}
+
+		if message := field.Tag.Get("errmsg"); message != "" {
+			de = messageSetter(field.Name, message, de)
+		}
+
+		fields = append(fields, newStructField(ty, field, de))
 	}
 
 	setter := func(source SourceValue, target reflect.Value) error {
@@ -374,7 +424,11 @@ func makeSetStruct(inConstruction inConstructionTypes, ty reflect.Type) (setter,
 			return ErrInvalidType
 		}
 
-		for idx, field := range fields {
+		base := baseOf(target)
+
+		for idx := range fields {
+			field := &fields[idx]
+
 			fieldSource, err := containerSource.Get(field.Name)
 			switch {
 			case errors.Is(err, ErrNoValue):
@@ -383,8 +437,7 @@ func makeSetStruct(inConstruction inConstructionTypes, ty reflect.Type) (setter,
 				return fmt.Errorf("lookup child %q: %w", field.Name, err)
 			}
 
-			fieldValue := target.FieldByIndex(field.Index)
-			if err := setters[idx](fieldSource, fieldValue); err != nil {
+			if err := field.apply(fieldSource, target, base); err != nil {
 				return fmt.Errorf("set field %q on %q: %w", field.Name, target.Type(), err)
 			}
 		}
@@ -556,6 +609,7 @@ type field struct {
 	Name  string
 	Type  reflect.Type
 	Index []int
+	Tag   reflect.StructTag
 }
 
 func fieldsToSerialize(ty reflect.Type) []field {
@@ -624,6 +678,7 @@ func fieldsToSerialize(ty reflect.Type) []field {
 					Name:  name,
 					Index: index,
 					Type:  fi.Type,
+					Tag:   fi.Tag,
 				},
 			})
 		}
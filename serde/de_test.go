@@ -308,7 +308,7 @@ func TestSetter(t *testing.T) {
 	studentSource := dummySourceValue{}
 
 	// get a string setter
-	nameSetter, _ := setterOf(inConstructionTypes{}, reflect.TypeFor[string]())
+	nameSetter, _ := setterOf(newInConstructionTypes(NewCache()), reflect.TypeFor[string]())
 
 	// get the SourceValue for the name of our student
 	nameSource, _ := studentSource.Get("name")
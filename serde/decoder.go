@@ -0,0 +1,55 @@
+package serde
+
+import (
+	"reflect"
+)
+
+// Decoder decodes SourceValues into values of type T. The setters for T are compiled
+// once by For, decoding does not need to look them up again.
+type Decoder[T any] struct {
+	setter setter
+}
+
+// DecoderOption configures the compilation of a Decoder.
+type DecoderOption func(config *decoderConfig)
+
+type decoderConfig struct {
+	cache *Cache
+}
+
+// WithCache compiles the Decoder into the given Cache instead of the process wide cache.
+// Pass NewCache() to give the Decoder a private cache that is freed together with it,
+// e.g. for short-lived generic types.
+func WithCache(cache *Cache) DecoderOption {
+	return func(config *decoderConfig) {
+		config.cache = cache
+	}
+}
+
+// For compiles a Decoder for T. Use it to compile the decoder once, e.g. at startup,
+// instead of looking up the compiled setters on every call to Unmarshal. An error is
+// returned if T, or any type reachable from T, can not be unmarshalled.
+//
+//	var decodeUser = must(serde.For[User]())
+//
+//	user, err := decodeUser.Decode(source)
+func For[T any](options ...DecoderOption) (Decoder[T], error) {
+	config := decoderConfig{cache: defaultCache}
+	for _, option := range options {
+		option(&config)
+	}
+
+	setter, err := config.cache.compile(reflect.TypeFor[T]())
+	if err != nil {
+		return Decoder[T]{}, err
+	}
+
+	return Decoder[T]{setter: setter}, nil
+}
+
+// Decode unmarshals the source into a new value of type T.
+func (d Decoder[T]) Decode(source SourceValue) (T, error) {
+	var target T
+	err := d.setter(source, reflect.ValueOf(&target).Elem())
+	return target, err
+}
@@ -0,0 +1,31 @@
+package serde
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	type Person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	decoder, err := For[Person]()
+	AssertEqual(t, err, nil)
+
+	value, err := decoder.Decode(dummySourceValue{Values: map[string]any{".name": "Albert", ".age": int64(76)}})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, Person{Name: "Albert", Age: 76})
+
+	_, err = decoder.Decode(dummySourceValue{Values: map[string]any{".age": "old"}})
+	AssertTrue(t, err != nil)
+}
+
+func TestForNotSupported(t *testing.T) {
+	_, err := For[struct{ Callback func() }]()
+
+	var notSupported NotSupportedError
+	AssertTrue(t, errors.As(err, &notSupported))
+}
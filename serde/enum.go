@@ -0,0 +1,142 @@
+package serde
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Enum is implemented by types that only allow a fixed set of values. Unmarshal rejects
+// values not returned by EnumValues. The values are compared to the string representation
+// of the unmarshalled value, as produced by encoding.TextMarshaler or fmt.Sprint.
+//
+//	type Color string
+//
+//	func (Color) EnumValues() []string {
+//		return []string{"red", "green", "blue"}
+//	}
+//
+// Instead of implementing Enum, the allowed values of a single field can be
+// listed in an enum struct tag:
+//
+//	type Filter struct {
+//		Color string `json:"color" enum:"red,green,blue"`
+//	}
+type Enum interface {
+	EnumValues() []string
+}
+
+var tyEnum = reflect.TypeFor[Enum]()
+
+// EnumError is returned if a value is not one of the allowed values of an Enum
+// or an enum struct tag.
+type EnumError struct {
+	Value   string
+	Allowed []string
+}
+
+func (e EnumError) Error() string {
+	return fmt.Sprintf("invalid value %q, allowed values are %s", e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// EnumValuesOf returns the allowed values of a field, either from its enum struct tag,
+// or from the Enum implementation of its type. Returns nil if the values are not restricted.
+func EnumValuesOf(field Field) []string {
+	if tag := field.Tag.Get("enum"); tag != "" {
+		return parseEnumTag(tag)
+	}
+
+	return enumValuesOfType(field.Type)
+}
+
+func parseEnumTag(tag string) []string {
+	var values []string
+	for _, value := range strings.Split(tag, ",") {
+		values = append(values, strings.TrimSpace(value))
+	}
+
+	return values
+}
+
+// enumValuesOfType returns the values of ty if it implements Enum,
+// following pointers and the elements of slices and arrays.
+func enumValuesOfType(ty reflect.Type) []string {
+	for !ty.Implements(tyEnum) {
+		switch ty.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			ty = ty.Elem()
+			continue
+		}
+
+		break
+	}
+
+	if !ty.Implements(tyEnum) {
+		return nil
+	}
+
+	return reflect.Zero(ty).Interface().(Enum).EnumValues()
+}
+
+// enumSetter wraps a setter to reject values not contained in allowed.
+func enumSetter(allowed []string, setter setter) setter {
+	return func(source SourceValue, target reflect.Value) error {
+		if err := setter(source, target); err != nil {
+			return err
+		}
+
+		return checkEnum(target, allowed)
+	}
+}
+
+// checkEnum checks the value, or every element of slices and arrays, against the allowed values.
+func checkEnum(value reflect.Value, allowed []string) error {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+
+		return checkEnum(value.Elem(), allowed)
+
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() != reflect.Uint8 {
+			for idx := range value.Len() {
+				if err := checkEnum(value.Index(idx), allowed); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
+	text, err := enumString(value)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(allowed, text) {
+		return EnumError{Value: text, Allowed: allowed}
+	}
+
+	return nil
+}
+
+func enumString(value reflect.Value) (string, error) {
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	if value.CanAddr() {
+		if marshaler, ok := value.Addr().Interface().(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+			return string(text), err
+		}
+	}
+
+	return fmt.Sprint(value.Interface()), nil
+}
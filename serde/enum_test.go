@@ -0,0 +1,59 @@
+package serde
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"testing"
+)
+
+type color string
+
+func (color) EnumValues() []string {
+	return []string{"red", "green", "blue"}
+}
+
+func TestUnmarshalEnum(t *testing.T) {
+	type Filter struct {
+		Color  color   `json:"color"`
+		Colors []color `json:"colors"`
+		Size   string  `json:"size" enum:"s, m, l"`
+		Level  int     `json:"level" enum:"1,2,3"`
+	}
+
+	valid := func() map[string]any {
+		return map[string]any{
+			".color":  "red",
+			".colors": []string{"green", "blue"},
+			".size":   "m",
+			".level":  int64(2),
+		}
+	}
+
+	value, err := UnmarshalNew[Filter](dummySourceValue{Values: valid()})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, Filter{Color: "red", Colors: []color{"green", "blue"}, Size: "m", Level: 2})
+
+	for key, invalid := range map[string]any{
+		".color":  "purple",
+		".colors": []string{"green", "purple"},
+		".size":   "xl",
+		".level":  int64(4),
+	} {
+		values := valid()
+		values[key] = invalid
+
+		_, err := UnmarshalNew[Filter](dummySourceValue{Values: values})
+
+		var enumErr EnumError
+		AssertTrue(t, errors.As(err, &enumErr))
+	}
+
+	values := valid()
+	values[".size"] = "xl"
+
+	_, err = UnmarshalNew[Filter](dummySourceValue{Values: values})
+
+	var enumErr EnumError
+	AssertTrue(t, errors.As(err, &enumErr))
+	AssertEqual(t, enumErr.Error(), `invalid value "xl", allowed values are s, m, l`)
+}
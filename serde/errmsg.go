@@ -0,0 +1,43 @@
+package serde
+
+import (
+	"reflect"
+)
+
+// MessageError carries the user facing message of a field defined by its errmsg struct tag.
+// If a value can not be unmarshalled into a field with an errmsg tag, the error is wrapped
+// into a MessageError:
+//
+//	type Person struct {
+//		Age int `json:"age" errmsg:"age must be a number between 0 and 120"`
+//	}
+//
+// Error returns the message only, the original error is available using Unwrap.
+type MessageError struct {
+	// Field is the name of the field the message belongs to
+	Field string
+
+	// Message is the text of the errmsg tag
+	Message string
+
+	Err error
+}
+
+func (e *MessageError) Error() string {
+	return e.Message
+}
+
+func (e *MessageError) Unwrap() error {
+	return e.Err
+}
+
+// messageSetter wraps a setter to attach the message to its errors.
+func messageSetter(field, message string, setter setter) setter {
+	return func(source SourceValue, target reflect.Value) error {
+		if err := setter(source, target); err != nil {
+			return &MessageError{Field: field, Message: message, Err: err}
+		}
+
+		return nil
+	}
+}
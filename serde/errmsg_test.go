@@ -0,0 +1,21 @@
+package serde
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"testing"
+)
+
+func TestUnmarshalErrorMessage(t *testing.T) {
+	type Person struct {
+		Age int `json:"age" errmsg:"age must be a number"`
+	}
+
+	_, err := UnmarshalNew[Person](dummySourceValue{Values: map[string]any{".age": "abc"}})
+
+	var messageErr *MessageError
+	AssertTrue(t, errors.As(err, &messageErr))
+	AssertEqual(t, messageErr.Field, "age")
+	AssertEqual(t, messageErr.Error(), "age must be a number")
+	AssertTrue(t, messageErr.Unwrap() != nil)
+}
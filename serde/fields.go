@@ -0,0 +1,35 @@
+package serde
+
+import "reflect"
+
+// Field describes a field of a struct that takes part in (de)serialization.
+type Field struct {
+	// Name is the name of the field after applying the json struct tag
+	Name string
+
+	// Type is the type of the field
+	Type reflect.Type
+
+	// Index is the index sequence for reflect.Value.FieldByIndex
+	Index []int
+
+	// Tag is the struct tag of the field
+	Tag reflect.StructTag
+}
+
+// Fields returns the fields of the struct type ty in the same order and with the same
+// names as used by Unmarshal, including promoted fields of embedded structs.
+// Panics if ty is not a struct.
+func Fields(ty reflect.Type) []Field {
+	var result []Field
+	for _, field := range fieldsToSerialize(ty) {
+		result = append(result, Field{
+			Name:  field.Name,
+			Type:  field.Type,
+			Index: field.Index,
+			Tag:   field.Tag,
+		})
+	}
+
+	return result
+}
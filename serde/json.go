@@ -0,0 +1,245 @@
+package serde
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ParseJSON reads a single json value from r and returns it as a SourceValue, so it can be
+// unmarshalled using the naming rules and struct tags of serde, like query or path values.
+// The value is read token by token using a json.Decoder. Numbers keep their textual
+// representation until they are unmarshalled into a number type.
+//
+// A json null leaves the target unchanged, object keys without a matching field are ignored.
+func ParseJSON(r io.Reader) (SourceValue, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	value, err := parseJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func parseJSONValue(decoder *json.Decoder) (jsonValue, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return jsonValue{}, fmt.Errorf("read json: %w", err)
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := map[string]jsonValue{}
+
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return jsonValue{}, fmt.Errorf("read json: %w", err)
+			}
+
+			key, _ := keyToken.(string)
+
+			value, err := parseJSONValue(decoder)
+			if err != nil {
+				return jsonValue{}, err
+			}
+
+			object[key] = value
+		}
+
+		// consume the closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return jsonValue{}, fmt.Errorf("read json: %w", err)
+		}
+
+		return jsonValue{value: object}, nil
+
+	case json.Delim('['):
+		var array []jsonValue
+
+		for decoder.More() {
+			value, err := parseJSONValue(decoder)
+			if err != nil {
+				return jsonValue{}, err
+			}
+
+			array = append(array, value)
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return jsonValue{}, fmt.Errorf("read json: %w", err)
+		}
+
+		// an empty array is not nil
+		if array == nil {
+			array = []jsonValue{}
+		}
+
+		return jsonValue{value: array}, nil
+
+	default:
+		// a string, json.Number, bool or nil
+		return jsonValue{value: token}, nil
+	}
+}
+
+// jsonValue is a SourceValue of a parsed json value. value is either nil,
+// a bool, a string, a json.Number, a []jsonValue or a map[string]jsonValue.
+type jsonValue struct {
+	value any
+}
+
+var _ IntSourceValue = jsonValue{}
+var _ ContainerSourceValue = jsonValue{}
+var _ SliceSourceValue = jsonValue{}
+var _ MapSourceValue = jsonValue{}
+
+func (j jsonValue) Bool() (bool, error) {
+	value, ok := j.value.(bool)
+	if !ok {
+		return false, ErrInvalidType
+	}
+
+	return value, nil
+}
+
+// number returns the number as StringValue to reuse its parsing.
+func (j jsonValue) number() (StringValue, error) {
+	value, ok := j.value.(json.Number)
+	if !ok {
+		return "", ErrInvalidType
+	}
+
+	return StringValue(value), nil
+}
+
+func (j jsonValue) Int() (int64, error) {
+	number, err := j.number()
+	if err != nil {
+		return 0, err
+	}
+
+	return number.Int()
+}
+
+func (j jsonValue) Float() (float64, error) {
+	number, err := j.number()
+	if err != nil {
+		return 0, err
+	}
+
+	return number.Float()
+}
+
+func (j jsonValue) String() (string, error) {
+	value, ok := j.value.(string)
+	if !ok {
+		return "", ErrInvalidType
+	}
+
+	return value, nil
+}
+
+func (j jsonValue) Int8() (int8, error) {
+	return withNumber(j, StringValue.Int8)
+}
+
+func (j jsonValue) Int16() (int16, error) {
+	return withNumber(j, StringValue.Int16)
+}
+
+func (j jsonValue) Int32() (int32, error) {
+	return withNumber(j, StringValue.Int32)
+}
+
+func (j jsonValue) Int64() (int64, error) {
+	return withNumber(j, StringValue.Int64)
+}
+
+func (j jsonValue) Uint8() (uint8, error) {
+	return withNumber(j, StringValue.Uint8)
+}
+
+func (j jsonValue) Uint16() (uint16, error) {
+	return withNumber(j, StringValue.Uint16)
+}
+
+func (j jsonValue) Uint32() (uint32, error) {
+	return withNumber(j, StringValue.Uint32)
+}
+
+func (j jsonValue) Uint64() (uint64, error) {
+	return withNumber(j, StringValue.Uint64)
+}
+
+func withNumber[T any](j jsonValue, parse func(StringValue) (T, error)) (T, error) {
+	number, err := j.number()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return parse(number)
+}
+
+func (j jsonValue) Get(key string) (SourceValue, error) {
+	switch value := j.value.(type) {
+	case nil:
+		// null has no children, the fields of the target keep their values
+		return nil, ErrNoValue
+
+	case map[string]jsonValue:
+		child, ok := value[key]
+		if !ok || child.value == nil {
+			return nil, ErrNoValue
+		}
+
+		return child, nil
+
+	default:
+		return nil, ErrInvalidType
+	}
+}
+
+func (j jsonValue) Iter() (iter.Seq[SourceValue], error) {
+	array, ok := j.value.([]jsonValue)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+
+	it := func(yield func(SourceValue) bool) {
+		for _, element := range array {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (j jsonValue) KeyValues() (iter.Seq2[SourceValue, SourceValue], error) {
+	object, ok := j.value.(map[string]jsonValue)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+
+	it := func(yield func(SourceValue, SourceValue) bool) {
+		for key, value := range object {
+			if !yield(StringValue(key), value) {
+				return
+			}
+		}
+	}
+
+	return it, nil
+}
@@ -0,0 +1,77 @@
+package serde
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseJSON(t *testing.T) {
+	type Address struct {
+		City string `json:"city" norm:"trim"`
+	}
+
+	type Person struct {
+		Name    string            `json:"name" norm:"trim,lower"`
+		Age     uint8             `json:"age"`
+		Score   float64           `json:"score"`
+		Active  bool              `json:"active"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]int    `json:"labels"`
+		Address *Address          `json:"address"`
+		Missing string            `json:"missing"`
+		Null    map[string]string `json:"null"`
+	}
+
+	source, err := ParseJSON(strings.NewReader(`{
+		"name": " ALBERT ",
+		"age": 76,
+		"score": 1.5e2,
+		"active": true,
+		"tags": ["physics", "violin"],
+		"labels": {"a": 1, "b": 2},
+		"address": {"city": " Ulm "},
+		"null": null,
+		"unknown": [1, {"nested": true}]
+	}`))
+	AssertEqual(t, err, nil)
+
+	person, err := UnmarshalNew[Person](source)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, person, Person{
+		Name:    "albert",
+		Age:     76,
+		Score:   150,
+		Active:  true,
+		Tags:    []string{"physics", "violin"},
+		Labels:  map[string]int{"a": 1, "b": 2},
+		Address: &Address{City: "Ulm"},
+	})
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	type Person struct {
+		Age uint8 `json:"age"`
+	}
+
+	_, err := ParseJSON(strings.NewReader(`{"age": `))
+	AssertTrue(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	_, err = ParseJSON(strings.NewReader(``))
+	AssertTrue(t, err != nil)
+
+	source, _ := ParseJSON(strings.NewReader(`{"age": "76"}`))
+	_, err = UnmarshalNew[Person](source)
+	AssertTrue(t, errors.Is(err, ErrInvalidType))
+
+	source, _ = ParseJSON(strings.NewReader(`{"age": 300}`))
+	_, err = UnmarshalNew[Person](source)
+	AssertTrue(t, err != nil)
+
+	source, _ = ParseJSON(strings.NewReader(`null`))
+	person, err := UnmarshalNew[Person](source)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, person, Person{})
+}
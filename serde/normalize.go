@@ -0,0 +1,146 @@
+package serde
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Normalizer transforms a string before it is set on a field.
+type Normalizer func(value string) string
+
+var normalizers = struct {
+	sync.RWMutex
+	byName map[string]Normalizer
+}{
+	byName: map[string]Normalizer{
+		"trim":     strings.TrimSpace,
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+		"collapse": collapseSpaces,
+	},
+}
+
+// RegisterNormalizer registers a Normalizer that can be referenced by name in a norm
+// struct tag. Fields are normalized when a string is unmarshalled into them, before the
+// value is parsed, e.g. into a TextUnmarshaler:
+//
+//	type Signup struct {
+//		Email string `json:"email" norm:"trim,lower"`
+//	}
+//
+// The built-in normalizers are trim, lower, upper and collapse, which replaces
+// runs of whitespace by a single space. Register normalizers in an init function,
+// as the tags of a type are resolved once, on its first use.
+func RegisterNormalizer(name string, normalizer Normalizer) {
+	normalizers.Lock()
+	defer normalizers.Unlock()
+
+	normalizers.byName[name] = normalizer
+}
+
+// normalizerOf combines the normalizers listed in the norm tag, or returns nil for an empty tag.
+func normalizerOf(tag string) (Normalizer, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	normalizers.RLock()
+	defer normalizers.RUnlock()
+
+	var chain []Normalizer
+	for _, name := range strings.Split(tag, ",") {
+		normalizer, ok := normalizers.byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown normalizer %q", name)
+		}
+
+		chain = append(chain, normalizer)
+	}
+
+	return func(value string) string {
+		for _, normalizer := range chain {
+			value = normalizer(value)
+		}
+
+		return value
+	}, nil
+}
+
+// normalizingSetter wraps the setter of a field, so it sees normalized strings.
+// Only strings, text unmarshalers and slices or arrays of them can be normalized.
+func normalizingSetter(ty reflect.Type, normalizer Normalizer, setter setter) (setter, error) {
+	if !isNormalizable(ty) {
+		return nil, fmt.Errorf("norm is not supported on %s", ty)
+	}
+
+	return func(source SourceValue, target reflect.Value) error {
+		return setter(normalizeSource(source, normalizer), target)
+	}, nil
+}
+
+func isNormalizable(ty reflect.Type) bool {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	switch {
+	case reflect.PointerTo(ty).Implements(tyTextUnmarshaler), ty.Kind() == reflect.String:
+		return true
+
+	case ty.Kind() == reflect.Slice, ty.Kind() == reflect.Array:
+		return isNormalizable(ty.Elem())
+
+	default:
+		return false
+	}
+}
+
+func normalizeSource(source SourceValue, normalizer Normalizer) SourceValue {
+	if slice, ok := source.(SliceSourceValue); ok {
+		return normalizedSliceSource{normalizedSource{slice, normalizer}, slice}
+	}
+
+	return normalizedSource{source, normalizer}
+}
+
+type normalizedSource struct {
+	SourceValue
+	normalizer Normalizer
+}
+
+func (n normalizedSource) String() (string, error) {
+	value, err := n.SourceValue.String()
+	if err != nil {
+		return "", err
+	}
+
+	return n.normalizer(value), nil
+}
+
+type normalizedSliceSource struct {
+	normalizedSource
+	slice SliceSourceValue
+}
+
+func (n normalizedSliceSource) Iter() (iter.Seq[SourceValue], error) {
+	values, err := n.slice.Iter()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(SourceValue) bool) {
+		for value := range values {
+			if !yield(normalizeSource(value, n.normalizer)) {
+				return
+			}
+		}
+	}, nil
+}
+
+func collapseSpaces(value string) string {
+	return strings.Join(strings.FieldsFunc(value, unicode.IsSpace), " ")
+}
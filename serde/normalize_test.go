@@ -0,0 +1,59 @@
+package serde
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	RegisterNormalizer("strip-dashes", func(value string) string {
+		return strings.ReplaceAll(value, "-", "")
+	})
+
+	type Signup struct {
+		Email string   `json:"email" norm:"trim,lower"`
+		Name  *string  `json:"name" norm:"collapse"`
+		Phone string   `json:"phone" norm:"strip-dashes"`
+		Tags  []string `json:"tags" norm:"upper"`
+		IP    net.IP   `json:"ip" norm:"trim"`
+		Raw   string   `json:"raw"`
+	}
+
+	sourceValue := dummySourceValue{
+		Values: map[string]any{
+			".email": "  Albert@Example.COM ",
+			".name":  " Albert \t Einstein ",
+			".phone": "555-12-34",
+			".tags":  []string{"a", "b"},
+			".ip":    " 10.0.0.1 ",
+			".raw":   " untouched ",
+		},
+	}
+
+	value, err := UnmarshalNew[Signup](sourceValue)
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value.Email, "albert@example.com")
+	AssertEqual(t, *value.Name, "Albert Einstein")
+	AssertEqual(t, value.Phone, "5551234")
+	AssertEqual(t, value.Tags, []string{"A", "B"})
+	AssertEqual(t, value.IP.String(), "10.0.0.1")
+	AssertEqual(t, value.Raw, " untouched ")
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	type Unknown struct {
+		Name string `norm:"shout"`
+	}
+
+	_, err := UnmarshalNew[Unknown](dummySourceValue{})
+	AssertTrue(t, err != nil)
+
+	type NotAString struct {
+		Age int `norm:"trim"`
+	}
+
+	_, err = UnmarshalNew[NotAString](dummySourceValue{})
+	AssertTrue(t, err != nil)
+}
@@ -0,0 +1,148 @@
+package serde
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// structField is a field of a struct together with its compiled setter.
+// With the build tag serde_unsafe, fields are addressed using their precomputed
+// offset instead of reflect.Value.FieldByIndex, and fields of the primitive kinds
+// string, bool, int and float64 are written using typed stores instead of reflection.
+type structField struct {
+	field
+
+	// offset of the field relative to the start of the outermost struct
+	offset uintptr
+
+	set   setter
+	store unsafeStore
+}
+
+// unsafeStore writes a value from source directly to the memory at ptr.
+type unsafeStore func(source SourceValue, ptr unsafe.Pointer) error
+
+func newStructField(ty reflect.Type, field field, set setter) structField {
+	sf := structField{field: field, set: set}
+
+	if useUnsafeFields {
+		sf.offset = fieldOffset(ty, field.Index)
+		sf.store = primitiveStoreOf(field)
+	}
+
+	return sf
+}
+
+// baseOf returns the address of the struct target if the fields can be accessed by their
+// offsets, or nil if reflection must be used.
+func baseOf(target reflect.Value) unsafe.Pointer {
+	if !useUnsafeFields || !target.CanAddr() {
+		return nil
+	}
+
+	return target.Addr().UnsafePointer()
+}
+
+// apply sets the field of target from the source. base is the result of baseOf(target).
+func (sf *structField) apply(source SourceValue, target reflect.Value, base unsafe.Pointer) error {
+	if base == nil {
+		return sf.set(source, target.FieldByIndex(sf.Index))
+	}
+
+	ptr := unsafe.Add(base, sf.offset)
+
+	if sf.store != nil {
+		return sf.store(source, ptr)
+	}
+
+	return sf.set(source, reflect.NewAt(sf.Type, ptr).Elem())
+}
+
+// fieldOffset sums up the offsets of the fields along the index. fieldsToSerialize
+// only follows embedded structs, never pointers, so all fields share the same memory block.
+func fieldOffset(ty reflect.Type, index []int) uintptr {
+	var offset uintptr
+
+	for _, idx := range index {
+		fi := ty.Field(idx)
+		offset += fi.Offset
+		ty = fi.Type
+	}
+
+	return offset
+}
+
+// primitiveStoreOf returns a typed store for fields of a primitive kind. Returns nil if the
+// field needs the full setter, e.g. because its type implements encoding.TextUnmarshaler
+// or Enum, or its tags request normalization or validation.
+func primitiveStoreOf(field field) unsafeStore {
+	ty := field.Type
+
+	if reflect.PointerTo(ty).Implements(tyTextUnmarshaler) || enumValuesOfType(ty) != nil {
+		return nil
+	}
+
+	for _, tag := range []string{"norm", "enum", "errmsg"} {
+		if _, ok := field.Tag.Lookup(tag); ok {
+			return nil
+		}
+	}
+
+	switch ty.Kind() {
+	case reflect.String:
+		return storeString
+
+	case reflect.Bool:
+		return storeBool
+
+	case reflect.Int:
+		return storeInt
+
+	case reflect.Float64:
+		return storeFloat64
+
+	default:
+		return nil
+	}
+}
+
+func storeString(source SourceValue, ptr unsafe.Pointer) error {
+	value, err := source.String()
+	if err != nil {
+		return fmt.Errorf("get string value: %w", err)
+	}
+
+	*(*string)(ptr) = value
+	return nil
+}
+
+func storeBool(source SourceValue, ptr unsafe.Pointer) error {
+	value, err := source.Bool()
+	if err != nil {
+		return fmt.Errorf("get bool value: %w", err)
+	}
+
+	*(*bool)(ptr) = value
+	return nil
+}
+
+func storeInt(source SourceValue, ptr unsafe.Pointer) error {
+	value, err := source.Int()
+	if err != nil {
+		return fmt.Errorf("get int value: %w", err)
+	}
+
+	*(*int)(ptr) = int(value)
+	return nil
+}
+
+func storeFloat64(source SourceValue, ptr unsafe.Pointer) error {
+	value, err := source.Float()
+	if err != nil {
+		return fmt.Errorf("get float value: %w", err)
+	}
+
+	*(*float64)(ptr) = value
+	return nil
+}
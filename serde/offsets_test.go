@@ -0,0 +1,137 @@
+package serde
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"reflect"
+	"testing"
+)
+
+type BenchAccount struct {
+	Score float64 `json:"score"`
+	Admin bool    `json:"admin"`
+}
+
+type BenchPerson struct {
+	BenchAccount
+
+	Name  string   `json:"name"`
+	Email string   `json:"email" norm:"lower"`
+	Age   int      `json:"age"`
+	Tags  []string `json:"tags"`
+}
+
+func TestFieldOffset(t *testing.T) {
+	ty := reflect.TypeFor[BenchPerson]()
+
+	field, _ := ty.FieldByName("Score")
+	AssertEqual(t, fieldOffset(ty, field.Index), reflect.TypeFor[BenchAccount]().Field(0).Offset)
+
+	field, _ = ty.FieldByName("Age")
+	AssertEqual(t, fieldOffset(ty, field.Index), field.Offset)
+}
+
+func TestStructFieldStores(t *testing.T) {
+	value, err := UnmarshalNew[BenchPerson](dummySourceValue{Values: map[string]any{
+		".name":  "Albert",
+		".email": "ALBERT@EXAMPLE.COM",
+		".age":   int64(76),
+		".score": 1.5,
+		".admin": nil,
+		".tags":  []string{"physics"},
+	}})
+
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, BenchPerson{
+		BenchAccount: BenchAccount{Score: 1.5},
+		Name:         "Albert",
+		Email:        "albert@example.com",
+		Age:          76,
+		Tags:         []string{"physics"},
+	})
+}
+
+// staticSource is a cheap SourceValue, so the benchmark measures the setters, not the source.
+type staticSource struct {
+	value    any
+	children map[string]*staticSource
+}
+
+func (s *staticSource) Bool() (bool, error) {
+	value, ok := s.value.(bool)
+	if !ok {
+		return false, ErrInvalidType
+	}
+
+	return value, nil
+}
+
+func (s *staticSource) Int() (int64, error) {
+	value, ok := s.value.(int64)
+	if !ok {
+		return 0, ErrInvalidType
+	}
+
+	return value, nil
+}
+
+func (s *staticSource) Float() (float64, error) {
+	value, ok := s.value.(float64)
+	if !ok {
+		return 0, ErrInvalidType
+	}
+
+	return value, nil
+}
+
+func (s *staticSource) String() (string, error) {
+	value, ok := s.value.(string)
+	if !ok {
+		return "", ErrInvalidType
+	}
+
+	return value, nil
+}
+
+func (s *staticSource) Get(key string) (SourceValue, error) {
+	child, ok := s.children[key]
+	if !ok {
+		return nil, ErrNoValue
+	}
+
+	return child, nil
+}
+
+// Compare the reflection based field access with the unsafe one using
+//
+//	go test -run - -bench StructSetter ./serde
+//	go test -run - -bench StructSetter -tags serde_unsafe ./serde
+func BenchmarkStructSetter(b *testing.B) {
+	source := &staticSource{children: map[string]*staticSource{
+		"name":  {value: "Albert"},
+		"email": {value: "albert@example.com"},
+		"age":   {value: int64(76)},
+		"score": {value: 1.5},
+		"admin": {value: true},
+	}}
+
+	type Person struct {
+		BenchAccount
+
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Age   int    `json:"age"`
+	}
+
+	decoder, err := For[Person]()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+
+	for range b.N {
+		if _, err := decoder.Decode(source); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package serde
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var tyTextMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+
+// MarshalValues flattens a struct into a map of string values. This is the inverse
+// of decoding query or path values: Field names are resolved the same way as
+// during unmarshalling, slices and arrays produce one string per element.
+//
+// Supported field types are bools, numbers, strings, types implementing encoding.TextMarshaler
+// as well as pointers, slices and arrays of them. Nil pointers are skipped.
+func MarshalValues(value any) (map[string][]string, error) {
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return nil, nil
+		}
+
+		rValue = rValue.Elem()
+	}
+
+	if rValue.Kind() != reflect.Struct {
+		return nil, NotSupportedError{Type: rValue.Type()}
+	}
+
+	result := map[string][]string{}
+
+	for _, field := range fieldsToSerialize(rValue.Type()) {
+		fieldValue, err := rValue.FieldByIndexErr(field.Index)
+		if err != nil {
+			// field is within a nil embedded pointer
+			continue
+		}
+
+		values, err := stringsOf(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("marshal field %q: %w", field.Name, err)
+		}
+
+		if len(values) > 0 {
+			result[field.Name] = values
+		}
+	}
+
+	return result, nil
+}
+
+func stringsOf(value reflect.Value) ([]string, error) {
+	if value.Type().Implements(tyTextMarshaler) {
+		if value.Kind() == reflect.Pointer && value.IsNil() {
+			return nil, nil
+		}
+
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{string(text)}, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil, nil
+		}
+
+		return stringsOf(value.Elem())
+
+	case reflect.Slice, reflect.Array:
+		var result []string
+		for idx := range value.Len() {
+			element, err := stringOf(value.Index(idx))
+			if err != nil {
+				return nil, fmt.Errorf("element idx=%d: %w", idx, err)
+			}
+
+			result = append(result, element)
+		}
+
+		return result, nil
+
+	default:
+		element, err := stringOf(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{element}, nil
+	}
+}
+
+func stringOf(value reflect.Value) (string, error) {
+	if value.Type().Implements(tyTextMarshaler) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32), nil
+
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64), nil
+
+	case reflect.Complex64:
+		return strconv.FormatComplex(value.Complex(), 'g', -1, 64), nil
+
+	case reflect.Complex128:
+		return strconv.FormatComplex(value.Complex(), 'g', -1, 128), nil
+
+	case reflect.String:
+		return value.String(), nil
+
+	default:
+		return "", NotSupportedError{Type: value.Type()}
+	}
+}
@@ -0,0 +1,49 @@
+package serde
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net"
+	"testing"
+)
+
+func TestMarshalValues(t *testing.T) {
+	type Embedded struct {
+		Page int `json:"page"`
+	}
+
+	type Struct struct {
+		Embedded
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+		Height  float64
+		Active  bool
+		IP      net.IP
+		Missing *string
+		Skip    string `json:"-"`
+	}
+
+	values, err := MarshalValues(Struct{
+		Embedded: Embedded{Page: 2},
+		Name:     "Albert",
+		Tags:     []string{"foo", "bar"},
+		Height:   1.76,
+		Active:   true,
+		IP:       net.IPv4(127, 0, 0, 1),
+		Skip:     "skipped",
+	})
+
+	AssertEqual(t, err, nil)
+	AssertEqual(t, values, map[string][]string{
+		"page":   {"2"},
+		"name":   {"Albert"},
+		"tags":   {"foo", "bar"},
+		"Height": {"1.76"},
+		"Active": {"true"},
+		"IP":     {"127.0.0.1"},
+	})
+}
+
+func TestMarshalValuesNotSupported(t *testing.T) {
+	_, err := MarshalValues(struct{ Values map[string]string }{})
+	AssertTrue(t, err != nil)
+}
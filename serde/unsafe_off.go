@@ -0,0 +1,7 @@
+//go:build !serde_unsafe
+
+package serde
+
+// useUnsafeFields enables offset based field access and typed stores in struct setters.
+// Build with the tag serde_unsafe to enable it.
+const useUnsafeFields = false
@@ -0,0 +1,6 @@
+//go:build serde_unsafe
+
+package serde
+
+// useUnsafeFields enables offset based field access and typed stores in struct setters.
+const useUnsafeFields = true
@@ -7,62 +7,210 @@ import (
 	"strings"
 )
 
-func handleNumberErr[T any](inputValue string, value T, err error) (T, error) {
-	var zeroValue T
-	if errors.Is(err, strconv.ErrSyntax) {
-		err := fmt.Errorf("parse number %q: %w", inputValue, err)
-		return zeroValue, errors.Join(err, ErrInvalidType)
+// numberError is returned if a StringValue can not be parsed as a number. It wraps
+// strconv.ErrSyntax or strconv.ErrRange and, if invalidType is set, ErrInvalidType.
+// Creating it is the only allocation when parsing fails.
+type numberError struct {
+	input       string
+	err         error
+	invalidType bool
+}
+
+func (e *numberError) Error() string {
+	return fmt.Sprintf("parse number %q: %s", e.input, e.err)
+}
+
+func (e *numberError) Unwrap() []error {
+	if e.invalidType {
+		return []error{e.err, ErrInvalidType}
+	}
+
+	return []error{e.err}
+}
+
+// syntaxError marks the input as invalid number, which is also an invalid type.
+func syntaxError(input string) error {
+	return &numberError{input: input, err: strconv.ErrSyntax, invalidType: true}
+}
+
+// rangeError marks the input as a number that does not fit into the target type.
+func rangeError(input string) error {
+	return &numberError{input: input, err: strconv.ErrRange}
+}
+
+// parseDigits parses a string of up to 19 ascii digits, which always fits into an uint64.
+// Returns false if the string is empty, too long or contains anything else than digits.
+func parseDigits(s string) (uint64, bool) {
+	if len(s) == 0 || len(s) > 19 {
+		return 0, false
+	}
+
+	var value uint64
+	for idx := 0; idx < len(s); idx++ {
+		ch := s[idx]
+		if ch < '0' || ch > '9' {
+			return 0, false
+		}
+
+		value = value*10 + uint64(ch-'0')
+	}
+
+	return value, true
+}
+
+// isDigits reports whether s is a non-empty string of ascii digits.
+func isDigits(s string) bool {
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] < '0' || s[idx] > '9' {
+			return false
+		}
+	}
+
+	return len(s) > 0
+}
+
+// parseInt parses a base 10 integer with the given bit size like strconv.ParseInt,
+// without allocating for common inputs.
+func parseInt(s string, bitSize int) (int64, error) {
+	digits, negative := s, false
+	if len(digits) > 0 && (digits[0] == '+' || digits[0] == '-') {
+		digits, negative = digits[1:], digits[0] == '-'
+	}
+
+	value, ok := parseDigits(digits)
+	if !ok {
+		if !isDigits(digits) {
+			return 0, syntaxError(s)
+		}
+
+		// too many digits for the fast path
+		return parseIntSlow(s, bitSize)
+	}
+
+	limit := uint64(1) << (bitSize - 1)
+
+	switch {
+	case !negative && value < limit:
+		return int64(value), nil
+
+	case negative && value <= limit:
+		return -int64(value), nil
+
+	default:
+		return 0, rangeError(s)
+	}
+}
+
+func parseIntSlow(s string, bitSize int) (int64, error) {
+	value, err := strconv.ParseInt(s, 10, bitSize)
+	if err != nil {
+		return 0, convertNumError(s, err)
+	}
+
+	return value, nil
+}
+
+// parseUint parses a base 10 unsigned integer with the given bit size like
+// strconv.ParseUint, without allocating for common inputs.
+func parseUint(s string, bitSize int) (uint64, error) {
+	value, ok := parseDigits(s)
+	if !ok {
+		if !isDigits(s) {
+			return 0, syntaxError(s)
+		}
+
+		// too many digits for the fast path
+		value, err := strconv.ParseUint(s, 10, bitSize)
+		if err != nil {
+			return 0, convertNumError(s, err)
+		}
+
+		return value, nil
+	}
+
+	if bitSize < 64 && value >= uint64(1)<<bitSize {
+		return 0, rangeError(s)
 	}
 
+	return value, nil
+}
+
+// parseFloat parses a float64. Plain integers are parsed without strconv.
+func parseFloat(s string) (float64, error) {
+	if value, ok := parseDigits(s); ok && value <= 1<<53 {
+		// every integer up to 2^53 is exactly representable as float64
+		return float64(value), nil
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		return zeroValue, err
+		return 0, convertNumError(s, err)
 	}
 
 	return value, nil
 }
 
+func convertNumError(s string, err error) error {
+	switch {
+	case errors.Is(err, strconv.ErrSyntax):
+		return syntaxError(s)
+	case errors.Is(err, strconv.ErrRange):
+		return rangeError(s)
+	default:
+		return err
+	}
+}
+
+// asInvalidType marks errors of the generic Int and Float accessors as ErrInvalidType.
+func asInvalidType(err error) error {
+	if numErr, ok := err.(*numberError); ok {
+		numErr.invalidType = true
+		return numErr
+	}
+
+	return errors.Join(ErrInvalidType, err)
+}
+
 type StringValue string
 
 var _ IntSourceValue = StringValue("")
 
 func (s StringValue) Int8() (int8, error) {
-	intValue, err := strconv.ParseInt(string(s), 10, 8)
-	return handleNumberErr(string(s), int8(intValue), err)
+	intValue, err := parseInt(string(s), 8)
+	return int8(intValue), err
 }
 
 func (s StringValue) Int16() (int16, error) {
-	intValue, err := strconv.ParseInt(string(s), 10, 16)
-	return handleNumberErr(string(s), int16(intValue), err)
+	intValue, err := parseInt(string(s), 16)
+	return int16(intValue), err
 }
 
 func (s StringValue) Int32() (int32, error) {
-	intValue, err := strconv.ParseInt(string(s), 10, 32)
-	return handleNumberErr(string(s), int32(intValue), err)
+	intValue, err := parseInt(string(s), 32)
+	return int32(intValue), err
 }
 
 func (s StringValue) Int64() (int64, error) {
-	intValue, err := strconv.ParseInt(string(s), 10, 64)
-	return handleNumberErr(string(s), intValue, err)
+	return parseInt(string(s), 64)
 }
 
 func (s StringValue) Uint8() (uint8, error) {
-	intValue, err := strconv.ParseUint(string(s), 10, 8)
-	return handleNumberErr(string(s), uint8(intValue), err)
+	intValue, err := parseUint(string(s), 8)
+	return uint8(intValue), err
 }
 
 func (s StringValue) Uint16() (uint16, error) {
-	intValue, err := strconv.ParseUint(string(s), 10, 16)
-	return handleNumberErr(string(s), uint16(intValue), err)
+	intValue, err := parseUint(string(s), 16)
+	return uint16(intValue), err
 }
 
 func (s StringValue) Uint32() (uint32, error) {
-	intValue, err := strconv.ParseUint(string(s), 10, 32)
-	return handleNumberErr(string(s), uint32(intValue), err)
+	intValue, err := parseUint(string(s), 32)
+	return uint32(intValue), err
 }
 
 func (s StringValue) Uint64() (uint64, error) {
-	intValue, err := strconv.ParseUint(string(s), 10, 64)
-	return handleNumberErr(string(s), intValue, err)
+	return parseUint(string(s), 64)
 }
 
 func (s StringValue) Bool() (bool, error) {
@@ -77,18 +225,18 @@ func (s StringValue) Bool() (bool, error) {
 }
 
 func (s StringValue) Int() (int64, error) {
-	parsedValue, err := strconv.ParseInt(string(s), 10, 64)
+	parsedValue, err := parseInt(string(s), 64)
 	if err != nil {
-		return 0, errors.Join(ErrInvalidType, err)
+		return 0, asInvalidType(err)
 	}
 
 	return parsedValue, nil
 }
 
 func (s StringValue) Float() (float64, error) {
-	parsedValue, err := strconv.ParseFloat(string(s), 64)
+	parsedValue, err := parseFloat(string(s))
 	if err != nil {
-		return 0, errors.Join(ErrInvalidType, err)
+		return 0, asInvalidType(err)
 	}
 
 	return parsedValue, nil
@@ -0,0 +1,103 @@
+package serde
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestStringValueInt(t *testing.T) {
+	inputs := []string{
+		"0", "-0", "+0", "1", "-1", "+42", "007",
+		"127", "128", "-128", "-129", "32767", "32768", "-32769",
+		"2147483647", "2147483648", "-2147483649",
+		"9223372036854775807", "9223372036854775808", "-9223372036854775808", "-9223372036854775809",
+		"99999999999999999999", "", "+", "-", "1_000", "0x10", "1.5", " 1", "abc",
+	}
+
+	for _, input := range inputs {
+		for _, bitSize := range []int{8, 16, 32, 64} {
+			expected, expectedErr := strconv.ParseInt(input, 10, bitSize)
+			actual, err := parseInt(input, bitSize)
+
+			AssertEqual(t, err != nil, expectedErr != nil)
+
+			if expectedErr == nil {
+				AssertEqual(t, actual, expected)
+			} else {
+				AssertTrue(t, errors.Is(err, strconv.ErrSyntax) == errors.Is(expectedErr, strconv.ErrSyntax))
+				AssertTrue(t, errors.Is(err, strconv.ErrRange) == errors.Is(expectedErr, strconv.ErrRange))
+			}
+		}
+	}
+}
+
+func TestStringValueUint(t *testing.T) {
+	inputs := []string{
+		"0", "1", "255", "256", "65535", "65536", "4294967295", "4294967296",
+		"18446744073709551615", "18446744073709551616", "-1", "+1", "", "abc",
+	}
+
+	for _, input := range inputs {
+		for _, bitSize := range []int{8, 16, 32, 64} {
+			expected, expectedErr := strconv.ParseUint(input, 10, bitSize)
+			actual, err := parseUint(input, bitSize)
+
+			AssertEqual(t, err != nil, expectedErr != nil)
+
+			if expectedErr == nil {
+				AssertEqual(t, actual, expected)
+			} else {
+				AssertTrue(t, errors.Is(err, strconv.ErrRange) == errors.Is(expectedErr, strconv.ErrRange))
+			}
+		}
+	}
+}
+
+func TestStringValueErrors(t *testing.T) {
+	_, err := StringValue("abc").Int8()
+	AssertTrue(t, errors.Is(err, ErrInvalidType))
+	AssertTrue(t, errors.Is(err, strconv.ErrSyntax))
+	AssertEqual(t, err.Error(), `parse number "abc": invalid syntax`)
+
+	_, err = StringValue("300").Int8()
+	AssertTrue(t, !errors.Is(err, ErrInvalidType))
+	AssertTrue(t, errors.Is(err, strconv.ErrRange))
+
+	_, err = StringValue("99999999999999999999").Int()
+	AssertTrue(t, errors.Is(err, ErrInvalidType))
+
+	_, err = StringValue("1.5x").Float()
+	AssertTrue(t, errors.Is(err, ErrInvalidType))
+
+	value, err := StringValue("9007199254740993").Float()
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, float64(9007199254740993))
+
+	value, err = StringValue("-1.5e3").Float()
+	AssertEqual(t, err, nil)
+	AssertEqual(t, value, -1500.0)
+
+	intValue, err := StringValue("-9223372036854775808").Int()
+	AssertEqual(t, err, nil)
+	AssertEqual(t, intValue, int64(math.MinInt64))
+}
+
+func TestStringValueAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = StringValue("12345").Int()
+		_, _ = StringValue("-42").Int32()
+		_, _ = StringValue("255").Uint8()
+		_, _ = StringValue("1024").Float()
+	})
+
+	AssertEqual(t, allocs, 0.0)
+
+	allocs = testing.AllocsPerRun(100, func() {
+		_, _ = StringValue("abc").Int()
+	})
+
+	AssertEqual(t, allocs, 1.0)
+}
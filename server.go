@@ -0,0 +1,241 @@
+package gum
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Server runs a http.Server until its context is canceled or the process receives
+// a shutdown signal. On shutdown, the server stops accepting new connections and
+// waits for in-flight requests to finish before calling the OnShutdown hooks:
+//
+//	server := gum.NewServer(":8080", mux,
+//		gum.OnShutdown(func(ctx context.Context) error { return db.Close() }),
+//	)
+//
+//	if err := server.Run(context.Background()); err != nil {
+//		log.Fatal(err)
+//	}
+type Server struct {
+	server          *http.Server
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+	onStart         []func(ctx context.Context) error
+	onShutdown      []func(ctx context.Context) error
+	inFlight        *InFlight
+
+	mu       sync.Mutex
+	listener net.Listener
+}
+
+// ServerOption configures a Server.
+type ServerOption func(s *Server)
+
+// NewServer creates a new Server serving handler on the given address.
+// By default, the server shuts down on SIGINT and SIGTERM, waits up to 30 seconds for
+// in-flight requests and uses a ReadHeaderTimeout of 10 seconds.
+func NewServer(addr string, handler http.Handler, options ...ServerOption) *Server {
+	s := &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		shutdownTimeout: 30 * time.Second,
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// ServerTimeouts sets the read, write and idle timeouts of the underlying http.Server.
+// A zero value keeps the timeout of the http.Server unchanged.
+func ServerTimeouts(read, write, idle time.Duration) ServerOption {
+	return func(s *Server) {
+		if read > 0 {
+			s.server.ReadTimeout = read
+		}
+
+		if write > 0 {
+			s.server.WriteTimeout = write
+		}
+
+		if idle > 0 {
+			s.server.IdleTimeout = idle
+		}
+	}
+}
+
+// ServerReadHeaderTimeout sets the ReadHeaderTimeout of the underlying http.Server.
+func ServerReadHeaderTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.server.ReadHeaderTimeout = timeout
+	}
+}
+
+// ServerTLS serves https using the given tls.Config. The config
+// must provide the certificates, e.g. using GetCertificate.
+func ServerTLS(config *tls.Config) ServerOption {
+	return func(s *Server) {
+		s.server.TLSConfig = config
+	}
+}
+
+// ServerShutdownTimeout sets how long the server waits for in-flight
+// requests to finish during shutdown.
+func ServerShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// ServerSignals sets the signals that trigger a graceful shutdown.
+// Pass no signals to only shut down when the context of Run is canceled.
+func ServerSignals(signals ...os.Signal) ServerOption {
+	return func(s *Server) {
+		s.signals = signals
+	}
+}
+
+// OnStart adds a hook that is called after the server started listening and before
+// it accepts requests. If a hook fails, the server is not started and Run returns the error.
+func OnStart(hook func(ctx context.Context) error) ServerOption {
+	return func(s *Server) {
+		s.onStart = append(s.onStart, hook)
+	}
+}
+
+// OnShutdown adds a hook that is called after all in-flight requests are finished,
+// e.g. to close database connections. Hooks are called in reverse order of registration.
+func OnShutdown(hook func(ctx context.Context) error) ServerOption {
+	return func(s *Server) {
+		s.onShutdown = append(s.onShutdown, hook)
+	}
+}
+
+// ServerInFlight tracks the requests of the server using the given InFlight. On shutdown,
+// the server rejects new requests and waits for all tracked requests to finish,
+// including requests on hijacked connections, before calling the OnShutdown hooks.
+func ServerInFlight(inFlight *InFlight) ServerOption {
+	return func(s *Server) {
+		s.inFlight = inFlight
+		s.server.Handler = inFlight.Middleware()(s.server.Handler)
+	}
+}
+
+// Addr returns the address the server listens on, or nil if the server was not started yet.
+// Useful to get the actual port when listening on port 0.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// Run starts the server and blocks until ctx is canceled, a shutdown signal is received
+// or the server fails. It then shuts down the server gracefully and runs the OnShutdown hooks.
+// Run returns nil after a graceful shutdown.
+func (s *Server) Run(ctx context.Context) error {
+	if len(s.signals) > 0 {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, s.signals...)
+		defer stop()
+	}
+
+	listener, err := net.Listen("tcp", s.listenAddr())
+	if err != nil {
+		return fmt.Errorf("listen on %q: %w", s.server.Addr, err)
+	}
+
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	for _, hook := range s.onStart {
+		if err := hook(ctx); err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("start hook: %w", err)
+		}
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		if s.server.TLSConfig != nil {
+			serveErr <- s.server.ServeTLS(listener, "", "")
+		} else {
+			serveErr <- s.server.Serve(listener)
+		}
+	}()
+
+	var errs []error
+
+	select {
+	case err := <-serveErr:
+		// the server failed before we requested a shutdown
+		errs = append(errs, fmt.Errorf("serve: %w", err))
+
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+		defer cancel()
+
+		if s.inFlight != nil {
+			s.inFlight.Shutdown()
+		}
+
+		if err := s.server.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown: %w", err))
+		}
+
+		if s.inFlight != nil {
+			if err := s.inFlight.Wait(shutdownCtx); err != nil {
+				errs = append(errs, fmt.Errorf("wait for in-flight requests: %w", err))
+			}
+		}
+
+		if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, fmt.Errorf("serve: %w", err))
+		}
+	}
+
+	hookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.shutdownTimeout)
+	defer cancel()
+
+	for _, hook := range slices.Backward(s.onShutdown) {
+		if err := hook(hookCtx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) listenAddr() string {
+	if s.server.Addr != "" {
+		return s.server.Addr
+	}
+
+	if s.server.TLSConfig != nil {
+		return ":https"
+	}
+
+	return ":http"
+}
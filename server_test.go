@@ -0,0 +1,118 @@
+package gum
+
+import (
+	"context"
+	. "github.com/go-gum/gum/internal/test"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var finished atomic.Bool
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// shutdown should wait for this request to finish
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+
+		_, _ = io.WriteString(w, "done")
+		finished.Store(true)
+	})
+
+	var events []string
+	responses := make(chan string, 1)
+
+	var server *Server
+	server = NewServer("127.0.0.1:0", handler,
+		ServerSignals(),
+		OnStart(func(ctx context.Context) error {
+			events = append(events, "start")
+
+			go func() {
+				resp, err := http.Get("http://" + server.Addr().String())
+				if err != nil {
+					responses <- err.Error()
+					return
+				}
+
+				defer func() { _ = resp.Body.Close() }()
+
+				body, _ := io.ReadAll(resp.Body)
+				responses <- string(body)
+			}()
+
+			return nil
+		}),
+		OnShutdown(func(ctx context.Context) error {
+			events = append(events, "shutdown 1")
+			return nil
+		}),
+		OnShutdown(func(ctx context.Context) error {
+			AssertTrue(t, finished.Load())
+			events = append(events, "shutdown 2")
+			return nil
+		}),
+	)
+
+	err := server.Run(ctx)
+	AssertEqual(t, err, nil)
+
+	AssertEqual(t, <-responses, "done")
+	AssertEqual(t, events, []string{"start", "shutdown 2", "shutdown 1"})
+}
+
+func TestServerInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var finished atomic.Bool
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		// http.Server.Shutdown does not wait for hijacked connections
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+
+		finished.Store(true)
+	})
+
+	var server *Server
+	server = NewServer("127.0.0.1:0", handler,
+		ServerSignals(),
+		ServerInFlight(NewInFlight()),
+		OnStart(func(ctx context.Context) error {
+			go func() {
+				conn, err := net.Dial("tcp", server.Addr().String())
+				if err != nil {
+					return
+				}
+
+				_, _ = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+
+			return nil
+		}),
+		OnShutdown(func(ctx context.Context) error {
+			AssertTrue(t, finished.Load())
+			return nil
+		}),
+	)
+
+	err := server.Run(ctx)
+	AssertEqual(t, err, nil)
+	AssertTrue(t, finished.Load())
+}
@@ -0,0 +1,159 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CookieOptions configure the cookies written by a Store.
+type CookieOptions struct {
+	// Name of the cookie, defaults to "session"
+	Name string
+
+	Path     string
+	Domain   string
+	MaxAge   time.Duration
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+func (o CookieOptions) name() string {
+	if o.Name == "" {
+		return "session"
+	}
+
+	return o.Name
+}
+
+func (o CookieOptions) cookie(value string) *http.Cookie {
+	path := o.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return &http.Cookie{
+		Name:     o.name(),
+		Value:    value,
+		Path:     path,
+		Domain:   o.Domain,
+		MaxAge:   int(o.MaxAge.Seconds()),
+		Secure:   o.Secure,
+		HttpOnly: o.HttpOnly,
+		SameSite: o.SameSite,
+	}
+}
+
+func (o CookieOptions) deleteCookie() *http.Cookie {
+	cookie := o.cookie("")
+	cookie.MaxAge = -1
+	return cookie
+}
+
+func (o CookieOptions) value(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(o.name())
+	if errors.Is(err, http.ErrNoCookie) {
+		return "", ErrNoSession
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return cookie.Value, nil
+}
+
+// CookieStore stores the session data in a cookie signed with HMAC-SHA256.
+// The data is not encrypted, the client can read but not modify it. The signature
+// covers the name of the cookie and, if MaxAge is set, the time the cookie expires,
+// so a captured cookie is rejected after MaxAge even if the client keeps it.
+type CookieStore struct {
+	key     []byte
+	options CookieOptions
+}
+
+var _ Store = CookieStore{}
+
+// NewCookieStore creates a new CookieStore that signs cookies using the given key.
+// The key should be at least 32 bytes of random data.
+func NewCookieStore(key []byte, options CookieOptions) CookieStore {
+	return CookieStore{key: key, options: options}
+}
+
+func (c CookieStore) Load(r *http.Request) ([]byte, error) {
+	value, err := c.options.value(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// treat an invalid cookie as if there was none
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 {
+		return nil, ErrNoSession
+	}
+
+	encodedData, encodedExpiresAt, encodedMac := parts[0], parts[1], parts[2]
+
+	data, err := base64.RawURLEncoding.DecodeString(encodedData)
+	if err != nil {
+		return nil, ErrNoSession
+	}
+
+	expiresAt, err := strconv.ParseInt(encodedExpiresAt, 10, 64)
+	if err != nil {
+		return nil, ErrNoSession
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMac)
+	if err != nil {
+		return nil, ErrNoSession
+	}
+
+	if !hmac.Equal(mac, c.sign(data, expiresAt)) {
+		return nil, ErrNoSession
+	}
+
+	if expiresAt != 0 && time.Now().Unix() >= expiresAt {
+		return nil, ErrNoSession
+	}
+
+	return data, nil
+}
+
+func (c CookieStore) Save(w http.ResponseWriter, r *http.Request, data []byte) error {
+	// an expiresAt of zero marks a cookie without MaxAge
+	var expiresAt int64
+	if c.options.MaxAge > 0 {
+		expiresAt = time.Now().Add(c.options.MaxAge).Unix()
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(data) + "." +
+		strconv.FormatInt(expiresAt, 10) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(data, expiresAt))
+
+	http.SetCookie(w, c.options.cookie(value))
+	return nil
+}
+
+func (c CookieStore) Delete(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, c.options.deleteCookie())
+	return nil
+}
+
+// sign computes the mac of the session data, bound to the name of the
+// cookie, so the value of one cookie can not be used as another one.
+func (c CookieStore) sign(data []byte, expiresAt int64) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(c.options.name()))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(expiresAt, 10)))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)
+}
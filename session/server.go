@@ -0,0 +1,203 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Backend stores session data by id, e.g. in a database or an external cache.
+type Backend interface {
+	// Get returns the data of the session with the given id.
+	// Returns ErrNoSession if no such session exists.
+	Get(ctx context.Context, id string) ([]byte, error)
+
+	// Set stores the data of the session with the given id for the given duration.
+	// A ttl of zero means the session does not expire.
+	Set(ctx context.Context, id string, data []byte, ttl time.Duration) error
+
+	// Delete deletes the session with the given id.
+	Delete(ctx context.Context, id string) error
+}
+
+// ServerStore stores the session data in a Backend. Only a random
+// session id is stored in a cookie on the client.
+type ServerStore struct {
+	backend Backend
+	options CookieOptions
+}
+
+var _ Store = ServerStore{}
+var _ Renewer = ServerStore{}
+
+// NewServerStore creates a new ServerStore. The MaxAge of the cookie options
+// is also used as ttl for the Backend.
+func NewServerStore(backend Backend, options CookieOptions) ServerStore {
+	return ServerStore{backend: backend, options: options}
+}
+
+func (s ServerStore) Load(r *http.Request) ([]byte, error) {
+	id, err := s.options.value(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.backend.Get(r.Context(), id)
+}
+
+// Save stores the session data under the id of the requests cookie. If the backend does
+// not know the id, a new id is generated, so a client can not choose its own session id.
+func (s ServerStore) Save(w http.ResponseWriter, r *http.Request, data []byte) error {
+	id, err := s.options.value(r)
+	if err == nil {
+		_, err = s.backend.Get(r.Context(), id)
+	}
+
+	switch {
+	case errors.Is(err, ErrNoSession):
+		// the request does not have a known session, start a new one
+		id, err = newSessionID()
+		if err != nil {
+			return err
+		}
+
+	case err != nil:
+		return fmt.Errorf("load session: %w", err)
+	}
+
+	return s.store(w, r, id, data)
+}
+
+// Renew stores the session data under a new id and deletes the session with the previous id.
+func (s ServerStore) Renew(w http.ResponseWriter, r *http.Request, data []byte) error {
+	if previous, err := s.options.value(r); err == nil {
+		if err := s.backend.Delete(r.Context(), previous); err != nil {
+			return fmt.Errorf("delete session: %w", err)
+		}
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		return err
+	}
+
+	return s.store(w, r, id, data)
+}
+
+func (s ServerStore) store(w http.ResponseWriter, r *http.Request, id string, data []byte) error {
+	if err := s.backend.Set(r.Context(), id, data, s.options.MaxAge); err != nil {
+		return fmt.Errorf("store session: %w", err)
+	}
+
+	http.SetCookie(w, s.options.cookie(id))
+	return nil
+}
+
+func (s ServerStore) Delete(w http.ResponseWriter, r *http.Request) error {
+	id, err := s.options.value(r)
+	switch {
+	case errors.Is(err, ErrNoSession):
+		return nil
+
+	case err != nil:
+		return err
+	}
+
+	if err := s.backend.Delete(r.Context(), id); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+
+	http.SetCookie(w, s.options.deleteCookie())
+	return nil
+}
+
+func newSessionID() (string, error) {
+	var buf [32]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("generate session id: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf[:]), nil
+}
+
+// sweepInterval is the number of calls to Set after which expired sessions are removed.
+const sweepInterval = 1024
+
+// MemoryBackend is a Backend that keeps all sessions in memory.
+// Expired sessions are removed when accessed and periodically.
+type MemoryBackend struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+	calls    int
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+type memorySession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func (m *MemoryBackend) Get(ctx context.Context, id string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNoSession
+	}
+
+	if !sess.expiresAt.IsZero() && time.Now().After(sess.expiresAt) {
+		delete(m.sessions, id)
+		return nil, ErrNoSession
+	}
+
+	return sess.data, nil
+}
+
+func (m *MemoryBackend) Set(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.sessions == nil {
+		m.sessions = map[string]memorySession{}
+	}
+
+	now := time.Now()
+	m.sweep(now)
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+
+	m.sessions[id] = memorySession{data: data, expiresAt: expiresAt}
+	return nil
+}
+
+func (m *MemoryBackend) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+	return nil
+}
+
+// sweep removes expired sessions that were not accessed since they expired.
+func (m *MemoryBackend) sweep(now time.Time) {
+	m.calls += 1
+	if m.calls%sweepInterval != 0 {
+		return
+	}
+
+	for id, sess := range m.sessions {
+		if !sess.expiresAt.IsZero() && now.After(sess.expiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Package session provides typed sessions for gum handlers.
+//
+// Sessions are stored by a Store, e.g. a signed cookie using CookieStore or a server side
+// backend using ServerStore. The Middleware makes the store available to the Session
+// extractor and saves modified sessions before the response headers are written:
+//
+//	type UserSession struct {
+//	  UserID string
+//	}
+//
+//	func handler(sess session.Session[UserSession]) error {
+//	  value, _, err := sess.Get()
+//	  ...
+//	  return sess.Set(value)
+//	}
+//
+//	http.ListenAndServe(":8080", session.Middleware(store)(mux))
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/internal"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// ErrNoSession is returned by a Store if the request does not have a session.
+var ErrNoSession = errors.New("no session")
+
+// Store loads and saves the raw session data of a request.
+type Store interface {
+	// Load loads the session data of the request.
+	// Returns ErrNoSession if the request does not have a session.
+	Load(r *http.Request) ([]byte, error)
+
+	// Save saves the session data. This is called before the response headers
+	// are written, so a Store can set cookies on the response.
+	Save(w http.ResponseWriter, r *http.Request, data []byte) error
+
+	// Delete deletes the session of the request.
+	Delete(w http.ResponseWriter, r *http.Request) error
+}
+
+// Renewer is implemented by a Store that identifies sessions by an id. Renew saves the
+// session data under a new id and invalidates the previous one.
+type Renewer interface {
+	Renew(w http.ResponseWriter, r *http.Request, data []byte) error
+}
+
+type contextKey struct{}
+
+// Middleware provides the Store to the Session extractor. Modified sessions are saved
+// right before the response headers are written.
+func Middleware(store Store) gum.Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m := &manager{store: store, req: r, w: w}
+
+			ctx := context.WithValue(r.Context(), contextKey{}, m)
+			r = r.WithContext(ctx)
+			m.req = r
+
+			delegate.ServeHTTP(&sessionWriter{ResponseWriter: w, manager: m}, r)
+
+			// save the session if the handler did not write anything
+			if err := m.commit(); err != nil {
+				logCommitError(r, err)
+			}
+		})
+	}
+}
+
+// manager holds the session state of a single request
+type manager struct {
+	store Store
+	req   *http.Request
+	w     http.ResponseWriter
+
+	mu        sync.Mutex
+	loaded    bool
+	data      []byte
+	dirty     bool
+	renew     bool
+	deleted   bool
+	committed bool
+}
+
+func (m *manager) load() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.loaded {
+		return m.data, nil
+	}
+
+	data, err := m.store.Load(m.req)
+	switch {
+	case errors.Is(err, ErrNoSession):
+		data = nil
+
+	case err != nil:
+		return nil, fmt.Errorf("load session: %w", err)
+	}
+
+	m.data = data
+	m.loaded = true
+
+	return data, nil
+}
+
+func (m *manager) set(data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data = data
+	m.loaded = true
+	m.dirty = true
+	m.deleted = false
+}
+
+func (m *manager) delete() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data = nil
+	m.loaded = true
+	m.dirty = false
+	m.renew = false
+	m.deleted = true
+}
+
+func (m *manager) renewID() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.renew = true
+}
+
+// commit saves or deletes the session in the store, if it was modified.
+// Only the first call to commit does anything.
+func (m *manager) commit() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.committed {
+		return nil
+	}
+
+	m.committed = true
+
+	switch {
+	case m.deleted:
+		return m.store.Delete(m.w, m.req)
+
+	case m.renew && m.loaded:
+		if renewer, ok := m.store.(Renewer); ok {
+			return renewer.Renew(m.w, m.req, m.data)
+		}
+
+		return m.store.Save(m.w, m.req, m.data)
+
+	case m.dirty:
+		return m.store.Save(m.w, m.req, m.data)
+
+	default:
+		return nil
+	}
+}
+
+// Session gives access to the session data of type T of the current request.
+// The data is loaded lazily on the first call to Get.
+//
+// Extracting a Session requires the Middleware.
+type Session[T any] struct {
+	manager *manager
+}
+
+var _ = gum.AssertFromRequest[Session[any]]()
+
+func (Session[T]) FromRequest(r *http.Request) (Session[T], error) {
+	m, ok := r.Context().Value(contextKey{}).(*manager)
+	if !ok {
+		return Session[T]{}, errors.New("session.Middleware is not installed")
+	}
+
+	return Session[T]{manager: m}, nil
+}
+
+// Get returns the session data. The boolean flag is false,
+// if the request does not have a session yet.
+func (s Session[T]) Get() (T, bool, error) {
+	var value T
+
+	data, err := s.manager.load()
+	if err != nil || data == nil {
+		return value, false, err
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false, fmt.Errorf("decode session: %w", err)
+	}
+
+	return value, true, nil
+}
+
+// Set replaces the session data. The session is saved before the response
+// headers are written, changes made after that point are lost.
+func (s Session[T]) Set(value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+
+	s.manager.set(data)
+	return nil
+}
+
+// Delete deletes the session.
+func (s Session[T]) Delete() {
+	s.manager.delete()
+}
+
+// Renew saves the session under a new id, if the Store supports it, and invalidates
+// the previous id. Call Renew when the privileges of a session change, e.g. after
+// a login, to prevent session fixation.
+func (s Session[T]) Renew() error {
+	if _, err := s.manager.load(); err != nil {
+		return err
+	}
+
+	s.manager.renewID()
+	return nil
+}
+
+// Close saves the session, if it was modified and the response has not been written yet.
+// It is called by gum.Handler after the handler function returned.
+func (s Session[T]) Close() error {
+	return s.manager.commit()
+}
+
+// sessionWriter commits the session before writing the response headers.
+type sessionWriter struct {
+	http.ResponseWriter
+	manager *manager
+}
+
+func (w *sessionWriter) WriteHeader(statusCode int) {
+	if err := w.manager.commit(); err != nil {
+		logCommitError(w.manager.req, err)
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *sessionWriter) Write(bytes []byte) (int, error) {
+	if err := w.manager.commit(); err != nil {
+		logCommitError(w.manager.req, err)
+	}
+
+	return w.ResponseWriter.Write(bytes)
+}
+
+// Flush commits the session, as flushing writes the response headers.
+func (w *sessionWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError is used by http.ResponseController and reports if flushing is not supported.
+func (w *sessionWriter) FlushError() error {
+	if err := w.manager.commit(); err != nil {
+		logCommitError(w.manager.req, err)
+	}
+
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func logCommitError(r *http.Request, err error) {
+	ctx := r.Context()
+	internal.LoggerOf(ctx).WarnContext(ctx, "Saving session failed", slog.String("err", err.Error()))
+}
@@ -0,0 +1,193 @@
+package session
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type counter struct {
+	Count int
+}
+
+func counterHandler() http.Handler {
+	return gum.Handler(func(sess Session[counter]) (http.Handler, error) {
+		value, _, err := sess.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		value.Count += 1
+
+		if err := sess.Set(value); err != nil {
+			return nil, err
+		}
+
+		return response.Text(strconv.Itoa(value.Count)), nil
+	})
+}
+
+func roundTrip(t *testing.T, handler http.Handler, cookies []*http.Cookie) (string, []*http.Cookie) {
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusOK)
+
+	return rec.Body.String(), rec.Result().Cookies()
+}
+
+func TestCookieStore(t *testing.T) {
+	store := NewCookieStore([]byte("secret"), CookieOptions{})
+	handler := Middleware(store)(counterHandler())
+
+	body, cookies := roundTrip(t, handler, nil)
+	AssertEqual(t, body, "1")
+	AssertEqual(t, len(cookies), 1)
+
+	body, _ = roundTrip(t, handler, cookies)
+	AssertEqual(t, body, "2")
+
+	// tampered cookies are ignored
+	_, signature, _ := strings.Cut(cookies[0].Value, ".")
+	cookies[0].Value = "eyJDb3VudCI6OTl9." + signature
+	body, _ = roundTrip(t, handler, cookies)
+	AssertEqual(t, body, "1")
+}
+
+func TestCookieStoreName(t *testing.T) {
+	handler := Middleware(NewCookieStore([]byte("secret"), CookieOptions{Name: "a"}))(counterHandler())
+	other := Middleware(NewCookieStore([]byte("secret"), CookieOptions{Name: "b"}))(counterHandler())
+
+	_, cookies := roundTrip(t, handler, nil)
+	_, _ = roundTrip(t, handler, cookies)
+
+	// the value of a cookie is not valid under a different name
+	cookies[0].Name = "b"
+	body, _ := roundTrip(t, other, cookies)
+	AssertEqual(t, body, "1")
+}
+
+func TestCookieStoreExpired(t *testing.T) {
+	store := NewCookieStore([]byte("secret"), CookieOptions{MaxAge: time.Hour})
+	handler := Middleware(store)(counterHandler())
+
+	cookie := func(expiresAt time.Time) *http.Cookie {
+		data := []byte(`{"Count":1}`)
+		value := base64.RawURLEncoding.EncodeToString(data) + "." +
+			strconv.FormatInt(expiresAt.Unix(), 10) + "." +
+			base64.RawURLEncoding.EncodeToString(store.sign(data, expiresAt.Unix()))
+
+		return &http.Cookie{Name: "session", Value: value}
+	}
+
+	body, _ := roundTrip(t, handler, []*http.Cookie{cookie(time.Now().Add(time.Minute))})
+	AssertEqual(t, body, "2")
+
+	// a validly signed cookie is rejected after it expired
+	body, _ = roundTrip(t, handler, []*http.Cookie{cookie(time.Now().Add(-time.Minute))})
+	AssertEqual(t, body, "1")
+}
+
+func TestMemoryBackendSweep(t *testing.T) {
+	var backend MemoryBackend
+	ctx := context.Background()
+
+	_ = backend.Set(ctx, "expired", []byte("data"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	for range sweepInterval {
+		_ = backend.Set(ctx, "other", []byte("data"), time.Hour)
+	}
+
+	// the expired session is removed without being accessed again
+	_, ok := backend.sessions["expired"]
+	AssertEqual(t, ok, false)
+	AssertEqual(t, len(backend.sessions), 1)
+}
+
+func TestServerStore(t *testing.T) {
+	store := NewServerStore(&MemoryBackend{}, CookieOptions{Name: "sid"})
+	handler := Middleware(store)(counterHandler())
+
+	body, cookies := roundTrip(t, handler, nil)
+	AssertEqual(t, body, "1")
+	AssertEqual(t, cookies[0].Name, "sid")
+
+	body, _ = roundTrip(t, handler, cookies)
+	AssertEqual(t, body, "2")
+
+	body, _ = roundTrip(t, handler, cookies)
+	AssertEqual(t, body, "3")
+}
+
+func TestSessionWithoutMiddleware(t *testing.T) {
+	rec := httptest.NewRecorder()
+	counterHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, rec.Code, http.StatusBadRequest)
+}
+
+func TestServerStoreUnknownID(t *testing.T) {
+	store := NewServerStore(&MemoryBackend{}, CookieOptions{Name: "sid"})
+	handler := Middleware(store)(counterHandler())
+
+	// an id chosen by the client is not used
+	body, cookies := roundTrip(t, handler, []*http.Cookie{{Name: "sid", Value: "attacker"}})
+	AssertEqual(t, body, "1")
+	AssertTrue(t, cookies[0].Value != "attacker")
+}
+
+func TestServerStoreRenew(t *testing.T) {
+	store := NewServerStore(&MemoryBackend{}, CookieOptions{Name: "sid"})
+
+	login := Middleware(store)(gum.Handler(func(sess Session[counter]) (http.Handler, error) {
+		if err := sess.Renew(); err != nil {
+			return nil, err
+		}
+
+		return response.Text("ok"), nil
+	}))
+
+	handler := Middleware(store)(counterHandler())
+
+	_, cookies := roundTrip(t, handler, nil)
+
+	_, renewed := roundTrip(t, login, cookies)
+	AssertTrue(t, renewed[0].Value != cookies[0].Value)
+
+	// the session data is kept with the new id
+	body, _ := roundTrip(t, handler, renewed)
+	AssertEqual(t, body, "2")
+
+	// the previous id is not valid anymore
+	body, _ = roundTrip(t, handler, cookies)
+	AssertEqual(t, body, "1")
+}
+
+func TestSessionFlush(t *testing.T) {
+	store := NewCookieStore([]byte("secret"), CookieOptions{})
+
+	handler := Middleware(store)(gum.Handler(func(sess Session[counter], w gum.ResponseWriter) error {
+		if err := sess.Set(counter{Count: 1}); err != nil {
+			return err
+		}
+
+		// flushing commits the headers, the session must be saved before
+		return http.NewResponseController(w).Flush()
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	AssertEqual(t, len(rec.Result().Cookies()), 1)
+}
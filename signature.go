@@ -0,0 +1,163 @@
+package gum
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HMACSignature describes how a request body is signed using HMAC,
+// e.g. for webhooks sent by GitHub:
+//
+//	HMACSignature{
+//	  Header: "X-Hub-Signature-256",
+//	  Prefix: "sha256=",
+//	  Secret: []byte(os.Getenv("WEBHOOK_SECRET")),
+//	}
+type HMACSignature struct {
+	// Header is the request header that holds the signature.
+	Header string
+
+	// Prefix is removed from the header value before decoding the signature.
+	Prefix string
+
+	// Secret is the shared secret used to compute the HMAC. It must not be empty.
+	Secret []byte
+
+	// Hash creates the hash function to use. Defaults to sha256.New.
+	Hash func() hash.Hash
+
+	// Decode decodes the signature from the header. Defaults to hex.DecodeString.
+	Decode func(string) ([]byte, error)
+
+	// MaxBodySize limits the number of bytes read from the body. Defaults to 1MB,
+	// a negative value means no limit.
+	MaxBodySize int64
+}
+
+// errNoSecret is returned when verifying a signature without a secret.
+var errNoSecret = errors.New("HMACSignature has no secret")
+
+// Verify verifies that signature is a valid signature of body.
+// The comparison is done in constant time.
+func (s HMACSignature) Verify(body []byte, signature string) error {
+	if len(s.Secret) == 0 {
+		// anybody could compute a signature using an empty key
+		return errNoSecret
+	}
+
+	decode := s.Decode
+	if decode == nil {
+		decode = hex.DecodeString
+	}
+
+	newHash := s.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+
+	signature, ok := strings.CutPrefix(strings.TrimSpace(signature), s.Prefix)
+	if !ok {
+		return fmt.Errorf("signature does not start with %q", s.Prefix)
+	}
+
+	expected, err := decode(signature)
+	if err != nil {
+		return fmt.Errorf("decode signature: %w", err)
+	}
+
+	mac := hmac.New(newHash, s.Secret)
+	mac.Write(body)
+
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+// VerifiedBody is the body of a request that was verified using VerifyHMAC.
+// Extraction fails if the request did not pass through VerifyHMAC.
+type VerifiedBody []byte
+
+type verifiedBodyKey struct{}
+
+func init() {
+	Register(func(r *http.Request) (VerifiedBody, error) {
+		body, ok := r.Context().Value(verifiedBodyKey{}).(VerifiedBody)
+		if !ok {
+			return nil, errors.New("request body was not verified, use VerifyHMAC")
+		}
+
+		return body, nil
+	})
+}
+
+// VerifyHMAC returns a Middleware that verifies the signature of the request body.
+// Requests with a missing or invalid signature are rejected with 401 Unauthorized.
+// If the HMACSignature has no Secret, all requests are rejected with 500 Internal Server Error.
+//
+// The body is read completely and replaced with an in-memory copy, so extractors
+// like JSON or RawBody can still read it. Use the VerifiedBody extractor to
+// access the verified payload directly.
+func VerifyHMAC(signature HMACSignature) Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(signature.Secret) == 0 {
+				err := fmt.Errorf("verify %s: %w", signature.Header, errNoSecret)
+				errorResponse(err, http.StatusInternalServerError).ServeHTTP(w, r)
+				return
+			}
+
+			value := r.Header.Get(signature.Header)
+			if value == "" {
+				err := fmt.Errorf("no %s header in request", signature.Header)
+				errorResponse(err, http.StatusUnauthorized).ServeHTTP(w, r)
+				return
+			}
+
+			maxBodySize := signature.MaxBodySize
+			if maxBodySize == 0 {
+				maxBodySize = 1 << 20
+			}
+
+			var body io.Reader = r.Body
+			if maxBodySize > 0 {
+				body = http.MaxBytesReader(w, r.Body, maxBodySize)
+			}
+
+			payload, err := io.ReadAll(body)
+			if err != nil {
+				statusCode := http.StatusBadRequest
+
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					statusCode = http.StatusRequestEntityTooLarge
+				}
+
+				errorResponse(fmt.Errorf("reading body: %w", err), statusCode).ServeHTTP(w, r)
+				return
+			}
+
+			if err := signature.Verify(payload, value); err != nil {
+				err = fmt.Errorf("verify %s: %w", signature.Header, err)
+				errorResponse(err, http.StatusUnauthorized).ServeHTTP(w, r)
+				return
+			}
+
+			// replace the consumed body with a copy for the next consumer
+			r = r.WithContext(context.WithValue(r.Context(), verifiedBodyKey{}, VerifiedBody(payload)))
+			r.Body = io.NopCloser(bytes.NewReader(payload))
+
+			delegate.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package gum
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyHMAC(t *testing.T) {
+	signature := HMACSignature{
+		Header: "X-Hub-Signature-256",
+		Prefix: "sha256=",
+		Secret: []byte("secret"),
+	}
+
+	type Event struct{ Action string }
+
+	var verified VerifiedBody
+	var event Event
+
+	handler := VerifyHMAC(signature)(Handler(func(body VerifiedBody, ev JSON[Event]) {
+		verified, event = body, ev.Value
+	}))
+
+	serve := func(body, sig string) int {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		if sig != "" {
+			req.Header.Set("X-Hub-Signature-256", sig)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	body := `{"Action": "opened"}`
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	validSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	AssertEqual(t, serve(body, validSignature), http.StatusOK)
+	AssertEqual(t, string(verified), body)
+	AssertEqual(t, event, Event{Action: "opened"})
+
+	AssertEqual(t, serve(body, ""), http.StatusUnauthorized)
+	AssertEqual(t, serve(body, "sha256=00"), http.StatusUnauthorized)
+	AssertEqual(t, serve(`{"Action": "closed"}`, validSignature), http.StatusUnauthorized)
+}
+
+func TestVerifyHMACMaxBodySize(t *testing.T) {
+	handler := VerifyHMAC(HMACSignature{
+		Header: "X-Signature",
+		Secret: []byte("secret"),
+	})(http.NotFoundHandler())
+
+	// the body is limited to 1MB by default
+	req := httptest.NewRequest("POST", "/", strings.NewReader(strings.Repeat("x", 1<<20+1)))
+	req.Header.Set("X-Signature", "00")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusRequestEntityTooLarge)
+}
+
+func TestVerifyHMACEmptySecret(t *testing.T) {
+	signature := HMACSignature{Header: "X-Signature"}
+
+	// a signature computed with an empty key is not accepted
+	mac := hmac.New(sha256.New, nil)
+	mac.Write([]byte("body"))
+	AssertTrue(t, signature.Verify([]byte("body"), hex.EncodeToString(mac.Sum(nil))) != nil)
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader("body"))
+	req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+	rec := httptest.NewRecorder()
+	VerifyHMAC(signature)(http.NotFoundHandler()).ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusInternalServerError)
+}
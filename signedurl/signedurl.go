@@ -0,0 +1,202 @@
+// Package signedurl creates and verifies expiring signed links, e.g. to let clients
+// download a file without further authentication:
+//
+//	signer := signedurl.New([]byte(os.Getenv("LINK_SECRET")))
+//
+//	mux.Handle("GET /reports/{id}/link", gum.Handler(func(path gum.PathValues[Report]) response.Lazy {
+//	  return signer.Redirect(&url.URL{Path: "/downloads/" + path.Value.ID}, 10*time.Minute)
+//	}))
+//
+//	mux.Handle("GET /downloads/{id}", gum.Handler(func(_ signedurl.Verified, path gum.PathValues[Report]) { ... }))
+//
+//	http.ListenAndServe(":8080", signer.Middleware()(mux))
+//
+// The signature covers the path and the canonical query of the url, but not its
+// scheme and host, so links stay valid behind proxies that rewrite the host.
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature is returned if the signature of a url is missing or invalid.
+	ErrInvalidSignature = errors.New("invalid url signature")
+
+	// ErrExpired is returned if a url has a valid signature, but is expired.
+	ErrExpired = errors.New("signed url expired")
+)
+
+// CanonicalQuery encodes the query values sorted by key. The order of multiple values
+// of the same key is kept. Spaces are encoded as %20, so the result does not depend on
+// how the client encoded the query.
+func CanonicalQuery(values url.Values) string {
+	return strings.ReplaceAll(values.Encode(), "+", "%20")
+}
+
+// Option configures a Signer.
+type Option func(s *Signer)
+
+// VerifyKeys adds secrets that are accepted when verifying a url, but not used to sign urls.
+// Use it to rotate the secret without invalidating links that are still in use.
+func VerifyKeys(secrets ...[]byte) Option {
+	return func(s *Signer) {
+		s.verifyKeys = append(s.verifyKeys, secrets...)
+	}
+}
+
+// Params sets the names of the query parameters holding the expiry time and the signature.
+// Defaults to "expires" and "signature".
+func Params(expires, signature string) Option {
+	return func(s *Signer) {
+		s.expiresParam = expires
+		s.signatureParam = signature
+	}
+}
+
+// Signer signs and verifies urls using HMAC-SHA256.
+type Signer struct {
+	secret         []byte
+	verifyKeys     [][]byte
+	expiresParam   string
+	signatureParam string
+	now            func() time.Time
+}
+
+// New creates a new Signer signing urls with the given secret.
+func New(secret []byte, options ...Option) *Signer {
+	s := &Signer{
+		secret:         secret,
+		expiresParam:   "expires",
+		signatureParam: "signature",
+		now:            time.Now,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// Sign returns a copy of u that expires after ttl. The expiry time and the
+// signature are added as query parameters.
+func (s *Signer) Sign(u *url.URL, ttl time.Duration) *url.URL {
+	query := u.Query()
+	query.Del(s.signatureParam)
+	query.Set(s.expiresParam, strconv.FormatInt(s.now().Add(ttl).Unix(), 10))
+
+	signature := s.mac(s.secret, u, query)
+	query.Set(s.signatureParam, base64.RawURLEncoding.EncodeToString(signature))
+
+	signed := *u
+	signed.RawQuery = CanonicalQuery(query)
+
+	return &signed
+}
+
+// Redirect responds with 302 Found, redirecting the client to target signed using Sign.
+func (s *Signer) Redirect(target *url.URL, ttl time.Duration) response.Lazy {
+	return response.Redirect(s.Sign(target, ttl).String(), http.StatusFound)
+}
+
+// Verify verifies the signature of u. Returns the expiry time of the url if it is valid.
+func (s *Signer) Verify(u *url.URL) (time.Time, error) {
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	signatures := query[s.signatureParam]
+	if len(signatures) != 1 {
+		return time.Time{}, fmt.Errorf("%w: expected exactly one %s parameter", ErrInvalidSignature, s.signatureParam)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(signatures[0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	query.Del(s.signatureParam)
+
+	valid := hmac.Equal(signature, s.mac(s.secret, u, query))
+	for _, key := range s.verifyKeys {
+		valid = valid || hmac.Equal(signature, s.mac(key, u, query))
+	}
+
+	if !valid {
+		return time.Time{}, fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+
+	// the expiry time is covered by the signature, so it can be trusted now
+	expires := query[s.expiresParam]
+	if len(expires) != 1 {
+		return time.Time{}, fmt.Errorf("%w: expected exactly one %s parameter", ErrInvalidSignature, s.expiresParam)
+	}
+
+	seconds, err := strconv.ParseInt(expires[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	expiresAt := time.Unix(seconds, 0)
+	if !s.now().Before(expiresAt) {
+		return expiresAt, ErrExpired
+	}
+
+	return expiresAt, nil
+}
+
+// mac computes the signature of the path of u and the given query values.
+func (s *Signer) mac(secret []byte, u *url.URL, query url.Values) []byte {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(path))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(CanonicalQuery(query)))
+	return mac.Sum(nil)
+}
+
+// Middleware provides the Signer to the Verified extractor.
+func (s *Signer) Middleware() gum.Middleware {
+	return gum.ProvideContextValue(s)
+}
+
+// Verified is extracted if the url of the request has a valid signature. Extraction
+// fails with 403 Forbidden if the signature is missing, invalid or expired.
+// Requires the Middleware of a Signer.
+type Verified struct {
+	// Expires is the time the url expires.
+	Expires time.Time
+}
+
+var _ = gum.AssertFromRequest[Verified]()
+
+func (Verified) FromRequest(r *http.Request) (Verified, error) {
+	signer, err := gum.Extract[gum.ContextValue[*Signer]](r)
+	if err != nil {
+		return Verified{}, fmt.Errorf("no signedurl.Signer in context: %w", err)
+	}
+
+	expires, err := signer.Value.Verify(r.URL)
+	if err != nil {
+		return Verified{}, gum.NewHTTPError(http.StatusForbidden, err)
+	}
+
+	return Verified{Expires: expires}, nil
+}
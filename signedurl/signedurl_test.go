@@ -0,0 +1,108 @@
+package signedurl
+
+import (
+	"errors"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCanonicalQuery(t *testing.T) {
+	a, _ := url.ParseQuery("b=2&a=x+y&b=1")
+	b, _ := url.ParseQuery("a=x%20y&b=2&b=1")
+
+	AssertEqual(t, CanonicalQuery(a), "a=x%20y&b=2&b=1")
+	AssertEqual(t, CanonicalQuery(a), CanonicalQuery(b))
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	signer := New([]byte("secret"))
+	signer.now = func() time.Time { return now }
+
+	signed := signer.Sign(&url.URL{Path: "/files/a b.pdf", RawQuery: "size=large&format=pdf"}, time.Minute)
+	AssertEqual(t, signed.Query().Get("expires"), "1700000060")
+	AssertTrue(t, signed.Query().Get("signature") != "")
+
+	// parse the url like a server would
+	parse := func(raw string) *url.URL {
+		parsed, err := url.ParseRequestURI(raw)
+		AssertEqual(t, err, nil)
+		return parsed
+	}
+
+	expires, err := signer.Verify(parse(signed.String()))
+	AssertEqual(t, err, nil)
+	AssertEqual(t, expires, now.Add(time.Minute))
+
+	// the order of the query parameters does not matter
+	query := signed.Query()
+	reordered := "/files/a%20b.pdf?signature=" + query.Get("signature") + "&format=pdf&expires=" + query.Get("expires") + "&size=large"
+	_, err = signer.Verify(parse(reordered))
+	AssertEqual(t, err, nil)
+
+	// tampered query
+	_, err = signer.Verify(parse(strings.Replace(signed.String(), "size=large", "size=small", 1)))
+	AssertTrue(t, errors.Is(err, ErrInvalidSignature))
+
+	// tampered path
+	_, err = signer.Verify(parse(strings.Replace(signed.String(), "/files/", "/other/", 1)))
+	AssertTrue(t, errors.Is(err, ErrInvalidSignature))
+
+	// unsigned
+	_, err = signer.Verify(parse("/files/a%20b.pdf"))
+	AssertTrue(t, errors.Is(err, ErrInvalidSignature))
+
+	// expired
+	now = now.Add(time.Minute)
+	_, err = signer.Verify(parse(signed.String()))
+	AssertTrue(t, errors.Is(err, ErrExpired))
+}
+
+func TestVerifyKeys(t *testing.T) {
+	signed := New([]byte("old")).Sign(&url.URL{Path: "/download"}, time.Minute)
+
+	_, err := New([]byte("new")).Verify(signed)
+	AssertTrue(t, errors.Is(err, ErrInvalidSignature))
+
+	_, err = New([]byte("new"), VerifyKeys([]byte("old"))).Verify(signed)
+	AssertEqual(t, err, nil)
+}
+
+func TestVerified(t *testing.T) {
+	signer := New([]byte("secret"), Params("e", "s"))
+
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /link", gum.Handler(func() response.Lazy {
+		return signer.Redirect(&url.URL{Path: "/download", RawQuery: "id=1"}, time.Minute)
+	}))
+
+	mux.Handle("GET /download", gum.Handler(func(verified Verified) http.Handler {
+		return response.Text("file")
+	}))
+
+	handler := signer.Middleware()(mux)
+
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/link", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusFound)
+
+	location := rec.Header.Get("Location")
+	AssertTrue(t, strings.HasPrefix(location, "/download?e="))
+	AssertTrue(t, strings.Contains(location, "&s="))
+
+	rec = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, location, nil))
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertEqual(t, rec.Text(), "file")
+
+	rec = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/download?id=1", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusForbidden)
+}
@@ -0,0 +1,93 @@
+package gum
+
+import (
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// SortDirection is the direction of a SortField
+type SortDirection int
+
+const (
+	// Ascending sorts from the smallest to the largest value
+	Ascending SortDirection = iota
+
+	// Descending sorts from the largest to the smallest value
+	Descending
+)
+
+func (d SortDirection) String() string {
+	if d == Descending {
+		return "desc"
+	}
+
+	return "asc"
+}
+
+// SortField is a single field of a Sort parameter
+type SortField struct {
+	// Field is the name of the field as defined by the json tag
+	Field     string
+	Direction SortDirection
+}
+
+// Sort parses the "sort" query parameter, e.g. "?sort=-created_at,name", into a list of
+// fields to sort by. Fields prefixed with "-" are sorted in descending order.
+//
+// Only the names of the fields of T are allowed, resolved the same way as by QueryValues.
+// Extraction fails with 400 Bad Request if the parameter contains an unknown field.
+type Sort[T any] struct {
+	Fields []SortField
+}
+
+var _ = AssertFromRequest[Sort[any]]()
+
+func (Sort[T]) FromRequest(r *http.Request) (Sort[T], error) {
+	allowed := map[string]bool{}
+
+	ty := reflect.TypeFor[T]()
+	if ty.Kind() == reflect.Struct {
+		for _, field := range serde.Fields(ty) {
+			allowed[field.Name] = true
+		}
+	}
+
+	var fields []SortField
+	seen := map[string]bool{}
+
+	for _, value := range r.URL.Query()["sort"] {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			direction := Ascending
+			switch name[0] {
+			case '-':
+				direction = Descending
+				name = name[1:]
+
+			case '+':
+				name = name[1:]
+			}
+
+			if !allowed[name] {
+				err := fmt.Errorf("unknown sort field %q", name)
+				return Sort[T]{}, NewHTTPError(http.StatusBadRequest, err)
+			}
+
+			if seen[name] {
+				continue
+			}
+
+			seen[name] = true
+			fields = append(fields, SortField{Field: name, Direction: direction})
+		}
+	}
+
+	return Sort[T]{Fields: fields}, nil
+}
@@ -0,0 +1,34 @@
+package gum
+
+import (
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type sortableUser struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	Password  string `json:"-"`
+}
+
+func TestSort(t *testing.T) {
+	req := httptest.NewRequest("GET", "/users?sort=-created_at,name&sort=+name", nil)
+
+	var extractedValue Sort[sortableUser]
+	Handler(func(v Sort[sortableUser]) { extractedValue = v }).ServeHTTP(nil, req)
+
+	AssertEqual(t, extractedValue.Fields, []SortField{
+		{Field: "created_at", Direction: Descending},
+		{Field: "name", Direction: Ascending},
+	})
+}
+
+func TestSort_UnknownField(t *testing.T) {
+	req := httptest.NewRequest("GET", "/users?sort=Password", nil)
+
+	rec := httptest.NewRecorder()
+	Handler(func(v Sort[sortableUser]) { t.FailNow() }).ServeHTTP(rec, req)
+	AssertEqual(t, rec.Code, http.StatusBadRequest)
+}
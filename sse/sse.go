@@ -0,0 +1,489 @@
+// Package sse broadcasts server-sent events to many subscribers.
+//
+// A Hub keeps the subscribers of each topic and a short history of published events,
+// so that clients reconnecting with a Last-Event-ID header receive the events they missed:
+//
+//	hub := sse.NewHub(sse.History(100), sse.Heartbeat(15*time.Second))
+//
+//	http.Handle("GET /events/{topic}", hub.Handler(func(r *http.Request) string {
+//		return r.PathValue("topic")
+//	}))
+//
+//	hub.Publish("news", sse.Event{Event: "headline", Data: "gum released"})
+//
+// Subscribers that can not keep up are handled according to the Backpressure policy.
+package sse
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a single server-sent event.
+type Event struct {
+	// ID identifies the event. Clients send the id of the last event they received
+	// in the Last-Event-ID header when they reconnect. Hub.Publish assigns an id
+	// if the event has none.
+	ID string
+
+	// Event is the event type. Clients receive events without a type as "message".
+	Event string
+
+	// Data is the payload of the event. It may span multiple lines.
+	Data string
+
+	// Retry tells the client how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+// ErrInvalidEvent is returned for events that can not be encoded in the
+// text/event-stream format: the ID or Event contains a line break, or the ID contains
+// a NUL character.
+var ErrInvalidEvent = errors.New("invalid event")
+
+// validate checks that the single line fields of the event can not
+// break out of their line and inject fields or events.
+func (e Event) validate() error {
+	if strings.ContainsAny(e.ID, "\r\n\x00") {
+		return fmt.Errorf("%w: id %q", ErrInvalidEvent, e.ID)
+	}
+
+	if strings.ContainsAny(e.Event, "\r\n") {
+		return fmt.Errorf("%w: event type %q", ErrInvalidEvent, e.Event)
+	}
+
+	return nil
+}
+
+// lineBreaks normalizes all line breaks the text/event-stream format accepts.
+var lineBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// WriteEvent writes event to w in the text/event-stream format.
+// Returns ErrInvalidEvent if the event can not be encoded.
+func WriteEvent(w io.Writer, event Event) error {
+	if err := event.validate(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+
+	if event.ID != "" {
+		b.WriteString("id: " + event.ID + "\n")
+	}
+
+	if event.Event != "" {
+		b.WriteString("event: " + event.Event + "\n")
+	}
+
+	if event.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	for _, line := range strings.Split(lineBreaks.Replace(event.Data), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// LastEventID is the value of the Last-Event-ID request header.
+// It is empty if the client did not send the header.
+type LastEventID string
+
+func init() {
+	gum.Register(func(r *http.Request) (LastEventID, error) {
+		return LastEventID(r.Header.Get("Last-Event-ID")), nil
+	})
+}
+
+// Backpressure decides what happens if a subscriber does not receive events
+// as fast as they are published and its buffer is full.
+type Backpressure int
+
+const (
+	// DropOldest discards the oldest buffered event to make room for the new one.
+	DropOldest Backpressure = iota
+
+	// DropNewest discards the new event.
+	DropNewest
+
+	// Disconnect closes the subscription. The client reconnects and
+	// resumes using the Last-Event-ID header.
+	Disconnect
+)
+
+// Option configures a Hub.
+type Option func(config *config)
+
+type config struct {
+	buffer       int
+	history      int
+	heartbeat    time.Duration
+	backpressure Backpressure
+}
+
+// Buffer sets the number of events buffered per subscriber. Defaults to 16.
+func Buffer(n int) Option {
+	return func(config *config) {
+		config.buffer = n
+	}
+}
+
+// History sets the number of events kept per topic to resume subscriptions
+// after a reconnect. Defaults to 0, no events are kept.
+func History(n int) Option {
+	return func(config *config) {
+		config.history = n
+	}
+}
+
+// Heartbeat sets the interval of the comments sent to idle subscribers, to keep proxies
+// from closing the connection. Defaults to 30 seconds, a value of zero disables heartbeats.
+func Heartbeat(interval time.Duration) Option {
+	return func(config *config) {
+		config.heartbeat = interval
+	}
+}
+
+// WithBackpressure sets the policy for slow subscribers. Defaults to DropOldest.
+func WithBackpressure(policy Backpressure) Option {
+	return func(config *config) {
+		config.backpressure = policy
+	}
+}
+
+// ErrHubClosed is returned when subscribing to a closed Hub.
+var ErrHubClosed = errors.New("hub is closed")
+
+// Hub broadcasts events to the subscribers of a topic.
+type Hub struct {
+	config config
+
+	mu     sync.Mutex
+	topics map[string]*topic
+	nextID uint64
+	closed bool
+}
+
+type topic struct {
+	subscribers map[*Subscription]struct{}
+	history     []Event
+}
+
+// NewHub creates a new Hub.
+func NewHub(options ...Option) *Hub {
+	config := config{
+		buffer:    16,
+		heartbeat: 30 * time.Second,
+	}
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &Hub{config: config, topics: map[string]*topic{}}
+}
+
+// Publish sends event to all current subscribers of the topic.
+// Returns ErrInvalidEvent if the event can not be encoded.
+func (h *Hub) Publish(topicName string, event Event) error {
+	if err := event.validate(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil
+	}
+
+	if event.ID == "" {
+		h.nextID++
+		event.ID = strconv.FormatUint(h.nextID, 10)
+	}
+
+	t := h.topicOf(topicName)
+
+	if h.config.history > 0 {
+		t.history = append(t.history, event)
+		if len(t.history) > h.config.history {
+			t.history = t.history[len(t.history)-h.config.history:]
+		}
+	}
+
+	for sub := range t.subscribers {
+		if !sub.offer(event, h.config.backpressure) {
+			h.remove(topicName, sub)
+		}
+	}
+
+	h.dropIfUnused(topicName)
+
+	return nil
+}
+
+// Subscribe subscribes to the events of a topic. If lastEventID is not empty, the events
+// published after lastEventID are replayed from the history first. If lastEventID is not
+// in the history anymore, the complete history is replayed.
+//
+// The subscription ends when ctx is done, when it is closed or when the hub is closed.
+func (h *Hub) Subscribe(ctx context.Context, topicName string, lastEventID string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+
+	t := h.topicOf(topicName)
+
+	var replay []Event
+	if lastEventID != "" {
+		replay = t.history
+
+		for idx, event := range t.history {
+			if event.ID == lastEventID {
+				replay = t.history[idx+1:]
+				break
+			}
+		}
+	}
+
+	sub := &Subscription{
+		hub:       h,
+		topic:     topicName,
+		events:    make(chan Event, len(replay)+h.config.buffer),
+		heartbeat: h.config.heartbeat,
+	}
+
+	for _, event := range replay {
+		sub.events <- event
+	}
+
+	t.subscribers[sub] = struct{}{}
+
+	sub.stop = context.AfterFunc(ctx, sub.Close)
+
+	return sub, nil
+}
+
+// Subscribers returns the number of subscribers of a topic.
+func (h *Hub) Subscribers(topicName string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[topicName]
+	if !ok {
+		return 0
+	}
+
+	return len(t.subscribers)
+}
+
+// Close ends all subscriptions. Subscribing to a closed hub fails with ErrHubClosed.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for name, t := range h.topics {
+		for sub := range t.subscribers {
+			h.remove(name, sub)
+		}
+	}
+}
+
+// Handler returns an http.Handler that subscribes each request to the topic returned by
+// topicOf and streams the events to the client. The Last-Event-ID header of the request
+// is used to resume the subscription. HEAD requests receive the headers of the stream
+// without subscribing.
+func (h *Hub) Handler(topicOf func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		sub, err := h.Subscribe(r.Context(), topicOf(r), r.Header.Get("Last-Event-ID"))
+		if err != nil {
+			response.Error(err, http.StatusServiceUnavailable).ServeHTTP(w, r)
+			return
+		}
+
+		sub.Response().ServeHTTP(w, r)
+	})
+}
+
+func (h *Hub) topicOf(name string) *topic {
+	t, ok := h.topics[name]
+	if !ok {
+		t = &topic{subscribers: map[*Subscription]struct{}{}}
+		h.topics[name] = t
+	}
+
+	return t
+}
+
+// remove removes the subscription from the topic and closes its channel.
+// Must be called with h.mu held.
+func (h *Hub) remove(topicName string, sub *Subscription) {
+	t, ok := h.topics[topicName]
+	if !ok {
+		return
+	}
+
+	if _, ok := t.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(t.subscribers, sub)
+	close(sub.events)
+
+	h.dropIfUnused(topicName)
+}
+
+// dropIfUnused removes a topic without subscribers and history.
+// Must be called with h.mu held.
+func (h *Hub) dropIfUnused(topicName string) {
+	if t := h.topics[topicName]; t != nil && len(t.subscribers) == 0 && len(t.history) == 0 {
+		delete(h.topics, topicName)
+	}
+}
+
+// Subscription receives the events of a topic.
+type Subscription struct {
+	hub       *Hub
+	topic     string
+	events    chan Event
+	heartbeat time.Duration
+	stop      func() bool
+}
+
+// Events returns the channel of events. It is closed when the subscription ends.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s.topic, s)
+}
+
+// Response streams the events of the subscription to the client until the
+// subscription ends. Idle connections receive heartbeat comments.
+func (s *Subscription) Response() response.Response {
+	body := func(w io.Writer) error {
+		defer s.Close()
+		defer s.stop()
+
+		buffered := bufio.NewWriter(w)
+
+		// send the headers right away, so the client knows the stream is open
+		if err := flush(w, buffered); err != nil {
+			return err
+		}
+
+		var heartbeat <-chan time.Time
+		if s.heartbeat > 0 {
+			ticker := time.NewTicker(s.heartbeat)
+			defer ticker.Stop()
+
+			heartbeat = ticker.C
+		}
+
+		for {
+			select {
+			case event, ok := <-s.events:
+				if !ok {
+					return nil
+				}
+
+				if err := WriteEvent(buffered, event); err != nil {
+					return fmt.Errorf("write event %q: %w", event.ID, err)
+				}
+
+				// write all events that are already waiting before flushing
+				if len(s.events) > 0 {
+					continue
+				}
+
+			case <-heartbeat:
+				if _, err := buffered.WriteString(":\n\n"); err != nil {
+					return err
+				}
+			}
+
+			if err := flush(w, buffered); err != nil {
+				return err
+			}
+		}
+	}
+
+	return response.New(body).
+		SetHeader("Content-Type", "text/event-stream").
+		SetHeader("Cache-Control", "no-cache").
+		SetHeader("X-Accel-Buffering", "no")
+}
+
+// offer delivers the event to the subscriber. Returns false if the subscriber
+// must be disconnected. Must be called with the hub lock held.
+func (s *Subscription) offer(event Event, policy Backpressure) bool {
+	for {
+		select {
+		case s.events <- event:
+			return true
+		default:
+		}
+
+		switch policy {
+		case DropNewest:
+			return true
+
+		case Disconnect:
+			return false
+
+		default:
+			// make room by dropping the oldest event. The subscriber might have received
+			// it concurrently, in which case we just try again.
+			select {
+			case <-s.events:
+			default:
+			}
+		}
+	}
+}
+
+func flush(w io.Writer, buffered *bufio.Writer) error {
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	if rw, ok := w.(http.ResponseWriter); ok {
+		err := http.NewResponseController(rw).Flush()
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+
+	return nil
+}
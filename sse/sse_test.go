@@ -0,0 +1,208 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteEvent(&buf, Event{ID: "1", Event: "update", Data: "first\nsecond", Retry: time.Second})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, buf.String(), "id: 1\nevent: update\nretry: 1000\ndata: first\ndata: second\n\n")
+}
+
+func TestWriteEventInvalid(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteEvent(&buf, Event{ID: "1\nevent: admin", Data: "x"})
+	AssertTrue(t, errors.Is(err, ErrInvalidEvent))
+
+	err = WriteEvent(&buf, Event{ID: "1\x00", Data: "x"})
+	AssertTrue(t, errors.Is(err, ErrInvalidEvent))
+
+	err = WriteEvent(&buf, Event{Event: "update\rdata: injected", Data: "x"})
+	AssertTrue(t, errors.Is(err, ErrInvalidEvent))
+	AssertEqual(t, buf.Len(), 0)
+
+	// a bare carriage return in the data starts a new data line
+	err = WriteEvent(&buf, Event{Data: "first\rid: 2\r\nthird"})
+	AssertEqual(t, err, nil)
+	AssertEqual(t, buf.String(), "data: first\ndata: id: 2\ndata: third\n\n")
+
+	hub := NewHub()
+	AssertTrue(t, errors.Is(hub.Publish("news", Event{ID: "a\nb"}), ErrInvalidEvent))
+}
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub(History(2))
+
+	sub, err := hub.Subscribe(context.Background(), "news", "")
+	AssertEqual(t, err, nil)
+	AssertEqual(t, hub.Subscribers("news"), 1)
+
+	hub.Publish("news", Event{Data: "a"})
+	hub.Publish("other", Event{Data: "b"})
+	hub.Publish("news", Event{Data: "c"})
+
+	AssertEqual(t, receive(t, sub), Event{ID: "1", Data: "a"})
+	AssertEqual(t, receive(t, sub), Event{ID: "3", Data: "c"})
+
+	sub.Close()
+	AssertEqual(t, hub.Subscribers("news"), 0)
+
+	_, ok := <-sub.Events()
+	AssertTrue(t, !ok)
+}
+
+func TestHubResume(t *testing.T) {
+	hub := NewHub(History(3))
+
+	for _, data := range []string{"a", "b", "c", "d"} {
+		hub.Publish("news", Event{Data: data})
+	}
+
+	// resume after a known event
+	sub, _ := hub.Subscribe(context.Background(), "news", "3")
+	AssertEqual(t, receive(t, sub).Data, "d")
+
+	// the event fell out of the history, replay everything we have
+	sub, _ = hub.Subscribe(context.Background(), "news", "1")
+	AssertEqual(t, receive(t, sub).Data, "b")
+	AssertEqual(t, receive(t, sub).Data, "c")
+	AssertEqual(t, receive(t, sub).Data, "d")
+}
+
+func TestHubBackpressure(t *testing.T) {
+	t.Run("DropOldest", func(t *testing.T) {
+		hub := NewHub(Buffer(2))
+		sub, _ := hub.Subscribe(context.Background(), "news", "")
+
+		for _, data := range []string{"a", "b", "c"} {
+			hub.Publish("news", Event{Data: data})
+		}
+
+		AssertEqual(t, receive(t, sub).Data, "b")
+		AssertEqual(t, receive(t, sub).Data, "c")
+	})
+
+	t.Run("DropNewest", func(t *testing.T) {
+		hub := NewHub(Buffer(2), WithBackpressure(DropNewest))
+		sub, _ := hub.Subscribe(context.Background(), "news", "")
+
+		for _, data := range []string{"a", "b", "c"} {
+			hub.Publish("news", Event{Data: data})
+		}
+
+		AssertEqual(t, receive(t, sub).Data, "a")
+		AssertEqual(t, receive(t, sub).Data, "b")
+	})
+
+	t.Run("Disconnect", func(t *testing.T) {
+		hub := NewHub(Buffer(1), WithBackpressure(Disconnect))
+		sub, _ := hub.Subscribe(context.Background(), "news", "")
+
+		hub.Publish("news", Event{Data: "a"})
+		hub.Publish("news", Event{Data: "b"})
+
+		AssertEqual(t, hub.Subscribers("news"), 0)
+		AssertEqual(t, receive(t, sub).Data, "a")
+
+		_, ok := <-sub.Events()
+		AssertTrue(t, !ok)
+	})
+}
+
+func TestHubContext(t *testing.T) {
+	hub := NewHub()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, _ := hub.Subscribe(ctx, "news", "")
+
+	cancel()
+
+	_, ok := <-sub.Events()
+	AssertTrue(t, !ok)
+	AssertEqual(t, hub.Subscribers("news"), 0)
+
+	hub.Close()
+
+	_, err := hub.Subscribe(context.Background(), "news", "")
+	AssertEqual(t, err, ErrHubClosed)
+}
+
+func TestHubHandler(t *testing.T) {
+	hub := NewHub(History(10), Heartbeat(100*time.Millisecond))
+	hub.Publish("news", Event{Data: "missed"})
+
+	server := httptest.NewServer(hub.Handler(func(r *http.Request) string {
+		return r.URL.Query().Get("topic")
+	}))
+
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"?topic=news", nil)
+	req.Header.Set("Last-Event-ID", "0")
+
+	resp, err := http.DefaultClient.Do(req)
+	AssertEqual(t, err, nil)
+
+	defer resp.Body.Close()
+
+	AssertEqual(t, resp.Header.Get("Content-Type"), "text/event-stream")
+
+	hub.Publish("news", Event{Event: "update", Data: "live"})
+
+	reader := bufio.NewReader(resp.Body)
+
+	var lines []string
+	for len(lines) < 7 {
+		line, err := reader.ReadString('\n')
+		AssertEqual(t, err, nil)
+
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+
+	AssertEqual(t, lines[:7], []string{"id: 1", "data: missed", "", "id: 2", "event: update", "data: live", ""})
+
+	// wait for a heartbeat
+	line, err := reader.ReadString('\n')
+	AssertEqual(t, err, nil)
+	AssertEqual(t, line, ":\n")
+
+	hub.Close()
+}
+
+func TestHubHandlerHead(t *testing.T) {
+	hub := NewHub()
+
+	handler := hub.Handler(func(r *http.Request) string { return "news" })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/", nil))
+
+	AssertEqual(t, rec.Code, http.StatusOK)
+	AssertEqual(t, rec.Header().Get("Content-Type"), "text/event-stream")
+	AssertEqual(t, hub.Subscribers("news"), 0)
+}
@@ -0,0 +1,215 @@
+// Package static serves static assets with fingerprinted file names.
+//
+// Assets hashes the content of every file in a fs.FS and serves each file under a
+// name containing the hash, e.g. "css/app.css" as "css/app.3f2a1b9c.css". As the
+// content of a fingerprinted file never changes, it is served with immutable cache
+// headers. Pre-compressed variants next to a file, e.g. "css/app.css.br" or
+// "css/app.css.gz", are served to clients accepting the respective encoding:
+//
+//	assets, err := static.New(os.DirFS("public"), "/assets/")
+//
+//	mux.Handle("GET /assets/", assets)
+//	tmpl := template.New("").Funcs(assets.FuncMap())
+//
+// Within templates, use {{ asset "css/app.css" }} to resolve the url of an asset.
+// Handlers can extract the *Assets if the Middleware is installed.
+package static
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/internal"
+	"github.com/go-gum/gum/response"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// encodings are the supported pre-compressed variants, in order of preference.
+var encodings = []struct {
+	name      string
+	extension string
+}{
+	{name: "br", extension: ".br"},
+	{name: "gzip", extension: ".gz"},
+}
+
+// Assets serves the files of a fs.FS under fingerprinted names.
+type Assets struct {
+	fsys   fs.FS
+	prefix string
+
+	// hashed maps the name of a file to its fingerprinted name
+	hashed map[string]string
+
+	// files maps fingerprinted names back to the original file name
+	files map[string]string
+}
+
+func init() {
+	gum.Register(gum.ContextValueExtractor[*Assets]())
+}
+
+// New hashes all files of fsys. prefix is the url path the assets are served at,
+// e.g. "/assets/". Files with the extension of a pre-compressed variant are only
+// served as variants of the uncompressed file.
+func New(fsys fs.FS, prefix string) (*Assets, error) {
+	a := &Assets{
+		fsys:   fsys,
+		prefix: strings.TrimSuffix(prefix, "/") + "/",
+		hashed: map[string]string{},
+		files:  map[string]string{},
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || isVariant(name) {
+			return err
+		}
+
+		hash, err := hashFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("hash %q: %w", name, err)
+		}
+
+		hashedName := fingerprint(name, hash)
+		a.hashed[name] = hashedName
+		a.files[hashedName] = name
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// URL returns the url of the fingerprinted asset with the given name, e.g. "css/app.css".
+// If no such asset exists, the url of the unmodified name is returned.
+func (a *Assets) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+
+	if hashed, ok := a.hashed[name]; ok {
+		return a.prefix + hashed
+	}
+
+	return a.prefix + name
+}
+
+// FuncMap returns the template function "asset", resolving asset urls using URL.
+func (a *Assets) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": a.URL}
+}
+
+// Middleware provides the *Assets to handlers, e.g. to resolve asset urls in a handler:
+//
+//	func page(assets *static.Assets) response.Response { ... }
+func (a *Assets) Middleware() gum.Middleware {
+	return gum.ProvideContextValue(a)
+}
+
+// ServeHTTP serves the asset at the requested path. The path must start with the prefix.
+// Fingerprinted names are served with immutable cache headers. Original names are served
+// as well, but must be revalidated by the client on every use.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutPrefix(r.URL.Path, a.prefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	immutable := false
+	if original, ok := a.files[name]; ok {
+		name, immutable = original, true
+	} else if _, ok := a.hashed[name]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	resp := response.FS(a.fsys, name)
+
+	if encoding, file, ok := a.openVariant(name, r.Header.Get("Accept-Encoding")); ok {
+		defer func() { _ = file.Close() }()
+
+		// the Content-Type is detected from the original name
+		resp = response.Content(file, time.Time{}, path.Base(name)).
+			SetHeader("Content-Encoding", encoding)
+	}
+
+	resp = resp.AddHeader("Vary", "Accept-Encoding")
+
+	if immutable {
+		resp = resp.CacheImmutable()
+	} else {
+		resp = resp.Cache(response.NoCache)
+	}
+
+	resp.ServeHTTP(w, r)
+}
+
+// openVariant opens the most preferred pre-compressed variant of the file accepted by the client.
+func (a *Assets) openVariant(name string, acceptEncoding string) (string, io.ReadSeekCloser, bool) {
+	names := make([]string, len(encodings))
+	for idx, encoding := range encodings {
+		names[idx] = encoding.name
+	}
+
+	for _, accepted := range internal.AcceptedEncodings(acceptEncoding, names) {
+		encoding := encodings[slices.Index(names, accepted)]
+
+		file, err := a.fsys.Open(name + encoding.extension)
+		if err != nil {
+			continue
+		}
+
+		content, ok := file.(io.ReadSeekCloser)
+		if !ok {
+			_ = file.Close()
+			continue
+		}
+
+		return encoding.name, content, true
+	}
+
+	return "", nil, false
+}
+
+func hashFile(fsys fs.FS, name string) (string, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:8], nil
+}
+
+// fingerprint inserts the hash before the extension of the file name.
+func fingerprint(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+func isVariant(name string) bool {
+	for _, encoding := range encodings {
+		if strings.HasSuffix(name, encoding.extension) {
+			return true
+		}
+	}
+
+	return false
+}
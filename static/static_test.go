@@ -0,0 +1,89 @@
+package static
+
+import (
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func newAssets(t *testing.T) *Assets {
+	fsys := fstest.MapFS{
+		"css/app.css":    {Data: []byte("body { color: red }")},
+		"css/app.css.gz": {Data: []byte("gzipped")},
+		"js/app.js":      {Data: []byte("console.log(1)")},
+	}
+
+	assets, err := New(fsys, "/assets/")
+	AssertEqual(t, err, nil)
+
+	return assets
+}
+
+func TestAssetsURL(t *testing.T) {
+	assets := newAssets(t)
+
+	AssertEqual(t, assets.URL("css/app.css"), "/assets/css/app.925e8741.css")
+	AssertEqual(t, assets.URL("/js/app.js"), "/assets/js/app.0a286891.js")
+	AssertEqual(t, assets.URL("unknown.png"), "/assets/unknown.png")
+
+	var buf strings.Builder
+	tmpl := template.Must(template.New("").Funcs(assets.FuncMap()).Parse(`{{ asset "css/app.css" }}`))
+	AssertEqual(t, tmpl.Execute(&buf, nil), nil)
+	AssertEqual(t, buf.String(), "/assets/css/app.925e8741.css")
+}
+
+func TestAssetsServe(t *testing.T) {
+	assets := newAssets(t)
+
+	req := httptest.NewRequest(http.MethodGet, assets.URL("css/app.css"), nil)
+	resp := gumtest.Serve(assets, req)
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertEqual(t, resp.Text(), "body { color: red }")
+	AssertEqual(t, resp.Header.Get("Content-Type"), "text/css; charset=utf-8")
+	AssertEqual(t, resp.Header.Get("Cache-Control"), "public, max-age=31536000, immutable")
+
+	req = httptest.NewRequest(http.MethodGet, assets.URL("css/app.css"), nil)
+	req.Header.Set("Accept-Encoding", "br;q=0, gzip")
+	resp = gumtest.Serve(assets, req)
+	AssertEqual(t, resp.Text(), "gzipped")
+	AssertEqual(t, resp.Header.Get("Content-Encoding"), "gzip")
+	AssertEqual(t, resp.Header.Get("Content-Type"), "text/css; charset=utf-8")
+
+	// the wildcard accepts the gzip variant, the br variant is missing
+	req = httptest.NewRequest(http.MethodGet, assets.URL("css/app.css"), nil)
+	req.Header.Set("Accept-Encoding", "*")
+	resp = gumtest.Serve(assets, req)
+	AssertEqual(t, resp.Header.Get("Content-Encoding"), "gzip")
+
+	req = httptest.NewRequest(http.MethodGet, assets.URL("css/app.css"), nil)
+	req.Header.Set("Accept-Encoding", "*, gzip;q=0")
+	resp = gumtest.Serve(assets, req)
+	AssertEqual(t, resp.Header.Get("Content-Encoding"), "")
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/js/app.js", nil)
+	resp = gumtest.Serve(assets, req)
+	AssertEqual(t, resp.Text(), "console.log(1)")
+	AssertEqual(t, resp.Header.Get("Cache-Control"), "no-cache")
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/css/app.css.gz", nil)
+	resp = gumtest.Serve(assets, req)
+	AssertEqual(t, resp.StatusCode, http.StatusNotFound)
+}
+
+func TestAssetsExtractor(t *testing.T) {
+	assets := newAssets(t)
+
+	handler := gum.Handler(func(assets *Assets) response.Response {
+		return response.Text(assets.URL("js/app.js"))
+	})
+
+	resp := gumtest.Serve(assets.Middleware()(handler), httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, resp.Text(), "/assets/js/app.0a286891.js")
+}
@@ -0,0 +1,139 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ExtractionTiming describes the extraction of a single handler parameter.
+type ExtractionTiming struct {
+	// Index is the index of the parameter of the handler function
+	Index int
+
+	// Type is the type of the parameter
+	Type reflect.Type
+
+	// Duration is the time it took to extract the parameter
+	Duration time.Duration
+
+	// Err is the error returned by the extractor, if any
+	Err error
+}
+
+// ExtractionTrace records how long the extraction of each handler parameter took. Install
+// it using the TraceExtraction middleware. Handlers can extract a *ExtractionTrace,
+// middleware can read it after the request was handled using ExtractionTraceOf:
+//
+//	func logSlowExtraction(delegate http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			delegate.ServeHTTP(w, r)
+//
+//			for _, timing := range gum.ExtractionTraceOf(r).Timings() {
+//				if timing.Duration > 100*time.Millisecond {
+//					slog.Warn("Slow extraction", slog.String("type", timing.Type.String()))
+//				}
+//			}
+//		})
+//	}
+//
+//	handler = gum.TraceExtraction()(logSlowExtraction(handler))
+//
+// A *ExtractionTrace extracted as a handler parameter contains all timings once the handler
+// function runs, except for parameters that are extracted concurrently with ParallelExtraction.
+type ExtractionTrace struct {
+	mu      sync.Mutex
+	timings []ExtractionTiming
+}
+
+// Timings returns the recorded timings in the order the extractions finished.
+// It is safe to call Timings on a nil *ExtractionTrace.
+func (t *ExtractionTrace) Timings() []ExtractionTiming {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.timings)
+}
+
+// Total returns the sum of all extraction durations.
+func (t *ExtractionTrace) Total() time.Duration {
+	var total time.Duration
+	for _, timing := range t.Timings() {
+		total += timing.Duration
+	}
+
+	return total
+}
+
+func (t *ExtractionTrace) record(timing ExtractionTiming) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timings = append(t.timings, timing)
+}
+
+type extractionTraceKey struct{}
+
+// TraceExtraction returns a Middleware that installs a new ExtractionTrace into the
+// context of each request. All gum handlers below the middleware record their
+// extractions into the trace.
+func TraceExtraction() Middleware {
+	return func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), extractionTraceKey{}, &ExtractionTrace{})
+			delegate.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ExtractionTraceOf returns the ExtractionTrace of the request,
+// or nil if the request did not pass through TraceExtraction.
+func ExtractionTraceOf(r *http.Request) *ExtractionTrace {
+	trace, _ := r.Context().Value(extractionTraceKey{}).(*ExtractionTrace)
+	return trace
+}
+
+func init() {
+	Register(func(r *http.Request) (*ExtractionTrace, error) {
+		trace := ExtractionTraceOf(r)
+		if trace == nil {
+			return nil, errors.New("no extraction trace in request, use TraceExtraction")
+		}
+
+		return trace, nil
+	})
+}
+
+// traceExtractors wraps each extractor to record its timing into the
+// ExtractionTrace of the request.
+func traceExtractors(extractors []extractor, fnType reflect.Type) []extractor {
+	traced := make([]extractor, len(extractors))
+
+	for idx, ex := range extractors {
+		ty := fnType.In(idx)
+
+		traced[idx] = func(r *http.Request) (reflect.Value, error) {
+			startTime := time.Now()
+			value, err := ex(r)
+
+			ExtractionTraceOf(r).record(ExtractionTiming{
+				Index:    idx,
+				Type:     ty,
+				Duration: time.Since(startTime),
+				Err:      err,
+			})
+
+			return value, err
+		}
+	}
+
+	return traced
+}
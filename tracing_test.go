@@ -0,0 +1,84 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type slowValue struct{}
+
+func (slowValue) FromRequest(r *http.Request) (slowValue, error) {
+	time.Sleep(10 * time.Millisecond)
+	return slowValue{}, nil
+}
+
+type failingValue struct{}
+
+func (failingValue) FromRequest(r *http.Request) (failingValue, error) {
+	return failingValue{}, errors.New("failed")
+}
+
+func TestExtractionTrace(t *testing.T) {
+	var inHandler []ExtractionTiming
+
+	handler := Handler(func(method Method, slow slowValue, trace *ExtractionTrace) {
+		inHandler = trace.Timings()
+	})
+
+	var afterHandler *ExtractionTrace
+	capture := func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			delegate.ServeHTTP(w, r)
+			afterHandler = ExtractionTraceOf(r)
+		})
+	}
+
+	rec := gumtest.Serve(TraceExtraction()(capture(handler)), httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+
+	// all parameters are extracted before the handler function runs
+	AssertEqual(t, len(inHandler), 3)
+
+	timings := afterHandler.Timings()
+	AssertEqual(t, len(timings), 3)
+
+	AssertEqual(t, timings[0].Index, 0)
+	AssertEqual(t, timings[0].Type, reflect.TypeFor[Method]())
+	AssertEqual(t, timings[1].Type, reflect.TypeFor[slowValue]())
+	AssertTrue(t, timings[1].Duration >= 10*time.Millisecond)
+	AssertTrue(t, afterHandler.Total() >= 10*time.Millisecond)
+}
+
+func TestExtractionTraceError(t *testing.T) {
+	var trace *ExtractionTrace
+	capture := func(delegate http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			delegate.ServeHTTP(w, r)
+			trace = ExtractionTraceOf(r)
+		})
+	}
+
+	handler := Handler(func(value failingValue) {})
+
+	rec := gumtest.Serve(TraceExtraction()(capture(handler)), httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusBadRequest)
+
+	timings := trace.Timings()
+	AssertEqual(t, len(timings), 1)
+	AssertTrue(t, timings[0].Err != nil)
+}
+
+func TestExtractionTraceMissing(t *testing.T) {
+	AssertEqual(t, ExtractionTraceOf(httptest.NewRequest(http.MethodGet, "/", nil)).Timings(), nil)
+
+	handler := Handler(func(trace *ExtractionTrace) {})
+
+	rec := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	AssertEqual(t, rec.StatusCode, http.StatusBadRequest)
+}
@@ -0,0 +1,121 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DirStore is a Store keeping uploads as files in a directory. The content of an upload
+// is stored in a file named by its id, its info in a file with an additional ".info" suffix.
+type DirStore struct {
+	dir string
+}
+
+var _ Store = DirStore{}
+
+// NewDirStore creates a new DirStore storing uploads in dir. The directory must exist.
+func NewDirStore(dir string) DirStore {
+	return DirStore{dir: dir}
+}
+
+// Path returns the path of the file holding the content of the upload.
+func (d DirStore) Path(id string) string {
+	return filepath.Join(d.dir, id)
+}
+
+func (d DirStore) Create(ctx context.Context, info Info) error {
+	// the offset is derived from the size of the content file
+	info.Offset = 0
+
+	encoded, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(d.Path(info.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(d.Path(info.ID)+".info", encoded, 0o600)
+}
+
+func (d DirStore) Info(ctx context.Context, id string) (Info, error) {
+	encoded, err := os.ReadFile(d.Path(id) + ".info")
+	if errors.Is(err, fs.ErrNotExist) {
+		return Info{}, ErrNotFound
+	}
+
+	if err != nil {
+		return Info{}, err
+	}
+
+	var info Info
+	if err := json.Unmarshal(encoded, &info); err != nil {
+		return Info{}, fmt.Errorf("decode info of upload %q: %w", id, err)
+	}
+
+	stat, err := os.Stat(d.Path(id))
+	if err != nil {
+		return Info{}, err
+	}
+
+	info.Offset = stat.Size()
+	return info, nil
+}
+
+func (d DirStore) Write(ctx context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	file, err := os.OpenFile(d.Path(id), os.O_WRONLY, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, ErrNotFound
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(file, r)
+	if err != nil {
+		return written, err
+	}
+
+	return written, file.Close()
+}
+
+func (d DirStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	file, err := os.Open(d.Path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (d DirStore) Delete(ctx context.Context, id string) error {
+	err := os.Remove(d.Path(id) + ".info")
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(d.Path(id))
+}
@@ -0,0 +1,94 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"sync"
+)
+
+// MemoryStore is an in-memory Store, useful for tests. Uploads are lost when the process exits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	uploads map[string]*memoryUpload
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+type memoryUpload struct {
+	info    Info
+	content []byte
+}
+
+// NewMemoryStore creates a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{uploads: map[string]*memoryUpload{}}
+}
+
+func (m *MemoryStore) Create(ctx context.Context, info Info) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	info.Metadata = maps.Clone(info.Metadata)
+	m.uploads[info.ID] = &memoryUpload{info: info}
+
+	return nil
+}
+
+func (m *MemoryStore) Info(ctx context.Context, id string) (Info, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.uploads[id]
+	if !ok {
+		return Info{}, ErrNotFound
+	}
+
+	info := upload.info
+	info.Metadata = maps.Clone(info.Metadata)
+	return info, nil
+}
+
+func (m *MemoryStore) Write(ctx context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	// read without holding the lock, the body might arrive slowly
+	var buf bytes.Buffer
+	_, readErr := io.Copy(&buf, r)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.uploads[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+
+	upload.content = append(upload.content, buf.Bytes()...)
+	upload.info.Offset += int64(buf.Len())
+
+	return int64(buf.Len()), readErr
+}
+
+func (m *MemoryStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.uploads[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(upload.content)), nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.uploads[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(m.uploads, id)
+	return nil
+}
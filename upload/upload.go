@@ -0,0 +1,434 @@
+// Package upload implements resumable uploads using the tus protocol, version 1.0.0,
+// including the creation and termination extensions. See https://tus.io/protocols/resumable-upload
+//
+// Clients create an upload using POST, which responds with the location of the new upload.
+// The content is then sent using one or more PATCH requests. If a request is interrupted,
+// clients ask for the current offset using HEAD and continue from there:
+//
+//	uploads := upload.New(upload.NewDirStore("/var/uploads"),
+//	  upload.MaxSize(1<<30),
+//	  upload.OnComplete(gum.Handler(func(ctx context.Context, u upload.Upload) error {
+//	    return process(ctx, u)
+//	  })),
+//	)
+//
+//	mux.Handle("/files/", uploads)
+//
+// The id of an upload is the last element of the request path and the location of a new
+// upload is the path of the creation request joined with its id. Mount the Server
+// without http.StripPrefix, so that locations point to the mounted path.
+package upload
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Version is the version of the tus protocol implemented by the Server.
+const Version = "1.0.0"
+
+// ErrNotFound is returned by a Store if an upload does not exist.
+var ErrNotFound = errors.New("upload not found")
+
+// Info describes the state of an upload.
+type Info struct {
+	// ID identifies the upload.
+	ID string
+
+	// Size is the total size of the upload in bytes.
+	Size int64
+
+	// Offset is the number of bytes received so far.
+	Offset int64
+
+	// Metadata holds the metadata sent by the client when creating the upload, e.g. a filename.
+	Metadata map[string]string
+}
+
+// Complete reports whether all bytes of the upload were received.
+func (i Info) Complete() bool {
+	return i.Offset == i.Size
+}
+
+// Store persists uploads. Implementations must be safe for concurrent use.
+// The Server never writes to the same upload concurrently.
+type Store interface {
+	// Create creates a new, empty upload.
+	Create(ctx context.Context, info Info) error
+
+	// Info returns the current state of the upload, or ErrNotFound.
+	Info(ctx context.Context, id string) (Info, error)
+
+	// Write appends the content of r to the upload. offset is the current offset of the
+	// upload. Returns the number of bytes written. Bytes written before an error occurred
+	// must be kept, so the client can resume the upload.
+	Write(ctx context.Context, id string, offset int64, r io.Reader) (int64, error)
+
+	// Open opens the content of the upload for reading.
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+
+	// Delete deletes the upload, or returns ErrNotFound.
+	Delete(ctx context.Context, id string) error
+}
+
+// Option configures a Server.
+type Option func(s *Server)
+
+// MaxSize rejects uploads larger than size bytes with 413 Request Entity Too Large.
+// A value of zero means no limit.
+func MaxSize(size int64) Option {
+	return func(s *Server) {
+		s.maxSize = size
+	}
+}
+
+// OnComplete sets the handler called by the PATCH request that completes an upload,
+// usually a gum.Handler extracting an Upload. Its response is sent as response of the
+// request, the Upload-Offset header is already set. Without a handler, the request is
+// answered with 204 No Content.
+func OnComplete(handler http.Handler) Option {
+	return func(s *Server) {
+		s.onComplete = handler
+	}
+}
+
+// Server is a http.Handler implementing the tus protocol.
+type Server struct {
+	store      Store
+	maxSize    int64
+	onComplete http.Handler
+
+	// locks holds the ids of the uploads that are currently being written
+	locks sync.Map
+}
+
+// New creates a new Server storing uploads in the given Store.
+func New(store Store, options ...Option) *Server {
+	s := &Server{store: store}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", Version)
+
+	if r.Method == http.MethodOptions {
+		s.options(w)
+		return
+	}
+
+	if r.Header.Get("Tus-Resumable") != Version {
+		w.Header().Set("Tus-Version", Version)
+		err := fmt.Errorf("unsupported tus version %q", r.Header.Get("Tus-Resumable"))
+		response.Error(err, http.StatusPreconditionFailed).ServeHTTP(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		s.create(w, r)
+		return
+	}
+
+	id := path.Base(r.URL.Path)
+	if !validID(id) {
+		response.Error(ErrNotFound, http.StatusNotFound).ServeHTTP(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodHead:
+		s.head(w, r, id)
+
+	case http.MethodPatch:
+		s.patch(w, r, id)
+
+	case http.MethodDelete:
+		s.delete(w, r, id)
+
+	default:
+		w.Header().Set("Allow", "OPTIONS, POST, HEAD, PATCH, DELETE")
+		err := fmt.Errorf("method %s not allowed", r.Method)
+		response.Error(err, http.StatusMethodNotAllowed).ServeHTTP(w, r)
+	}
+}
+
+func (s *Server) options(w http.ResponseWriter) {
+	header := w.Header()
+	header.Set("Tus-Version", Version)
+	header.Set("Tus-Extension", "creation,termination")
+
+	if s.maxSize > 0 {
+		header.Set("Tus-Max-Size", strconv.FormatInt(s.maxSize, 10))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	size, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || size < 0 {
+		err := errors.New("invalid or missing Upload-Length header")
+		response.Error(err, http.StatusBadRequest).ServeHTTP(w, r)
+		return
+	}
+
+	if s.maxSize > 0 && size > s.maxSize {
+		err := fmt.Errorf("upload of %d bytes exceeds the maximum size of %d bytes", size, s.maxSize)
+		response.Error(err, http.StatusRequestEntityTooLarge).ServeHTTP(w, r)
+		return
+	}
+
+	metadata, err := parseMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		response.Error(fmt.Errorf("parse Upload-Metadata: %w", err), http.StatusBadRequest).ServeHTTP(w, r)
+		return
+	}
+
+	info := Info{ID: newID(), Size: size, Metadata: metadata}
+
+	if err := s.store.Create(r.Context(), info); err != nil {
+		response.Error(fmt.Errorf("create upload: %w", err), http.StatusInternalServerError).ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+info.ID)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) head(w http.ResponseWriter, r *http.Request, id string) {
+	info, ok := s.info(w, r, id)
+	if !ok {
+		return
+	}
+
+	header := w.Header()
+	header.Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	header.Set("Upload-Length", strconv.FormatInt(info.Size, 10))
+	header.Set("Cache-Control", "no-store")
+
+	if len(info.Metadata) > 0 {
+		header.Set("Upload-Metadata", encodeMetadata(info.Metadata))
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		err := errors.New("Content-Type must be application/offset+octet-stream")
+		response.Error(err, http.StatusUnsupportedMediaType).ServeHTTP(w, r)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		err := errors.New("invalid or missing Upload-Offset header")
+		response.Error(err, http.StatusBadRequest).ServeHTTP(w, r)
+		return
+	}
+
+	unlock, ok := s.tryLock(id)
+	if !ok {
+		err := errors.New("upload is locked by another request")
+		response.Error(err, http.StatusLocked).ServeHTTP(w, r)
+		return
+	}
+
+	defer unlock()
+
+	info, ok := s.info(w, r, id)
+	if !ok {
+		return
+	}
+
+	if offset != info.Offset {
+		err := fmt.Errorf("Upload-Offset %d does not match the current offset %d", offset, info.Offset)
+		response.Error(err, http.StatusConflict).ServeHTTP(w, r)
+		return
+	}
+
+	wasComplete := info.Complete()
+
+	body := http.MaxBytesReader(w, r.Body, info.Size-info.Offset)
+	written, err := s.store.Write(r.Context(), id, info.Offset, body)
+
+	info.Offset += written
+	w.Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+
+		response.Error(fmt.Errorf("write upload: %w", err), statusCode).ServeHTTP(w, r)
+		return
+	}
+
+	// an empty upload is completed by each PATCH, as it never changes its state
+	completed := info.Complete() && (!wasComplete || info.Size == 0)
+
+	if completed && s.onComplete != nil {
+		ctx := context.WithValue(r.Context(), uploadKey{}, Upload{Info: info, store: s.store})
+		s.onComplete.ServeHTTP(w, r.WithContext(ctx))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, id string) {
+	unlock, ok := s.tryLock(id)
+	if !ok {
+		err := errors.New("upload is locked by another request")
+		response.Error(err, http.StatusLocked).ServeHTTP(w, r)
+		return
+	}
+
+	defer unlock()
+
+	err := s.store.Delete(r.Context(), id)
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		response.Error(err, http.StatusNotFound).ServeHTTP(w, r)
+
+	case err != nil:
+		response.Error(fmt.Errorf("delete upload: %w", err), http.StatusInternalServerError).ServeHTTP(w, r)
+
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// info loads the info of the upload. If that fails, an error response is written.
+func (s *Server) info(w http.ResponseWriter, r *http.Request, id string) (Info, bool) {
+	info, err := s.store.Info(r.Context(), id)
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		response.Error(err, http.StatusNotFound).ServeHTTP(w, r)
+		return Info{}, false
+
+	case err != nil:
+		response.Error(fmt.Errorf("load upload: %w", err), http.StatusInternalServerError).ServeHTTP(w, r)
+		return Info{}, false
+
+	default:
+		return info, true
+	}
+}
+
+// tryLock locks the upload with the given id. Returns false if the upload is already locked.
+func (s *Server) tryLock(id string) (func(), bool) {
+	if _, loaded := s.locks.LoadOrStore(id, struct{}{}); loaded {
+		return nil, false
+	}
+
+	return func() { s.locks.Delete(id) }, true
+}
+
+func newID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// validID checks that id could have been created by newID. This also
+// protects stores from ids that are not safe to use as file names.
+func validID(id string) bool {
+	_, err := hex.DecodeString(id)
+	return err == nil && len(id) == 32
+}
+
+// parseMetadata parses the Upload-Metadata header, a comma separated list of keys
+// followed by a space and their base64 encoded value. Values are optional.
+func parseMetadata(header string) (map[string]string, error) {
+	if strings.TrimSpace(header) == "" {
+		return nil, nil
+	}
+
+	metadata := map[string]string{}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("empty key")
+		}
+
+		if _, ok := metadata[key]; ok {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("value of key %q: %w", key, err)
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+func encodeMetadata(metadata map[string]string) string {
+	var pairs []string
+
+	for key, value := range metadata {
+		pair := key
+		if value != "" {
+			pair += " " + base64.StdEncoding.EncodeToString([]byte(value))
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+type uploadKey struct{}
+
+// Upload is the upload completed by the current request. It can only be extracted
+// by the handler set using OnComplete.
+type Upload struct {
+	Info
+
+	store Store
+}
+
+// Open opens the content of the upload for reading.
+func (u Upload) Open(ctx context.Context) (io.ReadCloser, error) {
+	return u.store.Open(ctx, u.ID)
+}
+
+func init() {
+	gum.Register(func(r *http.Request) (Upload, error) {
+		upload, ok := r.Context().Value(uploadKey{}).(Upload)
+		if !ok {
+			return Upload{}, errors.New("no completed upload in request, use upload.OnComplete")
+		}
+
+		return upload, nil
+	})
+}
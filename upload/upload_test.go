@@ -0,0 +1,166 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// failingReader returns the content and then fails, like an interrupted connection.
+type failingReader struct {
+	content io.Reader
+}
+
+func (f failingReader) Read(p []byte) (int, error) {
+	n, err := f.content.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+
+	return n, err
+}
+
+func TestServer(t *testing.T) {
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"dir":    NewDirStore(t.TempDir()),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			testServer(t, store)
+		})
+	}
+}
+
+func testServer(t *testing.T, store Store) {
+	var completed string
+
+	server := New(store, MaxSize(100), OnComplete(gum.Handler(func(ctx context.Context, u Upload) (http.Handler, error) {
+		content, err := u.Open(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		defer content.Close()
+
+		body, err := io.ReadAll(content)
+		completed = u.Metadata["filename"] + ":" + string(body)
+
+		return response.NoContent(), err
+	})))
+
+	mux := http.NewServeMux()
+	mux.Handle("/files/", server)
+
+	serve := func(method, target string, body io.Reader, headers ...string) gumtest.Response {
+		req := httptest.NewRequest(method, target, body)
+		req.Header.Set("Tus-Resumable", Version)
+
+		for idx := 0; idx < len(headers); idx += 2 {
+			req.Header.Set(headers[idx], headers[idx+1])
+		}
+
+		return gumtest.Serve(mux, req)
+	}
+
+	rec := serve(http.MethodOptions, "/files/", nil)
+	AssertEqual(t, rec.StatusCode, http.StatusNoContent)
+	AssertEqual(t, rec.Header.Get("Tus-Extension"), "creation,termination")
+	AssertEqual(t, rec.Header.Get("Tus-Max-Size"), "100")
+
+	// create the upload
+	rec = serve(http.MethodPost, "/files/", nil, "Upload-Length", "11", "Upload-Metadata", "filename aGVsbG8udHh0,private")
+	AssertEqual(t, rec.StatusCode, http.StatusCreated)
+
+	location := rec.Header.Get("Location")
+	AssertTrue(t, strings.HasPrefix(location, "/files/"))
+
+	rec = serve(http.MethodHead, location, nil)
+	AssertEqual(t, rec.StatusCode, http.StatusOK)
+	AssertEqual(t, rec.Header.Get("Upload-Offset"), "0")
+	AssertEqual(t, rec.Header.Get("Upload-Length"), "11")
+	AssertEqual(t, rec.Header.Get("Upload-Metadata"), "filename aGVsbG8udHh0,private")
+
+	patch := func(offset string, body io.Reader) gumtest.Response {
+		return serve(http.MethodPatch, location, body, "Content-Type", "application/offset+octet-stream", "Upload-Offset", offset)
+	}
+
+	// the first request is interrupted after some bytes
+	rec = patch("0", failingReader{strings.NewReader("hello")})
+	AssertEqual(t, rec.StatusCode, http.StatusInternalServerError)
+	AssertEqual(t, rec.Header.Get("Upload-Offset"), "5")
+
+	rec = serve(http.MethodHead, location, nil)
+	AssertEqual(t, rec.Header.Get("Upload-Offset"), "5")
+
+	// the offset must match
+	rec = patch("0", strings.NewReader(" world"))
+	AssertEqual(t, rec.StatusCode, http.StatusConflict)
+
+	// the upload can not grow beyond its size
+	rec = patch("5", strings.NewReader(" world, too long"))
+	AssertEqual(t, rec.StatusCode, http.StatusRequestEntityTooLarge)
+	AssertEqual(t, rec.Header.Get("Upload-Offset"), "11")
+	AssertEqual(t, completed, "")
+
+	rec = serve(http.MethodDelete, location, nil)
+	AssertEqual(t, rec.StatusCode, http.StatusNoContent)
+
+	rec = serve(http.MethodHead, location, nil)
+	AssertEqual(t, rec.StatusCode, http.StatusNotFound)
+
+	// a new upload in two parts
+	rec = serve(http.MethodPost, "/files/", nil, "Upload-Length", "11", "Upload-Metadata", "filename aGVsbG8udHh0")
+	location = rec.Header.Get("Location")
+
+	rec = patch("0", strings.NewReader("hello"))
+	AssertEqual(t, rec.StatusCode, http.StatusNoContent)
+	AssertEqual(t, completed, "")
+
+	rec = patch("5", strings.NewReader(" world"))
+	AssertEqual(t, rec.StatusCode, http.StatusNoContent)
+	AssertEqual(t, rec.Header.Get("Upload-Offset"), "11")
+	AssertEqual(t, completed, "hello.txt:hello world")
+}
+
+func TestServerErrors(t *testing.T) {
+	server := New(NewMemoryStore(), MaxSize(10))
+
+	serve := func(method, target string, headers ...string) int {
+		req := httptest.NewRequest(method, target, nil)
+		for idx := 0; idx < len(headers); idx += 2 {
+			req.Header.Set(headers[idx], headers[idx+1])
+		}
+
+		return gumtest.Serve(server, req).StatusCode
+	}
+
+	AssertEqual(t, serve(http.MethodPost, "/files/", "Upload-Length", "1"), http.StatusPreconditionFailed)
+	AssertEqual(t, serve(http.MethodPost, "/files/", "Tus-Resumable", Version), http.StatusBadRequest)
+	AssertEqual(t, serve(http.MethodPost, "/files/", "Tus-Resumable", Version, "Upload-Length", "11"), http.StatusRequestEntityTooLarge)
+	AssertEqual(t, serve(http.MethodPost, "/files/", "Tus-Resumable", Version, "Upload-Length", "1", "Upload-Metadata", "a !!"), http.StatusBadRequest)
+	AssertEqual(t, serve(http.MethodHead, "/files/../secret", "Tus-Resumable", Version), http.StatusNotFound)
+	AssertEqual(t, serve(http.MethodHead, "/files/00000000000000000000000000000000", "Tus-Resumable", Version), http.StatusNotFound)
+	AssertEqual(t, serve(http.MethodGet, "/files/00000000000000000000000000000000", "Tus-Resumable", Version), http.StatusMethodNotAllowed)
+	AssertEqual(t, serve(http.MethodPatch, "/files/00000000000000000000000000000000", "Tus-Resumable", Version), http.StatusUnsupportedMediaType)
+}
+
+func TestParseMetadata(t *testing.T) {
+	metadata, err := parseMetadata("filename d29ybGRfZG9taW5hdGlvbl9wbGFuLnBkZg==, is_confidential")
+	AssertEqual(t, err, nil)
+	AssertEqual(t, metadata, map[string]string{"filename": "world_domination_plan.pdf", "is_confidential": ""})
+
+	_, err = parseMetadata("a YQ==,a YQ==")
+	AssertTrue(t, err != nil)
+
+	AssertEqual(t, encodeMetadata(metadata), "filename d29ybGRfZG9taW5hdGlvbl9wbGFuLnBkZg==,is_confidential")
+}
@@ -0,0 +1,495 @@
+// Package validate checks struct values against rules defined in struct tags.
+//
+// Rules are listed in the validate tag, separated by commas:
+//
+//	type Signup struct {
+//		Name  string   `json:"name" validate:"required,min=3,max=32"`
+//		Email string   `json:"email" validate:"required,email"`
+//		Plan  string   `json:"plan" validate:"oneof=free pro"`
+//		Code  string   `json:"code" validate:"len=6,regexp=^[0-9]+$"`
+//		Tags  []string `json:"tags" validate:"max=5"`
+//	}
+//
+// The following rules are supported:
+//
+//   - required: the value must not be the zero value
+//   - min, max: bounds of numbers, or of the length of strings, slices and maps
+//   - len: the exact length of strings, slices and maps
+//   - oneof: a space separated list of allowed values. The zero value is accepted,
+//     unless the field is also required
+//   - email: a valid email address
+//   - regexp: the string must match the regular expression. As the expression
+//     may contain commas, regexp must be the last rule of the tag.
+//
+// Values of fields with an enum struct tag or a type implementing serde.Enum
+// must be one of the allowed values.
+//
+// The message of a violation can be replaced by a user facing text using the errmsg tag,
+// which is also used by serde if a value can not be unmarshalled into the field:
+//
+//	Age int `json:"age" validate:"min=0,max=120" errmsg:"age must be a number between 0 and 120"`
+//
+// Nested structs, pointers to structs and slices of structs are validated recursively.
+// Fields are named as in serde, using the json struct tag. The gum extractors JSON,
+// QueryValues, PathValues and FormValues validate their values automatically.
+package validate
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum/serde"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldError describes a single violated rule.
+type FieldError struct {
+	// Field is the path of the field, e.g. "address.city" or "items[2].name"
+	Field string `json:"field"`
+
+	// Rule is the name of the violated rule, e.g. "min"
+	Rule string `json:"rule"`
+
+	// Message is a human readable description of the violation
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Errors lists all violations found in a value.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	var messages []string
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Struct validates the struct value, which may also be a pointer to a struct.
+// Returns Errors listing all violations, or nil if the value is valid.
+// Returns a different error if the validate tags of the type are invalid.
+func Struct(value any) error {
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Pointer {
+		if rValue.IsNil() {
+			return nil
+		}
+
+		rValue = rValue.Elem()
+	}
+
+	if rValue.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	if err := validateStruct(rValue, "", &errs); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// HasRules reports whether values of type ty have any validation rules, including
+// rules of nested structs. Use it to skip validation for types without rules.
+func HasRules(ty reflect.Type) bool {
+	if cached, ok := cachedHasRules.Load(ty); ok {
+		return cached.(bool)
+	}
+
+	result := hasRules(ty, map[reflect.Type]bool{})
+	cachedHasRules.Store(ty, result)
+
+	return result
+}
+
+var cachedHasRules sync.Map
+
+func hasRules(ty reflect.Type, seen map[reflect.Type]bool) bool {
+	ty = structTypeOf(ty)
+	if ty == nil || seen[ty] {
+		return false
+	}
+
+	seen[ty] = true
+
+	for _, field := range serde.Fields(ty) {
+		if _, ok := field.Tag.Lookup("validate"); ok {
+			return true
+		}
+
+		if serde.EnumValuesOf(field) != nil {
+			return true
+		}
+
+		if hasRules(field.Type, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structTypeOf returns the struct type behind pointers, slices and
+// arrays of ty, or nil if there is no struct type.
+func structTypeOf(ty reflect.Type) reflect.Type {
+	for {
+		switch ty.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			ty = ty.Elem()
+
+		case reflect.Struct:
+			return ty
+
+		default:
+			return nil
+		}
+	}
+}
+
+type rule struct {
+	name  string
+	check func(value reflect.Value) (string, bool)
+}
+
+type fieldRules struct {
+	field serde.Field
+	rules []rule
+}
+
+var cachedRules sync.Map
+
+func rulesOf(ty reflect.Type) ([]fieldRules, error) {
+	if cached, ok := cachedRules.Load(ty); ok {
+		return cached.([]fieldRules), nil
+	}
+
+	var result []fieldRules
+
+	for _, field := range serde.Fields(ty) {
+		rules, err := parseRules(field.Type, field.Tag.Get("validate"))
+		if err != nil {
+			return nil, fmt.Errorf("field %q of %s: %w", field.Name, ty, err)
+		}
+
+		if allowed := serde.EnumValuesOf(field); allowed != nil {
+			rules = append(rules, enumRule(allowed))
+		}
+
+		result = append(result, fieldRules{field: field, rules: rules})
+	}
+
+	cachedRules.Store(ty, result)
+
+	return result, nil
+}
+
+func validateStruct(value reflect.Value, path string, errs *Errors) error {
+	fields, err := rulesOf(value.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		fieldValue := value.FieldByIndex(field.field.Index)
+		fieldPath := joinPath(path, field.field.Name)
+
+		valid := true
+		for _, rule := range field.rules {
+			if message, ok := rule.check(fieldValue); !ok {
+				if custom := field.field.Tag.Get("errmsg"); custom != "" {
+					message = custom
+				}
+
+				*errs = append(*errs, FieldError{Field: fieldPath, Rule: rule.name, Message: message})
+				valid = false
+				break
+			}
+		}
+
+		if valid {
+			if err := validateNested(fieldValue, fieldPath, errs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateNested(value reflect.Value, path string, errs *Errors) error {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+
+		return validateNested(value.Elem(), path, errs)
+
+	case reflect.Struct:
+		return validateStruct(value, path, errs)
+
+	case reflect.Slice, reflect.Array:
+		if structTypeOf(value.Type().Elem()) == nil {
+			return nil
+		}
+
+		for idx := range value.Len() {
+			if err := validateNested(value.Index(idx), path+"["+strconv.Itoa(idx)+"]", errs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func parseRules(ty reflect.Type, tag string) ([]rule, error) {
+	var rules []rule
+
+	for tag != "" {
+		var spec string
+		if strings.HasPrefix(tag, "regexp=") {
+			// the expression may contain commas and must be the last rule
+			spec, tag = tag, ""
+		} else {
+			spec, tag, _ = strings.Cut(tag, ",")
+		}
+
+		name, param, _ := strings.Cut(strings.TrimSpace(spec), "=")
+		if name == "" {
+			continue
+		}
+
+		r, err := makeRule(ty, name, param)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", spec, err)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func makeRule(ty reflect.Type, name, param string) (rule, error) {
+	switch name {
+	case "required":
+		return rule{name: name, check: func(value reflect.Value) (string, bool) {
+			return "is required", !value.IsZero()
+		}}, nil
+
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return rule{}, fmt.Errorf("invalid number %q", param)
+		}
+
+		return makeBoundsRule(ty, name, limit)
+
+	case "oneof":
+		allowed := strings.Fields(param)
+		if len(allowed) == 0 {
+			return rule{}, errors.New("no values given")
+		}
+
+		message := "must be one of " + strings.Join(allowed, ", ")
+
+		return rule{name: name, check: func(value reflect.Value) (string, bool) {
+			value = indirect(value)
+			if !value.IsValid() || value.IsZero() {
+				// like email and regexp, oneof does not check missing values.
+				// combine it with required to reject them.
+				return "", true
+			}
+
+			text := fmt.Sprint(value.Interface())
+			for _, candidate := range allowed {
+				if candidate == text {
+					return "", true
+				}
+			}
+
+			return message, false
+		}}, nil
+
+	case "email":
+		return makeStringRule(ty, name, func(text string) (string, bool) {
+			address, err := mail.ParseAddress(text)
+			return "must be a valid email address", err == nil && address.Address == text
+		})
+
+	case "regexp":
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return rule{}, err
+		}
+
+		return makeStringRule(ty, name, func(text string) (string, bool) {
+			return "must match " + param, re.MatchString(text)
+		})
+
+	default:
+		return rule{}, fmt.Errorf("unknown rule %q", name)
+	}
+}
+
+// enumRule checks the value, or each element of a slice, against the allowed values
+// of an enum struct tag or a type implementing serde.Enum.
+func enumRule(allowed []string) rule {
+	message := "must be one of " + strings.Join(allowed, ", ")
+
+	var check func(value reflect.Value) bool
+	check = func(value reflect.Value) bool {
+		value = indirect(value)
+
+		switch {
+		case !value.IsValid() || value.IsZero():
+			// combine with required to reject missing values
+			return true
+
+		case value.Kind() == reflect.Slice || value.Kind() == reflect.Array:
+			for idx := range value.Len() {
+				if !check(value.Index(idx)) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		text := fmt.Sprint(value.Interface())
+		if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+			if marshaled, err := marshaler.MarshalText(); err == nil {
+				text = string(marshaled)
+			}
+		}
+
+		return slices.Contains(allowed, text)
+	}
+
+	return rule{name: "enum", check: func(value reflect.Value) (string, bool) {
+		return message, check(value)
+	}}
+}
+
+// makeStringRule creates a rule for string fields. Empty strings are always valid,
+// combine with required to reject them.
+func makeStringRule(ty reflect.Type, name string, check func(text string) (string, bool)) (rule, error) {
+	if derefType(ty).Kind() != reflect.String {
+		return rule{}, fmt.Errorf("only supported on strings, got %s", ty)
+	}
+
+	return rule{name: name, check: func(value reflect.Value) (string, bool) {
+		value = indirect(value)
+		if !value.IsValid() || value.String() == "" {
+			return "", true
+		}
+
+		return check(value.String())
+	}}, nil
+}
+
+func makeBoundsRule(ty reflect.Type, name string, limit float64) (rule, error) {
+	var measure func(value reflect.Value) float64
+	var unit string
+
+	switch derefType(ty).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		measure = func(value reflect.Value) float64 { return float64(value.Int()) }
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		measure = func(value reflect.Value) float64 { return float64(value.Uint()) }
+
+	case reflect.Float32, reflect.Float64:
+		measure = func(value reflect.Value) float64 { return value.Float() }
+
+	case reflect.String:
+		measure = func(value reflect.Value) float64 { return float64(utf8.RuneCountInString(value.String())) }
+		unit = " characters"
+
+	case reflect.Slice, reflect.Array, reflect.Map:
+		measure = func(value reflect.Value) float64 { return float64(value.Len()) }
+		unit = " items"
+
+	default:
+		return rule{}, fmt.Errorf("not supported on %s", ty)
+	}
+
+	if name == "len" && unit == "" {
+		return rule{}, fmt.Errorf("len is not supported on %s", ty)
+	}
+
+	formatted := strconv.FormatFloat(limit, 'f', -1, 64)
+
+	var message string
+	var valid func(measured float64) bool
+
+	switch name {
+	case "min":
+		message = "must be at least " + formatted + unit
+		valid = func(measured float64) bool { return measured >= limit }
+
+	case "max":
+		message = "must be at most " + formatted + unit
+		valid = func(measured float64) bool { return measured <= limit }
+
+	default:
+		message = "must have exactly " + formatted + unit
+		valid = func(measured float64) bool { return measured == limit }
+	}
+
+	return rule{name: name, check: func(value reflect.Value) (string, bool) {
+		value = indirect(value)
+		if !value.IsValid() {
+			return "", true
+		}
+
+		return message, valid(measure(value))
+	}}, nil
+}
+
+func derefType(ty reflect.Type) reflect.Type {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+
+	return ty
+}
+
+// indirect follows pointers, returns an invalid value for nil pointers.
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	return value
+}
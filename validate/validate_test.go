@@ -0,0 +1,141 @@
+package validate
+
+import (
+	"errors"
+	. "github.com/go-gum/gum/internal/test"
+	"reflect"
+	"testing"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type signup struct {
+	Name    string    `json:"name" validate:"required,min=3,max=8"`
+	Email   string    `json:"email" validate:"email"`
+	Plan    string    `json:"plan" validate:"oneof=free pro"`
+	Code    string    `json:"code" validate:"len=4,regexp=^[0-9]{2,}$"`
+	Age     *int      `json:"age" validate:"min=18"`
+	Tags    []string  `json:"tags" validate:"max=2"`
+	Address address   `json:"address"`
+	Others  []address `json:"others"`
+}
+
+func TestStruct(t *testing.T) {
+	valid := signup{
+		Name:    "Albert",
+		Email:   "albert@example.com",
+		Plan:    "pro",
+		Code:    "1234",
+		Address: address{City: "Berlin"},
+	}
+
+	AssertEqual(t, Struct(valid), nil)
+	AssertEqual(t, Struct(&valid), nil)
+
+	age := 12
+
+	invalid := signup{
+		Name:   "Al",
+		Email:  "not an email",
+		Plan:   "enterprise",
+		Code:   "12a4",
+		Age:    &age,
+		Tags:   []string{"a", "b", "c"},
+		Others: []address{{City: "Paris"}, {}},
+	}
+
+	err := Struct(invalid)
+
+	var errs Errors
+	AssertTrue(t, errors.As(err, &errs))
+
+	AssertEqual(t, errs, Errors{
+		{Field: "name", Rule: "min", Message: "must be at least 3 characters"},
+		{Field: "email", Rule: "email", Message: "must be a valid email address"},
+		{Field: "plan", Rule: "oneof", Message: "must be one of free, pro"},
+		{Field: "code", Rule: "regexp", Message: "must match ^[0-9]{2,}$"},
+		{Field: "age", Rule: "min", Message: "must be at least 18"},
+		{Field: "tags", Rule: "max", Message: "must be at most 2 items"},
+		{Field: "address.city", Rule: "required", Message: "is required"},
+		{Field: "others[1].city", Rule: "required", Message: "is required"},
+	})
+}
+
+func TestOneOfOptional(t *testing.T) {
+	type Filter struct {
+		Plan     string `json:"plan" validate:"oneof=free pro"`
+		Required string `json:"required" validate:"required,oneof=free pro"`
+		Level    int    `json:"level" validate:"oneof=1 2 3"`
+	}
+
+	AssertEqual(t, Struct(Filter{Required: "free"}), nil)
+
+	AssertEqual(t, Struct(Filter{}), error(Errors{
+		{Field: "required", Rule: "required", Message: "is required"},
+	}))
+
+	AssertEqual(t, Struct(Filter{Required: "free", Level: 4}), error(Errors{
+		{Field: "level", Rule: "oneof", Message: "must be one of 1, 2, 3"},
+	}))
+}
+
+func TestInvalidTags(t *testing.T) {
+	type Invalid struct {
+		Value bool `validate:"min=1"`
+	}
+
+	err := Struct(Invalid{})
+	AssertTrue(t, err != nil)
+
+	var errs Errors
+	AssertTrue(t, !errors.As(err, &errs))
+}
+
+func TestHasRules(t *testing.T) {
+	type Plain struct {
+		Name string
+	}
+
+	AssertTrue(t, HasRules(reflect.TypeFor[signup]()))
+	AssertTrue(t, HasRules(reflect.TypeFor[[]*signup]()))
+	AssertTrue(t, !HasRules(reflect.TypeFor[Plain]()))
+	AssertTrue(t, !HasRules(reflect.TypeFor[string]()))
+}
+
+type color string
+
+func (color) EnumValues() []string {
+	return []string{"red", "green"}
+}
+
+func TestEnum(t *testing.T) {
+	type Paint struct {
+		Color  color    `json:"color"`
+		Colors []color  `json:"colors"`
+		Finish string   `json:"finish" enum:"matte,gloss"`
+		Extras []string `json:"extras"`
+	}
+
+	AssertTrue(t, HasRules(reflect.TypeFor[Paint]()))
+	AssertEqual(t, Struct(Paint{Color: "red", Colors: []color{"green"}, Finish: "matte"}), nil)
+	AssertEqual(t, Struct(Paint{}), nil)
+
+	err := Struct(Paint{Color: "blue", Colors: []color{"red", "pink"}, Finish: "satin"})
+	AssertEqual(t, err, error(Errors{
+		{Field: "color", Rule: "enum", Message: "must be one of red, green"},
+		{Field: "colors", Rule: "enum", Message: "must be one of red, green"},
+		{Field: "finish", Rule: "enum", Message: "must be one of matte, gloss"},
+	}))
+}
+
+func TestErrorMessageTag(t *testing.T) {
+	type Person struct {
+		Name string `json:"name" validate:"required,min=2" errmsg:"please tell us your name"`
+	}
+
+	AssertEqual(t, Struct(Person{Name: "A"}), error(Errors{
+		{Field: "name", Rule: "min", Message: "please tell us your name"},
+	}))
+}
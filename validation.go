@@ -0,0 +1,25 @@
+package gum
+
+import (
+	"errors"
+	"github.com/go-gum/gum/validate"
+	"net/http"
+	"reflect"
+)
+
+// validateValue validates a decoded value of type T using its validate struct tags.
+// Violations are reported as HTTPError with status 422 Unprocessable Entity.
+func validateValue[T any](value T) error {
+	if !validate.HasRules(reflect.TypeFor[T]()) {
+		return nil
+	}
+
+	err := validate.Struct(value)
+
+	var violations validate.Errors
+	if errors.As(err, &violations) {
+		return NewHTTPError(http.StatusUnprocessableEntity, err)
+	}
+
+	return err
+}
@@ -0,0 +1,58 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	type Signup struct {
+		Name  string `json:"name" validate:"required,min=3"`
+		Email string `json:"email" validate:"email"`
+	}
+
+	handler := Handler(func(body JSON[Signup]) response.Response {
+		return response.Text("welcome " + body.Value.Name)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "Albert"}`))
+	resp := gumtest.Serve(handler, req)
+	AssertEqual(t, resp.StatusCode, http.StatusOK)
+	AssertEqual(t, resp.Text(), "welcome Albert")
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "Al", "email": "nope"}`))
+	resp = gumtest.Serve(handler, req)
+	AssertEqual(t, resp.StatusCode, http.StatusUnprocessableEntity)
+	AssertTrue(t, strings.Contains(resp.Text(), "name: must be at least 3 characters"))
+	AssertTrue(t, strings.Contains(resp.Text(), "email: must be a valid email address"))
+
+	type Page struct {
+		Limit int `json:"limit" validate:"max=100"`
+	}
+
+	query := Handler(func(page QueryValues[Page]) {})
+
+	resp = gumtest.Serve(query, httptest.NewRequest(http.MethodGet, "/?limit=500", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusUnprocessableEntity)
+}
+
+func TestErrorMessageTag(t *testing.T) {
+	type Person struct {
+		Age int `json:"age" validate:"max=120" errmsg:"age must be a number between 0 and 120"`
+	}
+
+	handler := Handler(func(query QueryValues[Person]) {})
+
+	resp := gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/?age=abc", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusBadRequest)
+	AssertEqual(t, resp.Text(), "age must be a number between 0 and 120")
+
+	resp = gumtest.Serve(handler, httptest.NewRequest(http.MethodGet, "/?age=500", nil))
+	AssertEqual(t, resp.StatusCode, http.StatusUnprocessableEntity)
+	AssertTrue(t, strings.Contains(resp.Text(), "age: age must be a number between 0 and 120"))
+}
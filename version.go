@@ -0,0 +1,215 @@
+package gum
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the api version a request was routed to by Versions.
+type Version string
+
+type versionKey struct{}
+
+func init() {
+	Register(func(r *http.Request) (Version, error) {
+		version, ok := r.Context().Value(versionKey{}).(Version)
+		if !ok {
+			return "", errors.New("request was not routed by Versions")
+		}
+
+		return version, nil
+	})
+}
+
+// Versions routes requests to the handler of an api version. The version is selected by
+// the first segment of the path, e.g. "/v2/users", or by the version parameter of the
+// media types in the Accept header, e.g. "application/json; version=2". The version
+// prefix is stripped from the path before the request is passed to the handler. If the
+// path does not select a version, the response varies by the Accept header.
+// Handlers can access the selected version using the Version extractor:
+//
+//	versions := gum.NewVersions(gum.DefaultVersion("v2"))
+//	versions.Handle("v1", v1Mux, gum.DeprecatedVersion(deprecatedAt, sunsetAt, "https://example.com/migrate"))
+//	versions.Handle("v2", v2Mux)
+//
+//	http.ListenAndServe(":8080", versions)
+type Versions struct {
+	defaultVersion string
+	handlers       map[string]versionHandler
+}
+
+type versionHandler struct {
+	handler     http.Handler
+	deprecation time.Time
+	sunset      time.Time
+	link        string
+}
+
+// VersionsOption configures Versions.
+type VersionsOption func(v *Versions)
+
+// DefaultVersion sets the version used for requests that do not select a version.
+// Without a default version, such requests are rejected with 404 Not Found.
+func DefaultVersion(version string) VersionsOption {
+	return func(v *Versions) {
+		v.defaultVersion = version
+	}
+}
+
+// VersionOption configures a single version.
+type VersionOption func(h *versionHandler)
+
+// DeprecatedVersion marks a version as deprecated. Responses of the version include a
+// Deprecation header (RFC 9745) with the given time. If sunset is not zero, a Sunset
+// header (RFC 8594) is added, if link is not empty, a Link header pointing to
+// documentation about the deprecation.
+func DeprecatedVersion(deprecation, sunset time.Time, link string) VersionOption {
+	return func(h *versionHandler) {
+		h.deprecation = deprecation
+		h.sunset = sunset
+		h.link = link
+	}
+}
+
+// NewVersions creates a new Versions without any registered versions.
+func NewVersions(options ...VersionsOption) *Versions {
+	v := &Versions{handlers: map[string]versionHandler{}}
+
+	for _, option := range options {
+		option(v)
+	}
+
+	return v
+}
+
+// Handle registers the handler of a version, e.g. "v1". Versions must not contain slashes.
+func (v *Versions) Handle(version string, handler http.Handler, options ...VersionOption) {
+	if version == "" || strings.Contains(version, "/") {
+		panic("gum: invalid version " + version)
+	}
+
+	h := versionHandler{handler: handler}
+	for _, option := range options {
+		option(&h)
+	}
+
+	v.handlers[version] = h
+}
+
+func (v *Versions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	version, h, ok := v.fromPath(r)
+	if ok {
+		r = stripVersionPrefix(r, version)
+	} else {
+		// the version, and therefore the response, depends on the Accept header
+		w.Header().Add("Vary", "Accept")
+		version, h, ok = v.fromAccept(r)
+	}
+
+	if !ok {
+		version, h, ok = v.lookup(v.defaultVersion)
+	}
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	header := w.Header()
+
+	if !h.deprecation.IsZero() {
+		header.Set("Deprecation", "@"+formatUnix(h.deprecation))
+	}
+
+	if !h.sunset.IsZero() {
+		header.Set("Sunset", h.sunset.UTC().Format(http.TimeFormat))
+	}
+
+	if h.link != "" {
+		header.Add("Link", "<"+h.link+`>; rel="deprecation"`)
+	}
+
+	ctx := context.WithValue(r.Context(), versionKey{}, Version(version))
+	h.handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func (v *Versions) lookup(version string) (string, versionHandler, bool) {
+	if version == "" {
+		return "", versionHandler{}, false
+	}
+
+	if h, ok := v.handlers[version]; ok {
+		return version, h, true
+	}
+
+	// accept "2" for a version named "v2"
+	if h, ok := v.handlers["v"+version]; ok {
+		return "v" + version, h, true
+	}
+
+	return "", versionHandler{}, false
+}
+
+func (v *Versions) fromPath(r *http.Request) (string, versionHandler, bool) {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	h, ok := v.handlers[segment]
+	return segment, h, ok
+}
+
+func (v *Versions) fromAccept(r *http.Request) (string, versionHandler, bool) {
+	mediaRanges, err := Extract[Accept](r)
+	if err != nil {
+		return "", versionHandler{}, false
+	}
+
+	for _, mediaRange := range mediaRanges {
+		if version, ok := versionParam(mediaRange.Params); ok {
+			return v.lookup(version)
+		}
+	}
+
+	return "", versionHandler{}, false
+}
+
+func versionParam(params map[string]string) (string, bool) {
+	for key, value := range params {
+		if strings.EqualFold(key, "version") && value != "" {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// stripVersionPrefix returns a shallow copy of r with the version prefix removed from the path.
+func stripVersionPrefix(r *http.Request, version string) *http.Request {
+	prefix := "/" + version
+
+	r2 := r.WithContext(r.Context())
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+
+	r2.URL.Path = strings.TrimPrefix(r.URL.Path, prefix)
+	if r2.URL.Path == "" {
+		r2.URL.Path = "/"
+	}
+
+	if r.URL.RawPath != "" {
+		r2.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, prefix)
+		if r2.URL.RawPath == "" {
+			r2.URL.RawPath = "/"
+		}
+	}
+
+	return r2
+}
+
+func formatUnix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
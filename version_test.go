@@ -0,0 +1,56 @@
+package gum
+
+import (
+	"github.com/go-gum/gum/gumtest"
+	. "github.com/go-gum/gum/internal/test"
+	"github.com/go-gum/gum/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVersions(t *testing.T) {
+	handler := func(name string) http.Handler {
+		return Handler(func(version Version, r *http.Request) response.Response {
+			return response.Text(name + " " + string(version) + " " + r.URL.Path)
+		})
+	}
+
+	deprecation := time.Unix(1700000000, 0)
+
+	versions := NewVersions(DefaultVersion("v2"))
+	versions.Handle("v1", handler("first"), DeprecatedVersion(deprecation, time.Time{}, "https://example.com/v2"))
+	versions.Handle("v2", handler("second"))
+
+	serve := func(path, accept string) gumtest.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		return gumtest.Serve(versions, req)
+	}
+
+	resp := serve("/v1/users", "")
+	AssertEqual(t, resp.Text(), "first v1 /users")
+	AssertEqual(t, resp.Header.Get("Deprecation"), "@1700000000")
+	AssertEqual(t, resp.Header.Get("Link"), `<https://example.com/v2>; rel="deprecation"`)
+
+	resp = serve("/v2", "")
+	AssertEqual(t, resp.Text(), "second v2 /")
+	AssertEqual(t, resp.Header.Get("Deprecation"), "")
+
+	AssertEqual(t, resp.Header.Get("Vary"), "")
+
+	resp = serve("/users", "application/json; version=1")
+	AssertEqual(t, resp.Text(), "first v1 /users")
+	AssertEqual(t, resp.Header.Get("Vary"), "Accept")
+
+	resp = serve("/users", "")
+	AssertEqual(t, resp.Text(), "second v2 /users")
+	AssertEqual(t, resp.Header.Get("Vary"), "Accept")
+
+	resp = serve("/users", "application/json; version=3")
+	AssertEqual(t, resp.Text(), "second v2 /users")
+}
@@ -0,0 +1,270 @@
+// Package webhooks receives webhooks: it verifies the signature of a delivery, routes it
+// by its event type to a typed handler and answers with a status code that makes the
+// sender retry only when a retry can succeed:
+//
+//	receiver := webhooks.New(
+//		webhooks.Signature(gum.HMACSignature{
+//			Header: "X-Hub-Signature-256",
+//			Prefix: "sha256=",
+//			Secret: []byte(os.Getenv("WEBHOOK_SECRET")),
+//		}),
+//		webhooks.EventHeader("X-GitHub-Event"),
+//	)
+//
+//	webhooks.On(receiver, "push", func(ctx context.Context, event webhooks.Event[PushEvent]) error {
+//		return deploy(ctx, event.Payload.Ref)
+//	})
+//
+//	http.Handle("POST /webhooks/github", receiver)
+//
+// The status codes of the response are:
+//
+//   - 204 No Content if the handler succeeded.
+//   - 202 Accepted if no handler is registered for the event type.
+//   - 400 Bad Request if the payload could not be decoded.
+//   - 401 Unauthorized if the signature is missing or invalid.
+//   - 422 Unprocessable Entity if the handler returned a Permanent error.
+//   - 500 Internal Server Error for all other errors, so that the sender retries the delivery.
+//     Use Retry to send 503 Service Unavailable with a Retry-After header instead.
+//
+// A handler may also return a gum.HTTPError to choose the status code itself.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gum/gum"
+	"github.com/go-gum/gum/response"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event is a single webhook delivery.
+type Event[T any] struct {
+	// Type is the event type the delivery was routed by.
+	Type string
+
+	// Payload is the decoded body of the delivery.
+	Payload T
+
+	// Body is the raw body of the delivery.
+	Body []byte
+
+	// Header holds the request headers of the delivery, e.g. a delivery id.
+	Header http.Header
+}
+
+type handler func(ctx context.Context, eventType string, body []byte, header http.Header) error
+
+// Option configures a Receiver.
+type Option func(config *config)
+
+type config struct {
+	signature   *gum.HMACSignature
+	eventType   func(header http.Header, body []byte) (string, error)
+	maxBodySize int64
+}
+
+// Signature verifies the signature of every delivery before it is routed.
+// Deliveries are not verified by default.
+func Signature(signature gum.HMACSignature) Option {
+	return func(config *config) {
+		config.signature = &signature
+	}
+}
+
+// EventHeader reads the event type from the given request header, e.g. "X-GitHub-Event".
+func EventHeader(name string) Option {
+	return func(config *config) {
+		config.eventType = func(header http.Header, body []byte) (string, error) {
+			value := header.Get(name)
+			if value == "" {
+				return "", fmt.Errorf("no %s header in request", name)
+			}
+
+			return value, nil
+		}
+	}
+}
+
+// EventField reads the event type from a string field of the json payload. Nested fields
+// are separated by dots, e.g. "data.type". Defaults to the field "type".
+func EventField(path string) Option {
+	return func(config *config) {
+		config.eventType = func(header http.Header, body []byte) (string, error) {
+			return eventTypeOf(body, strings.Split(path, "."))
+		}
+	}
+}
+
+// MaxBodySize limits the size of a delivery. Defaults to 1MB.
+func MaxBodySize(n int64) Option {
+	return func(config *config) {
+		config.maxBodySize = n
+	}
+}
+
+// Receiver is an http.Handler that dispatches webhook deliveries to the handlers
+// registered with On.
+type Receiver struct {
+	config   config
+	handlers map[string]handler
+}
+
+// New creates a new Receiver without any handlers.
+func New(options ...Option) *Receiver {
+	config := config{maxBodySize: 1 << 20}
+	EventField("type")(&config)
+
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &Receiver{config: config, handlers: map[string]handler{}}
+}
+
+// On registers fn as the handler for deliveries with the given event type. The payload is
+// decoded from json into T. Use json.RawMessage to decode the payload yourself.
+func On[T any](receiver *Receiver, eventType string, fn func(ctx context.Context, event Event[T]) error) {
+	receiver.handlers[eventType] = func(ctx context.Context, eventType string, body []byte, header http.Header) error {
+		var payload T
+		if err := json.Unmarshal(body, &payload); err != nil {
+			err = fmt.Errorf("decode %q payload: %w", eventType, err)
+			return gum.NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		event := Event[T]{
+			Type:    eventType,
+			Payload: payload,
+			Body:    body,
+			Header:  header,
+		}
+
+		return fn(ctx, event)
+	}
+}
+
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rc.config.signature != nil {
+		signature := *rc.config.signature
+		if signature.MaxBodySize == 0 {
+			signature.MaxBodySize = rc.config.maxBodySize
+		}
+
+		gum.VerifyHMAC(signature)(http.HandlerFunc(rc.dispatch)).ServeHTTP(w, r)
+		return
+	}
+
+	rc.dispatch(w, r)
+}
+
+func (rc *Receiver) dispatch(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, rc.config.maxBodySize))
+	if err != nil {
+		statusCode := http.StatusBadRequest
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+
+		response.Error(fmt.Errorf("reading body: %w", err), statusCode).ServeHTTP(w, r)
+		return
+	}
+
+	eventType, err := rc.config.eventType(r.Header, body)
+	if err != nil {
+		response.Error(fmt.Errorf("event type: %w", err), http.StatusBadRequest).ServeHTTP(w, r)
+		return
+	}
+
+	handler, ok := rc.handlers[eventType]
+	if !ok {
+		// acknowledge the delivery, a retry would not be handled either
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if err := handler(r.Context(), eventType, body, r.Header); err != nil {
+		errorResponseOf(err).ServeHTTP(w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func errorResponseOf(err error) http.Handler {
+	var httpErr *gum.HTTPError
+	if errors.As(err, &httpErr) {
+		return response.Error(err, httpErr.StatusCode).UpdateWith(0, httpErr.Header)
+	}
+
+	var permanentErr permanentError
+	if errors.As(err, &permanentErr) {
+		return response.Error(err, http.StatusUnprocessableEntity)
+	}
+
+	var retryErr retryError
+	if errors.As(err, &retryErr) {
+		httpErr := gum.ServiceUnavailableError(retryErr.after, err)
+		return response.Error(err, httpErr.StatusCode).UpdateWith(0, httpErr.Header)
+	}
+
+	return response.Error(err, http.StatusInternalServerError)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as permanent: the delivery can not succeed when retried.
+// The Receiver answers with 422 Unprocessable Entity.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+type retryError struct {
+	err   error
+	after time.Duration
+}
+
+func (e retryError) Error() string { return e.err.Error() }
+func (e retryError) Unwrap() error { return e.err }
+
+// Retry asks the sender to retry the delivery after the given duration.
+// The Receiver answers with 503 Service Unavailable and a Retry-After header.
+func Retry(err error, after time.Duration) error {
+	return retryError{err: err, after: after}
+}
+
+func eventTypeOf(body []byte, path []string) (string, error) {
+	value := json.RawMessage(body)
+
+	for _, name := range path {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(value, &object); err != nil {
+			return "", fmt.Errorf("decode payload: %w", err)
+		}
+
+		field, ok := object[name]
+		if !ok {
+			return "", fmt.Errorf("no field %q in payload", strings.Join(path, "."))
+		}
+
+		value = field
+	}
+
+	var eventType string
+	if err := json.Unmarshal(value, &eventType); err != nil {
+		return "", fmt.Errorf("field %q is not a string", strings.Join(path, "."))
+	}
+
+	return eventType, nil
+}
@@ -0,0 +1,146 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/go-gum/gum"
+	. "github.com/go-gum/gum/internal/test"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type PushEvent struct {
+	Ref string `json:"ref"`
+}
+
+func deliver(receiver http.Handler, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiver(t *testing.T) {
+	var received []string
+
+	receiver := New(EventField("event.type"))
+
+	On(receiver, "push", func(ctx context.Context, event Event[PushEvent]) error {
+		received = append(received, event.Type+":"+event.Payload.Ref)
+		return nil
+	})
+
+	On(receiver, "permanent", func(ctx context.Context, event Event[PushEvent]) error {
+		return Permanent(errors.New("unknown repository"))
+	})
+
+	On(receiver, "retry", func(ctx context.Context, event Event[PushEvent]) error {
+		return Retry(errors.New("database unavailable"), 30*time.Second)
+	})
+
+	On(receiver, "retry-soon", func(ctx context.Context, event Event[PushEvent]) error {
+		return Retry(errors.New("database unavailable"), 400*time.Millisecond)
+	})
+
+	On(receiver, "fail", func(ctx context.Context, event Event[PushEvent]) error {
+		return errors.New("failed")
+	})
+
+	On(receiver, "teapot", func(ctx context.Context, event Event[PushEvent]) error {
+		return gum.NewHTTPError(http.StatusTeapot, errors.New("teapot"))
+	})
+
+	t.Run("Handled", func(t *testing.T) {
+		rec := deliver(receiver, `{"event": {"type": "push"}, "ref": "main"}`, nil)
+		AssertEqual(t, rec.Code, http.StatusNoContent)
+		AssertEqual(t, received, []string{"push:main"})
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		rec := deliver(receiver, `{"event": {"type": "ping"}}`, nil)
+		AssertEqual(t, rec.Code, http.StatusAccepted)
+	})
+
+	t.Run("NoEventType", func(t *testing.T) {
+		rec := deliver(receiver, `{"ref": "main"}`, nil)
+		AssertEqual(t, rec.Code, http.StatusBadRequest)
+	})
+
+	t.Run("InvalidPayload", func(t *testing.T) {
+		rec := deliver(receiver, `{"event": {"type": "push"}, "ref": 12}`, nil)
+		AssertEqual(t, rec.Code, http.StatusBadRequest)
+	})
+
+	t.Run("Permanent", func(t *testing.T) {
+		rec := deliver(receiver, `{"event": {"type": "permanent"}}`, nil)
+		AssertEqual(t, rec.Code, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		rec := deliver(receiver, `{"event": {"type": "retry"}}`, nil)
+		AssertEqual(t, rec.Code, http.StatusServiceUnavailable)
+		AssertEqual(t, rec.Header().Get("Retry-After"), "30")
+
+		// the delay is rounded up to full seconds
+		rec = deliver(receiver, `{"event": {"type": "retry-soon"}}`, nil)
+		AssertEqual(t, rec.Code, http.StatusServiceUnavailable)
+		AssertEqual(t, rec.Header().Get("Retry-After"), "1")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		rec := deliver(receiver, `{"event": {"type": "fail"}}`, nil)
+		AssertEqual(t, rec.Code, http.StatusInternalServerError)
+	})
+
+	t.Run("HTTPError", func(t *testing.T) {
+		rec := deliver(receiver, `{"event": {"type": "teapot"}}`, nil)
+		AssertEqual(t, rec.Code, http.StatusTeapot)
+	})
+}
+
+func TestReceiverSignature(t *testing.T) {
+	secret := []byte("secret")
+
+	receiver := New(
+		Signature(gum.HMACSignature{Header: "X-Signature", Prefix: "sha256=", Secret: secret}),
+		EventHeader("X-Event"),
+	)
+
+	var body []byte
+	On(receiver, "push", func(ctx context.Context, event Event[PushEvent]) error {
+		body = event.Body
+		return nil
+	})
+
+	payload := `{"ref": "main"}`
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	t.Run("Valid", func(t *testing.T) {
+		rec := deliver(receiver, payload, http.Header{"X-Signature": {signature}, "X-Event": {"push"}})
+		AssertEqual(t, rec.Code, http.StatusNoContent)
+		AssertEqual(t, string(body), payload)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		rec := deliver(receiver, payload, http.Header{"X-Signature": {"sha256=00"}, "X-Event": {"push"}})
+		AssertEqual(t, rec.Code, http.StatusUnauthorized)
+	})
+
+	t.Run("NoEventHeader", func(t *testing.T) {
+		rec := deliver(receiver, payload, http.Header{"X-Signature": {signature}})
+		AssertEqual(t, rec.Code, http.StatusBadRequest)
+	})
+}